@@ -0,0 +1,134 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Denisowiec/FoleyBookkeeper/internal/db"
+)
+
+const bookingTimeLayout = "2006-01-02 15:04"
+
+func commandCreateBooking(cfg *config, args []string) error {
+	// Takes project title, episode number or code like S02E05 (0 for none), room name, start time,
+	// duration, title and a list of usernames as input
+	if len(args) < 6 {
+		return fmt.Errorf("invalid number of arguments")
+	}
+
+	prj, err := getProjectByName(cfg, args[0])
+	if err != nil {
+		return err
+	}
+	startsAt, err := time.Parse(bookingTimeLayout, args[3])
+	if err != nil {
+		return err
+	}
+	duration, err := time.ParseDuration(args[4])
+	if err != nil {
+		return err
+	}
+	endsAt := startsAt.Add(duration)
+	title := args[5]
+
+	episodeID := ""
+	if args[1] != "0" {
+		ep, err := getEpisodeByNumber(cfg, prj.Title, args[1])
+		if err != nil {
+			return err
+		}
+		episodeID = ep.ID.String()
+	}
+
+	room, err := getRoomByName(cfg, args[2])
+	if err != nil {
+		return err
+	}
+
+	// We convert the usernames in the arguments into a list of IDs
+	users := []string{}
+	for _, username := range args[6:] {
+		userID, err := getUserID(cfg, username)
+		if err != nil {
+			return err
+		}
+		users = append(users, userID)
+	}
+
+	createBookingReq := struct {
+		Title     string `json:"title"`
+		StartsAt  string `json:"starts_at"`
+		EndsAt    string `json:"ends_at"`
+		RoomID    string `json:"room_id"`
+		ProjectID string `json:"project_id"`
+		EpisodeID string `json:"episode_id"`
+	}{
+		Title:     title,
+		StartsAt:  startsAt.Format(bookingTimeLayout),
+		EndsAt:    endsAt.Format(bookingTimeLayout),
+		RoomID:    room.ID.String(),
+		ProjectID: prj.ID.String(),
+		EpisodeID: episodeID,
+	}
+
+	url := fmt.Sprintf("%s/api/bookings", cfg.serverAddress)
+	resp, err := sendRequest(createBookingReq, "POST", url, cfg.jwt)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		return processErrorResponse(resp)
+	}
+
+	booking := db.Booking{}
+	err = processResponse(resp, &booking)
+	if err != nil {
+		return err
+	}
+
+	if len(users) > 0 {
+		reqUsersBody := struct {
+			UserIDs []string `json:"user_ids"`
+		}{
+			UserIDs: users,
+		}
+		url = fmt.Sprintf("%s/api/bookings/%s", cfg.serverAddress, booking.ID.String())
+		resp2, err := sendRequest(reqUsersBody, "POST", url, cfg.jwt)
+		if err != nil {
+			return err
+		}
+		defer resp2.Body.Close()
+		if resp2.StatusCode != http.StatusAccepted {
+			return processErrorResponse(resp2)
+		}
+	}
+
+	fmt.Printf("Booking %s in %s on %s created successfully\n", booking.Title, room.RoomName, startsAt.Format(bookingTimeLayout))
+	return nil
+}
+
+func commandGetBookings(cfg *config, args []string) error {
+	// Lists bookings between two dates. Without arguments lists the next four weeks
+	reqBody := struct {
+		From string `json:"from"`
+		To   string `json:"to"`
+	}{}
+	if len(args) >= 1 {
+		reqBody.From = args[0]
+	}
+	if len(args) >= 2 {
+		reqBody.To = args[1]
+	}
+
+	list, err := getThing(cfg, "/api/bookings", reqBody, []db.Booking{})
+	if err != nil {
+		return err
+	}
+
+	for _, item := range list {
+		fmt.Printf("%s - %s: %s\n", item.StartsAt.Format(bookingTimeLayout), item.EndsAt.Format("15:04"), item.Title)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+)
+
+func commandCreateCalendarFeed(cfg *config, args []string) error {
+	// Creates a calendar feed URL that can be subscribed to in calendar applications
+	// Takes the scope (user, room or project) and the name of the user, room or project
+	// Without a name, a user feed is created for the logged in user
+	if len(args) < 1 {
+		return fmt.Errorf("invalid number of arguments")
+	}
+
+	reqBody := struct {
+		Scope    string `json:"scope"`
+		TargetID string `json:"target_id"`
+	}{
+		Scope: args[0],
+	}
+
+	if len(args) >= 2 {
+		switch args[0] {
+		case "user":
+			userID, err := getUserID(cfg, args[1])
+			if err != nil {
+				return err
+			}
+			reqBody.TargetID = userID
+		case "room":
+			room, err := getRoomByName(cfg, args[1])
+			if err != nil {
+				return err
+			}
+			reqBody.TargetID = room.ID.String()
+		case "project":
+			prj, err := getProjectByName(cfg, args[1])
+			if err != nil {
+				return err
+			}
+			reqBody.TargetID = prj.ID.String()
+		default:
+			return fmt.Errorf("unknown feed scope %s", args[0])
+		}
+	}
+
+	url := fmt.Sprintf("%s/api/calendar-feeds", cfg.serverAddress)
+	resp, err := sendRequest(reqBody, "POST", url, cfg.jwt)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		return processErrorResponse(resp)
+	}
+
+	feed := struct {
+		Path string `json:"path"`
+	}{}
+	err = processResponse(resp, &feed)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Subscribe to this URL in your calendar:\n%s%s\n", cfg.serverAddress, feed.Path)
+	return nil
+}
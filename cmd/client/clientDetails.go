@@ -0,0 +1,335 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Denisowiec/FoleyBookkeeper/internal/db"
+)
+
+type clientDetails struct {
+	db.Client
+	Contacts  []db.ClientContact `json:"contacts"`
+	Addresses []db.ClientAddress `json:"addresses"`
+}
+
+func getClientDetails(cfg *config, name string) (clientDetails, error) {
+	client, err := getClientByName(cfg, name)
+	if err != nil {
+		return clientDetails{}, err
+	}
+
+	url := fmt.Sprintf("%s/api/clients/%s", cfg.serverAddress, client.ID)
+	resp, err := sendEmptyRequest("GET", url, cfg.jwt)
+	if err != nil {
+		return clientDetails{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return clientDetails{}, processErrorResponse(resp)
+	}
+
+	details := clientDetails{}
+	err = processResponse(resp, &details)
+	return details, err
+}
+
+func (d clientDetails) contactByName(name string) (db.ClientContact, error) {
+	for _, c := range d.Contacts {
+		if strings.EqualFold(c.Name, name) {
+			return c, nil
+		}
+	}
+	return db.ClientContact{}, fmt.Errorf("contact %s not found for %s", name, d.ClientName)
+}
+
+func commandSetClientBilling(cfg *config, args []string) error {
+	// Takes client name and any of tax_id=, currency=, payment_terms= and language=
+	// Fields left out keep their values, empty ones are cleared
+	if len(args) < 2 {
+		return fmt.Errorf("invalid number of arguments")
+	}
+
+	client, err := getClientByName(cfg, args[0])
+	if err != nil {
+		return err
+	}
+
+	reqBody := struct {
+		TaxID            *string `json:"tax_id,omitempty"`
+		DefaultCurrency  *string `json:"default_currency,omitempty"`
+		PaymentTermsDays *int32  `json:"payment_terms_days,omitempty"`
+		InvoiceLanguage  *string `json:"invoice_language,omitempty"`
+	}{}
+	for _, arg := range args[1:] {
+		key, value, found := strings.Cut(arg, "=")
+		if !found {
+			return fmt.Errorf("expected key=value, got %s", arg)
+		}
+		switch key {
+		case "tax_id":
+			reqBody.TaxID = &value
+		case "currency":
+			reqBody.DefaultCurrency = &value
+		case "payment_terms":
+			var days int32
+			_, err := fmt.Sscanf(value, "%d", &days)
+			if err != nil {
+				return fmt.Errorf("invalid payment terms %s", value)
+			}
+			reqBody.PaymentTermsDays = &days
+		case "language":
+			reqBody.InvoiceLanguage = &value
+		default:
+			return fmt.Errorf("unknown field %s", key)
+		}
+	}
+
+	url := fmt.Sprintf("%s/api/clients/%s/billing", cfg.serverAddress, client.ID)
+	resp, err := sendRequest(reqBody, "PUT", url, cfg.jwt)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		return processErrorResponse(resp)
+	}
+
+	fmt.Printf("Billing details of %s updated successfully\n", client.ClientName)
+	return nil
+}
+
+func commandSetClientAddress(cfg *config, args []string) error {
+	// Takes client name, address kind (billing or postal) and
+	// line1=, line2=, postal_code=, city= and country=
+	if len(args) < 3 {
+		return fmt.Errorf("invalid number of arguments")
+	}
+
+	client, err := getClientByName(cfg, args[0])
+	if err != nil {
+		return err
+	}
+
+	reqBody := map[string]string{}
+	for _, arg := range args[2:] {
+		key, value, found := strings.Cut(arg, "=")
+		if !found {
+			return fmt.Errorf("expected key=value, got %s", arg)
+		}
+		switch key {
+		case "line1", "line2", "postal_code", "city", "country":
+			reqBody[key] = value
+		default:
+			return fmt.Errorf("unknown field %s", key)
+		}
+	}
+
+	url := fmt.Sprintf("%s/api/clients/%s/addresses/%s", cfg.serverAddress, client.ID, args[1])
+	resp, err := sendRequest(reqBody, "PUT", url, cfg.jwt)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		return processErrorResponse(resp)
+	}
+
+	fmt.Printf("%s address of %s set successfully\n", args[1], client.ClientName)
+	return nil
+}
+
+func commandRemoveClientAddress(cfg *config, args []string) error {
+	// Takes client name and address kind (billing or postal)
+	if len(args) < 2 {
+		return fmt.Errorf("invalid number of arguments")
+	}
+
+	client, err := getClientByName(cfg, args[0])
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/api/clients/%s/addresses/%s", cfg.serverAddress, client.ID, args[1])
+	resp, err := sendEmptyRequest("DELETE", url, cfg.jwt)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		return processErrorResponse(resp)
+	}
+
+	fmt.Printf("%s address of %s removed successfully\n", args[1], client.ClientName)
+	return nil
+}
+
+type contactReqType struct {
+	Name  string `json:"name"`
+	Role  string `json:"role"`
+	Email string `json:"email"`
+	Phone string `json:"phone"`
+}
+
+func (c *contactReqType) apply(args []string) error {
+	for _, arg := range args {
+		key, value, found := strings.Cut(arg, "=")
+		if !found {
+			return fmt.Errorf("expected key=value, got %s", arg)
+		}
+		switch key {
+		case "name":
+			c.Name = value
+		case "role":
+			c.Role = value
+		case "email":
+			c.Email = value
+		case "phone":
+			c.Phone = value
+		default:
+			return fmt.Errorf("unknown field %s", key)
+		}
+	}
+	return nil
+}
+
+func commandAddContact(cfg *config, args []string) error {
+	// Takes client name, contact name and optionally role=, email= and phone=
+	if len(args) < 2 {
+		return fmt.Errorf("invalid number of arguments")
+	}
+
+	client, err := getClientByName(cfg, args[0])
+	if err != nil {
+		return err
+	}
+
+	reqBody := contactReqType{Name: args[1]}
+	err = reqBody.apply(args[2:])
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/api/clients/%s/contacts", cfg.serverAddress, client.ID)
+	resp, err := sendRequest(reqBody, "POST", url, cfg.jwt)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return processErrorResponse(resp)
+	}
+
+	fmt.Printf("Contact %s added to %s\n", reqBody.Name, client.ClientName)
+	return nil
+}
+
+func commandUpdateContact(cfg *config, args []string) error {
+	// Takes client name, contact name and any of name=, role=, email= and phone=
+	// Fields left out keep their values
+	if len(args) < 3 {
+		return fmt.Errorf("invalid number of arguments")
+	}
+
+	details, err := getClientDetails(cfg, args[0])
+	if err != nil {
+		return err
+	}
+	contact, err := details.contactByName(args[1])
+	if err != nil {
+		return err
+	}
+
+	reqBody := contactReqType{
+		Name:  contact.Name,
+		Role:  contact.Role.String,
+		Email: contact.Email.String,
+		Phone: contact.Phone.String,
+	}
+	err = reqBody.apply(args[2:])
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/api/contacts/%s", cfg.serverAddress, contact.ID)
+	resp, err := sendRequest(reqBody, "PUT", url, cfg.jwt)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		return processErrorResponse(resp)
+	}
+
+	fmt.Printf("Contact %s updated successfully\n", reqBody.Name)
+	return nil
+}
+
+func commandRemoveContact(cfg *config, args []string) error {
+	// Takes client name and contact name
+	if len(args) < 2 {
+		return fmt.Errorf("invalid number of arguments")
+	}
+
+	details, err := getClientDetails(cfg, args[0])
+	if err != nil {
+		return err
+	}
+	contact, err := details.contactByName(args[1])
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/api/contacts/%s", cfg.serverAddress, contact.ID)
+	resp, err := sendEmptyRequest("DELETE", url, cfg.jwt)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		return processErrorResponse(resp)
+	}
+
+	fmt.Printf("Contact %s removed from %s\n", contact.Name, details.ClientName)
+	return nil
+}
+
+func printContact(c db.ClientContact) {
+	fmt.Printf("  %s", c.Name)
+	if c.Role.Valid {
+		fmt.Printf(" (%s)", c.Role.String)
+	}
+	if c.Email.Valid {
+		fmt.Printf(", email: %s", c.Email.String)
+	}
+	if c.Phone.Valid {
+		fmt.Printf(", phone: %s", c.Phone.String)
+	}
+	fmt.Println()
+}
+
+func commandListContacts(cfg *config, args []string) error {
+	// Takes client name
+	if len(args) < 1 {
+		return fmt.Errorf("invalid number of arguments")
+	}
+
+	details, err := getClientDetails(cfg, args[0])
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Contacts of %s:\n", details.ClientName)
+	for _, c := range details.Contacts {
+		printContact(c)
+	}
+	return nil
+}
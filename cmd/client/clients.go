@@ -56,9 +56,10 @@ func commandCreateClient(cfg *config, args []string) error {
 	reqBody := reqBodyType{}
 	reqBody.ClientName = args[0]
 
-	if len(args) == 2 {
+	if len(args) >= 2 {
 		reqBody.Email = args[1]
-	} else if len(args) >= 3 {
+	}
+	if len(args) >= 3 {
 		reqBody.Notes = args[2]
 	}
 
@@ -146,13 +147,31 @@ func commandGetClient(cfg *config, args []string) error {
 		return fmt.Errorf("invalid number of arguments")
 	}
 
-	client, err := getClientByName(cfg, args[0])
+	client, err := getClientDetails(cfg, args[0])
 	if err != nil {
 		return err
 	}
 
 	fmt.Printf("Name: %s\nID: %s\nCreated at: %v\nUpdated at: %v\nEmail: %s\nNotes: %s\n",
 		client.ClientName, client.ID.String(), client.CreatedAt, client.UpdatedAt, client.Email.String, client.Notes.String)
+	fmt.Printf("Tax ID: %s\nDefault currency: %s\nInvoice language: %s\n",
+		client.TaxID.String, client.DefaultCurrency.String, client.InvoiceLanguage.String)
+	if client.PaymentTermsDays.Valid {
+		fmt.Printf("Payment terms: %d days\n", client.PaymentTermsDays.Int32)
+	}
+	for _, a := range client.Addresses {
+		fmt.Printf("%s address: %s", a.Kind, a.Line1)
+		if a.Line2.Valid {
+			fmt.Printf(", %s", a.Line2.String)
+		}
+		fmt.Printf(", %s %s, %s\n", a.PostalCode, a.City, a.Country)
+	}
+	if len(client.Contacts) > 0 {
+		fmt.Println("Contacts:")
+		for _, c := range client.Contacts {
+			printContact(c)
+		}
+	}
 
 	return nil
 }
@@ -181,3 +200,104 @@ func commandGetAllClients(cfg *config, args []string) error {
 
 	return nil
 }
+
+func commandDeleteClient(cfg *config, args []string) error {
+	// Takes client name and optionally "force", which also deletes the
+	// client's projects with all their episodes, sessions and calculations
+	if len(args) < 1 {
+		return fmt.Errorf("invalid number of arguments")
+	}
+
+	client, err := getClientByName(cfg, args[0])
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/api/clients/%s", cfg.serverAddress, client.ID)
+	if len(args) >= 2 {
+		if args[1] != "force" {
+			return fmt.Errorf("unknown option %s", args[1])
+		}
+		if !askConfirmation(cfg, fmt.Sprintf("Delete %s with all its projects and everything logged for them?", client.ClientName)) {
+			return nil
+		}
+		url += "?force=true"
+	}
+
+	resp, err := sendEmptyRequest("DELETE", url, cfg.jwt)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		return processErrorResponse(resp)
+	}
+
+	fmt.Printf("Client %s moved to the trash\n", client.ClientName)
+	return nil
+}
+
+func commandMergeClient(cfg *config, args []string) error {
+	// Takes the name of the duplicate client and of the client to merge it into
+	if len(args) < 2 {
+		return fmt.Errorf("invalid number of arguments")
+	}
+
+	source, err := getClientByName(cfg, args[0])
+	if err != nil {
+		return err
+	}
+	target, err := getClientByName(cfg, args[1])
+	if err != nil {
+		return err
+	}
+	if !askConfirmation(cfg, fmt.Sprintf("Move everything of %s to %s and delete %s?", source.ClientName, target.ClientName, source.ClientName)) {
+		return nil
+	}
+
+	reqBody := struct {
+		TargetID string `json:"target_id"`
+	}{
+		TargetID: target.ID.String(),
+	}
+	url := fmt.Sprintf("%s/api/clients/%s/merge", cfg.serverAddress, source.ID)
+	resp, err := sendRequest(reqBody, "POST", url, cfg.jwt)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		return processErrorResponse(resp)
+	}
+
+	report := struct {
+		Moved struct {
+			Episodes     int64 `json:"episodes"`
+			Sessions     int64 `json:"sessions"`
+			Calculations int64 `json:"calculations"`
+			Bookings     int64 `json:"bookings"`
+		} `json:"moved"`
+		Projects  []db.Project       `json:"projects"`
+		Contacts  []db.ClientContact `json:"contacts"`
+		Addresses []db.ClientAddress `json:"addresses"`
+	}{}
+	err = processResponse(resp, &report)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s merged into %s\n", source.ClientName, target.ClientName)
+	for _, p := range report.Projects {
+		if p.DeletedAt.Valid {
+			fmt.Printf("  moved project %s (in the trash)\n", p.Title)
+			continue
+		}
+		fmt.Printf("  moved project %s\n", p.Title)
+	}
+	fmt.Printf("  %d episodes, %d sessions, %d calculations and %d bookings came along\n",
+		report.Moved.Episodes, report.Moved.Sessions, report.Moved.Calculations, report.Moved.Bookings)
+	fmt.Printf("  moved %d contacts and %d addresses\n", len(report.Contacts), len(report.Addresses))
+	return nil
+}
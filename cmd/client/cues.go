@@ -0,0 +1,167 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type cueType struct {
+	ID           string `json:"id"`
+	CueNumber    string `json:"cue_number"`
+	Reel         string `json:"reel"`
+	TimecodeIn   string `json:"timecode_in"`
+	TimecodeOut  string `json:"timecode_out"`
+	Duration     string `json:"duration"`
+	FrameRate    string `json:"frame_rate"`
+	Description  string `json:"description"`
+	Part         string `json:"part"`
+	Status       string `json:"status"`
+	SessionCount int64  `json:"session_count"`
+	Minutes      int64  `json:"minutes"`
+}
+
+func commandCreateCue(cfg *config, args []string) error {
+	// Takes project title, episode number, cue number, timecode in, timecode out,
+	// frame rate, and optionally the part and a description
+	if len(args) < 6 {
+		return fmt.Errorf("invalid number of arguments")
+	}
+
+	ep, err := getEpisodeByNumber(cfg, args[0], args[1])
+	if err != nil {
+		return err
+	}
+
+	reqBody := struct {
+		CueNumber   string `json:"cue_number"`
+		TimecodeIn  string `json:"timecode_in"`
+		TimecodeOut string `json:"timecode_out"`
+		FrameRate   string `json:"frame_rate"`
+		Part        string `json:"part"`
+		Description string `json:"description"`
+	}{
+		CueNumber:   args[2],
+		TimecodeIn:  args[3],
+		TimecodeOut: args[4],
+		FrameRate:   args[5],
+	}
+	if len(args) >= 7 {
+		reqBody.Part = args[6]
+	}
+	if len(args) >= 8 {
+		reqBody.Description = strings.Join(args[7:], " ")
+	}
+
+	url := fmt.Sprintf("%s/api/episodes/%s/cues", cfg.serverAddress, ep.ID)
+	resp, err := sendRequest(reqBody, "POST", url, cfg.jwt)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return processErrorResponse(resp)
+	}
+
+	cue := cueType{}
+	err = processResponse(resp, &cue)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Cue %s (%s - %s) created successfully\n", cue.CueNumber, cue.TimecodeIn, cue.TimecodeOut)
+	return nil
+}
+
+func commandGetCues(cfg *config, args []string) error {
+	// Lists the cues of an episode with their progress
+	// Takes project title and episode number
+	if len(args) < 2 {
+		return fmt.Errorf("invalid number of arguments")
+	}
+
+	ep, err := getEpisodeByNumber(cfg, args[0], args[1])
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/api/episodes/%s/cues", cfg.serverAddress, ep.ID)
+	resp, err := sendEmptyRequest("GET", url, cfg.jwt)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return processErrorResponse(resp)
+	}
+
+	var list []cueType
+	err = processResponse(resp, &list)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range list {
+		fmt.Printf("%-8s %s - %s  %-10s %-9s %3d min  %s\n", c.CueNumber, c.TimecodeIn, c.TimecodeOut, c.Part, c.Status, c.Minutes, c.Description)
+	}
+	return nil
+}
+
+func commandCueSheet(cfg *config, args []string) error {
+	// Saves the cue sheet of an episode to a file.
+	// Takes project title, episode number and the file path. The format
+	// follows the file extension unless given, options are given as
+	// key=value, e.g. sort=number group=part reel=R2 format=pdf
+	options := url.Values{}
+	positional := []string{}
+	for _, arg := range args {
+		if key, value, found := strings.Cut(arg, "="); found {
+			options.Set(key, value)
+			continue
+		}
+		positional = append(positional, arg)
+	}
+
+	if len(positional) < 3 {
+		return fmt.Errorf("invalid number of arguments")
+	}
+
+	ep, err := getEpisodeByNumber(cfg, positional[0], positional[1])
+	if err != nil {
+		return err
+	}
+	path := positional[2]
+	if options.Get("format") == "" {
+		options.Set("format", strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), "."))
+	}
+
+	url := fmt.Sprintf("%s/api/episodes/%s/cue-sheet?%s", cfg.serverAddress, ep.ID, options.Encode())
+	resp, err := sendEmptyRequest("GET", url, cfg.jwt)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return processErrorResponse(resp)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = io.Copy(file, resp.Body)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Cue sheet saved to %s\n", path)
+	return nil
+}
@@ -3,14 +3,41 @@ package main
 import (
 	"fmt"
 	"net/http"
+	"os"
 	"strconv"
+	"strings"
 
 	"github.com/Denisowiec/FoleyBookkeeper/internal/db"
+	"github.com/Denisowiec/FoleyBookkeeper/internal/episodecode"
 )
 
+func getEpisodeByNumber(cfg *config, projectTitle string, episodeNumber string) (db.Episode, error) {
+	// A helper function for commands that take a project title and an episode number
+	// or a code like S02E05
+	code, err := episodecode.Parse(episodeNumber)
+	if err != nil {
+		return db.Episode{}, err
+	}
+
+	prj, err := getProjectByName(cfg, projectTitle)
+	if err != nil {
+		return db.Episode{}, err
+	}
+
+	getEpReq := struct {
+		ProjectID string `json:"project_id"`
+		Code      string `json:"code"`
+	}{
+		ProjectID: prj.ID.String(),
+		Code:      code.String(),
+	}
+
+	return getThing(cfg, "/api/episodes", getEpReq, db.Episode{})
+}
+
 func commandCreateEpisode(cfg *config, args []string) error {
 	// This command creates a new episodes
-	// Takes project title, episode number and title as arguments
+	// Takes project title, episode number or code like S02E05 and title as arguments
 	if len(args) < 1 {
 		return fmt.Errorf("invalid number of arguments")
 	}
@@ -22,10 +49,10 @@ func commandCreateEpisode(cfg *config, args []string) error {
 
 	projectID := prj.ID.String()
 	var title string
-	var epNumber int
+	var code episodecode.Code
 
 	if len(args) >= 2 {
-		epNumber, err = strconv.Atoi(args[1])
+		code, err = episodecode.Parse(args[1])
 		if err != nil {
 			return err
 		}
@@ -37,14 +64,16 @@ func commandCreateEpisode(cfg *config, args []string) error {
 	url := fmt.Sprintf("%s/api/episodes", cfg.serverAddress)
 
 	type createEpisodeReqType struct {
-		Title         string `json:"title"`
-		ProjectID     string `json:"project_id"`
-		EpisodeNumber int    `json:"episode_number"`
+		Title     string `json:"title"`
+		ProjectID string `json:"project_id"`
+		Code      string `json:"code,omitempty"`
 	}
 	createEpisodeReq := createEpisodeReqType{
-		Title:         title,
-		EpisodeNumber: epNumber,
-		ProjectID:     projectID,
+		Title:     title,
+		ProjectID: projectID,
+	}
+	if code.Episode > 0 {
+		createEpisodeReq.Code = code.String()
 	}
 
 	resp, err := sendRequest(createEpisodeReq, "POST", url, cfg.jwt)
@@ -63,8 +92,8 @@ func commandCreateEpisode(cfg *config, args []string) error {
 		return err
 	}
 
-	if epNumber > 0 {
-		fmt.Printf("Episode for project %s created successfully\n", prj.Title)
+	if code.Episode > 0 {
+		fmt.Printf("Episode %s for project %s created successfully\n", code, prj.Title)
 	} else {
 		fmt.Printf("Episode %d for project %s created successfully\n", ep.EpisodeNumber, prj.Title)
 	}
@@ -103,9 +132,253 @@ func commandGetEpisodesForProject(cfg *config, args []string) error {
 	if err != nil {
 		return err
 	}
+	seasons, err := getSeasonNumbers(cfg, prj)
+	if err != nil {
+		return err
+	}
 	fmt.Println("Episodes of project", prj.Title)
 	for _, e := range eps {
-		fmt.Printf("Title: %s, Number: %d\n", e.Title.String, e.EpisodeNumber)
+		code := episodecode.Code{Season: seasons[e.SeasonID.UUID], Episode: int(e.EpisodeNumber)}
+		fmt.Printf("Title: %s, Number: %s\n", e.Title.String, code)
+	}
+	return nil
+}
+
+func parseEpisodeRange(input string) (season, from, to int, err error) {
+	// Takes ranges like 1-52 or S02E01-S02E26, a single episode is a range too
+	first, last, found := strings.Cut(input, "-")
+	if !found {
+		last = first
+	}
+	fromCode, err := episodecode.Parse(first)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	toCode, err := episodecode.Parse(last)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	if toCode.Season != 0 && toCode.Season != fromCode.Season {
+		return 0, 0, 0, fmt.Errorf("episode ranges can't span seasons")
+	}
+	if toCode.Episode < fromCode.Episode {
+		return 0, 0, 0, fmt.Errorf("invalid range %s", input)
+	}
+	return fromCode.Season, fromCode.Episode, toCode.Episode, nil
+}
+
+func commandCreateEpisodes(cfg *config, args []string) error {
+	// Creates a range of episodes at once
+	// Takes project title, the range, e.g. 1-52 or S02E01-S02E26, and a title
+	// pattern like "Episode {nn}". Titles can also come from a CSV file given as
+	// csv=<file>, runtime=, picture_lock= and frame_rate= apply to all episodes
+	options := map[string]string{}
+	positional := []string{}
+	for _, arg := range args {
+		if key, value, found := strings.Cut(arg, "="); found {
+			options[key] = value
+			continue
+		}
+		positional = append(positional, arg)
+	}
+	if len(positional) < 2 {
+		return fmt.Errorf("invalid number of arguments")
+	}
+
+	prj, err := getProjectByName(cfg, positional[0])
+	if err != nil {
+		return err
+	}
+	season, from, to, err := parseEpisodeRange(positional[1])
+	if err != nil {
+		return err
+	}
+
+	reqBody := struct {
+		Season       string  `json:"season,omitempty"`
+		From         int     `json:"from"`
+		To           int     `json:"to"`
+		TitlePattern string  `json:"title_pattern"`
+		TitlesCSV    string  `json:"titles_csv"`
+		Runtime      *string `json:"runtime,omitempty"`
+		PictureLock  *string `json:"picture_lock,omitempty"`
+		FrameRate    *string `json:"frame_rate,omitempty"`
+	}{
+		From: from,
+		To:   to,
+	}
+	if season != 0 {
+		reqBody.Season = strconv.Itoa(season)
+	}
+	if len(positional) >= 3 {
+		reqBody.TitlePattern = positional[2]
+	}
+	for key, value := range options {
+		switch key {
+		case "csv":
+			dat, err := os.ReadFile(value)
+			if err != nil {
+				return err
+			}
+			reqBody.TitlesCSV = string(dat)
+		case "runtime":
+			reqBody.Runtime = &value
+		case "picture_lock":
+			reqBody.PictureLock = &value
+		case "frame_rate":
+			reqBody.FrameRate = &value
+		default:
+			return fmt.Errorf("unknown option %s", key)
+		}
+	}
+
+	url := fmt.Sprintf("%s/api/projects/%s/episodes", cfg.serverAddress, prj.ID)
+	resp, err := sendRequest(reqBody, "POST", url, cfg.jwt)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return processErrorResponse(resp)
+	}
+
+	var eps []db.Episode
+	err = processResponse(resp, &eps)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%d episodes of %s created successfully\n", len(eps), prj.Title)
+	return nil
+}
+
+func commandSetEpisodeInfo(cfg *config, args []string) error {
+	// Sets the runtime, picture lock date and frame rate of an episode
+	// Takes project title, episode number and any of runtime=, picture_lock=
+	// and frame_rate=. Empty values clear them
+	if len(args) < 3 {
+		return fmt.Errorf("invalid number of arguments")
+	}
+
+	ep, err := getEpisodeByNumber(cfg, args[0], args[1])
+	if err != nil {
+		return err
+	}
+
+	// Updates replace the episode's title, number and season, so we send
+	// the current ones along
+	reqBody := struct {
+		Title         string  `json:"title"`
+		EpisodeNumber int32   `json:"episode_number"`
+		ProjectID     string  `json:"project_id"`
+		SeasonID      string  `json:"season_id"`
+		Runtime       *string `json:"runtime,omitempty"`
+		PictureLock   *string `json:"picture_lock,omitempty"`
+		FrameRate     *string `json:"frame_rate,omitempty"`
+	}{
+		Title:         ep.Title.String,
+		EpisodeNumber: ep.EpisodeNumber,
+		ProjectID:     ep.ProjectID.String(),
+	}
+	if ep.SeasonID.Valid {
+		reqBody.SeasonID = ep.SeasonID.UUID.String()
+	}
+	for _, arg := range args[2:] {
+		key, value, found := strings.Cut(arg, "=")
+		if !found {
+			return fmt.Errorf("expected key=value, got %s", arg)
+		}
+		switch key {
+		case "runtime":
+			reqBody.Runtime = &value
+		case "picture_lock":
+			reqBody.PictureLock = &value
+		case "frame_rate":
+			reqBody.FrameRate = &value
+		default:
+			return fmt.Errorf("unknown field %s", key)
+		}
+	}
+
+	url := fmt.Sprintf("%s/api/episodes/%s", cfg.serverAddress, ep.ID)
+	resp, err := sendRequest(reqBody, "PUT", url, cfg.jwt)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		return processErrorResponse(resp)
+	}
+
+	fmt.Printf("Episode %s of %s updated successfully\n", strings.ToUpper(args[1]), args[0])
+	return nil
+}
+
+func commandRuntimeReport(cfg *config, args []string) error {
+	// Shows the minutes logged for the episodes of a project per minute of runtime
+	// Takes project title
+	if len(args) < 1 {
+		return fmt.Errorf("invalid number of arguments")
+	}
+
+	prj, err := getProjectByName(cfg, args[0])
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/api/projects/%s/runtime-report", cfg.serverAddress, prj.ID)
+	resp, err := sendEmptyRequest("GET", url, cfg.jwt)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return processErrorResponse(resp)
+	}
+
+	report := struct {
+		Episodes []struct {
+			Code           string   `json:"code"`
+			Title          string   `json:"title"`
+			RuntimeSeconds *int32   `json:"runtime_seconds"`
+			PictureLock    string   `json:"picture_lock"`
+			Minutes        int64    `json:"minutes"`
+			PerRuntimeMin  *float64 `json:"minutes_per_runtime_minute"`
+		} `json:"episodes"`
+		RuntimeSeconds int32    `json:"runtime_seconds"`
+		Minutes        int64    `json:"minutes"`
+		PerRuntimeMin  *float64 `json:"minutes_per_runtime_minute"`
+	}{}
+	err = processResponse(resp, &report)
+	if err != nil {
+		return err
+	}
+
+	formatRuntime := func(seconds int32) string {
+		return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+	}
+	formatRatio := func(ratio *float64) string {
+		if ratio == nil {
+			return "-"
+		}
+		return fmt.Sprintf("%.2f", *ratio)
+	}
+
+	fmt.Printf("%-7s %-8s %-11s %8s %9s  %s\n", "Ep", "Runtime", "Lock", "Minutes", "Per min", "Title")
+	for _, ep := range report.Episodes {
+		runtime := "-"
+		if ep.RuntimeSeconds != nil {
+			runtime = formatRuntime(*ep.RuntimeSeconds)
+		}
+		lock := ep.PictureLock
+		if lock == "" {
+			lock = "-"
+		}
+		fmt.Printf("%-7s %-8s %-11s %8d %9s  %s\n", ep.Code, runtime, lock, ep.Minutes, formatRatio(ep.PerRuntimeMin), ep.Title)
 	}
+	fmt.Printf("Episodes with a runtime: %s of runtime, %d minutes logged, %s per minute of runtime\n",
+		formatRuntime(report.RuntimeSeconds), report.Minutes, formatRatio(report.PerRuntimeMin))
 	return nil
 }
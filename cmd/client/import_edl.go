@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+)
+
+func commandImportEDL(cfg *config, args []string) error {
+	// Imports the cues of an episode from a CMX3600 EDL.
+	// Takes the file path, project title and episode number, optionally
+	// the frame rate (- to use the one in the file), whether cues come
+	// from events or markers, and the reel the cues belong to
+	if len(args) < 3 {
+		return fmt.Errorf("invalid number of arguments")
+	}
+
+	dat, err := os.ReadFile(args[0])
+	if err != nil {
+		return err
+	}
+	ep, err := getEpisodeByNumber(cfg, args[1], args[2])
+	if err != nil {
+		return err
+	}
+
+	type importReqType struct {
+		EDL       string `json:"edl"`
+		EpisodeID string `json:"episode_id"`
+		FrameRate string `json:"frame_rate"`
+		CuesFrom  string `json:"cues_from"`
+		Reel      string `json:"reel"`
+		Confirm   bool   `json:"confirm"`
+	}
+	importReq := importReqType{
+		EDL:       string(dat),
+		EpisodeID: ep.ID.String(),
+		CuesFrom:  "events",
+	}
+	if len(args) >= 4 && args[3] != "-" {
+		importReq.FrameRate = args[3]
+	}
+	if len(args) >= 5 {
+		importReq.CuesFrom = args[4]
+	}
+	if len(args) >= 6 {
+		importReq.Reel = args[5]
+	}
+
+	type importItem struct {
+		CueNumber   string `json:"cue_number"`
+		TimecodeIn  string `json:"timecode_in"`
+		TimecodeOut string `json:"timecode_out"`
+		Description string `json:"description"`
+		Action      string `json:"action"`
+		Reason      string `json:"reason"`
+	}
+	type unparsedLine struct {
+		Line   int    `json:"line"`
+		Text   string `json:"text"`
+		Reason string `json:"reason"`
+	}
+	type importRespType struct {
+		Confirmed bool           `json:"confirmed"`
+		Title     string         `json:"title"`
+		FrameRate string         `json:"frame_rate"`
+		Items     []importItem   `json:"items"`
+		Unparsed  []unparsedLine `json:"unparsed"`
+	}
+
+	url := fmt.Sprintf("%s/api/imports/edl", cfg.serverAddress)
+
+	// First we only ask for a preview
+	resp, err := sendRequest(importReq, "POST", url, cfg.jwt)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return processErrorResponse(resp)
+	}
+
+	preview := importRespType{}
+	err = processResponse(resp, &preview)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s at %s fps\n", preview.Title, preview.FrameRate)
+	toImport := 0
+	for _, item := range preview.Items {
+		fmt.Printf("%-6s %-6s %s - %s %s", item.Action, item.CueNumber, item.TimecodeIn, item.TimecodeOut, item.Description)
+		if item.Action == "skip" {
+			fmt.Printf(" (%s)\n", item.Reason)
+			continue
+		}
+		toImport++
+		fmt.Println()
+	}
+	if len(preview.Unparsed) > 0 {
+		fmt.Println("Lines that couldn't be parsed:")
+		for _, l := range preview.Unparsed {
+			fmt.Printf("%5d: %s (%s)\n", l.Line, l.Text, l.Reason)
+		}
+	}
+
+	if toImport == 0 {
+		fmt.Println("Nothing to import.")
+		return nil
+	}
+	if !askConfirmation(cfg, fmt.Sprintf("Import %d cues?", toImport)) {
+		fmt.Println("Import cancelled.")
+		return nil
+	}
+
+	importReq.Confirm = true
+	resp2, err := sendRequest(importReq, "POST", url, cfg.jwt)
+	if err != nil {
+		return err
+	}
+	defer resp2.Body.Close()
+	if resp2.StatusCode != http.StatusCreated {
+		return processErrorResponse(resp2)
+	}
+
+	fmt.Printf("Imported %d cues successfully\n", toImport)
+	return nil
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+)
+
+func commandImportICS(cfg *config, args []string) error {
+	// Imports an .ics file exported from a calendar application.
+	// Takes the file path, what to create (bookings or sessions), optionally
+	// a room for the bookings and a title pattern
+	if len(args) < 1 {
+		return fmt.Errorf("invalid number of arguments")
+	}
+
+	dat, err := os.ReadFile(args[0])
+	if err != nil {
+		return err
+	}
+
+	type importReqType struct {
+		Calendar string   `json:"calendar"`
+		Create   string   `json:"create"`
+		Patterns []string `json:"patterns"`
+		RoomID   string   `json:"room_id"`
+		Confirm  bool     `json:"confirm"`
+	}
+	importReq := importReqType{
+		Calendar: string(dat),
+		Create:   "bookings",
+	}
+	if len(args) >= 2 {
+		importReq.Create = args[1]
+	}
+	if len(args) >= 3 && args[2] != "-" {
+		room, err := getRoomByName(cfg, args[2])
+		if err != nil {
+			return err
+		}
+		importReq.RoomID = room.ID.String()
+	}
+	if len(args) >= 4 {
+		importReq.Patterns = []string{args[3]}
+	}
+
+	type importItem struct {
+		Summary       string             `json:"summary"`
+		StartsAt      time.Time          `json:"starts_at"`
+		EndsAt        time.Time          `json:"ends_at"`
+		ProjectTitle  string             `json:"project_title"`
+		EpisodeNumber int                `json:"episode_number"`
+		EpisodeCode   string             `json:"episode_code"`
+		Part          string             `json:"part"`
+		Action        string             `json:"action"`
+		Reason        string             `json:"reason"`
+		Warnings      []sessionViolation `json:"warnings"`
+	}
+	type importRespType struct {
+		Confirmed bool         `json:"confirmed"`
+		Items     []importItem `json:"items"`
+	}
+
+	url := fmt.Sprintf("%s/api/imports/ics", cfg.serverAddress)
+
+	// First we only ask for a preview
+	resp, err := sendRequest(importReq, "POST", url, cfg.jwt)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return processErrorResponse(resp)
+	}
+
+	preview := importRespType{}
+	err = processResponse(resp, &preview)
+	if err != nil {
+		return err
+	}
+
+	toImport := 0
+	for _, item := range preview.Items {
+		fmt.Printf("%-6s %s - %s %s", item.Action, item.StartsAt.Format("2006-01-02 15:04"), item.EndsAt.Format("15:04"), item.Summary)
+		if item.Action == "skip" {
+			fmt.Printf(" (%s)\n", item.Reason)
+			continue
+		}
+		toImport++
+		fmt.Printf(" -> %s, episode %s %s\n", item.ProjectTitle, item.EpisodeCode, item.Part)
+		printViolations(item.Warnings)
+	}
+
+	if toImport == 0 {
+		fmt.Println("Nothing to import.")
+		return nil
+	}
+	if !askConfirmation(cfg, fmt.Sprintf("Import %d %s?", toImport, importReq.Create)) {
+		fmt.Println("Import cancelled.")
+		return nil
+	}
+
+	importReq.Confirm = true
+	resp2, err := sendRequest(importReq, "POST", url, cfg.jwt)
+	if err != nil {
+		return err
+	}
+	defer resp2.Body.Close()
+	if resp2.StatusCode != http.StatusCreated {
+		return processErrorResponse(resp2)
+	}
+
+	fmt.Printf("Imported %d %s successfully\n", toImport, importReq.Create)
+	return nil
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+)
+
+type trackType struct {
+	Name           string `json:"track_name"`
+	Comments       string `json:"comments"`
+	ClipCount      int32  `json:"clip_count"`
+	MutedClipCount int32  `json:"muted_clip_count"`
+	ClipLength     string `json:"clip_length"`
+}
+
+func printTracks(tracks []trackType) {
+	for _, t := range tracks {
+		fmt.Printf("%-24s %4d clips (%d muted) %s %s\n", t.Name, t.ClipCount, t.MutedClipCount, t.ClipLength, t.Comments)
+	}
+}
+
+func commandImportProTools(cfg *config, args []string) error {
+	// Imports a Pro Tools "Session Info as Text" export of an episode.
+	// Takes the file path, project title and episode number, and optionally
+	// the frame rate for exports that don't name their timecode format
+	if len(args) < 3 {
+		return fmt.Errorf("invalid number of arguments")
+	}
+
+	dat, err := os.ReadFile(args[0])
+	if err != nil {
+		return err
+	}
+	ep, err := getEpisodeByNumber(cfg, args[1], args[2])
+	if err != nil {
+		return err
+	}
+
+	type importReqType struct {
+		SessionInfo string `json:"session_info"`
+		EpisodeID   string `json:"episode_id"`
+		FrameRate   string `json:"frame_rate"`
+		Confirm     bool   `json:"confirm"`
+	}
+	importReq := importReqType{
+		SessionInfo: string(dat),
+		EpisodeID:   ep.ID.String(),
+	}
+	if len(args) >= 4 && args[3] != "-" {
+		importReq.FrameRate = args[3]
+	}
+
+	type importItem struct {
+		CueNumber   string `json:"cue_number"`
+		TimecodeIn  string `json:"timecode_in"`
+		Description string `json:"description"`
+		Action      string `json:"action"`
+		Reason      string `json:"reason"`
+	}
+	type unparsedLine struct {
+		Line   int    `json:"line"`
+		Text   string `json:"text"`
+		Reason string `json:"reason"`
+	}
+	type importRespType struct {
+		Confirmed    bool           `json:"confirmed"`
+		SessionName  string         `json:"session_name"`
+		FrameRate    string         `json:"frame_rate"`
+		OnlineFiles  int            `json:"online_files"`
+		OfflineFiles int            `json:"offline_files"`
+		ClipCount    int            `json:"clip_count"`
+		Items        []importItem   `json:"items"`
+		Tracks       []trackType    `json:"tracks"`
+		Unparsed     []unparsedLine `json:"unparsed"`
+	}
+
+	url := fmt.Sprintf("%s/api/imports/protools", cfg.serverAddress)
+
+	// First we only ask for a preview
+	resp, err := sendRequest(importReq, "POST", url, cfg.jwt)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return processErrorResponse(resp)
+	}
+
+	preview := importRespType{}
+	err = processResponse(resp, &preview)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s at %s fps: %d files online, %d offline, %d clips\n",
+		preview.SessionName, preview.FrameRate, preview.OnlineFiles, preview.OfflineFiles, preview.ClipCount)
+	printTracks(preview.Tracks)
+	fmt.Println("Cues from markers:")
+	toImport := 0
+	for _, item := range preview.Items {
+		fmt.Printf("%-6s %-6s %s %s", item.Action, item.CueNumber, item.TimecodeIn, item.Description)
+		if item.Action == "skip" {
+			fmt.Printf(" (%s)\n", item.Reason)
+			continue
+		}
+		toImport++
+		fmt.Println()
+	}
+	if len(preview.Unparsed) > 0 {
+		fmt.Println("Lines that couldn't be parsed:")
+		for _, l := range preview.Unparsed {
+			fmt.Printf("%5d: %s (%s)\n", l.Line, l.Text, l.Reason)
+		}
+	}
+
+	question := fmt.Sprintf("Import %d cues and %d tracks?", toImport, len(preview.Tracks))
+	if !askConfirmation(cfg, question) {
+		fmt.Println("Import cancelled.")
+		return nil
+	}
+
+	importReq.Confirm = true
+	resp2, err := sendRequest(importReq, "POST", url, cfg.jwt)
+	if err != nil {
+		return err
+	}
+	defer resp2.Body.Close()
+	if resp2.StatusCode != http.StatusCreated {
+		return processErrorResponse(resp2)
+	}
+
+	fmt.Printf("Imported %d cues and %d tracks successfully\n", toImport, len(preview.Tracks))
+	return nil
+}
+
+func commandGetEpisodeTracks(cfg *config, args []string) error {
+	// Lists the tracks of the latest Pro Tools export of an episode
+	// Takes project title and episode number
+	if len(args) < 2 {
+		return fmt.Errorf("invalid number of arguments")
+	}
+
+	ep, err := getEpisodeByNumber(cfg, args[0], args[1])
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/api/episodes/%s/tracks", cfg.serverAddress, ep.ID)
+	resp, err := sendEmptyRequest("GET", url, cfg.jwt)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return processErrorResponse(resp)
+	}
+
+	tracksResp := struct {
+		SessionName string      `json:"session_name"`
+		ImportedAt  *time.Time  `json:"imported_at"`
+		Tracks      []trackType `json:"tracks"`
+	}{}
+	err = processResponse(resp, &tracksResp)
+	if err != nil {
+		return err
+	}
+
+	if tracksResp.ImportedAt == nil {
+		fmt.Println("No Pro Tools session imported for this episode.")
+		return nil
+	}
+	fmt.Printf("%s, imported %s\n", tracksResp.SessionName, tracksResp.ImportedAt.Format("2006-01-02 15:04"))
+	printTracks(tracksResp.Tracks)
+	return nil
+}
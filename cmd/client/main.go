@@ -19,6 +19,7 @@ func main() {
 		jwt:           "",
 		serverAddress: "http://localhost:8080",
 		commands:      listCommands(),
+		scanner:       scanner,
 	}
 
 	for {
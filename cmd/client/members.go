@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/Denisowiec/FoleyBookkeeper/internal/db"
+)
+
+func commandSetMember(cfg *config, args []string) error {
+	// Takes project title, username and optionally the project role:
+	// lead, member or viewer. Members are the default
+	if len(args) < 2 {
+		return fmt.Errorf("invalid number of arguments")
+	}
+
+	prj, err := getProjectByName(cfg, args[0])
+	if err != nil {
+		return err
+	}
+	userID, err := getUserID(cfg, args[1])
+	if err != nil {
+		return err
+	}
+	reqBody := struct {
+		Role string `json:"role"`
+	}{}
+	if len(args) > 2 {
+		reqBody.Role = args[2]
+	}
+
+	url := fmt.Sprintf("%s/api/projects/%s/members/%s", cfg.serverAddress, prj.ID, userID)
+	resp, err := sendRequest(reqBody, "PUT", url, cfg.jwt)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		return processErrorResponse(resp)
+	}
+
+	member := db.GetProjectMembersRow{}
+	err = processResponse(resp, &member)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s is a %s of %s\n", member.Username, member.Role, prj.Title)
+	return nil
+}
+
+func commandRemoveMember(cfg *config, args []string) error {
+	// Takes project title and username
+	if len(args) < 2 {
+		return fmt.Errorf("invalid number of arguments")
+	}
+
+	prj, err := getProjectByName(cfg, args[0])
+	if err != nil {
+		return err
+	}
+	userID, err := getUserID(cfg, args[1])
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/api/projects/%s/members/%s", cfg.serverAddress, prj.ID, userID)
+	resp, err := sendEmptyRequest("DELETE", url, cfg.jwt)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		return processErrorResponse(resp)
+	}
+
+	fmt.Printf("%s removed from %s\n", args[1], prj.Title)
+	return nil
+}
+
+func commandListMembers(cfg *config, args []string) error {
+	// Takes project title
+	if len(args) < 1 {
+		return fmt.Errorf("invalid number of arguments")
+	}
+
+	prj, err := getProjectByName(cfg, args[0])
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/api/projects/%s/members", cfg.serverAddress, prj.ID)
+	resp, err := sendEmptyRequest("GET", url, cfg.jwt)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return processErrorResponse(resp)
+	}
+
+	members := []db.GetProjectMembersRow{}
+	err = processResponse(resp, &members)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Members of %s:\n", prj.Title)
+	for _, m := range members {
+		fmt.Printf("  %s (%s)\n", m.Username, m.Role)
+	}
+	return nil
+}
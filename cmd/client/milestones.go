@@ -0,0 +1,202 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Denisowiec/FoleyBookkeeper/internal/db"
+)
+
+type dueMilestoneType struct {
+	db.GetOpenMilestonesDueBeforeRow
+	DaysLeft int `json:"days_left"`
+}
+
+func commandCreateMilestone(cfg *config, args []string) error {
+	// Takes project title, episode number (or - for the whole project),
+	// kind (delivery, client_review or internal), due date and the title
+	if len(args) < 5 {
+		return fmt.Errorf("invalid number of arguments")
+	}
+
+	prj, err := getProjectByName(cfg, args[0])
+	if err != nil {
+		return err
+	}
+	episodeID := ""
+	if args[1] != "-" {
+		ep, err := getEpisodeByNumber(cfg, args[0], args[1])
+		if err != nil {
+			return err
+		}
+		episodeID = ep.ID.String()
+	}
+
+	reqBody := struct {
+		ProjectID string `json:"project_id"`
+		EpisodeID string `json:"episode_id"`
+		Kind      string `json:"kind"`
+		DueDate   string `json:"due_date"`
+		Title     string `json:"title"`
+	}{
+		ProjectID: prj.ID.String(),
+		EpisodeID: episodeID,
+		Kind:      args[2],
+		DueDate:   args[3],
+		Title:     strings.Join(args[4:], " "),
+	}
+
+	url := fmt.Sprintf("%s/api/milestones", cfg.serverAddress)
+	resp, err := sendRequest(reqBody, "POST", url, cfg.jwt)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return processErrorResponse(resp)
+	}
+
+	milestone := db.Milestone{}
+	err = processResponse(resp, &milestone)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Milestone %s due %s created successfully\n", milestone.Title, milestone.DueDate.Format(time.DateOnly))
+	return nil
+}
+
+func commandGetMilestones(cfg *config, args []string) error {
+	// Lists all milestones of a project
+	// Takes project title
+	if len(args) < 1 {
+		return fmt.Errorf("invalid number of arguments")
+	}
+
+	prj, err := getProjectByName(cfg, args[0])
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/api/projects/%s/milestones", cfg.serverAddress, prj.ID)
+	resp, err := sendEmptyRequest("GET", url, cfg.jwt)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return processErrorResponse(resp)
+	}
+
+	var list []db.Milestone
+	err = processResponse(resp, &list)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range list {
+		done := ""
+		if m.CompletedAt.Valid {
+			done = " (completed)"
+		}
+		fmt.Printf("%s  %s  %-13s %s%s\n", m.ID, m.DueDate.Format(time.DateOnly), m.Kind, m.Title, done)
+	}
+	return nil
+}
+
+func commandCompleteMilestone(cfg *config, args []string) error {
+	// Marks a milestone completed
+	// Takes the milestone's ID, as shown by list-milestones
+	if len(args) < 1 {
+		return fmt.Errorf("invalid number of arguments")
+	}
+
+	milestone, err := getThingByID(cfg, "/api/milestones", args[0], db.Milestone{})
+	if err != nil {
+		return err
+	}
+
+	// Updates replace all fields, so the current ones are sent back
+	completed := true
+	reqBody := struct {
+		EpisodeID string `json:"episode_id"`
+		Kind      string `json:"kind"`
+		Title     string `json:"title"`
+		DueDate   string `json:"due_date"`
+		Notes     string `json:"notes"`
+		Completed *bool  `json:"completed"`
+	}{
+		Kind:      string(milestone.Kind),
+		Title:     milestone.Title,
+		DueDate:   milestone.DueDate.Format(time.DateOnly),
+		Notes:     milestone.Notes.String,
+		Completed: &completed,
+	}
+	if milestone.EpisodeID.Valid {
+		reqBody.EpisodeID = milestone.EpisodeID.UUID.String()
+	}
+
+	url := fmt.Sprintf("%s/api/milestones/%s", cfg.serverAddress, milestone.ID)
+	resp, err := sendRequest(reqBody, "PUT", url, cfg.jwt)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		return processErrorResponse(resp)
+	}
+
+	fmt.Printf("Milestone %s completed\n", milestone.Title)
+	return nil
+}
+
+func printDueMilestones(heading string, list []dueMilestoneType) {
+	if len(list) == 0 {
+		return
+	}
+	fmt.Println(heading)
+	for _, m := range list {
+		target := m.ProjectTitle
+		if m.EpisodeNumber.Valid {
+			target = fmt.Sprintf("%s E%02d", m.ProjectTitle, m.EpisodeNumber.Int32)
+		}
+		fmt.Printf("  %s  %-24s %-13s %-30s %4d min logged\n", m.DueDate.Format(time.DateOnly), target, m.Kind, m.Title, m.Minutes)
+	}
+}
+
+func commandDue(cfg *config, args []string) error {
+	// Lists overdue milestones and the ones due by the end of the week,
+	// or by the given date
+	until := time.Now()
+	// Weeks end on Sunday
+	until = until.AddDate(0, 0, (7-int(until.Weekday()))%7)
+	reqBody := struct {
+		Until string `json:"until"`
+	}{
+		Until: until.Format(time.DateOnly),
+	}
+	if len(args) >= 1 {
+		reqBody.Until = args[0]
+	}
+
+	due, err := getThing(cfg, "/api/milestones", reqBody, struct {
+		Overdue  []dueMilestoneType `json:"overdue"`
+		Upcoming []dueMilestoneType `json:"upcoming"`
+	}{})
+	if err != nil {
+		return err
+	}
+
+	if len(due.Overdue) == 0 && len(due.Upcoming) == 0 {
+		fmt.Printf("Nothing due until %s\n", reqBody.Until)
+		return nil
+	}
+	printDueMilestones("Overdue:", due.Overdue)
+	printDueMilestones(fmt.Sprintf("Due until %s:", reqBody.Until), due.Upcoming)
+	return nil
+}
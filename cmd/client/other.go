@@ -1,6 +1,9 @@
 package main
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 func commandHelp(cfg *config, args []string) error {
 	if len(args) == 0 {
@@ -17,3 +20,13 @@ func commandHelp(cfg *config, args []string) error {
 	}
 	return nil
 }
+
+func askConfirmation(cfg *config, question string) bool {
+	// Asks the user a yes/no question. Anything other than yes counts as no
+	fmt.Printf("%s [y/N] ", question)
+	if !cfg.scanner.Scan() {
+		return false
+	}
+	answer := strings.ToLower(strings.TrimSpace(cfg.scanner.Text()))
+	return answer == "y" || answer == "yes"
+}
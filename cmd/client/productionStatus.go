@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+type statusCellType struct {
+	Status   string `json:"status"`
+	Assignee string `json:"assignee"`
+}
+
+func (c statusCellType) String() string {
+	if c.Assignee == "" {
+		return c.Status
+	}
+	return fmt.Sprintf("%s (%s)", c.Status, c.Assignee)
+}
+
+func commandSetStatus(cfg *config, args []string) error {
+	// Sets the production status of a part of an episode
+	// Takes project title, episode number, part code, status, and optionally
+	// the assignee's username and notes
+	if len(args) < 4 {
+		return fmt.Errorf("invalid number of arguments")
+	}
+
+	ep, err := getEpisodeByNumber(cfg, args[0], args[1])
+	if err != nil {
+		return err
+	}
+
+	reqBody := struct {
+		Status     string  `json:"status"`
+		AssigneeID *string `json:"assignee_id,omitempty"`
+		Notes      *string `json:"notes,omitempty"`
+	}{
+		Status: args[3],
+	}
+	if len(args) >= 5 {
+		userID, err := getUserID(cfg, args[4])
+		if err != nil {
+			return err
+		}
+		reqBody.AssigneeID = &userID
+	}
+	if len(args) >= 6 {
+		notes := strings.Join(args[5:], " ")
+		reqBody.Notes = &notes
+	}
+
+	url := fmt.Sprintf("%s/api/episodes/%s/parts/%s/status", cfg.serverAddress, ep.ID, args[2])
+	resp, err := sendRequest(reqBody, "PUT", url, cfg.jwt)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		return processErrorResponse(resp)
+	}
+
+	fmt.Printf("Episode %s, %s set to %s\n", args[1], args[2], args[3])
+	return nil
+}
+
+func commandStatusMatrix(cfg *config, args []string) error {
+	// Prints the production status of every part of every episode of a project
+	// Takes project title
+	if len(args) < 1 {
+		return fmt.Errorf("invalid number of arguments")
+	}
+
+	prj, err := getProjectByName(cfg, args[0])
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/api/projects/%s/status-matrix", cfg.serverAddress, prj.ID)
+	resp, err := sendEmptyRequest("GET", url, cfg.jwt)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return processErrorResponse(resp)
+	}
+
+	matrix := struct {
+		Parts []struct {
+			Code        string `json:"code"`
+			DisplayName string `json:"display_name"`
+		} `json:"parts"`
+		Episodes []struct {
+			Code  string                    `json:"code"`
+			Title string                    `json:"title"`
+			Parts map[string]statusCellType `json:"parts"`
+		} `json:"episodes"`
+	}{}
+	err = processResponse(resp, &matrix)
+	if err != nil {
+		return err
+	}
+
+	// Columns are as wide as the longest part name or cell in them
+	widths := make([]int, len(matrix.Parts))
+	for i, p := range matrix.Parts {
+		widths[i] = len(p.DisplayName)
+		for _, ep := range matrix.Episodes {
+			widths[i] = max(widths[i], len(ep.Parts[p.Code].String()))
+		}
+	}
+
+	fmt.Printf("%-7s", "Ep")
+	for i, p := range matrix.Parts {
+		fmt.Printf("  %-*s", widths[i], p.DisplayName)
+	}
+	fmt.Println()
+	for _, ep := range matrix.Episodes {
+		fmt.Printf("%-7s", ep.Code)
+		for i, p := range matrix.Parts {
+			fmt.Printf("  %-*s", widths[i], ep.Parts[p.Code])
+		}
+		fmt.Println()
+	}
+	return nil
+}
@@ -21,7 +21,8 @@ func getProjectByName(cfg *config, name string) (db.Project, error) {
 
 func commandCreateProject(cfg *config, args []string) error {
 	// Command hanldes creating new projects
-	// Takes the project title and client name as arguments
+	// Takes the project title, client name and optionally the project type
+	// (series, feature, commercial or game) as arguments
 	if len(args) < 2 {
 		return fmt.Errorf("invalid number of arguments")
 	}
@@ -36,13 +37,17 @@ func commandCreateProject(cfg *config, args []string) error {
 	url := fmt.Sprintf("%s/api/projects", cfg.serverAddress)
 
 	type createProjectReqType struct {
-		Title    string `json:"title"`
-		ClientID string `json:"client_id"`
+		Title       string `json:"title"`
+		ClientID    string `json:"client_id"`
+		ProjectType string `json:"project_type"`
 	}
 	createProjectReq := createProjectReqType{
 		Title:    args[0],
 		ClientID: client.ID.String(),
 	}
+	if len(args) >= 3 {
+		createProjectReq.ProjectType = args[2]
+	}
 
 	resp, err := sendRequest(createProjectReq, "POST", url, cfg.jwt)
 	if err != nil {
@@ -60,7 +65,7 @@ func commandCreateProject(cfg *config, args []string) error {
 		return err
 	}
 
-	fmt.Printf("Project %s created successfully\n", prj.Title)
+	fmt.Printf("Project %s (%s) created successfully\n", prj.Title, prj.ProjectType)
 
 	return nil
 }
@@ -149,13 +154,18 @@ func commandGetProjectInfo(cfg *config, args []string) error {
 	}
 
 	// Now we format the output
-	fmt.Printf("Project title: %s\nProjectID: %s\nCreated at: %v\nUpdated at: %v\nClient: %s\n",
-		prj.Title, prj.ID.String(), prj.CreatedAt, prj.UpdatedAt, client.ClientName)
+	fmt.Printf("Project title: %s\nProjectID: %s\nCreated at: %v\nUpdated at: %v\nClient: %s\nType: %s\nStatus: %s\n",
+		prj.Title, prj.ID.String(), prj.CreatedAt, prj.UpdatedAt, client.ClientName, prj.ProjectType, prj.Status)
 	return nil
 }
 
 func commandGetAllProjects(cfg *config, args []string) error {
+	// Archived projects are left out, unless asked for with a status filter,
+	// e.g. list-projects archived, list-projects active,on_hold or list-projects all
 	url := fmt.Sprintf("%s/api/projects", cfg.serverAddress)
+	if len(args) >= 1 {
+		url += "?status=" + args[0]
+	}
 
 	var list []db.Project
 
@@ -178,7 +188,45 @@ func commandGetAllProjects(cfg *config, args []string) error {
 		if err != nil {
 			return err
 		}
-		fmt.Printf("Name: %s, client: %s\n", item.Title, client.ClientName)
+		fmt.Printf("Name: %s, client: %s, status: %s\n", item.Title, client.ClientName, item.Status)
 	}
 	return nil
 }
+
+func commandSetProjectStatus(cfg *config, args []string) error {
+	// Takes the project title and the new status: bidding, active, on_hold,
+	// delivered, closed or archived
+	if len(args) < 2 {
+		return fmt.Errorf("invalid number of arguments")
+	}
+
+	prj, err := getProjectByName(cfg, args[0])
+	if err != nil {
+		return err
+	}
+
+	reqBody := struct {
+		Status string `json:"status"`
+	}{
+		Status: args[1],
+	}
+
+	url := fmt.Sprintf("%s/api/projects/%s/status", cfg.serverAddress, prj.ID)
+	resp, err := sendRequest(reqBody, "PUT", url, cfg.jwt)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		return processErrorResponse(resp)
+	}
+
+	err = processResponse(resp, &prj)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Project %s is now %s\n", prj.Title, prj.Status)
+	return nil
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"bufio"
 	"strings"
 
 	"github.com/google/uuid"
@@ -17,9 +18,12 @@ type config struct {
 	username      string
 	email         string
 	jwt           string
+	refreshToken  string
 	userID        uuid.UUID
 	serverAddress string
 	commands      map[string]cliCommand
+	// The input scanner is shared with commands that ask for confirmation
+	scanner *bufio.Scanner
 }
 
 func cleanInput(text string) []string {
@@ -93,9 +97,39 @@ func listCommands() map[string]cliCommand {
 		"login": {
 			name:        "login",
 			description: "Log in to the system.",
-			usage:       "login <email> <password>",
+			usage:       "login <email> <password> <device label, optional>",
 			callback:    commandLogin,
 		},
+		"refresh-login": {
+			name:        "refresh-login",
+			description: "Gets a new JWT with the refresh token, when the old one expired.",
+			usage:       "refresh-login",
+			callback:    commandRefreshLogin,
+		},
+		"logout": {
+			name:        "logout",
+			description: "Logs out of the system.",
+			usage:       "logout",
+			callback:    commandLogout,
+		},
+		"logins": {
+			name:        "logins",
+			description: "Lists the active logins of a user, your own by default. Only admins can see other users' logins.",
+			usage:       "logins <username, optional>",
+			callback:    commandListLogins,
+		},
+		"revoke-login": {
+			name:        "revoke-login",
+			description: "Logs a user out on one device, given the login ID from the logins list.",
+			usage:       "revoke-login <login ID> <username, optional>",
+			callback:    commandRevokeLogin,
+		},
+		"logout-everywhere": {
+			name:        "logout-everywhere",
+			description: "Logs out of the system on all devices.",
+			usage:       "logout-everywhere",
+			callback:    commandLogoutEverywhere,
+		},
 		"help": {
 			name:        "help",
 			description: "Lists all available commands or usage information for a given command.",
@@ -120,6 +154,12 @@ func listCommands() map[string]cliCommand {
 			usage:       "get-user <ID>",
 			callback:    commandGetUserInfo,
 		},
+		"set-role": {
+			name:        "set-role",
+			description: "Assigns a role to a user: admin, manager, engineer or accountant. Admins only.",
+			usage:       "set-role <username> <role>",
+			callback:    commandSetRole,
+		},
 		"create-client": {
 			name:        "create-client",
 			description: "Create a new client.",
@@ -134,20 +174,86 @@ func listCommands() map[string]cliCommand {
 		},
 		"show-client": {
 			name:        "show-client",
-			description: "Display basic info about a client",
+			description: "Display info about a client with its addresses and contacts",
 			usage:       "show-client <client-name>",
 			callback:    commandGetClient,
 		},
+		"delete-client": {
+			name:        "delete-client",
+			description: "Moves a client to the trash. Clients with projects are only deleted with force, together with their projects",
+			usage:       "delete-client <client> [force]",
+			callback:    commandDeleteClient,
+		},
+		"merge-client": {
+			name:        "merge-client",
+			description: "Moves the projects, contacts and missing details of a duplicate client to another client and deletes the duplicate",
+			usage:       "merge-client <duplicate> <target>",
+			callback:    commandMergeClient,
+		},
+		"set-client-billing": {
+			name:        "set-client-billing",
+			description: "Sets a client's tax ID (NIP or EU VAT), default currency, payment terms and invoice language",
+			usage:       "set-client-billing <client> [tax_id=<id>] [currency=<code>] [payment_terms=<days>] [language=<code>]",
+			callback:    commandSetClientBilling,
+		},
+		"set-client-address": {
+			name:        "set-client-address",
+			description: "Sets a client's billing or postal address",
+			usage:       "set-client-address <client> <billing|postal> line1=<street> [line2=<more>] postal_code=<code> city=<city> country=<country>",
+			callback:    commandSetClientAddress,
+		},
+		"remove-client-address": {
+			name:        "remove-client-address",
+			description: "Removes a client's billing or postal address",
+			usage:       "remove-client-address <client> <billing|postal>",
+			callback:    commandRemoveClientAddress,
+		},
+		"add-contact": {
+			name:        "add-contact",
+			description: "Adds a contact person to a client",
+			usage:       "add-contact <client> <name> [role=<role>] [email=<email>] [phone=<phone>]",
+			callback:    commandAddContact,
+		},
+		"update-contact": {
+			name:        "update-contact",
+			description: "Updates a client's contact person",
+			usage:       "update-contact <client> <name> [name=<new name>] [role=<role>] [email=<email>] [phone=<phone>]",
+			callback:    commandUpdateContact,
+		},
+		"remove-contact": {
+			name:        "remove-contact",
+			description: "Removes a client's contact person",
+			usage:       "remove-contact <client> <name>",
+			callback:    commandRemoveContact,
+		},
+		"list-contacts": {
+			name:        "list-contacts",
+			description: "Lists the contact persons of a client",
+			usage:       "list-contacts <client>",
+			callback:    commandListContacts,
+		},
 		"list-clients": {
 			name:        "list-clients",
 			description: "Lists all clients",
 			usage:       "list-clients",
 			callback:    commandGetAllClients,
 		},
+		"trash": {
+			name:        "trash",
+			description: "Lists the deleted clients, projects, episodes, sessions and calculations",
+			usage:       "trash",
+			callback:    commandGetTrash,
+		},
+		"restore": {
+			name:        "restore",
+			description: "Restores an item from the trash with everything deleted together with it",
+			usage:       "restore <client|project|episode|session|calculation> <ID or name>",
+			callback:    commandRestore,
+		},
 		"create-project": {
 			name:        "create-project",
 			description: "Create a new project",
-			usage:       "create-project <title> <client>",
+			usage:       "create-project <title> <client> <series|feature|commercial|game>",
 			callback:    commandCreateProject,
 		},
 		"update-project": {
@@ -164,16 +270,82 @@ func listCommands() map[string]cliCommand {
 		},
 		"list-projects": {
 			name:        "list-projects",
-			description: "List all projects",
-			usage:       "list-projects",
+			description: "List all projects, archived ones only when asked for",
+			usage:       "list-projects <status, comma separated statuses or all>",
 			callback:    commandGetAllProjects,
 		},
+		"set-project-status": {
+			name:        "set-project-status",
+			description: "Moves a project to bidding, active, on_hold, delivered, closed or archived",
+			usage:       "set-project-status <title> <status>",
+			callback:    commandSetProjectStatus,
+		},
+		"set-member": {
+			name:        "set-member",
+			description: "Adds a user to a project or changes their role on it: lead, member or viewer",
+			usage:       "set-member <project title> <username> <role>",
+			callback:    commandSetMember,
+		},
+		"remove-member": {
+			name:        "remove-member",
+			description: "Removes a user from a project",
+			usage:       "remove-member <project title> <username>",
+			callback:    commandRemoveMember,
+		},
+		"list-members": {
+			name:        "list-members",
+			description: "Lists the members of a project with their roles",
+			usage:       "list-members <project title>",
+			callback:    commandListMembers,
+		},
+		"create-unit": {
+			name:        "create-unit",
+			description: "Adds a season, reel or deliverable to a project, optionally under another unit",
+			usage:       "create-unit <project title> <season|reel|deliverable> <name> <parent unit>",
+			callback:    commandCreateUnit,
+		},
+		"list-units": {
+			name:        "list-units",
+			description: "Shows the seasons, reels and deliverables of a project",
+			usage:       "list-units <project title>",
+			callback:    commandGetUnits,
+		},
+		"create-season": {
+			name:        "create-season",
+			description: "Adds a season to a project, with the defaults for its calculations",
+			usage:       "create-season <project title> <season number> <start date> <end date> <budget> <currency> <exchange rate>",
+			callback:    commandCreateSeason,
+		},
+		"show-season": {
+			name:        "show-season",
+			description: "Shows a season of a project with its episodes",
+			usage:       "show-season <project title> <season number, e.g. S02>",
+			callback:    commandShowSeason,
+		},
 		"create-episode": {
 			name:        "create-episode",
 			description: "Creates an episode",
-			usage:       "create-episode <project title> <episode number> <episode title>",
+			usage:       "create-episode <project title> <episode number or code like S02E05> <episode title>",
 			callback:    commandCreateEpisode,
 		},
+		"create-episodes": {
+			name:        "create-episodes",
+			description: "Creates a range of episodes, titled after a pattern with {n}, {nn}, {nnn} or {code}, or from a CSV file of titles",
+			usage:       "create-episodes <project title> <range, e.g. 1-52 or S02E01-S02E26> <title pattern> csv=<file> runtime=<22m> picture_lock=<date> frame_rate=<25>",
+			callback:    commandCreateEpisodes,
+		},
+		"set-episode-info": {
+			name:        "set-episode-info",
+			description: "Sets the runtime, picture lock date and frame rate of an episode",
+			usage:       "set-episode-info <project title> <episode number> runtime=<22:30> picture_lock=<date> frame_rate=<23.976>",
+			callback:    commandSetEpisodeInfo,
+		},
+		"runtime-report": {
+			name:        "runtime-report",
+			description: "Shows the minutes logged for each episode of a project per minute of its runtime",
+			usage:       "runtime-report <project title>",
+			callback:    commandRuntimeReport,
+		},
 		"get-project-eps": {
 			name:        "get-project-eps",
 			description: "Returns all episodes for a given project",
@@ -183,14 +355,176 @@ func listCommands() map[string]cliCommand {
 		"create-session": {
 			name:        "create-session",
 			description: "Creates a new session",
-			usage:       "create-session <project title> <episode number> <date> <duration> <part worked on> <activity done> <user1> <user2> etc...",
+			usage:       "create-session <project title> <episode number or code, unit name or -> <date> <duration> <part worked on> <activity done> <user1> <user2> etc...",
 			callback:    commandCreateSession,
 		},
 		"get-sessions": {
 			name:        "get-sessions",
 			description: "Lists some sessions",
-			usage:       "get-sessions <how many> <project title> <episode number>",
+			usage:       "get-sessions <how many> <project title> <episode number or code, or unit name> <filter=value> etc...",
 			callback:    commandGetSessions,
 		},
+		"create-room": {
+			name:        "create-room",
+			description: "Creates a new studio room",
+			usage:       "create-room <name> <notes>",
+			callback:    commandCreateRoom,
+		},
+		"list-rooms": {
+			name:        "list-rooms",
+			description: "Lists all studio rooms",
+			usage:       "list-rooms",
+			callback:    commandGetAllRooms,
+		},
+		"create-booking": {
+			name:        "create-booking",
+			description: "Books studio time",
+			usage:       "create-booking <project title> <episode number, code like S02E05 or 0> <room> <\"YYYY-MM-DD HH:MM\"> <duration> <title> <user1> <user2> etc...",
+			callback:    commandCreateBooking,
+		},
+		"list-bookings": {
+			name:        "list-bookings",
+			description: "Lists bookings, by default for the next four weeks",
+			usage:       "list-bookings <from date> <to date>",
+			callback:    commandGetBookings,
+		},
+		"calendar-feed": {
+			name:        "calendar-feed",
+			description: "Creates a calendar subscription URL for a user, room or project",
+			usage:       "calendar-feed <user|room|project> <name>",
+			callback:    commandCreateCalendarFeed,
+		},
+		"create-cue": {
+			name:        "create-cue",
+			description: "Adds a cue to an episode",
+			usage:       "create-cue <project title> <episode number> <cue number> <timecode in> <timecode out> <frame rate> <part> <description>",
+			callback:    commandCreateCue,
+		},
+		"list-cues": {
+			name:        "list-cues",
+			description: "Lists the cues of an episode with minutes spent on them",
+			usage:       "list-cues <project title> <episode number>",
+			callback:    commandGetCues,
+		},
+		"cue-sheet": {
+			name:        "cue-sheet",
+			description: "Saves the cue sheet of an episode as csv, html, pdf or ale",
+			usage:       "cue-sheet <project title> <episode number> <file> [format=pdf] [sort=number] [group=part] [reel=R1]",
+			callback:    commandCueSheet,
+		},
+		"submit-week": {
+			name:        "submit-week",
+			description: "Submits your sessions of a week for approval",
+			usage:       "submit-week <any date in the week>",
+			callback:    commandSubmitWeek,
+		},
+		"pending-approvals": {
+			name:        "pending-approvals",
+			description: "Lists timesheets waiting for approval",
+			usage:       "pending-approvals",
+			callback:    commandGetPendingTimesheets,
+		},
+		"approve-timesheet": {
+			name:        "approve-timesheet",
+			description: "Approves a submitted timesheet",
+			usage:       "approve-timesheet <timesheet id> <comment>",
+			callback:    commandApproveTimesheet,
+		},
+		"reject-timesheet": {
+			name:        "reject-timesheet",
+			description: "Rejects a submitted timesheet, the comment is required",
+			usage:       "reject-timesheet <timesheet id> <comment>",
+			callback:    commandRejectTimesheet,
+		},
+		"create-part": {
+			name:        "create-part",
+			description: "Adds a part sessions can be logged for",
+			usage:       "create-part <code> <display name> <billing weight>",
+			callback:    commandCreatePart,
+		},
+		"list-parts": {
+			name:        "list-parts",
+			description: "Lists the parts sessions can be logged for",
+			usage:       "list-parts",
+			callback:    commandGetParts,
+		},
+		"create-activity": {
+			name:        "create-activity",
+			description: "Adds an activity sessions can be logged as",
+			usage:       "create-activity <code> <display name> <billing weight>",
+			callback:    commandCreateActivity,
+		},
+		"list-activities": {
+			name:        "list-activities",
+			description: "Lists the activities sessions can be logged as",
+			usage:       "list-activities",
+			callback:    commandGetActivities,
+		},
+		"session-anomalies": {
+			name:        "session-anomalies",
+			description: "Lists sessions that break the validation rules",
+			usage:       "session-anomalies <from date> <to date>",
+			callback:    commandGetSessionAnomalies,
+		},
+		"import-ics": {
+			name:        "import-ics",
+			description: "Imports bookings or sessions from an iCalendar file, after showing a preview",
+			usage:       "import-ics <file> <bookings|sessions> <room> <title pattern>",
+			callback:    commandImportICS,
+		},
+		"import-edl": {
+			name:        "import-edl",
+			description: "Imports the cues of an episode from a CMX3600 EDL, after showing a preview",
+			usage:       "import-edl <file> <project title> <episode number> <frame rate|-> <events|markers> <reel>",
+			callback:    commandImportEDL,
+		},
+		"import-protools": {
+			name:        "import-protools",
+			description: "Imports cues and track statistics of an episode from a Pro Tools session info text, after showing a preview",
+			usage:       "import-protools <file> <project title> <episode number> <frame rate|->",
+			callback:    commandImportProTools,
+		},
+		"list-tracks": {
+			name:        "list-tracks",
+			description: "Lists the tracks of the latest Pro Tools session imported for an episode",
+			usage:       "list-tracks <project title> <episode number>",
+			callback:    commandGetEpisodeTracks,
+		},
+		"status-matrix": {
+			name:        "status-matrix",
+			description: "Shows the production status of every part of every episode of a project",
+			usage:       "status-matrix <project title>",
+			callback:    commandStatusMatrix,
+		},
+		"set-status": {
+			name:        "set-status",
+			description: "Sets the production status of a part of an episode",
+			usage:       "set-status <project title> <episode number> <part> <status> <assignee> <notes>",
+			callback:    commandSetStatus,
+		},
+		"create-milestone": {
+			name:        "create-milestone",
+			description: "Adds a delivery deadline, client review date or internal milestone to a project or episode",
+			usage:       "create-milestone <project title> <episode number or -> <delivery|client_review|internal> <due date> <title>",
+			callback:    commandCreateMilestone,
+		},
+		"list-milestones": {
+			name:        "list-milestones",
+			description: "Lists all milestones of a project",
+			usage:       "list-milestones <project title>",
+			callback:    commandGetMilestones,
+		},
+		"complete-milestone": {
+			name:        "complete-milestone",
+			description: "Marks a milestone completed",
+			usage:       "complete-milestone <milestone ID>",
+			callback:    commandCompleteMilestone,
+		},
+		"due": {
+			name:        "due",
+			description: "Shows what's overdue and due this week, with the minutes logged so far",
+			usage:       "due <until date>",
+			callback:    commandDue,
+		},
 	}
 }
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/Denisowiec/FoleyBookkeeper/internal/db"
+)
+
+func getRoomByName(cfg *config, name string) (db.Room, error) {
+	// A helper function, since fetching a room by name is needed by the booking and feed commands
+	type getRoomReqType struct {
+		RoomName string `json:"room_name"`
+	}
+	getRoomReq := getRoomReqType{
+		RoomName: name,
+	}
+
+	return getThing(cfg, "/api/rooms", getRoomReq, db.Room{})
+}
+
+func commandCreateRoom(cfg *config, args []string) error {
+	// Command handles creating new studio rooms
+	// Takes the room name and optionally some notes
+	if len(args) < 1 {
+		return fmt.Errorf("invalid number of arguments")
+	}
+	url := fmt.Sprintf("%s/api/rooms", cfg.serverAddress)
+
+	type reqBodyType struct {
+		RoomName string `json:"room_name"`
+		Notes    string `json:"notes"`
+	}
+	reqBody := reqBodyType{
+		RoomName: args[0],
+	}
+	if len(args) >= 2 {
+		reqBody.Notes = args[1]
+	}
+
+	resp, err := sendRequest(reqBody, "POST", url, cfg.jwt)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return processErrorResponse(resp)
+	}
+
+	room := db.Room{}
+	err = processResponse(resp, &room)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Room %s created successfully\n", room.RoomName)
+	return nil
+}
+
+func commandGetAllRooms(cfg *config, args []string) error {
+	url := fmt.Sprintf("%s/api/rooms", cfg.serverAddress)
+
+	resp, err := sendEmptyRequest("GET", url, cfg.jwt)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return processErrorResponse(resp)
+	}
+
+	var list []db.Room
+	err = processResponse(resp, &list)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range list {
+		fmt.Printf("%s, notes: %s\n", item.RoomName, item.Notes.String)
+	}
+	return nil
+}
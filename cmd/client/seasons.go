@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Denisowiec/FoleyBookkeeper/internal/db"
+	"github.com/Denisowiec/FoleyBookkeeper/internal/episodecode"
+	"github.com/google/uuid"
+)
+
+func getSeasonNumbers(cfg *config, prj db.Project) (map[uuid.UUID]int, error) {
+	// Maps the IDs of a project's seasons to their numbers
+	units, err := getUnitsForProject(cfg, prj)
+	if err != nil {
+		return nil, err
+	}
+	seasons := map[uuid.UUID]int{}
+	for _, u := range units {
+		if u.Kind == db.UnitKindSeason && u.Number.Valid {
+			seasons[u.ID] = int(u.Number.Int32)
+		}
+	}
+	return seasons, nil
+}
+
+func commandCreateSeason(cfg *config, args []string) error {
+	// Takes project title, season number and optionally the start and end dates,
+	// the budget, currency and exchange rate new calculations for it start with
+	if len(args) < 2 {
+		return fmt.Errorf("invalid number of arguments")
+	}
+
+	prj, err := getProjectByName(cfg, args[0])
+	if err != nil {
+		return err
+	}
+	number, err := episodecode.ParseSeason(args[1])
+	if err != nil {
+		return err
+	}
+
+	reqBody := struct {
+		Kind         string `json:"kind"`
+		Name         string `json:"name"`
+		Number       int    `json:"number"`
+		SortOrder    int    `json:"sort_order"`
+		StartsOn     string `json:"starts_on,omitempty"`
+		EndsOn       string `json:"ends_on,omitempty"`
+		Budget       string `json:"budget,omitempty"`
+		Currency     string `json:"currency,omitempty"`
+		ExchangeRate string `json:"exchange_rate,omitempty"`
+	}{
+		Kind:      string(db.UnitKindSeason),
+		Name:      fmt.Sprintf("Season %d", number),
+		Number:    number,
+		SortOrder: number,
+	}
+	optional := []*string{&reqBody.StartsOn, &reqBody.EndsOn, &reqBody.Budget, &reqBody.Currency, &reqBody.ExchangeRate}
+	for i, arg := range args[2:] {
+		if i >= len(optional) {
+			break
+		}
+		*optional[i] = arg
+	}
+
+	url := fmt.Sprintf("%s/api/projects/%s/units", cfg.serverAddress, prj.ID)
+	resp, err := sendRequest(reqBody, "POST", url, cfg.jwt)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return processErrorResponse(resp)
+	}
+
+	fmt.Printf("Season %d of %s created successfully\n", number, prj.Title)
+	return nil
+}
+
+func commandShowSeason(cfg *config, args []string) error {
+	// Takes project title and season number, e.g. S02 or 2
+	if len(args) < 2 {
+		return fmt.Errorf("invalid number of arguments")
+	}
+
+	prj, err := getProjectByName(cfg, args[0])
+	if err != nil {
+		return err
+	}
+	number, err := episodecode.ParseSeason(args[1])
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/api/projects/%s/seasons/%d", cfg.serverAddress, prj.ID, number)
+	resp, err := sendEmptyRequest("GET", url, cfg.jwt)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return processErrorResponse(resp)
+	}
+
+	season := struct {
+		db.ProjectUnit
+		Episodes []db.Episode `json:"episodes"`
+	}{}
+	err = processResponse(resp, &season)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s, %s\n", prj.Title, season.Name)
+	if season.StartsOn.Valid || season.EndsOn.Valid {
+		dates := []string{"?", "?"}
+		if season.StartsOn.Valid {
+			dates[0] = season.StartsOn.Time.Format(time.DateOnly)
+		}
+		if season.EndsOn.Valid {
+			dates[1] = season.EndsOn.Time.Format(time.DateOnly)
+		}
+		fmt.Printf("Dates: %s - %s\n", dates[0], dates[1])
+	}
+	if season.Budget.Valid {
+		fmt.Printf("Default budget: %s %s\n", season.Budget.String, season.Currency.String)
+	}
+	if season.ExchangeRate.Valid {
+		fmt.Printf("Default exchange rate: %s\n", season.ExchangeRate.String)
+	}
+	fmt.Printf("Episodes: %d\n", len(season.Episodes))
+	for _, e := range season.Episodes {
+		code := episodecode.Code{Season: number, Episode: int(e.EpisodeNumber)}
+		fmt.Printf("  %s %s\n", code, e.Title.String)
+	}
+	return nil
+}
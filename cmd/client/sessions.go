@@ -3,24 +3,22 @@ package main
 import (
 	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/Denisowiec/FoleyBookkeeper/internal/db"
 )
 
 func commandCreateSession(cfg *config, args []string) error {
-	// Takes project title, episode number, date of session,
-	// duration of session, part worked on, activity done and a list of usernames as input
+	// Takes project title, episode number (or unit name, or - for the whole project),
+	// date of session, duration of session, part worked on, activity done
+	// and a list of usernames as input
 	if len(args) < 6 {
 		return fmt.Errorf("invalid number of arguments")
 	}
 
-	projectName := args[0]
-	episodeNumber, err := strconv.Atoi(args[1])
-	if err != nil {
-		return err
-	}
 	dateFromInput, err := time.Parse(time.DateOnly, args[2])
 	if err != nil {
 		return err
@@ -49,28 +47,8 @@ func commandCreateSession(cfg *config, args []string) error {
 		users = append(users, userID)
 	}
 
-	// Now we need the project id
-	reqPrjBody := struct {
-		ProjectTitle string `json:"title"`
-	}{
-		ProjectTitle: projectName,
-	}
-
-	prj, err := getThing(cfg, "/api/projects", reqPrjBody, db.Project{})
-	if err != nil {
-		return err
-	}
-
-	// Now we need the episode ID
-	reqEpBody := struct {
-		ProjectID     string `json:"project_id"`
-		EpisodeNumber int    `json:"episode_number"`
-	}{
-		ProjectID:     prj.ID.String(),
-		EpisodeNumber: episodeNumber,
-	}
-
-	ep, err := getThing(cfg, "/api/episodes", reqEpBody, db.Episode{})
+	// Now we need to know what the session is logged for
+	level, err := getSessionLevel(cfg, args[0], args[1])
 	if err != nil {
 		return err
 	}
@@ -78,16 +56,16 @@ func commandCreateSession(cfg *config, args []string) error {
 	// Now we have everything we need to record a session
 
 	type createSesType struct {
-		Duration     int32  `json:"duration"`
-		SessionDate  string `json:"session_date"`
-		EpisodeID    string `json:"episode_id"`
+		Duration    int32  `json:"duration"`
+		SessionDate string `json:"session_date"`
+		sessionLevel
 		PartWorkedOn string `json:"part_worked_on"`
 		ActivityDone string `json:"activity_done"`
 	}
 	createSesReq := createSesType{
 		Duration:     duration,
 		SessionDate:  date,
-		EpisodeID:    ep.ID.String(),
+		sessionLevel: level,
 		PartWorkedOn: partWorkedOn,
 		ActivityDone: activityDone,
 	}
@@ -97,16 +75,23 @@ func commandCreateSession(cfg *config, args []string) error {
 	if err != nil {
 		return err
 	}
+	if resp.StatusCode == http.StatusUnprocessableEntity {
+		return processViolationResponse(resp)
+	}
 	if resp.StatusCode != http.StatusCreated {
 		return processErrorResponse(resp)
 	}
 
-	ses := db.Session{}
+	ses := struct {
+		db.Session
+		Warnings []sessionViolation `json:"warnings"`
+	}{}
 
 	err = processResponse(resp, &ses)
 	if err != nil {
 		return err
 	}
+	printViolations(ses.Warnings)
 
 	// Now we add the users to the session
 	reqUsSesBody := struct {
@@ -121,42 +106,53 @@ func commandCreateSession(cfg *config, args []string) error {
 	if err != nil {
 		return err
 	}
+	if resp2.StatusCode == http.StatusUnprocessableEntity {
+		return processViolationResponse(resp2)
+	}
 	if resp2.StatusCode != http.StatusAccepted {
 		return processErrorResponse(resp2)
 	}
 
-	// Commented out for the time being, because there's no need to extract the json data from the response
-	/*resp2Body := struct {
-		Users []string `json:"user_ids"`
+	resp2Body := struct {
+		Warnings []sessionViolation `json:"warnings"`
 	}{}
 
 	err = processResponse(resp2, &resp2Body)
 	if err != nil {
 		return err
-	}*/
+	}
+	printViolations(resp2Body.Warnings)
 
-	fmt.Printf("Session for episode %d of %s created successfully\n", episodeNumber, projectName)
+	fmt.Printf("Session for %s created successfully\n", level.label)
 
 	return nil
 }
 
 func commandGetSessions(cfg *config, args []string) error {
-	// This command Gets a list of sessions for a given project/episode
-	// Takes number of items, project title, episode number as arguments
-	if len(args) < 2 {
+	// This command Gets a list of sessions for a given project/episode/unit
+	// Takes number of items, project title, episode number or unit name as arguments.
+	// Any further filters can be given as key=value, e.g. part=footsteps from=2024-03-01
+	filters := url.Values{}
+	positional := []string{}
+	for _, arg := range args {
+		if key, value, found := strings.Cut(arg, "="); found {
+			filters.Add(key, value)
+			continue
+		}
+		positional = append(positional, arg)
+	}
+
+	if len(positional) < 2 {
 		return fmt.Errorf("invalid number of arguments")
 	}
 
-	limit := 20
-	if len(args) == 3 {
-		var err error
-		limit, err = strconv.Atoi(args[0])
-		if err != nil {
-			return err
-		}
+	limit, err := strconv.Atoi(positional[0])
+	if err != nil {
+		return err
 	}
+	filters.Set("limit", strconv.Itoa(limit))
 
-	projectName := args[1]
+	projectName := positional[1]
 
 	// Now we need the project id
 	reqPrjBody := struct {
@@ -169,68 +165,54 @@ func commandGetSessions(cfg *config, args []string) error {
 	if err != nil {
 		return err
 	}
-	projectID := prj.ID.String()
-
-	type listItem struct {
-		db.Session `json:"session"`
-		Users      []db.GetUsersForSessionRow `json:"users"`
-	}
-	list := []listItem{}
 
 	// The request will be different depending on the arguments given
-	var episodeNumber int
-	if len(args) >= 3 {
-		episodeNumber, err = strconv.Atoi(args[2])
+	if len(positional) >= 3 {
+		level, err := getSessionLevel(cfg, projectName, positional[2])
 		if err != nil {
 			return err
 		}
-
-		reqBody := struct {
-			ProjectID     string `json:"project_id"`
-			EpisodeNumber int    `json:"episode_number"`
-		}{
-			ProjectID:     projectID,
-			EpisodeNumber: episodeNumber,
-		}
-
-		ep, err := getThing(cfg, "/api/episodes", reqBody, db.Episode{})
-		if err != nil {
-			return err
+		switch {
+		case level.EpisodeID != "":
+			filters.Set("episode", level.EpisodeID)
+		case level.UnitID != "":
+			filters.Set("unit", level.UnitID)
+		default:
+			filters.Set("project", level.ProjectID)
 		}
+		fmt.Printf("Sessions for %s:\n", level.label)
+	} else {
+		filters.Set("project", prj.ID.String())
+		fmt.Printf("Sessions for project %s:\n", prj.Title)
+	}
 
-		episodeID := ep.ID.String()
-
-		req2Body := struct {
-			Limit     int    `json:"limit"`
-			EpisodeID string `json:"episode_id"`
-		}{
-			Limit:     limit,
-			EpisodeID: episodeID,
-		}
+	type listItem struct {
+		db.Session `json:"session"`
+		Users      []db.GetUsersForSessionRow `json:"users"`
+	}
+	page := struct {
+		Sessions   []listItem `json:"sessions"`
+		Total      int64      `json:"total"`
+		NextCursor string     `json:"next_cursor"`
+	}{}
 
-		list, err = getThing(cfg, "/api/sessions", req2Body, list)
-		if err != nil {
-			return err
-		}
+	reqURL := fmt.Sprintf("%s/api/sessions?%s", cfg.serverAddress, filters.Encode())
+	resp, err := sendEmptyRequest("GET", reqURL, cfg.jwt)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
 
-		fmt.Printf("Sessions for project %s, episode %d:\n", prj.Title, episodeNumber)
-	} else {
-		reqBody := struct {
-			Limit     int    `json:"limit"`
-			ProjectID string `json:"project_id"`
-		}{
-			Limit:     limit,
-			ProjectID: projectID,
-		}
-		list, err = getThing(cfg, "/api/sessions", reqBody, list)
-		if err != nil {
-			return err
-		}
+	if resp.StatusCode != http.StatusOK {
+		return processErrorResponse(resp)
+	}
 
-		fmt.Printf("Sessions for project %s:\n", prj.Title)
+	err = processResponse(resp, &page)
+	if err != nil {
+		return err
 	}
 
-	for _, item := range list {
+	for _, item := range page.Sessions {
 		fmt.Printf("%s, %s, %s: ", item.SessionDate.Format(time.DateOnly), item.ActivityDone, item.PartWorkedOn)
 		for i, u := range item.Users {
 			if i > 0 {
@@ -240,6 +222,63 @@ func commandGetSessions(cfg *config, args []string) error {
 		}
 		fmt.Printf("\n")
 	}
+	fmt.Printf("Showing %d of %d sessions\n", len(page.Sessions), page.Total)
 
 	return nil
 }
+
+type sessionViolation struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+	Date     string `json:"date"`
+}
+
+func printViolations(violations []sessionViolation) {
+	for _, v := range violations {
+		fmt.Printf("%s: %s\n", v.Severity, v.Message)
+	}
+}
+
+func processViolationResponse(resp *http.Response) error {
+	// The server lists the rules a rejected session breaks
+	rejection := struct {
+		Error      string             `json:"error"`
+		Violations []sessionViolation `json:"violations"`
+	}{}
+	err := processResponse(resp, &rejection)
+	if err != nil {
+		return err
+	}
+	printViolations(rejection.Violations)
+	return fmt.Errorf(rejection.Error)
+}
+
+func commandGetSessionAnomalies(cfg *config, args []string) error {
+	// Lists sessions breaking the validation rules
+	// Takes optional from and to dates as arguments
+	reqBody := struct {
+		From string `json:"from"`
+		To   string `json:"to"`
+	}{}
+	if len(args) >= 1 {
+		reqBody.From = args[0]
+	}
+	if len(args) >= 2 {
+		reqBody.To = args[1]
+	}
+
+	list, err := getThing(cfg, "/api/reports/session-anomalies", reqBody, []sessionViolation{})
+	if err != nil {
+		return err
+	}
+
+	if len(list) == 0 {
+		fmt.Println("No anomalies found.")
+		return nil
+	}
+	for _, v := range list {
+		fmt.Printf("%s %-6s %-13s %s\n", v.Date, v.Severity, v.Rule, v.Message)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/Denisowiec/FoleyBookkeeper/internal/db"
+)
+
+// Parts and activities are managed the same way, so the commands share these helpers
+
+func createTaxonomyTerm(cfg *config, urlSuffix string, args []string) (db.Part, error) {
+	// Takes the code, optionally a display name and a billing weight
+	if len(args) < 1 {
+		return db.Part{}, fmt.Errorf("invalid number of arguments")
+	}
+	url := fmt.Sprintf("%s%s", cfg.serverAddress, urlSuffix)
+
+	reqBody := struct {
+		Code          string `json:"code"`
+		DisplayName   string `json:"display_name"`
+		BillingWeight string `json:"billing_weight"`
+	}{
+		Code: args[0],
+	}
+	if len(args) >= 2 {
+		reqBody.DisplayName = args[1]
+	}
+	if len(args) >= 3 {
+		reqBody.BillingWeight = args[2]
+	}
+
+	resp, err := sendRequest(reqBody, "POST", url, cfg.jwt)
+	if err != nil {
+		return db.Part{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return db.Part{}, processErrorResponse(resp)
+	}
+
+	// Parts and activities have the same fields
+	term := db.Part{}
+	err = processResponse(resp, &term)
+	return term, err
+}
+
+func printTaxonomy(cfg *config, urlSuffix string) error {
+	url := fmt.Sprintf("%s%s", cfg.serverAddress, urlSuffix)
+
+	resp, err := sendEmptyRequest("GET", url, cfg.jwt)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return processErrorResponse(resp)
+	}
+
+	var list []db.Part
+	err = processResponse(resp, &list)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range list {
+		fmt.Printf("%-12s %s", item.Code, item.DisplayName)
+		if item.BillingWeight.Valid {
+			fmt.Printf(", billing weight %s", item.BillingWeight.String)
+		}
+		if !item.Active {
+			fmt.Print(" (inactive)")
+		}
+		fmt.Println()
+	}
+	return nil
+}
+
+func commandCreatePart(cfg *config, args []string) error {
+	part, err := createTaxonomyTerm(cfg, "/api/parts", args)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Part %s created successfully\n", part.DisplayName)
+	return nil
+}
+
+func commandCreateActivity(cfg *config, args []string) error {
+	activity, err := createTaxonomyTerm(cfg, "/api/activities", args)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Activity %s created successfully\n", activity.DisplayName)
+	return nil
+}
+
+func commandGetParts(cfg *config, args []string) error {
+	return printTaxonomy(cfg, "/api/parts")
+}
+
+func commandGetActivities(cfg *config, args []string) error {
+	return printTaxonomy(cfg, "/api/activities")
+}
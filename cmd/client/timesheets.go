@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Denisowiec/FoleyBookkeeper/internal/db"
+)
+
+func commandSubmitWeek(cfg *config, args []string) error {
+	// Submits the user's sessions of a week for approval
+	// Takes any date within the week, defaults to the current week
+	reqBody := struct {
+		Week string `json:"week"`
+	}{}
+	if len(args) >= 1 {
+		reqBody.Week = args[0]
+	}
+
+	url := fmt.Sprintf("%s/api/timesheets", cfg.serverAddress)
+	resp, err := sendRequest(reqBody, "POST", url, cfg.jwt)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return processErrorResponse(resp)
+	}
+
+	timesheet := struct {
+		db.Timesheet
+		Sessions []db.Session `json:"sessions"`
+	}{}
+	err = processResponse(resp, &timesheet)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Submitted %d sessions for the week of %s\n", len(timesheet.Sessions), timesheet.WeekStart.Format(time.DateOnly))
+	return nil
+}
+
+func commandGetPendingTimesheets(cfg *config, args []string) error {
+	url := fmt.Sprintf("%s/api/timesheets/pending", cfg.serverAddress)
+
+	resp, err := sendEmptyRequest("GET", url, cfg.jwt)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return processErrorResponse(resp)
+	}
+
+	var list []db.GetPendingTimesheetsRow
+	err = processResponse(resp, &list)
+	if err != nil {
+		return err
+	}
+
+	if len(list) == 0 {
+		fmt.Println("No timesheets waiting for approval.")
+		return nil
+	}
+	for _, item := range list {
+		duration := time.Duration(item.Minutes) * time.Minute
+		fmt.Printf("%s  %-15s week of %s, %d sessions, %s\n", item.ID, item.Username, item.WeekStart.Format(time.DateOnly), item.SessionCount, duration)
+	}
+	return nil
+}
+
+func reviewTimesheet(cfg *config, args []string, action string) error {
+	// Takes the timesheet ID and a comment, which may be several words
+	if len(args) < 1 {
+		return fmt.Errorf("invalid number of arguments")
+	}
+	reqBody := struct {
+		Comment string `json:"comment"`
+	}{
+		Comment: strings.Join(args[1:], " "),
+	}
+
+	url := fmt.Sprintf("%s/api/timesheets/%s/%s", cfg.serverAddress, args[0], action)
+	resp, err := sendRequest(reqBody, "POST", url, cfg.jwt)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		return processErrorResponse(resp)
+	}
+
+	fmt.Printf("Timesheet %s reviewed successfully\n", args[0])
+	return nil
+}
+
+func commandApproveTimesheet(cfg *config, args []string) error {
+	return reviewTimesheet(cfg, args, "approve")
+}
+
+func commandRejectTimesheet(cfg *config, args []string) error {
+	return reviewTimesheet(cfg, args, "reject")
+}
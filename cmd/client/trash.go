@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Denisowiec/FoleyBookkeeper/internal/db"
+)
+
+type trashList struct {
+	RetentionDays int32            `json:"retention_days"`
+	Items         []db.GetTrashRow `json:"items"`
+}
+
+func getTrash(cfg *config) (trashList, error) {
+	url := fmt.Sprintf("%s/api/trash", cfg.serverAddress)
+	resp, err := sendEmptyRequest("GET", url, cfg.jwt)
+	if err != nil {
+		return trashList{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return trashList{}, processErrorResponse(resp)
+	}
+
+	trash := trashList{}
+	err = processResponse(resp, &trash)
+	return trash, err
+}
+
+func describeTrashItem(item db.GetTrashRow) string {
+	text := fmt.Sprintf("%s %s", item.Kind, item.Name)
+	if item.ProjectTitle.Valid {
+		text += fmt.Sprintf(" (%s)", item.ProjectTitle.String)
+	}
+	dependents := []string{}
+	for _, d := range []struct {
+		count int64
+		name  string
+	}{
+		{item.Projects, "projects"},
+		{item.Episodes, "episodes"},
+		{item.Sessions, "sessions"},
+		{item.Calculations, "calculations"},
+	} {
+		if d.count > 0 {
+			dependents = append(dependents, fmt.Sprintf("%d %s", d.count, d.name))
+		}
+	}
+	if len(dependents) > 0 {
+		text += ", with " + strings.Join(dependents, ", ")
+	}
+	return text
+}
+
+func commandGetTrash(cfg *config, args []string) error {
+	trash, err := getTrash(cfg)
+	if err != nil {
+		return err
+	}
+
+	if len(trash.Items) == 0 {
+		fmt.Println("The trash is empty")
+		return nil
+	}
+	fmt.Printf("Items are purged %d days after deletion\n", trash.RetentionDays)
+	for _, item := range trash.Items {
+		purgeOn := item.DeletedAt.AddDate(0, 0, int(trash.RetentionDays))
+		fmt.Printf("%s  %s, deleted %s, purged after %s\n", item.ID, describeTrashItem(item),
+			item.DeletedAt.Format(time.DateOnly), purgeOn.Format(time.DateOnly))
+	}
+	return nil
+}
+
+func commandRestore(cfg *config, args []string) error {
+	// Takes the kind of item (client, project, episode, session or calculation)
+	// and its ID or name as the trash lists it
+	if len(args) < 2 {
+		return fmt.Errorf("invalid number of arguments")
+	}
+	kind := strings.ToLower(args[0])
+	name := strings.Join(args[1:], " ")
+
+	trash, err := getTrash(cfg)
+	if err != nil {
+		return err
+	}
+	matches := []db.GetTrashRow{}
+	for _, item := range trash.Items {
+		if item.Kind == kind && (item.ID.String() == name || strings.EqualFold(item.Name, name)) {
+			matches = append(matches, item)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return fmt.Errorf("no %s %s in the trash", kind, name)
+	case 1:
+	default:
+		return fmt.Errorf("%d items named %s in the trash, restore one by its ID", len(matches), name)
+	}
+	item := matches[0]
+
+	url := fmt.Sprintf("%s/api/trash/%s/%s/restore", cfg.serverAddress, item.Kind, item.ID)
+	resp, err := sendEmptyRequest("POST", url, cfg.jwt)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		return processErrorResponse(resp)
+	}
+
+	fmt.Printf("Restored %s\n", describeTrashItem(item))
+	return nil
+}
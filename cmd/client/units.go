@@ -0,0 +1,168 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Denisowiec/FoleyBookkeeper/internal/db"
+	"github.com/Denisowiec/FoleyBookkeeper/internal/episodecode"
+	"github.com/google/uuid"
+)
+
+func getUnitsForProject(cfg *config, prj db.Project) ([]db.ProjectUnit, error) {
+	url := fmt.Sprintf("%s/api/projects/%s/units", cfg.serverAddress, prj.ID)
+	resp, err := sendEmptyRequest("GET", url, cfg.jwt)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, processErrorResponse(resp)
+	}
+
+	var list []db.ProjectUnit
+	err = processResponse(resp, &list)
+	return list, err
+}
+
+func getUnitByName(cfg *config, prj db.Project, name string) (db.ProjectUnit, error) {
+	// Unit names are matched case-insensitively, seasons can also be given
+	// by their number, e.g. S02
+	units, err := getUnitsForProject(cfg, prj)
+	if err != nil {
+		return db.ProjectUnit{}, err
+	}
+	for _, u := range units {
+		if strings.EqualFold(u.Name, name) {
+			return u, nil
+		}
+	}
+	if season, err := episodecode.ParseSeason(name); err == nil && strings.HasPrefix(strings.ToUpper(name), "S") {
+		for _, u := range units {
+			if u.Kind == db.UnitKindSeason && u.Number.Valid && int(u.Number.Int32) == season {
+				return u, nil
+			}
+		}
+	}
+	return db.ProjectUnit{}, fmt.Errorf("unit %s not found in %s", name, prj.Title)
+}
+
+// sessionLevel is where a session is logged: an episode, a unit or the
+// project itself
+type sessionLevel struct {
+	ProjectID string `json:"project_id"`
+	EpisodeID string `json:"episode_id,omitempty"`
+	UnitID    string `json:"unit_id,omitempty"`
+	label     string
+}
+
+func getSessionLevel(cfg *config, projectTitle, level string) (sessionLevel, error) {
+	// Takes an episode number or code like S02E05, a unit name, or - for the whole project
+	prj, err := getProjectByName(cfg, projectTitle)
+	if err != nil {
+		return sessionLevel{}, err
+	}
+	result := sessionLevel{ProjectID: prj.ID.String(), label: fmt.Sprintf("project %s", prj.Title)}
+	if level == "-" {
+		return result, nil
+	}
+	if episodecode.IsCode(level) {
+		ep, err := getEpisodeByNumber(cfg, projectTitle, level)
+		if err != nil {
+			return sessionLevel{}, err
+		}
+		result.EpisodeID = ep.ID.String()
+		result.label = fmt.Sprintf("project %s, episode %s", prj.Title, strings.ToUpper(level))
+		return result, nil
+	}
+	unit, err := getUnitByName(cfg, prj, level)
+	if err != nil {
+		return sessionLevel{}, err
+	}
+	result.UnitID = unit.ID.String()
+	result.label = fmt.Sprintf("project %s, %s %s", prj.Title, unit.Kind, unit.Name)
+	return result, nil
+}
+
+func commandCreateUnit(cfg *config, args []string) error {
+	// Takes project title, kind (season, reel or deliverable), the unit's name
+	// and optionally the name of the unit it belongs to
+	if len(args) < 3 {
+		return fmt.Errorf("invalid number of arguments")
+	}
+
+	prj, err := getProjectByName(cfg, args[0])
+	if err != nil {
+		return err
+	}
+
+	reqBody := struct {
+		Kind     string `json:"kind"`
+		Name     string `json:"name"`
+		ParentID string `json:"parent_id"`
+	}{
+		Kind: args[1],
+		Name: args[2],
+	}
+	if len(args) >= 4 {
+		parent, err := getUnitByName(cfg, prj, args[3])
+		if err != nil {
+			return err
+		}
+		reqBody.ParentID = parent.ID.String()
+	}
+
+	url := fmt.Sprintf("%s/api/projects/%s/units", cfg.serverAddress, prj.ID)
+	resp, err := sendRequest(reqBody, "POST", url, cfg.jwt)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return processErrorResponse(resp)
+	}
+
+	unit := db.ProjectUnit{}
+	err = processResponse(resp, &unit)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s %s created successfully\n", unit.Kind, unit.Name)
+	return nil
+}
+
+func commandGetUnits(cfg *config, args []string) error {
+	// Prints the units of a project as a tree
+	// Takes project title
+	if len(args) < 1 {
+		return fmt.Errorf("invalid number of arguments")
+	}
+
+	prj, err := getProjectByName(cfg, args[0])
+	if err != nil {
+		return err
+	}
+	units, err := getUnitsForProject(cfg, prj)
+	if err != nil {
+		return err
+	}
+
+	children := map[uuid.NullUUID][]db.ProjectUnit{}
+	for _, u := range units {
+		children[u.ParentID] = append(children[u.ParentID], u)
+	}
+	var printUnits func(parent uuid.NullUUID, depth int)
+	printUnits = func(parent uuid.NullUUID, depth int) {
+		for _, u := range children[parent] {
+			fmt.Printf("%s%-11s %s\n", strings.Repeat("  ", depth), u.Kind, u.Name)
+			printUnits(uuid.NullUUID{UUID: u.ID, Valid: true}, depth+1)
+		}
+	}
+	fmt.Printf("%s (%s)\n", prj.Title, prj.ProjectType)
+	printUnits(uuid.NullUUID{}, 1)
+	return nil
+}
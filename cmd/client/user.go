@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"net/http"
+	"os"
 	"time"
 
 	"github.com/Denisowiec/FoleyBookkeeper/internal/db"
@@ -101,11 +102,18 @@ func commandLogin(cfg *config, args []string) error {
 	type reqBodyType struct {
 		Email    string `json:"email"`
 		Password string `json:"password"`
+		Device   string `json:"device"`
 	}
 	reqBody := reqBodyType{
 		Email:    args[0],
 		Password: args[1],
 	}
+	// The device label shows up when listing logins, the host name by default
+	if len(args) > 2 {
+		reqBody.Device = args[2]
+	} else if host, err := os.Hostname(); err == nil {
+		reqBody.Device = host
+	}
 
 	resp, err := sendRequest(reqBody, "POST", url, "")
 	if err != nil {
@@ -119,6 +127,7 @@ func commandLogin(cfg *config, args []string) error {
 		UpdatedAt    time.Time `json:"updated_at"`
 		Username     string    `json:"username"`
 		Email        string    `json:"email"`
+		Role         string    `json:"role"`
 		JWT          string    `json:"jwt"`
 		RefreshToken string    `json:"refresh_token"`
 		Error        string    `json:"error"`
@@ -138,11 +147,12 @@ func commandLogin(cfg *config, args []string) error {
 
 	// We save the jwt in our cfg struct
 	cfg.jwt = loginResponse.JWT
+	cfg.refreshToken = loginResponse.RefreshToken
 	cfg.username = loginResponse.Username
 	cfg.email = loginResponse.Email
 	cfg.userID = loginResponse.ID
 
-	fmt.Printf("Logged in as %s.\n", loginResponse.Username)
+	fmt.Printf("Logged in as %s (%s).\n", loginResponse.Username, loginResponse.Role)
 
 	return nil
 }
@@ -264,6 +274,7 @@ func commandGetUserInfo(cfg *config, args []string) error {
 		UpdatedAt time.Time `json:"updated_at"`
 		Username  string    `json:"username"`
 		Email     string    `json:"email"`
+		Role      string    `json:"role"`
 		Error     string    `json:"error"`
 	}
 	respBody := getUserResponseType{}
@@ -276,8 +287,213 @@ func commandGetUserInfo(cfg *config, args []string) error {
 		return fmt.Errorf(respBody.Error)
 	}
 
-	fmt.Printf("UserID: %s\nCreated: %v\nUpdated: %v\nUsername: %s\nEmail: %s\n",
-		respBody.ID.String(), respBody.CreatedAt, respBody.UpdatedAt, respBody.Username, respBody.Email)
+	fmt.Printf("UserID: %s\nCreated: %v\nUpdated: %v\nUsername: %s\nEmail: %s\nRole: %s\n",
+		respBody.ID.String(), respBody.CreatedAt, respBody.UpdatedAt, respBody.Username, respBody.Email, respBody.Role)
+
+	return nil
+}
+
+func commandSetRole(cfg *config, args []string) error {
+	// Only admins are allowed to assign roles
+	if len(args) < 2 {
+		return fmt.Errorf("invalid number of arguments")
+	}
+	userID, err := getUserID(cfg, args[0])
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/api/users/%s/role", cfg.serverAddress, userID)
+	reqBody := struct {
+		Role string `json:"role"`
+	}{Role: args[1]}
+
+	resp, err := sendRequest(reqBody, "PUT", url, cfg.jwt)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		return processErrorResponse(resp)
+	}
+
+	user := db.User{}
+	err = processResponse(resp, &user)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s is now %s.\n", user.Username, user.Role)
+	return nil
+}
+
+func commandRefreshLogin(cfg *config, args []string) error {
+	// The refresh token works once, the server sends a new one with the JWT
+	if cfg.refreshToken == "" {
+		return fmt.Errorf("not logged in")
+	}
+	url := fmt.Sprintf("%s/api/refresh", cfg.serverAddress)
+	reqBody := struct {
+		RefreshToken string `json:"refresh_token"`
+	}{RefreshToken: cfg.refreshToken}
+
+	resp, err := sendRequest(reqBody, "POST", url, "")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		// A refused token won't work later either
+		cfg.jwt, cfg.refreshToken = "", ""
+		return processErrorResponse(resp)
+	}
+
+	respBody := struct {
+		JWT          string `json:"jwt"`
+		RefreshToken string `json:"refresh_token"`
+	}{}
+	err = processResponse(resp, &respBody)
+	if err != nil {
+		return err
+	}
+	cfg.jwt = respBody.JWT
+	cfg.refreshToken = respBody.RefreshToken
+
+	fmt.Printf("Login of %s refreshed.\n", cfg.username)
+	return nil
+}
+
+func logOutLocally(cfg *config) {
+	cfg.jwt = ""
+	cfg.refreshToken = ""
+	cfg.username = ""
+	cfg.email = ""
+	cfg.userID = uuid.UUID{}
+}
+
+func commandLogout(cfg *config, args []string) error {
+	if cfg.refreshToken == "" {
+		return fmt.Errorf("not logged in")
+	}
+	url := fmt.Sprintf("%s/api/logout", cfg.serverAddress)
+	reqBody := struct {
+		RefreshToken string `json:"refresh_token"`
+	}{RefreshToken: cfg.refreshToken}
+
+	resp, err := sendRequest(reqBody, "POST", url, "")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		return processErrorResponse(resp)
+	}
+
+	logOutLocally(cfg)
+	fmt.Println("Logged out.")
+	return nil
+}
+
+func commandLogoutEverywhere(cfg *config, args []string) error {
+	url := fmt.Sprintf("%s/api/logout-everywhere", cfg.serverAddress)
+	resp, err := sendEmptyRequest("POST", url, cfg.jwt)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		return processErrorResponse(resp)
+	}
+
+	respBody := struct {
+		Revoked int `json:"revoked"`
+	}{}
+	err = processResponse(resp, &respBody)
+	if err != nil {
+		return err
+	}
+
+	logOutLocally(cfg)
+	fmt.Printf("Logged out of %d logins.\n", respBody.Revoked)
+	return nil
+}
+
+func getLoginsOwner(cfg *config, args []string) (string, string, error) {
+	// Logins of another user, by name, or our own
+	if len(args) < 1 {
+		return cfg.userID.String(), cfg.username, nil
+	}
+	id, err := getUserID(cfg, args[0])
+	return id, args[0], err
+}
+
+func commandListLogins(cfg *config, args []string) error {
+	userID, username, err := getLoginsOwner(cfg, args)
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/api/users/%s/logins", cfg.serverAddress, userID)
+	resp, err := sendEmptyRequest("GET", url, cfg.jwt)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return processErrorResponse(resp)
+	}
+
+	logins := []db.GetActiveLoginsRow{}
+	err = processResponse(resp, &logins)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Active logins of %s:\n", username)
+	for _, l := range logins {
+		device := l.DeviceLabel.String
+		if device == "" {
+			device = "unnamed device"
+		}
+		fmt.Printf("  %s: %s, logged in %s, last used %s",
+			l.FamilyID, device, l.LoggedInAt.Format(time.DateTime), l.LastUsedAt.Format(time.DateTime))
+		if l.IpAddress.Valid {
+			fmt.Printf(" from %s", l.IpAddress.String)
+		}
+		if l.UserAgent.Valid {
+			fmt.Printf(" (%s)", l.UserAgent.String)
+		}
+		fmt.Println()
+	}
+	return nil
+}
+
+func commandRevokeLogin(cfg *config, args []string) error {
+	// Takes the login ID from the list and optionally the username
+	if len(args) < 1 {
+		return fmt.Errorf("invalid number of arguments")
+	}
+	userID, username, err := getLoginsOwner(cfg, args[1:])
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/api/users/%s/logins/%s", cfg.serverAddress, userID, args[0])
+	resp, err := sendEmptyRequest("DELETE", url, cfg.jwt)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		return processErrorResponse(resp)
+	}
 
+	fmt.Printf("Login %s of %s revoked.\n", args[0], username)
 	return nil
 }
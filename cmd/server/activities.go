@@ -0,0 +1,217 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Denisowiec/FoleyBookkeeper/internal/db"
+	"github.com/google/uuid"
+)
+
+func (cfg *apiConfig) handlerCreateActivity(w http.ResponseWriter, r *http.Request) {
+	// Adds a new kind of work sessions can be logged as, e.g. premix
+	// The code is what sessions refer to and can't be changed later
+	_, _, err := authenticateUser(r, cfg.secret)
+	if err != nil {
+		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+		return
+	}
+
+	activityInput := taxonomyInputType{}
+	decoder := json.NewDecoder(r.Body)
+	err = decoder.Decode(&activityInput)
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+
+	code := strings.ToLower(strings.TrimSpace(activityInput.Code))
+	if code == "" {
+		respondWithError(w, "Activity code required", http.StatusBadRequest, nil)
+		return
+	}
+	if activityInput.DisplayName == "" {
+		activityInput.DisplayName = code
+	}
+	weight, err := parseBillingWeight(activityInput.BillingWeight.Value)
+	if err != nil {
+		respondWithError(w, "Invalid billing weight", http.StatusBadRequest, err)
+		return
+	}
+
+	var advancesTo db.NullProductionStatus
+	if activityInput.AdvancesTo != nil {
+		advancesTo, err = advancesToFromInput(*activityInput.AdvancesTo)
+		if err != nil {
+			respondWithError(w, err.Error(), http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	var sortOrder int32
+	if activityInput.SortOrder != nil {
+		sortOrder = *activityInput.SortOrder
+	}
+
+	createActivityParams := db.CreateActivityParams{
+		Code:          code,
+		DisplayName:   activityInput.DisplayName,
+		SortOrder:     sortOrder,
+		Active:        activityInput.Active == nil || *activityInput.Active,
+		BillingWeight: weight,
+		AdvancesTo:    advancesTo,
+	}
+	activity, err := cfg.db.CreateActivity(r.Context(), createActivityParams)
+	if err != nil {
+		respondWithError(w, "Error creating activity", http.StatusInternalServerError, err)
+		return
+	}
+
+	err = respondWithJSON(w, http.StatusCreated, activity)
+	if err != nil {
+		respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
+		return
+	}
+}
+
+func (cfg *apiConfig) handlerUpdateActivity(w http.ResponseWriter, r *http.Request) {
+	// Changes the display name, ordering, active flag, billing weight or the
+	// production status an activity advances parts to
+	// Fields not given keep their values
+	_, _, err := authenticateUser(r, cfg.secret)
+	if err != nil {
+		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+		return
+	}
+
+	activityID, err := uuid.Parse(r.PathValue("activityid"))
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+
+	activity, err := cfg.db.GetActivityByID(r.Context(), activityID)
+	if err != nil {
+		respondWithError(w, "Activity not found", http.StatusNotFound, err)
+		return
+	}
+
+	activityInput := taxonomyInputType{}
+	decoder := json.NewDecoder(r.Body)
+	err = decoder.Decode(&activityInput)
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+
+	updateActivityParams := db.UpdateActivityParams{
+		ID:            activity.ID,
+		DisplayName:   activity.DisplayName,
+		SortOrder:     activity.SortOrder,
+		Active:        activity.Active,
+		BillingWeight: activity.BillingWeight,
+		AdvancesTo:    activity.AdvancesTo,
+	}
+	if activityInput.DisplayName != "" {
+		updateActivityParams.DisplayName = activityInput.DisplayName
+	}
+	if activityInput.SortOrder != nil {
+		updateActivityParams.SortOrder = *activityInput.SortOrder
+	}
+	if activityInput.Active != nil {
+		updateActivityParams.Active = *activityInput.Active
+	}
+	// Null or an empty value takes the weight away
+	if activityInput.BillingWeight.Set {
+		updateActivityParams.BillingWeight, err = parseBillingWeight(activityInput.BillingWeight.Value)
+		if err != nil {
+			respondWithError(w, "Invalid billing weight", http.StatusBadRequest, err)
+			return
+		}
+	}
+	if activityInput.AdvancesTo != nil {
+		updateActivityParams.AdvancesTo, err = advancesToFromInput(*activityInput.AdvancesTo)
+		if err != nil {
+			respondWithError(w, err.Error(), http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	activity, err = cfg.db.UpdateActivity(r.Context(), updateActivityParams)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+
+	err = respondWithJSON(w, http.StatusAccepted, activity)
+	if err != nil {
+		respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
+		return
+	}
+}
+
+func (cfg *apiConfig) handlerGetActivities(w http.ResponseWriter, r *http.Request) {
+	// Lists all activities, inactive ones included, in their display order
+	_, _, err := authenticateUser(r, cfg.secret)
+	if err != nil {
+		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+		return
+	}
+
+	list, err := cfg.db.GetAllActivities(r.Context())
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+
+	err = respondWithJSON(w, http.StatusOK, list)
+	if err != nil {
+		respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
+		return
+	}
+}
+
+func (cfg *apiConfig) handlerDeleteActivity(w http.ResponseWriter, r *http.Request) {
+	// Activities already used by sessions can't be deleted, only deactivated
+	_, _, err := authenticateUser(r, cfg.secret)
+	if err != nil {
+		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+		return
+	}
+
+	activityID, err := uuid.Parse(r.PathValue("activityid"))
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+
+	activity, err := cfg.db.GetActivityByID(r.Context(), activityID)
+	if err != nil {
+		respondWithError(w, "Activity not found", http.StatusNotFound, err)
+		return
+	}
+
+	count, err := cfg.db.CountSessionsForActivity(r.Context(), activity.Code)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+	if count > 0 {
+		respondWithError(w, fmt.Sprintf("Activity is used by %d sessions, deactivate it instead", count), http.StatusConflict, nil)
+		return
+	}
+
+	activity, err = cfg.db.DeleteActivity(r.Context(), activityID)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+
+	err = respondWithJSON(w, http.StatusAccepted, activity)
+	if err != nil {
+		respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
+		return
+	}
+}
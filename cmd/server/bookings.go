@@ -0,0 +1,360 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/Denisowiec/FoleyBookkeeper/internal/db"
+	"github.com/google/uuid"
+)
+
+// Bookings are planned studio time, as opposed to sessions which record
+// the work that was actually done. Times are given in the studio's local time.
+const bookingTimeLayout = "2006-01-02 15:04"
+
+type bookingInputType struct {
+	Title     string `json:"title"`
+	StartsAt  string `json:"starts_at"`
+	EndsAt    string `json:"ends_at"`
+	RoomID    string `json:"room_id"`
+	ProjectID string `json:"project_id"`
+	EpisodeID string `json:"episode_id"`
+	Notes     string `json:"notes"`
+}
+
+func parseBookingInput(input bookingInputType) (db.CreateBookingParams, error) {
+	// Converts the json input into the parameters of a booking
+	params := db.CreateBookingParams{}
+	var err error
+
+	if input.Title == "" {
+		return params, fmt.Errorf("booking title required")
+	}
+	params.Title = input.Title
+
+	params.StartsAt, err = time.Parse(bookingTimeLayout, input.StartsAt)
+	if err != nil {
+		return params, err
+	}
+	params.EndsAt, err = time.Parse(bookingTimeLayout, input.EndsAt)
+	if err != nil {
+		return params, err
+	}
+	if !params.EndsAt.After(params.StartsAt) {
+		return params, fmt.Errorf("booking has to end after it starts")
+	}
+
+	params.ProjectID, err = uuid.Parse(input.ProjectID)
+	if err != nil {
+		return params, err
+	}
+	params.RoomID, err = parseNullUUID(input.RoomID)
+	if err != nil {
+		return params, err
+	}
+	params.EpisodeID, err = parseNullUUID(input.EpisodeID)
+	if err != nil {
+		return params, err
+	}
+	params.Notes = sql.NullString{String: input.Notes, Valid: input.Notes != ""}
+
+	return params, nil
+}
+
+func (cfg *apiConfig) handlerCreateBooking(w http.ResponseWriter, r *http.Request) {
+	_, _, err := authenticateUser(r, cfg.secret)
+	if err != nil {
+		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+		return
+	}
+
+	bookingInput := bookingInputType{}
+	decoder := json.NewDecoder(r.Body)
+	err = decoder.Decode(&bookingInput)
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+
+	createBookingParams, err := parseBookingInput(bookingInput)
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+	if !cfg.requireProjectContribution(w, r, createBookingParams.ProjectID) {
+		return
+	}
+
+	booking, err := cfg.db.CreateBooking(r.Context(), createBookingParams)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+
+	err = respondWithJSON(w, http.StatusCreated, booking)
+	if err != nil {
+		respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
+		return
+	}
+}
+
+func (cfg *apiConfig) handlerUpdateBooking(w http.ResponseWriter, r *http.Request) {
+	_, _, err := authenticateUser(r, cfg.secret)
+	if err != nil {
+		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+		return
+	}
+
+	bookingID, err := uuid.Parse(r.PathValue("bookingid"))
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+
+	bookingInput := bookingInputType{}
+	decoder := json.NewDecoder(r.Body)
+	err = decoder.Decode(&bookingInput)
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+
+	params, err := parseBookingInput(bookingInput)
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+
+	// Moving a booking to another project takes being able to work on both
+	old, err := cfg.db.GetBooking(r.Context(), bookingID)
+	if err != nil {
+		respondWithError(w, "Booking not found", http.StatusNotFound, err)
+		return
+	}
+	if !cfg.requireProjectContribution(w, r, old.ProjectID, params.ProjectID) {
+		return
+	}
+
+	updateBookingParams := db.UpdateBookingParams{
+		ID:        bookingID,
+		Title:     params.Title,
+		StartsAt:  params.StartsAt,
+		EndsAt:    params.EndsAt,
+		RoomID:    params.RoomID,
+		ProjectID: params.ProjectID,
+		EpisodeID: params.EpisodeID,
+		Notes:     params.Notes,
+	}
+
+	booking, err := cfg.db.UpdateBooking(r.Context(), updateBookingParams)
+	if err != nil {
+		respondWithError(w, "Booking not found", http.StatusNotFound, err)
+		return
+	}
+
+	err = respondWithJSON(w, http.StatusAccepted, booking)
+	if err != nil {
+		respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
+		return
+	}
+}
+
+func (cfg *apiConfig) handlerAddUsersToBooking(w http.ResponseWriter, r *http.Request) {
+	_, _, err := authenticateUser(r, cfg.secret)
+	if err != nil {
+		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+		return
+	}
+
+	type addUsersToBookingInputType struct {
+		UserIDs []string `json:"user_ids"`
+	}
+	input := addUsersToBookingInputType{}
+
+	decoder := json.NewDecoder(r.Body)
+	err = decoder.Decode(&input)
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+
+	bookingID, err := uuid.Parse(r.PathValue("bookingid"))
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+	booking, err := cfg.db.GetBooking(r.Context(), bookingID)
+	if err != nil {
+		respondWithError(w, "Booking not found", http.StatusNotFound, err)
+		return
+	}
+	if !cfg.requireProjectContribution(w, r, booking.ProjectID) {
+		return
+	}
+
+	for _, user := range input.UserIDs {
+		id, err := uuid.Parse(user)
+		if err != nil {
+			respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+			return
+		}
+		addUserParams := db.AddUserToBookingParams{
+			UserID:    id,
+			BookingID: bookingID,
+		}
+		_, err = cfg.db.AddUserToBooking(r.Context(), addUserParams)
+		if err != nil {
+			respondWithError(w, "Error adding user to booking", http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	err = respondWithJSON(w, http.StatusAccepted, input)
+	if err != nil {
+		respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
+		return
+	}
+}
+
+func (cfg *apiConfig) handlerGetBooking(w http.ResponseWriter, r *http.Request) {
+	_, _, err := authenticateUser(r, cfg.secret)
+	if err != nil {
+		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+		return
+	}
+
+	bookingID, err := uuid.Parse(r.PathValue("bookingid"))
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+
+	booking, err := cfg.db.GetBooking(r.Context(), bookingID)
+	if err != nil {
+		respondWithError(w, "Booking not found", http.StatusNotFound, err)
+		return
+	}
+	if !cfg.requireProjectVisible(w, r, booking.ProjectID) {
+		return
+	}
+
+	users, err := cfg.db.GetUsersForBooking(r.Context(), bookingID)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+
+	getBookingResp := struct {
+		db.Booking
+		Users []db.GetUsersForBookingRow `json:"users"`
+	}{
+		Booking: booking,
+		Users:   users,
+	}
+
+	err = respondWithJSON(w, http.StatusOK, getBookingResp)
+	if err != nil {
+		respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
+		return
+	}
+}
+
+func (cfg *apiConfig) handlerGetBookings(w http.ResponseWriter, r *http.Request) {
+	// Returns bookings of the user's projects overlapping the given date
+	// range. Without input it returns the bookings for the next four weeks
+	_, _, err := authenticateUser(r, cfg.secret)
+	if err != nil {
+		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+		return
+	}
+
+	reqInput := struct {
+		From string `json:"from"`
+		To   string `json:"to"`
+	}{}
+
+	decoder := json.NewDecoder(r.Body)
+	err = decoder.Decode(&reqInput)
+	if err != nil && err != io.EOF {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+
+	from := time.Now().Truncate(24 * time.Hour)
+	if reqInput.From != "" {
+		from, err = time.Parse(time.DateOnly, reqInput.From)
+		if err != nil {
+			respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+			return
+		}
+	}
+	to := from.AddDate(0, 0, 28)
+	if reqInput.To != "" {
+		to, err = time.Parse(time.DateOnly, reqInput.To)
+		if err != nil {
+			respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+			return
+		}
+		// The end date is inclusive
+		to = to.AddDate(0, 0, 1)
+	}
+
+	getBookingsParams := db.GetBookingsInRangeParams{
+		EndsAt:   from,
+		StartsAt: to,
+	}
+	list, err := cfg.db.GetBookingsInRange(r.Context(), getBookingsParams)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+	access, err := cfg.requestProjectAccess(r)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+
+	err = respondWithJSON(w, http.StatusOK, access.visibleBookings(list))
+	if err != nil {
+		respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
+		return
+	}
+}
+
+func (cfg *apiConfig) handlerDeleteBooking(w http.ResponseWriter, r *http.Request) {
+	_, _, err := authenticateUser(r, cfg.secret)
+	if err != nil {
+		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+		return
+	}
+
+	bookingID, err := uuid.Parse(r.PathValue("bookingid"))
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+	booking, err := cfg.db.GetBooking(r.Context(), bookingID)
+	if err != nil {
+		respondWithError(w, "Booking not found", http.StatusNotFound, err)
+		return
+	}
+	if !cfg.requireProjectContribution(w, r, booking.ProjectID) {
+		return
+	}
+
+	booking, err = cfg.db.DeleteBooking(r.Context(), bookingID)
+	if err != nil {
+		respondWithError(w, "Booking not found", http.StatusNotFound, err)
+		return
+	}
+
+	err = respondWithJSON(w, http.StatusAccepted, booking)
+	if err != nil {
+		respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
+		return
+	}
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Denisowiec/FoleyBookkeeper/internal/db"
+	"github.com/Denisowiec/FoleyBookkeeper/internal/episodecode"
+	"github.com/google/uuid"
+)
+
+// More than that in one go is most likely a typo
+const maxBulkEpisodes = 500
+
+func expandTitlePattern(pattern string, code episodecode.Code) string {
+	// Patterns can use {n}, {nn} and {nnn} for the episode number, zero-padded
+	// to the given width, and {code} for the whole code, e.g. S02E05
+	replacer := strings.NewReplacer(
+		"{nnn}", fmt.Sprintf("%03d", code.Episode),
+		"{nn}", fmt.Sprintf("%02d", code.Episode),
+		"{n}", strconv.Itoa(code.Episode),
+		"{code}", code.String(),
+	)
+	return strings.TrimSpace(replacer.Replace(pattern))
+}
+
+func parseTitlesCSV(text string, first int) (map[int]string, error) {
+	// Rows are either "number,title" or just a title, which then goes to the
+	// episode after the previous row's. A header row is skipped
+	reader := csv.NewReader(strings.NewReader(text))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	titles := map[int]string{}
+	next := first
+	for row := 1; ; row++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(record) == 0 || (len(record) == 1 && strings.TrimSpace(record[0]) == "") {
+			continue
+		}
+		if len(record) == 1 {
+			titles[next] = strings.TrimSpace(record[0])
+			next++
+			continue
+		}
+		number, err := strconv.Atoi(strings.TrimSpace(record[0]))
+		if err != nil {
+			if row == 1 {
+				continue
+			}
+			return nil, fmt.Errorf("row %d: invalid episode number %s", row, record[0])
+		}
+		titles[number] = strings.TrimSpace(record[1])
+		next = number + 1
+	}
+	return titles, nil
+}
+
+func (cfg *apiConfig) handlerCreateEpisodes(w http.ResponseWriter, r *http.Request) {
+	// Creates a range of episodes of a project, optionally in a season. Titles
+	// come from the CSV, or from the title pattern for episodes not in it.
+	// Either all episodes get created or none do
+	_, _, err := authenticateUser(r, cfg.secret)
+	if err != nil {
+		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+		return
+	}
+
+	projectID, err := uuid.Parse(r.PathValue("projectid"))
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+	_, err = cfg.db.GetProjectByID(r.Context(), projectID)
+	if err != nil {
+		respondWithError(w, "Project not found", http.StatusNotFound, err)
+		return
+	}
+
+	bulkInput := struct {
+		Season       string `json:"season"`
+		From         int    `json:"from"`
+		To           int    `json:"to"`
+		TitlePattern string `json:"title_pattern"`
+		TitlesCSV    string `json:"titles_csv"`
+		episodeMetadataInput
+	}{}
+	decoder := json.NewDecoder(r.Body)
+	err = decoder.Decode(&bulkInput)
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+
+	if bulkInput.From < 1 || bulkInput.To < bulkInput.From {
+		respondWithError(w, "Invalid range of episodes", http.StatusBadRequest, nil)
+		return
+	}
+	if bulkInput.To-bulkInput.From+1 > maxBulkEpisodes {
+		respondWithError(w, fmt.Sprintf("At most %d episodes can be created at once", maxBulkEpisodes), http.StatusBadRequest, nil)
+		return
+	}
+	seasonNumber := 0
+	seasonID := uuid.NullUUID{}
+	if bulkInput.Season != "" {
+		seasonNumber, err = episodecode.ParseSeason(bulkInput.Season)
+		if err != nil {
+			respondWithError(w, err.Error(), http.StatusBadRequest, err)
+			return
+		}
+		season, err := cfg.getSeason(r.Context(), projectID, seasonNumber)
+		if err != nil {
+			respondWithError(w, err.Error(), http.StatusNotFound, err)
+			return
+		}
+		seasonID = uuid.NullUUID{UUID: season.ID, Valid: true}
+	}
+	titles := map[int]string{}
+	if bulkInput.TitlesCSV != "" {
+		titles, err = parseTitlesCSV(bulkInput.TitlesCSV, bulkInput.From)
+		if err != nil {
+			respondWithError(w, err.Error(), http.StatusBadRequest, err)
+			return
+		}
+	}
+	metadata, err := parseEpisodeMetadata(bulkInput.episodeMetadataInput, episodeMetadata{})
+	if err != nil {
+		respondWithError(w, err.Error(), http.StatusBadRequest, err)
+		return
+	}
+
+	// Existing episodes in the range stop the whole batch
+	episodes, err := cfg.getProjectEpisodes(r.Context(), projectID)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+	taken := []string{}
+	for _, ep := range episodes.list {
+		if ep.SeasonID == seasonID && int(ep.EpisodeNumber) >= bulkInput.From && int(ep.EpisodeNumber) <= bulkInput.To {
+			taken = append(taken, episodes.code(ep).String())
+		}
+	}
+	if len(taken) > 0 {
+		respondWithError(w, fmt.Sprintf("Episodes %s already exist", strings.Join(taken, ", ")), http.StatusConflict, nil)
+		return
+	}
+
+	tx, err := cfg.conn.BeginTx(r.Context(), nil)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+	defer tx.Rollback()
+	qtx := cfg.db.WithTx(tx)
+
+	created := []db.Episode{}
+	for number := bulkInput.From; number <= bulkInput.To; number++ {
+		title, ok := titles[number]
+		if !ok && bulkInput.TitlePattern != "" {
+			title = expandTitlePattern(bulkInput.TitlePattern, episodecode.Code{Season: seasonNumber, Episode: number})
+		}
+		createEpisodeParams := db.CreateEpisodeParams{
+			Title:          sql.NullString{String: title, Valid: title != ""},
+			EpisodeNumber:  int32(number),
+			ProjectID:      projectID,
+			SeasonID:       seasonID,
+			RuntimeSeconds: metadata.RuntimeSeconds,
+			PictureLock:    metadata.PictureLock,
+			FrameRate:      metadata.FrameRate,
+		}
+		ep, err := qtx.CreateEpisode(r.Context(), createEpisodeParams)
+		if err != nil {
+			respondWithError(w, "Error creating episode", http.StatusInternalServerError, err)
+			return
+		}
+		created = append(created, ep)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+
+	err = respondWithJSON(w, http.StatusCreated, created)
+	if err != nil {
+		respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
+		return
+	}
+}
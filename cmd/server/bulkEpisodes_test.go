@@ -0,0 +1,77 @@
+package main
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/Denisowiec/FoleyBookkeeper/internal/episodecode"
+)
+
+func TestExpandTitlePattern(t *testing.T) {
+	code := episodecode.Code{Season: 2, Episode: 5}
+	cases := map[string]string{
+		"Episode {n}":        "Episode 5",
+		"Ep{nn}":             "Ep05",
+		"{nnn} - untitled":   "005 - untitled",
+		"Cartoon {code}":     "Cartoon S02E05",
+		"No placeholders   ": "No placeholders",
+	}
+	for pattern, want := range cases {
+		if got := expandTitlePattern(pattern, code); got != want {
+			t.Errorf("%q: expected %q, got %q", pattern, want, got)
+		}
+	}
+}
+
+func TestParseTitlesCSV(t *testing.T) {
+	titles, err := parseTitlesCSV("number,title\n3,The Egg\n5,\"Rain, Again\"\nThe Kite\n", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if titles[3] != "The Egg" || titles[5] != "Rain, Again" || titles[6] != "The Kite" || len(titles) != 3 {
+		t.Errorf("titles parsed incorrectly: %v", titles)
+	}
+
+	titles, err = parseTitlesCSV("Pilot\n\nSecond\n", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if titles[10] != "Pilot" || titles[11] != "Second" {
+		t.Errorf("titles without numbers should follow the first episode: %v", titles)
+	}
+
+	_, err = parseTitlesCSV("1,One\nx,Two\n", 1)
+	if err == nil {
+		t.Error("expected an error for an invalid number after the first row")
+	}
+}
+
+func TestParseRuntime(t *testing.T) {
+	cases := map[string]int32{
+		"22":       22 * 60,
+		"22m30s":   22*60 + 30,
+		"22:30":    22*60 + 30,
+		"01:02:03": 3723,
+	}
+	for input, want := range cases {
+		got, err := parseRuntime(input)
+		if err != nil || got != want {
+			t.Errorf("%q: expected %d, got %d, %v", input, want, got, err)
+		}
+	}
+	for _, input := range []string{"", "0", "22:75", "soon", "-5m"} {
+		if _, err := parseRuntime(input); err == nil {
+			t.Errorf("%q: expected an error", input)
+		}
+	}
+}
+
+func TestWorkPerRuntimeMinute(t *testing.T) {
+	ratio := workPerRuntimeMinute(660, sql.NullInt32{Int32: 22 * 60, Valid: true})
+	if ratio == nil || *ratio != 30 {
+		t.Errorf("expected 30 minutes per runtime minute, got %v", ratio)
+	}
+	if workPerRuntimeMinute(660, sql.NullInt32{}) != nil {
+		t.Error("episodes without a runtime should have no ratio")
+	}
+}
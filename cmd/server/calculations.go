@@ -10,6 +10,9 @@ import (
 )
 
 func (cfg *apiConfig) handlerCreateCalculation(w http.ResponseWriter, r *http.Request) {
+	// A calculation can be made for a unit, e.g. a season. It's added to the
+	// calculation right away and its defaults fill in the values not given.
+	// With whole_project set it counts every session of the project instead
 	_, _, err := authenticateUser(r, cfg.secret)
 	if err != nil {
 		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
@@ -21,6 +24,8 @@ func (cfg *apiConfig) handlerCreateCalculation(w http.ResponseWriter, r *http.Re
 		Budget       string `json:"budget"`
 		Currency     string `json:"currency"`
 		ExchangeRate string `json:"exchange_rate"`
+		UnitID       string `json:"unit_id"`
+		WholeProject bool   `json:"whole_project"`
 	}{}
 
 	decoder := json.NewDecoder(r.Body)
@@ -38,6 +43,56 @@ func (cfg *apiConfig) handlerCreateCalculation(w http.ResponseWriter, r *http.Re
 		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
 		return
 	}
+	if !cfg.requireProjectVisible(w, r, projectID) {
+		return
+	}
+	err = cfg.checkProjectAcceptsWork(r.Context(), projectID)
+	if err != nil {
+		respondWithError(w, err.Error(), http.StatusConflict, err)
+		return
+	}
+	unitID, err := parseNullUUID(calcInput.UnitID)
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+	if unitID.Valid && calcInput.WholeProject {
+		respondWithError(w, "A calculation covers either a unit or the whole project", http.StatusBadRequest, nil)
+		return
+	}
+	if unitID.Valid {
+		unit, err := cfg.db.GetProjectUnitByID(r.Context(), unitID.UUID)
+		if err != nil || unit.ProjectID != projectID {
+			respondWithError(w, "Unit not found in the calculation's project", http.StatusBadRequest, err)
+			return
+		}
+		if calcInput.Budget == "" {
+			calcInput.Budget = unit.Budget.String
+		}
+		if calcInput.Currency == "" {
+			calcInput.Currency = unit.Currency.String
+		}
+		if calcInput.ExchangeRate == "" {
+			calcInput.ExchangeRate = unit.ExchangeRate.String
+		}
+	}
+	if calcInput.Currency == "" {
+		// Without a currency from the input or the unit we bill in the client's
+		prj, err := cfg.db.GetProjectByID(r.Context(), projectID)
+		if err != nil {
+			respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+			return
+		}
+		client, err := cfg.db.GetClientByID(r.Context(), prj.ClientID)
+		if err != nil {
+			respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+			return
+		}
+		calcInput.Currency = client.DefaultCurrency.String
+		if calcInput.Currency == "" {
+			calcInput.Currency = "PLN"
+		}
+	}
 	var budget decimal.Decimal
 	if calcInput.Budget != "" {
 		budget, err = decimal.NewFromString(calcInput.Budget)
@@ -65,9 +120,35 @@ func (cfg *apiConfig) handlerCreateCalculation(w http.ResponseWriter, r *http.Re
 		Budget:       budget.String(),
 		Currency:     currency,
 		ExchangeRate: exchangeRate.String(),
+		WholeProject: calcInput.WholeProject,
 	}
 
-	calc, err := cfg.db.CreateCalculation(r.Context(), createCalcParams)
+	tx, err := cfg.conn.BeginTx(r.Context(), nil)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+	defer tx.Rollback()
+	qtx := cfg.db.WithTx(tx)
+
+	calc, err := qtx.CreateCalculation(r.Context(), createCalcParams)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+	if unitID.Valid {
+		addUnitParams := db.AddUnitToCalculationParams{
+			UnitID: unitID.UUID,
+			CalcID: calc.ID,
+		}
+		_, err = qtx.AddUnitToCalculation(r.Context(), addUnitParams)
+		if err != nil {
+			respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	err = tx.Commit()
 	if err != nil {
 		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
 		return
@@ -90,6 +171,8 @@ func (cfg *apiConfig) handlerCreateCalculation(w http.ResponseWriter, r *http.Re
 }*/
 
 func (cfg *apiConfig) handlerAddEpisodesToCalculation(w http.ResponseWriter, r *http.Request) {
+	// Adds an episode or a unit to a calculation. Units bring the units below
+	// them along
 	_, _, err := authenticateUser(r, cfg.secret)
 	if err != nil {
 		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
@@ -98,6 +181,7 @@ func (cfg *apiConfig) handlerAddEpisodesToCalculation(w http.ResponseWriter, r *
 
 	addEppsInput := struct {
 		EpisodeID string `json:"episode_id"`
+		UnitID    string `json:"unit_id"`
 	}{}
 
 	decoder := json.NewDecoder(r.Body)
@@ -107,28 +191,68 @@ func (cfg *apiConfig) handlerAddEpisodesToCalculation(w http.ResponseWriter, r *
 		return
 	}
 
-	episodeID, err := uuid.Parse(addEppsInput.EpisodeID)
+	calcID, err := uuid.Parse(r.PathValue("calcid"))
 	if err != nil {
 		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
 		return
 	}
-	calcID, err := uuid.Parse(r.PathValue("calcid"))
+	calc, err := cfg.db.GetCalculation(r.Context(), calcID)
 	if err != nil {
-		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		respondWithError(w, "Calculation not found", http.StatusNotFound, err)
 		return
 	}
-
-	addEppsParams := db.AddEpisodeToCalculationParams{
-		EpisodeID: episodeID,
-		CalcID:    calcID,
+	if !cfg.requireProjectVisible(w, r, calc.ProjectID) {
+		return
 	}
-
-	ret, err := cfg.db.AddEpisodeToCalculation(r.Context(), addEppsParams)
+	err = cfg.checkProjectAcceptsWork(r.Context(), calc.ProjectID)
 	if err != nil {
-		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		respondWithError(w, err.Error(), http.StatusConflict, err)
 		return
 	}
 
+	var ret any
+	if addEppsInput.UnitID != "" {
+		unitID, err := uuid.Parse(addEppsInput.UnitID)
+		if err != nil {
+			respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+			return
+		}
+		unit, err := cfg.db.GetProjectUnitByID(r.Context(), unitID)
+		if err != nil || unit.ProjectID != calc.ProjectID {
+			respondWithError(w, "Unit not found in the calculation's project", http.StatusBadRequest, err)
+			return
+		}
+		addUnitParams := db.AddUnitToCalculationParams{
+			UnitID: unitID,
+			CalcID: calcID,
+		}
+		ret, err = cfg.db.AddUnitToCalculation(r.Context(), addUnitParams)
+		if err != nil {
+			respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+			return
+		}
+	} else {
+		episodeID, err := uuid.Parse(addEppsInput.EpisodeID)
+		if err != nil {
+			respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+			return
+		}
+		ep, err := cfg.db.GetEpisodeByID(r.Context(), episodeID)
+		if err != nil || ep.ProjectID != calc.ProjectID {
+			respondWithError(w, "Episode not found in the calculation's project", http.StatusBadRequest, err)
+			return
+		}
+		addEppsParams := db.AddEpisodeToCalculationParams{
+			EpisodeID: episodeID,
+			CalcID:    calcID,
+		}
+		ret, err = cfg.db.AddEpisodeToCalculation(r.Context(), addEppsParams)
+		if err != nil {
+			respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+			return
+		}
+	}
+
 	err = respondWithJSON(w, http.StatusAccepted, ret)
 	if err != nil {
 		respondWithError(w, "Error processing return data", http.StatusInternalServerError, err)
@@ -154,6 +278,9 @@ func (cfg *apiConfig) handlerGetCalculation(w http.ResponseWriter, r *http.Reque
 		respondWithError(w, "Calculation not found", http.StatusBadRequest, err)
 		return
 	}
+	if !cfg.requireProjectVisible(w, r, calc.ProjectID) {
+		return
+	}
 
 	minutes, err := cfg.db.GetMinutesForCalculation(r.Context(), calcID)
 	if err != nil {
@@ -197,3 +324,39 @@ func (cfg *apiConfig) handlerGetCalculation(w http.ResponseWriter, r *http.Reque
 		return
 	}
 }
+
+func (cfg *apiConfig) handlerDeleteCalculation(w http.ResponseWriter, r *http.Request) {
+	// Moves a calculation to the trash
+	_, _, err := authenticateUser(r, cfg.secret)
+	if err != nil {
+		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+		return
+	}
+
+	calcID, err := uuid.Parse(r.PathValue("calcid"))
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+
+	previous, err := cfg.db.GetCalculation(r.Context(), calcID)
+	if err != nil {
+		respondWithError(w, "Calculation not found", http.StatusNotFound, err)
+		return
+	}
+	if !cfg.requireProjectVisible(w, r, previous.ProjectID) {
+		return
+	}
+
+	calc, err := cfg.db.SoftDeleteCalculation(r.Context(), calcID)
+	if err != nil {
+		respondWithError(w, "Calculation not found", http.StatusNotFound, err)
+		return
+	}
+
+	err = respondWithJSON(w, http.StatusAccepted, calc)
+	if err != nil {
+		respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
+		return
+	}
+}
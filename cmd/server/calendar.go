@@ -0,0 +1,319 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Denisowiec/FoleyBookkeeper/internal/auth"
+	"github.com/Denisowiec/FoleyBookkeeper/internal/db"
+	"github.com/Denisowiec/FoleyBookkeeper/internal/episodecode"
+	"github.com/Denisowiec/FoleyBookkeeper/internal/ical"
+	"github.com/google/uuid"
+)
+
+// How far into the past the calendar feeds reach
+const calendarFeedHistory = 180 * 24 * time.Hour
+
+func strToFeedScope(input string) (db.FeedScope, error) {
+	switch input {
+	case "user":
+		return db.FeedScopeUser, nil
+	case "room":
+		return db.FeedScopeRoom, nil
+	case "project":
+		return db.FeedScopeProject, nil
+	default:
+		return "", fmt.Errorf("feed scope unknown")
+	}
+}
+
+var (
+	errFeedTargetHidden      = errors.New("feed target not visible to the user")
+	errFeedScheduleForbidden = errors.New("feed of another user's schedule")
+)
+
+func checkFeedTarget(access projectAccess, scope db.FeedScope, targetID uuid.UUID) error {
+	// Projects the user isn't on are hidden like everywhere else. Rooms
+	// are shared by everyone
+	switch {
+	case scope == db.FeedScopeProject && !access.canSee(targetID):
+		return errFeedTargetHidden
+	case scope == db.FeedScopeUser && !access.canSeeSchedule(targetID):
+		return errFeedScheduleForbidden
+	}
+	return nil
+}
+
+func (cfg *apiConfig) handlerCreateCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	// Creates a secret feed URL for a user, a room or a project
+	// If no target is given for a user feed, the feed is for the logged in user
+	userID, _, err := authenticateUser(r, cfg.secret)
+	if err != nil {
+		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+		return
+	}
+
+	feedInput := struct {
+		Scope    string `json:"scope"`
+		TargetID string `json:"target_id"`
+	}{}
+
+	decoder := json.NewDecoder(r.Body)
+	err = decoder.Decode(&feedInput)
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+
+	scope, err := strToFeedScope(feedInput.Scope)
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+
+	var targetID uuid.UUID
+	if feedInput.TargetID == "" && scope == db.FeedScopeUser {
+		targetID = userID
+	} else {
+		targetID, err = uuid.Parse(feedInput.TargetID)
+		if err != nil {
+			respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	// The feed is readable without logging in, so its target has to be
+	// something the user may see
+	access, err := cfg.requestProjectAccess(r)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+	err = checkFeedTarget(access, scope, targetID)
+	if errors.Is(err, errFeedTargetHidden) {
+		respondWithError(w, "Feed target not found", http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		respondWithError(w, "Only admins and managers can create feeds of other users' schedules", http.StatusForbidden, err)
+		return
+	}
+
+	// We make sure the feed points at something that exists
+	switch scope {
+	case db.FeedScopeUser:
+		_, err = cfg.db.GetUserByID(r.Context(), targetID)
+	case db.FeedScopeRoom:
+		_, err = cfg.db.GetRoomByID(r.Context(), targetID)
+	case db.FeedScopeProject:
+		_, err = cfg.db.GetProjectByID(r.Context(), targetID)
+	}
+	if err != nil {
+		respondWithError(w, "Feed target not found", http.StatusNotFound, err)
+		return
+	}
+
+	createFeedParams := db.CreateCalendarFeedParams{
+		Token:    auth.MakeFeedToken(),
+		OwnerID:  userID,
+		Scope:    scope,
+		TargetID: targetID,
+	}
+	feed, err := cfg.db.CreateCalendarFeed(r.Context(), createFeedParams)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+
+	feedResp := struct {
+		db.CalendarFeed
+		Path string `json:"path"`
+	}{
+		CalendarFeed: feed,
+		Path:         fmt.Sprintf("/api/calendar/%s", feed.Token),
+	}
+
+	err = respondWithJSON(w, http.StatusCreated, feedResp)
+	if err != nil {
+		respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
+		return
+	}
+}
+
+func (cfg *apiConfig) handlerGetCalendarFeeds(w http.ResponseWriter, r *http.Request) {
+	// Lists the feeds created by the logged in user
+	userID, _, err := authenticateUser(r, cfg.secret)
+	if err != nil {
+		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+		return
+	}
+
+	list, err := cfg.db.GetCalendarFeedsForUser(r.Context(), userID)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+
+	err = respondWithJSON(w, http.StatusOK, list)
+	if err != nil {
+		respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
+		return
+	}
+}
+
+func (cfg *apiConfig) handlerDeleteCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	// Revokes a feed. Calendars subscribed to it stop getting updates
+	userID, _, err := authenticateUser(r, cfg.secret)
+	if err != nil {
+		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+		return
+	}
+
+	deleteFeedParams := db.DeleteCalendarFeedParams{
+		Token:   r.PathValue("token"),
+		OwnerID: userID,
+	}
+	feed, err := cfg.db.DeleteCalendarFeed(r.Context(), deleteFeedParams)
+	if err != nil {
+		respondWithError(w, "Feed not found", http.StatusNotFound, err)
+		return
+	}
+
+	err = respondWithJSON(w, http.StatusAccepted, feed)
+	if err != nil {
+		respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
+		return
+	}
+}
+
+func (cfg *apiConfig) handlerCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	// Serves the iCalendar feed. Calendar applications can't send our JWTs,
+	// so the secret token in the URL is the only authentication here
+	feed, err := cfg.db.GetCalendarFeed(r.Context(), r.PathValue("token"))
+	if err != nil {
+		respondWithError(w, "Feed not found", http.StatusNotFound, err)
+		return
+	}
+
+	// The feed shows what its owner may see now, which may be less than when
+	// it was created. Feeds whose target the owner lost access to are gone
+	owner, err := cfg.db.GetUserByID(r.Context(), feed.OwnerID)
+	if err != nil {
+		respondWithError(w, "Feed not found", http.StatusNotFound, err)
+		return
+	}
+	access, err := cfg.getProjectAccess(r.Context(), owner)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+	err = checkFeedTarget(access, feed.Scope, feed.TargetID)
+	if err != nil {
+		respondWithError(w, "Feed no longer available", http.StatusGone, err)
+		return
+	}
+
+	since := time.Now().Add(-calendarFeedHistory)
+	bookingsParams := db.GetBookingsForFeedParams{Since: since}
+	sessionsParams := db.GetSessionsForFeedParams{Since: since}
+	target := uuid.NullUUID{UUID: feed.TargetID, Valid: true}
+
+	cal := ical.Calendar{}
+	switch feed.Scope {
+	case db.FeedScopeUser:
+		user, err := cfg.db.GetUserByID(r.Context(), feed.TargetID)
+		if err != nil {
+			respondWithError(w, "Feed not found", http.StatusNotFound, err)
+			return
+		}
+		cal.Name = fmt.Sprintf("FoleyBookkeeper: %s", user.Username)
+		bookingsParams.UserID = target
+		sessionsParams.UserID = target
+	case db.FeedScopeRoom:
+		room, err := cfg.db.GetRoomByID(r.Context(), feed.TargetID)
+		if err != nil {
+			respondWithError(w, "Feed not found", http.StatusNotFound, err)
+			return
+		}
+		cal.Name = fmt.Sprintf("FoleyBookkeeper: %s", room.RoomName)
+		bookingsParams.RoomID = target
+	case db.FeedScopeProject:
+		prj, err := cfg.db.GetProjectByID(r.Context(), feed.TargetID)
+		if err != nil {
+			respondWithError(w, "Feed not found", http.StatusNotFound, err)
+			return
+		}
+		cal.Name = fmt.Sprintf("FoleyBookkeeper: %s", prj.Title)
+		bookingsParams.ProjectID = target
+		sessionsParams.ProjectID = target
+	}
+
+	bookings, err := cfg.db.GetBookingsForFeed(r.Context(), bookingsParams)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+	for _, b := range bookings {
+		if !access.canSee(b.ProjectID) {
+			continue
+		}
+		description := fmt.Sprintf("Project: %s", b.ProjectTitle)
+		if b.Notes.Valid {
+			description = fmt.Sprintf("%s\n%s", description, b.Notes.String)
+		}
+		cal.Events = append(cal.Events, ical.Event{
+			// UIDs have to stay the same between downloads, so that
+			// calendars update the events instead of duplicating them
+			UID:          fmt.Sprintf("booking-%s@foleybookkeeper", b.ID),
+			Start:        b.StartsAt,
+			End:          b.EndsAt,
+			Summary:      b.Title,
+			Description:  description,
+			Location:     b.RoomName.String,
+			LastModified: b.UpdatedAt,
+		})
+	}
+
+	// Sessions only have a date, so they are published as all-day events.
+	// Rooms aren't recorded for sessions, so room feeds contain bookings only
+	if feed.Scope != db.FeedScopeRoom {
+		sessions, err := cfg.db.GetSessionsForFeed(r.Context(), sessionsParams)
+		if err != nil {
+			respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+			return
+		}
+		for _, s := range sessions {
+			if !access.canSee(s.ProjectID) {
+				continue
+			}
+			duration := time.Duration(s.Duration) * time.Minute
+			// Sessions above the episode level are named after their unit, if any
+			level := s.UnitName.String
+			if s.EpisodeNumber.Valid {
+				level = episodecode.Code{Season: int(s.SeasonNumber.Int32), Episode: int(s.EpisodeNumber.Int32)}.String()
+			}
+			cal.Events = append(cal.Events, ical.Event{
+				UID:          fmt.Sprintf("session-%s@foleybookkeeper", s.ID),
+				Start:        s.SessionDate,
+				End:          s.SessionDate.AddDate(0, 0, 1),
+				AllDay:       true,
+				Summary:      strings.Join(strings.Fields(fmt.Sprintf("%s %s %s (%s)", s.ProjectTitle, level, s.PartWorkedOn, s.ActivityDone)), " "),
+				Description:  fmt.Sprintf("Logged time: %s", duration),
+				LastModified: s.UpdatedAt,
+			})
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	err = cal.Encode(w)
+	if err != nil {
+		// The headers are already sent, so all we can do is log the error
+		log.Println("Error writing calendar feed", err)
+	}
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Denisowiec/FoleyBookkeeper/internal/db"
+	"github.com/google/uuid"
+)
+
+func TestCheckFeedTarget(t *testing.T) {
+	joined, other := uuid.New(), uuid.New()
+	member := projectAccess{
+		userID: uuid.New(),
+		roles:  map[uuid.UUID]db.ProjectRole{joined: db.ProjectRoleViewer},
+	}
+	manager := projectAccess{userID: uuid.New(), global: true}
+
+	cases := []struct {
+		access projectAccess
+		scope  db.FeedScope
+		target uuid.UUID
+		err    error
+	}{
+		{member, db.FeedScopeProject, joined, nil},
+		{member, db.FeedScopeProject, other, errFeedTargetHidden},
+		{member, db.FeedScopeUser, member.userID, nil},
+		{member, db.FeedScopeUser, other, errFeedScheduleForbidden},
+		{member, db.FeedScopeRoom, other, nil},
+		{manager, db.FeedScopeProject, other, nil},
+		{manager, db.FeedScopeUser, other, nil},
+	}
+	for _, c := range cases {
+		if err := checkFeedTarget(c.access, c.scope, c.target); !errors.Is(err, c.err) {
+			t.Errorf("%s feed, global %v: got %v, expected %v", c.scope, c.access.global, err, c.err)
+		}
+	}
+}
@@ -0,0 +1,484 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/Denisowiec/FoleyBookkeeper/internal/db"
+	"github.com/google/uuid"
+)
+
+// Country prefixes of EU VAT numbers, Greece uses EL and Northern Ireland XI
+var vatPrefixes = []string{
+	"AT", "BE", "BG", "CY", "CZ", "DE", "DK", "EE", "EL", "ES", "FI", "FR", "HR", "HU",
+	"IE", "IT", "LT", "LU", "LV", "MT", "NL", "PL", "PT", "RO", "SE", "SI", "SK", "XI",
+}
+
+var (
+	vatPattern      = regexp.MustCompile(`^[A-Z]{2}[0-9A-Z+*]{2,12}$`)
+	currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+	languagePattern = regexp.MustCompile(`^[a-z]{2}$`)
+)
+
+func validNIP(nip string) bool {
+	// Polish NIPs are 10 digits, the last one is a checksum
+	if len(nip) != 10 {
+		return false
+	}
+	weights := []int{6, 5, 7, 2, 3, 4, 5, 6, 7}
+	sum := 0
+	for i, r := range nip {
+		if r < '0' || r > '9' {
+			return false
+		}
+		if i < 9 {
+			sum += int(r-'0') * weights[i]
+		}
+	}
+	return sum%11 == int(nip[9]-'0')
+}
+
+func normalizeTaxID(input string) (string, error) {
+	// Takes a NIP or an EU VAT number, with or without spaces and dashes.
+	// Polish VAT numbers are NIPs with the PL prefix, so they're checked the same
+	taxID := strings.ToUpper(strings.NewReplacer(" ", "", "-", "").Replace(input))
+	if validNIP(taxID) {
+		return taxID, nil
+	}
+	if !vatPattern.MatchString(taxID) || !slices.Contains(vatPrefixes, taxID[:2]) {
+		return "", fmt.Errorf("tax ID %s is neither a NIP nor an EU VAT number", input)
+	}
+	if strings.HasPrefix(taxID, "PL") && !validNIP(taxID[2:]) {
+		return "", fmt.Errorf("tax ID %s has an invalid NIP", input)
+	}
+	return taxID, nil
+}
+
+var addressKinds = []db.AddressKind{db.AddressKindBilling, db.AddressKindPostal}
+
+func strToAddressKind(input string) (db.AddressKind, error) {
+	kind := db.AddressKind(strings.ToLower(strings.TrimSpace(input)))
+	if !slices.Contains(addressKinds, kind) {
+		return "", fmt.Errorf("address kind %s unknown", input)
+	}
+	return kind, nil
+}
+
+func nullString(s string) sql.NullString {
+	s = strings.TrimSpace(s)
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+// clientDetails is a client with everything needed to bill it
+type clientDetails struct {
+	db.Client
+	Contacts  []db.ClientContact `json:"contacts"`
+	Addresses []db.ClientAddress `json:"addresses"`
+}
+
+func (cfg *apiConfig) getClientDetails(r *http.Request, client db.Client) (clientDetails, error) {
+	contacts, err := cfg.db.GetContactsForClient(r.Context(), client.ID)
+	if err != nil {
+		return clientDetails{}, err
+	}
+	addresses, err := cfg.db.GetAddressesForClient(r.Context(), client.ID)
+	if err != nil {
+		return clientDetails{}, err
+	}
+	return clientDetails{Client: client, Contacts: contacts, Addresses: addresses}, nil
+}
+
+func (d clientDetails) address(kind db.AddressKind) (db.ClientAddress, bool) {
+	for _, a := range d.Addresses {
+		if a.Kind == kind {
+			return a, true
+		}
+	}
+	return db.ClientAddress{}, false
+}
+
+func (d clientDetails) billingAddress() (db.ClientAddress, bool) {
+	// Clients without a separate billing address are billed at their postal one
+	if a, ok := d.address(db.AddressKindBilling); ok {
+		return a, true
+	}
+	return d.address(db.AddressKindPostal)
+}
+
+func formatAddress(a db.ClientAddress) string {
+	parts := []string{a.Line1}
+	if a.Line2.Valid {
+		parts = append(parts, a.Line2.String)
+	}
+	parts = append(parts, a.PostalCode+" "+a.City, a.Country)
+	return strings.Join(parts, ", ")
+}
+
+func (d clientDetails) documentDetails() []string {
+	// Lines identifying the client on documents we send out
+	lines := []string{"Client: " + d.ClientName}
+	if a, ok := d.billingAddress(); ok {
+		lines = append(lines, formatAddress(a))
+	}
+	if d.TaxID.Valid {
+		lines = append(lines, "Tax ID: "+d.TaxID.String)
+	}
+	return lines
+}
+
+func (cfg *apiConfig) handlerSetClientBilling(w http.ResponseWriter, r *http.Request) {
+	// Sets the tax ID, default currency, payment terms and invoice language of
+	// a client. Fields not given keep their values, empty ones are cleared
+	_, _, err := authenticateUser(r, cfg.secret)
+	if err != nil {
+		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+		return
+	}
+
+	clientID, err := uuid.Parse(r.PathValue("clientid"))
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+	client, err := cfg.db.GetClientByID(r.Context(), clientID)
+	if err != nil {
+		respondWithError(w, "Client not found", http.StatusNotFound, err)
+		return
+	}
+
+	billingInput := struct {
+		TaxID            *string `json:"tax_id"`
+		DefaultCurrency  *string `json:"default_currency"`
+		PaymentTermsDays *int32  `json:"payment_terms_days"`
+		InvoiceLanguage  *string `json:"invoice_language"`
+	}{}
+	decoder := json.NewDecoder(r.Body)
+	err = decoder.Decode(&billingInput)
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+
+	params := db.SetClientBillingParams{
+		ID:               client.ID,
+		TaxID:            client.TaxID,
+		DefaultCurrency:  client.DefaultCurrency,
+		PaymentTermsDays: client.PaymentTermsDays,
+		InvoiceLanguage:  client.InvoiceLanguage,
+	}
+	if billingInput.TaxID != nil {
+		params.TaxID = sql.NullString{}
+		if strings.TrimSpace(*billingInput.TaxID) != "" {
+			taxID, err := normalizeTaxID(*billingInput.TaxID)
+			if err != nil {
+				respondWithError(w, err.Error(), http.StatusBadRequest, err)
+				return
+			}
+			params.TaxID = sql.NullString{String: taxID, Valid: true}
+		}
+	}
+	if billingInput.DefaultCurrency != nil {
+		params.DefaultCurrency = nullString(strings.ToUpper(*billingInput.DefaultCurrency))
+		if params.DefaultCurrency.Valid && !currencyPattern.MatchString(params.DefaultCurrency.String) {
+			respondWithError(w, "Currencies are three letter codes, e.g. PLN", http.StatusBadRequest, nil)
+			return
+		}
+	}
+	if billingInput.PaymentTermsDays != nil {
+		days := *billingInput.PaymentTermsDays
+		if days < 0 || days > 365 {
+			respondWithError(w, "Payment terms have to be between 0 and 365 days", http.StatusBadRequest, nil)
+			return
+		}
+		params.PaymentTermsDays = sql.NullInt32{Int32: days, Valid: true}
+	}
+	if billingInput.InvoiceLanguage != nil {
+		params.InvoiceLanguage = nullString(strings.ToLower(*billingInput.InvoiceLanguage))
+		if params.InvoiceLanguage.Valid && !languagePattern.MatchString(params.InvoiceLanguage.String) {
+			respondWithError(w, "Languages are two letter codes, e.g. pl", http.StatusBadRequest, nil)
+			return
+		}
+	}
+
+	client, err = cfg.db.SetClientBilling(r.Context(), params)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+
+	err = respondWithJSON(w, http.StatusAccepted, client)
+	if err != nil {
+		respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
+		return
+	}
+}
+
+func (cfg *apiConfig) handlerSetClientAddress(w http.ResponseWriter, r *http.Request) {
+	// Sets the billing or postal address of a client, replacing the previous one
+	_, _, err := authenticateUser(r, cfg.secret)
+	if err != nil {
+		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+		return
+	}
+
+	clientID, err := uuid.Parse(r.PathValue("clientid"))
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+	kind, err := strToAddressKind(r.PathValue("kind"))
+	if err != nil {
+		respondWithError(w, err.Error(), http.StatusBadRequest, err)
+		return
+	}
+	_, err = cfg.db.GetClientByID(r.Context(), clientID)
+	if err != nil {
+		respondWithError(w, "Client not found", http.StatusNotFound, err)
+		return
+	}
+
+	addressInput := struct {
+		Line1      string `json:"line1"`
+		Line2      string `json:"line2"`
+		PostalCode string `json:"postal_code"`
+		City       string `json:"city"`
+		Country    string `json:"country"`
+	}{}
+	decoder := json.NewDecoder(r.Body)
+	err = decoder.Decode(&addressInput)
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+
+	params := db.SetClientAddressParams{
+		ClientID:   clientID,
+		Kind:       kind,
+		Line1:      strings.TrimSpace(addressInput.Line1),
+		Line2:      nullString(addressInput.Line2),
+		PostalCode: strings.TrimSpace(addressInput.PostalCode),
+		City:       strings.TrimSpace(addressInput.City),
+		Country:    strings.TrimSpace(addressInput.Country),
+	}
+	if params.Line1 == "" || params.PostalCode == "" || params.City == "" || params.Country == "" {
+		respondWithError(w, "Addresses need a street, postal code, city and country", http.StatusBadRequest, nil)
+		return
+	}
+
+	address, err := cfg.db.SetClientAddress(r.Context(), params)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+
+	err = respondWithJSON(w, http.StatusAccepted, address)
+	if err != nil {
+		respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
+		return
+	}
+}
+
+func (cfg *apiConfig) handlerDeleteClientAddress(w http.ResponseWriter, r *http.Request) {
+	_, _, err := authenticateUser(r, cfg.secret)
+	if err != nil {
+		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+		return
+	}
+
+	clientID, err := uuid.Parse(r.PathValue("clientid"))
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+	kind, err := strToAddressKind(r.PathValue("kind"))
+	if err != nil {
+		respondWithError(w, err.Error(), http.StatusBadRequest, err)
+		return
+	}
+
+	address, err := cfg.db.DeleteClientAddress(r.Context(), db.DeleteClientAddressParams{ClientID: clientID, Kind: kind})
+	if err != nil {
+		respondWithError(w, "Address not found", http.StatusNotFound, err)
+		return
+	}
+
+	err = respondWithJSON(w, http.StatusAccepted, address)
+	if err != nil {
+		respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
+		return
+	}
+}
+
+type contactInputType struct {
+	Name  string `json:"name"`
+	Role  string `json:"role"`
+	Email string `json:"email"`
+	Phone string `json:"phone"`
+}
+
+func checkContactInput(input contactInputType) error {
+	if strings.TrimSpace(input.Name) == "" {
+		return errors.New("contact name required")
+	}
+	email := strings.TrimSpace(input.Email)
+	if email != "" && !validateEmail(email) {
+		return fmt.Errorf("invalid email %s", email)
+	}
+	return nil
+}
+
+func (cfg *apiConfig) handlerCreateClientContact(w http.ResponseWriter, r *http.Request) {
+	_, _, err := authenticateUser(r, cfg.secret)
+	if err != nil {
+		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+		return
+	}
+
+	clientID, err := uuid.Parse(r.PathValue("clientid"))
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+	_, err = cfg.db.GetClientByID(r.Context(), clientID)
+	if err != nil {
+		respondWithError(w, "Client not found", http.StatusNotFound, err)
+		return
+	}
+
+	contactInput := contactInputType{}
+	decoder := json.NewDecoder(r.Body)
+	err = decoder.Decode(&contactInput)
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+	err = checkContactInput(contactInput)
+	if err != nil {
+		respondWithError(w, err.Error(), http.StatusBadRequest, err)
+		return
+	}
+
+	params := db.CreateClientContactParams{
+		ClientID: clientID,
+		Name:     strings.TrimSpace(contactInput.Name),
+		Role:     nullString(contactInput.Role),
+		Email:    nullString(contactInput.Email),
+		Phone:    nullString(contactInput.Phone),
+	}
+	contact, err := cfg.db.CreateClientContact(r.Context(), params)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+
+	err = respondWithJSON(w, http.StatusCreated, contact)
+	if err != nil {
+		respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
+		return
+	}
+}
+
+func (cfg *apiConfig) handlerUpdateClientContact(w http.ResponseWriter, r *http.Request) {
+	// Replaces the name, role, email and phone of a contact
+	_, _, err := authenticateUser(r, cfg.secret)
+	if err != nil {
+		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+		return
+	}
+
+	contactID, err := uuid.Parse(r.PathValue("contactid"))
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+
+	contactInput := contactInputType{}
+	decoder := json.NewDecoder(r.Body)
+	err = decoder.Decode(&contactInput)
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+	err = checkContactInput(contactInput)
+	if err != nil {
+		respondWithError(w, err.Error(), http.StatusBadRequest, err)
+		return
+	}
+
+	params := db.UpdateClientContactParams{
+		ID:    contactID,
+		Name:  strings.TrimSpace(contactInput.Name),
+		Role:  nullString(contactInput.Role),
+		Email: nullString(contactInput.Email),
+		Phone: nullString(contactInput.Phone),
+	}
+	contact, err := cfg.db.UpdateClientContact(r.Context(), params)
+	if err != nil {
+		respondWithError(w, "Contact not found", http.StatusNotFound, err)
+		return
+	}
+
+	err = respondWithJSON(w, http.StatusAccepted, contact)
+	if err != nil {
+		respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
+		return
+	}
+}
+
+func (cfg *apiConfig) handlerGetClientContacts(w http.ResponseWriter, r *http.Request) {
+	_, _, err := authenticateUser(r, cfg.secret)
+	if err != nil {
+		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+		return
+	}
+
+	clientID, err := uuid.Parse(r.PathValue("clientid"))
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+
+	list, err := cfg.db.GetContactsForClient(r.Context(), clientID)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+
+	err = respondWithJSON(w, http.StatusOK, list)
+	if err != nil {
+		respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
+		return
+	}
+}
+
+func (cfg *apiConfig) handlerDeleteClientContact(w http.ResponseWriter, r *http.Request) {
+	_, _, err := authenticateUser(r, cfg.secret)
+	if err != nil {
+		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+		return
+	}
+
+	contactID, err := uuid.Parse(r.PathValue("contactid"))
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+
+	contact, err := cfg.db.DeleteClientContact(r.Context(), contactID)
+	if err != nil {
+		respondWithError(w, "Contact not found", http.StatusNotFound, err)
+		return
+	}
+
+	err = respondWithJSON(w, http.StatusAccepted, contact)
+	if err != nil {
+		respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
+		return
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/Denisowiec/FoleyBookkeeper/internal/db"
+)
+
+func TestNormalizeTaxID(t *testing.T) {
+	valid := map[string]string{
+		"123-456-32-18":  "1234563218",
+		"123 456 32 18":  "1234563218",
+		"PL 1234563218":  "PL1234563218",
+		"de123456789":    "DE123456789",
+		"EL 094259216":   "EL094259216",
+		"NL123456789B01": "NL123456789B01",
+	}
+	for input, expected := range valid {
+		taxID, err := normalizeTaxID(input)
+		if err != nil || taxID != expected {
+			t.Errorf("tax ID %s normalized to %s, %v, expected %s", input, taxID, err, expected)
+		}
+	}
+
+	invalid := []string{
+		"1234563219",   // wrong NIP checksum
+		"PL1234563219", // wrong NIP checksum behind the prefix
+		"US123456789",  // not an EU country
+		"DE1",
+		"",
+	}
+	for _, input := range invalid {
+		_, err := normalizeTaxID(input)
+		if err == nil {
+			t.Errorf("tax ID %q should be rejected", input)
+		}
+	}
+}
+
+func TestClientDocumentDetails(t *testing.T) {
+	client := clientDetails{
+		Client: db.Client{ClientName: "Studio", TaxID: sql.NullString{String: "1234563218", Valid: true}},
+		Addresses: []db.ClientAddress{
+			{Kind: db.AddressKindPostal, Line1: "Postal 1", PostalCode: "00-001", City: "Warszawa", Country: "PL"},
+		},
+	}
+	lines := client.documentDetails()
+	if len(lines) != 3 || lines[1] != "Postal 1, 00-001 Warszawa, PL" || lines[2] != "Tax ID: 1234563218" {
+		t.Errorf("clients without a billing address should use the postal one: %q", lines)
+	}
+
+	client.Addresses = append(client.Addresses, db.ClientAddress{
+		Kind: db.AddressKindBilling, Line1: "Billing 2", Line2: sql.NullString{String: "Floor 3", Valid: true},
+		PostalCode: "30-001", City: "Kraków", Country: "PL",
+	})
+	lines = client.documentDetails()
+	if len(lines) != 3 || lines[1] != "Billing 2, Floor 3, 30-001 Kraków, PL" {
+		t.Errorf("the billing address should win over the postal one: %q", lines)
+	}
+}
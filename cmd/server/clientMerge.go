@@ -0,0 +1,182 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Denisowiec/FoleyBookkeeper/internal/db"
+	"github.com/google/uuid"
+)
+
+func describeDependencies(deps db.GetClientDependenciesRow) string {
+	// E.g. "2 projects, 1 episode and 14 sessions", leaving out what's not there
+	counts := []struct {
+		count int64
+		name  string
+	}{
+		{deps.Projects, "project"},
+		{deps.Episodes, "episode"},
+		{deps.Sessions, "session"},
+		{deps.Calculations, "calculation"},
+		{deps.Bookings, "booking"},
+	}
+	parts := []string{}
+	for _, c := range counts {
+		if c.count == 0 {
+			continue
+		}
+		part := fmt.Sprintf("%d %s", c.count, c.name)
+		if c.count != 1 {
+			part += "s"
+		}
+		parts = append(parts, part)
+	}
+	switch len(parts) {
+	case 0:
+		return "nothing"
+	case 1:
+		return parts[0]
+	}
+	return strings.Join(parts[:len(parts)-1], ", ") + " and " + parts[len(parts)-1]
+}
+
+func mergeBilling(target, source db.Client) db.SetClientBillingParams {
+	// The target's billing details win, the source only fills in the gaps
+	params := db.SetClientBillingParams{
+		ID:               target.ID,
+		TaxID:            target.TaxID,
+		DefaultCurrency:  target.DefaultCurrency,
+		PaymentTermsDays: target.PaymentTermsDays,
+		InvoiceLanguage:  target.InvoiceLanguage,
+	}
+	if !params.TaxID.Valid {
+		params.TaxID = source.TaxID
+	}
+	if !params.DefaultCurrency.Valid {
+		params.DefaultCurrency = source.DefaultCurrency
+	}
+	if !params.PaymentTermsDays.Valid {
+		params.PaymentTermsDays = source.PaymentTermsDays
+	}
+	if !params.InvoiceLanguage.Valid {
+		params.InvoiceLanguage = source.InvoiceLanguage
+	}
+	return params
+}
+
+func (cfg *apiConfig) handlerMergeClient(w http.ResponseWriter, r *http.Request) {
+	// Merges a duplicate client into the target client. Projects and contacts
+	// move to the target, as do the addresses and billing details it lacks,
+	// then the duplicate goes to the trash. All of it happens in one transaction
+	_, _, err := authenticateUser(r, cfg.secret)
+	if err != nil {
+		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+		return
+	}
+
+	sourceID, err := uuid.Parse(r.PathValue("clientid"))
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+
+	mergeInput := struct {
+		TargetID string `json:"target_id"`
+	}{}
+	decoder := json.NewDecoder(r.Body)
+	err = decoder.Decode(&mergeInput)
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+	targetID, err := uuid.Parse(mergeInput.TargetID)
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+	if targetID == sourceID {
+		respondWithError(w, "A client can't be merged into itself", http.StatusBadRequest, nil)
+		return
+	}
+
+	source, err := cfg.db.GetClientByID(r.Context(), sourceID)
+	if err != nil {
+		respondWithError(w, "Client not found", http.StatusNotFound, err)
+		return
+	}
+	target, err := cfg.db.GetClientByID(r.Context(), targetID)
+	if err != nil {
+		respondWithError(w, "Target client not found", http.StatusNotFound, err)
+		return
+	}
+
+	tx, err := cfg.conn.BeginTx(r.Context(), nil)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+	defer tx.Rollback()
+	qtx := cfg.db.WithTx(tx)
+
+	// Counted inside the transaction, so the report matches what moved.
+	// Trashed projects move too, so they can be restored under the target
+	deps, err := qtx.GetMovableClientDependencies(r.Context(), sourceID)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+	moveParams := db.MoveProjectsToClientParams{FromClientID: sourceID, ToClientID: targetID}
+	projects, err := qtx.MoveProjectsToClient(r.Context(), moveParams)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+	contacts, err := qtx.MoveContactsToClient(r.Context(), db.MoveContactsToClientParams(moveParams))
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+	addresses, err := qtx.MoveAddressesToClient(r.Context(), db.MoveAddressesToClientParams(moveParams))
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+	target, err = qtx.SetClientBilling(r.Context(), mergeBilling(target, source))
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+	source, err = qtx.SoftDeleteClient(r.Context(), sourceID)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+	err = tx.Commit()
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+
+	resp := struct {
+		Target    db.Client                          `json:"target"`
+		Merged    db.Client                          `json:"merged"`
+		Moved     db.GetMovableClientDependenciesRow `json:"moved"`
+		Projects  []db.Project                       `json:"projects"`
+		Contacts  []db.ClientContact                 `json:"contacts"`
+		Addresses []db.ClientAddress                 `json:"addresses"`
+	}{
+		Target:    target,
+		Merged:    source,
+		Moved:     deps,
+		Projects:  projects,
+		Contacts:  contacts,
+		Addresses: addresses,
+	}
+	err = respondWithJSON(w, http.StatusAccepted, resp)
+	if err != nil {
+		respondWithError(w, "Error processing response data", http.StatusInternalServerError, err)
+		return
+	}
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/Denisowiec/FoleyBookkeeper/internal/db"
+	"github.com/google/uuid"
+)
+
+func TestDescribeDependencies(t *testing.T) {
+	cases := map[string]db.GetClientDependenciesRow{
+		"nothing":                              {},
+		"1 project":                            {Projects: 1},
+		"2 projects and 1 episode":             {Projects: 2, Episodes: 1},
+		"1 project, 3 sessions and 2 bookings": {Projects: 1, Sessions: 3, Bookings: 2},
+	}
+	for expected, deps := range cases {
+		if got := describeDependencies(deps); got != expected {
+			t.Errorf("dependencies %+v described as %q, expected %q", deps, got, expected)
+		}
+	}
+}
+
+func TestMergeBilling(t *testing.T) {
+	target := db.Client{
+		ID:              uuid.New(),
+		DefaultCurrency: sql.NullString{String: "EUR", Valid: true},
+	}
+	source := db.Client{
+		ID:               uuid.New(),
+		TaxID:            sql.NullString{String: "1234563218", Valid: true},
+		DefaultCurrency:  sql.NullString{String: "PLN", Valid: true},
+		PaymentTermsDays: sql.NullInt32{Int32: 30, Valid: true},
+	}
+
+	params := mergeBilling(target, source)
+	if params.ID != target.ID {
+		t.Errorf("merged billing details should be set on the target")
+	}
+	if params.DefaultCurrency.String != "EUR" {
+		t.Errorf("the target's currency should win, got %s", params.DefaultCurrency.String)
+	}
+	if params.TaxID.String != "1234563218" || params.PaymentTermsDays.Int32 != 30 {
+		t.Errorf("the source should fill in the target's gaps: %+v", params)
+	}
+	if params.InvoiceLanguage.Valid {
+		t.Errorf("a language neither client has should stay empty")
+	}
+}
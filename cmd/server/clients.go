@@ -3,8 +3,10 @@ package main
 import (
 	"database/sql"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 
 	"github.com/Denisowiec/FoleyBookkeeper/internal/db"
 	"github.com/google/uuid"
@@ -37,7 +39,7 @@ func (cfg *apiConfig) handlerCreateClient(w http.ResponseWriter, r *http.Request
 
 	createClientParams := db.CreateClientParams{
 		ClientName: clientInput.ClientName,
-		Email:      sql.NullString{String: clientInput.ClientName, Valid: true},
+		Email:      sql.NullString{String: clientInput.Email, Valid: clientInput.Email != ""},
 		Notes:      sql.NullString{String: clientInput.Notes, Valid: true},
 	}
 
@@ -57,6 +59,7 @@ func (cfg *apiConfig) handlerGetClientByID(w http.ResponseWriter, r *http.Reques
 	// Function for handling requests to get client data by it's id
 	// Requires authentification
 	// Takes an id for the client in the url
+	// Returns the client with its contacts and addresses
 	_, _, err := authenticateUser(r, cfg.secret)
 	if err != nil {
 		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
@@ -74,8 +77,13 @@ func (cfg *apiConfig) handlerGetClientByID(w http.ResponseWriter, r *http.Reques
 		respondWithError(w, "Error fetching data from database", http.StatusInternalServerError, err)
 		return
 	}
+	details, err := cfg.getClientDetails(r, client)
+	if err != nil {
+		respondWithError(w, "Error fetching data from database", http.StatusInternalServerError, err)
+		return
+	}
 
-	err = respondWithJSON(w, http.StatusOK, client)
+	err = respondWithJSON(w, http.StatusOK, details)
 	if err != nil {
 		respondWithError(w, "Unable to process response data", http.StatusInternalServerError, err)
 		return
@@ -185,6 +193,8 @@ func (cfg *apiConfig) handlerUpdateClient(w http.ResponseWriter, r *http.Request
 }
 
 func (cfg *apiConfig) handlerDeleteClient(w http.ResponseWriter, r *http.Request) {
+	// Moves a client to the trash. Clients with projects are only deleted with
+	// force=true in the query, which moves the projects and everything in them too
 	_, _, err := authenticateUser(r, cfg.secret)
 	if err != nil {
 		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
@@ -196,14 +206,70 @@ func (cfg *apiConfig) handlerDeleteClient(w http.ResponseWriter, r *http.Request
 		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
 		return
 	}
+	force := false
+	if value := r.URL.Query().Get("force"); value != "" {
+		force, err = strconv.ParseBool(value)
+		if err != nil {
+			respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+			return
+		}
+	}
 
-	client, err := cfg.db.DeleteClient(r.Context(), clientID)
+	client, err := cfg.db.GetClientByID(r.Context(), clientID)
 	if err != nil {
 		respondWithError(w, "Client not found", http.StatusNotFound, err)
 		return
 	}
+	deps, err := cfg.db.GetClientDependencies(r.Context(), clientID)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+	if deps.Projects > 0 && !force {
+		msg := fmt.Sprintf("Client %s has %s. Merge it into another client or delete it with force", client.ClientName, describeDependencies(deps))
+		respondWithError(w, msg, http.StatusConflict, nil)
+		return
+	}
 
-	err = respondWithJSON(w, http.StatusAccepted, client)
+	tx, err := cfg.conn.BeginTx(r.Context(), nil)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+	defer tx.Rollback()
+	qtx := cfg.db.WithTx(tx)
+
+	client, err = qtx.SoftDeleteClient(r.Context(), clientID)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+	projects, err := qtx.SoftDeleteProjectsForClient(r.Context(), clientID)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+	for _, p := range projects {
+		err = softDeleteProjectContents(r.Context(), qtx, p.ID)
+		if err != nil {
+			respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+			return
+		}
+	}
+	err = tx.Commit()
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+
+	resp := struct {
+		db.Client
+		Deleted db.GetClientDependenciesRow `json:"deleted"`
+	}{
+		Client:  client,
+		Deleted: deps,
+	}
+	err = respondWithJSON(w, http.StatusAccepted, resp)
 	if err != nil {
 		respondWithError(w, "Error processing response data", http.StatusInternalServerError, err)
 		return
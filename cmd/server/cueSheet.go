@@ -0,0 +1,291 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/Denisowiec/FoleyBookkeeper/internal/cuesheet"
+	"github.com/Denisowiec/FoleyBookkeeper/internal/db"
+	"github.com/google/uuid"
+)
+
+// Cue sheet formats with their content types and file extensions
+var cueSheetFormats = map[string]struct {
+	contentType string
+	extension   string
+	write       func(io.Writer, cuesheet.Sheet) error
+}{
+	"csv":  {"text/csv; charset=utf-8", "csv", cuesheet.WriteCSV},
+	"html": {"text/html; charset=utf-8", "html", cuesheet.WriteHTML},
+	"pdf":  {"application/pdf", "pdf", cuesheet.WritePDF},
+	"ale":  {"text/plain; charset=utf-8", "ale", cuesheet.WriteALE},
+}
+
+// Statuses sort in the order cues go through them
+var cueStatusOrder = map[db.CueStatus]int{
+	db.CueStatusPending:  0,
+	db.CueStatusRecorded: 1,
+	db.CueStatusEdited:   2,
+	db.CueStatusApproved: 3,
+	db.CueStatusOmitted:  4,
+}
+
+var naturalChunk = regexp.MustCompile(`\d+|\D+`)
+
+func naturalCompare(a, b string) int {
+	// Compares cue numbers the way people read them, so F2 comes before F10
+	chunksA := naturalChunk.FindAllString(strings.ToLower(a), -1)
+	chunksB := naturalChunk.FindAllString(strings.ToLower(b), -1)
+	for i := 0; i < len(chunksA) && i < len(chunksB); i++ {
+		ca, cb := chunksA[i], chunksB[i]
+		if ca == cb {
+			continue
+		}
+		digitsA, digitsB := ca[0] >= '0' && ca[0] <= '9', cb[0] >= '0' && cb[0] <= '9'
+		if digitsA && digitsB {
+			na, nb := strings.TrimLeft(ca, "0"), strings.TrimLeft(cb, "0")
+			if len(na) != len(nb) {
+				return cmp.Compare(len(na), len(nb))
+			}
+			if na != nb {
+				return strings.Compare(na, nb)
+			}
+			continue
+		}
+		return strings.Compare(ca, cb)
+	}
+	return cmp.Compare(len(chunksA), len(chunksB))
+}
+
+func sortCues(cues []db.Cue, order string, partOrder map[string]int32) error {
+	// Sorts by cue number, timecode, part, status or duration, prefixed with -
+	// for descending order. Ties are broken by reel and timecode
+	byTimecode := func(a, b db.Cue) int {
+		if a.Reel.String != b.Reel.String {
+			return naturalCompare(a.Reel.String, b.Reel.String)
+		}
+		if a.FrameIn != b.FrameIn {
+			return cmp.Compare(a.FrameIn, b.FrameIn)
+		}
+		return naturalCompare(a.CueNumber, b.CueNumber)
+	}
+
+	var compare func(a, b db.Cue) int
+	switch strings.TrimPrefix(order, "-") {
+	case "", "timecode":
+		compare = byTimecode
+	case "number":
+		compare = func(a, b db.Cue) int {
+			return naturalCompare(a.CueNumber, b.CueNumber)
+		}
+	case "part":
+		compare = func(a, b db.Cue) int {
+			return cmp.Compare(cuePartOrder(a, partOrder), cuePartOrder(b, partOrder))
+		}
+	case "status":
+		compare = func(a, b db.Cue) int {
+			return cmp.Compare(cueStatusOrder[a.Status], cueStatusOrder[b.Status])
+		}
+	case "duration":
+		compare = func(a, b db.Cue) int {
+			return cmp.Compare(a.FrameOut-a.FrameIn, b.FrameOut-b.FrameIn)
+		}
+	default:
+		return fmt.Errorf("cues can't be sorted by %s", order)
+	}
+
+	descending := strings.HasPrefix(order, "-")
+	slices.SortStableFunc(cues, func(a, b db.Cue) int {
+		c := compare(a, b)
+		if descending {
+			c = -c
+		}
+		if c == 0 {
+			c = byTimecode(a, b)
+		}
+		return c
+	})
+	return nil
+}
+
+func cuePartOrder(c db.Cue, partOrder map[string]int32) int64 {
+	// Cues without a part, or with a part that was removed, come last
+	order, ok := partOrder[c.Part.String]
+	if !c.Part.Valid || !ok {
+		return 1 << 32
+	}
+	return int64(order)
+}
+
+func groupCuesByPart(cues []db.Cue, parts []db.Part) []cuesheet.Group {
+	// Groups follow the order of the parts, the cues keep their order
+	// within each group
+	byPart := map[string][]cuesheet.Cue{}
+	for _, c := range cues {
+		byPart[c.Part.String] = append(byPart[c.Part.String], cueToSheet(c))
+	}
+
+	groups := []cuesheet.Group{}
+	for _, p := range parts {
+		if list, ok := byPart[p.Code]; ok {
+			groups = append(groups, cuesheet.Group{Name: p.DisplayName, Cues: list})
+			delete(byPart, p.Code)
+		}
+	}
+	if list, ok := byPart[""]; ok {
+		groups = append(groups, cuesheet.Group{Name: "No part", Cues: list})
+	}
+	return groups
+}
+
+func cueToSheet(c db.Cue) cuesheet.Cue {
+	resp := cueToResponse(c)
+	return cuesheet.Cue{
+		Number:      resp.CueNumber,
+		Reel:        resp.Reel,
+		TimecodeIn:  resp.TimecodeIn,
+		TimecodeOut: resp.TimecodeOut,
+		Duration:    resp.Duration,
+		Part:        resp.Part,
+		Description: resp.Description,
+		Status:      string(resp.Status),
+	}
+}
+
+func (cfg *apiConfig) handlerGetCueSheet(w http.ResponseWriter, r *http.Request) {
+	// Builds the cue sheet of an episode, or of one of its reels, e.g.
+	// ?format=pdf&reel=R2&sort=number&group=part
+	// Formats are csv, html, pdf and ale
+	_, _, err := authenticateUser(r, cfg.secret)
+	if err != nil {
+		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+		return
+	}
+
+	episodeID, err := uuid.Parse(r.PathValue("episodeid"))
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+
+	query := r.URL.Query()
+	formatName := query.Get("format")
+	if formatName == "" {
+		formatName = "csv"
+	}
+	format, ok := cueSheetFormats[formatName]
+	if !ok {
+		respondWithError(w, fmt.Sprintf("Cue sheet format %s not supported", formatName), http.StatusBadRequest, nil)
+		return
+	}
+	group := query.Get("group")
+	if group != "" && group != "part" {
+		respondWithError(w, "Cues can only be grouped by part", http.StatusBadRequest, nil)
+		return
+	}
+	reel := query.Get("reel")
+
+	ep, err := cfg.db.GetEpisodeByID(r.Context(), episodeID)
+	if err != nil {
+		respondWithError(w, "Episode not found", http.StatusNotFound, err)
+		return
+	}
+	if !cfg.requireProjectVisible(w, r, ep.ProjectID) {
+		return
+	}
+	prj, err := cfg.db.GetProjectByID(r.Context(), ep.ProjectID)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+	client, err := cfg.db.GetClientByID(r.Context(), prj.ClientID)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+	clientInfo, err := cfg.getClientDetails(r, client)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+	allCues, err := cfg.db.GetCuesForEpisode(r.Context(), episodeID)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+	parts, err := cfg.db.GetAllParts(r.Context())
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+
+	cues := []db.Cue{}
+	rates := []string{}
+	for _, c := range allCues {
+		if reel != "" && !strings.EqualFold(c.Reel.String, reel) {
+			continue
+		}
+		cues = append(cues, c)
+		if !slices.Contains(rates, c.FrameRate) {
+			rates = append(rates, c.FrameRate)
+		}
+	}
+
+	partOrder := map[string]int32{}
+	for _, p := range parts {
+		partOrder[p.Code] = p.SortOrder
+	}
+	err = sortCues(cues, query.Get("sort"), partOrder)
+	if err != nil {
+		respondWithError(w, err.Error(), http.StatusBadRequest, err)
+		return
+	}
+
+	code := cfg.episodeCode(r.Context(), ep)
+	sheet := cuesheet.Sheet{
+		Title:     fmt.Sprintf("%s, episode %s", prj.Title, code),
+		FrameRate: strings.Join(rates, ", "),
+	}
+	if ep.Title.Valid {
+		sheet.Title += ": " + ep.Title.String
+	}
+	sheet.Details = append(sheet.Details, clientInfo.documentDetails()...)
+	if reel != "" {
+		sheet.Details = append(sheet.Details, "Reel "+reel)
+	}
+	if group == "part" {
+		sheet.Groups = groupCuesByPart(cues, parts)
+	} else {
+		list := []cuesheet.Cue{}
+		for _, c := range cues {
+			list = append(list, cueToSheet(c))
+		}
+		sheet.Groups = []cuesheet.Group{{Cues: list}}
+	}
+
+	filename := fmt.Sprintf("%s_%s", prj.Title, code)
+	if reel != "" {
+		filename += "_" + reel
+	}
+	filename = strings.Map(func(r rune) rune {
+		if r == '"' || r == '/' || r == '\\' || r < 32 {
+			return '_'
+		}
+		return r
+	}, filename)
+
+	w.Header().Set("Content-Type", format.contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`inline; filename="%s_cues.%s"`, filename, format.extension))
+	w.WriteHeader(http.StatusOK)
+	err = format.write(w, sheet)
+	if err != nil {
+		// The headers are already sent, so all we can do is log the error
+		log.Println("Error writing cue sheet", err)
+	}
+}
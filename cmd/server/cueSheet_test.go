@@ -0,0 +1,68 @@
+package main
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/Denisowiec/FoleyBookkeeper/internal/db"
+)
+
+func TestNaturalCompare(t *testing.T) {
+	ordered := []string{"F1", "F2", "F10", "f11", "F11a", "F100", "G1"}
+	for i := 0; i < len(ordered)-1; i++ {
+		if naturalCompare(ordered[i], ordered[i+1]) >= 0 || naturalCompare(ordered[i+1], ordered[i]) <= 0 {
+			t.Errorf("%s should come before %s", ordered[i], ordered[i+1])
+		}
+	}
+}
+
+func TestSortAndGroupCues(t *testing.T) {
+	part := func(code string) sql.NullString {
+		return sql.NullString{String: code, Valid: code != ""}
+	}
+	cues := []db.Cue{
+		{CueNumber: "F10", FrameRate: "25", FrameIn: 100, FrameOut: 300, Part: part("props"), Status: db.CueStatusRecorded},
+		{CueNumber: "F2", FrameRate: "25", FrameIn: 500, FrameOut: 510, Part: part("footsteps"), Status: db.CueStatusPending},
+		{CueNumber: "F3", FrameRate: "25", FrameIn: 50, FrameOut: 60, Part: part(""), Status: db.CueStatusApproved},
+		{CueNumber: "F1", FrameRate: "25", FrameIn: 10, FrameOut: 20, Part: part("footsteps"), Status: db.CueStatusPending},
+	}
+	parts := []db.Part{
+		{Code: "footsteps", DisplayName: "Footsteps", SortOrder: 1},
+		{Code: "props", DisplayName: "Props", SortOrder: 2},
+	}
+	partOrder := map[string]int32{"footsteps": 1, "props": 2}
+
+	numbers := func() string {
+		s := ""
+		for _, c := range cues {
+			s += c.CueNumber + " "
+		}
+		return s
+	}
+	cases := map[string]string{
+		"":          "F1 F3 F10 F2 ",
+		"number":    "F1 F2 F3 F10 ",
+		"-number":   "F10 F3 F2 F1 ",
+		"part":      "F1 F2 F10 F3 ",
+		"status":    "F1 F2 F10 F3 ",
+		"-duration": "F10 F1 F3 F2 ",
+	}
+	for order, expected := range cases {
+		err := sortCues(cues, order, partOrder)
+		if err != nil {
+			t.Fatalf("error sorting by %s: %s", order, err)
+		}
+		if numbers() != expected {
+			t.Errorf("sorted by %q as %s, expected %s", order, numbers(), expected)
+		}
+	}
+	if err := sortCues(cues, "colour", partOrder); err == nil {
+		t.Error("unknown sort order should give an error")
+	}
+
+	sortCues(cues, "number", partOrder)
+	groups := groupCuesByPart(cues, parts)
+	if len(groups) != 3 || groups[0].Name != "Footsteps" || len(groups[0].Cues) != 2 || groups[2].Name != "No part" {
+		t.Errorf("cues grouped as %+v", groups)
+	}
+}
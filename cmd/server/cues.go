@@ -0,0 +1,485 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Denisowiec/FoleyBookkeeper/internal/db"
+	"github.com/Denisowiec/FoleyBookkeeper/internal/timecode"
+	"github.com/google/uuid"
+)
+
+func strToCueStatus(input string) (db.CueStatus, error) {
+	switch input {
+	case "", "pending":
+		return db.CueStatusPending, nil
+	case "recorded":
+		return db.CueStatusRecorded, nil
+	case "edited":
+		return db.CueStatusEdited, nil
+	case "approved":
+		return db.CueStatusApproved, nil
+	case "omitted":
+		return db.CueStatusOmitted, nil
+	default:
+		return "", fmt.Errorf("cue status unknown")
+	}
+}
+
+type cueInputType struct {
+	CueNumber   string `json:"cue_number"`
+	Reel        string `json:"reel"`
+	TimecodeIn  string `json:"timecode_in"`
+	TimecodeOut string `json:"timecode_out"`
+	FrameRate   string `json:"frame_rate"`
+	Description string `json:"description"`
+	Part        string `json:"part"`
+	Status      string `json:"status"`
+}
+
+func parseCueInput(input cueInputType) (db.CreateCueParams, error) {
+	// Converts the json input into parameters ready for the database.
+	// The episode is left for the caller to fill in
+	params := db.CreateCueParams{}
+
+	params.CueNumber = strings.TrimSpace(input.CueNumber)
+	if params.CueNumber == "" {
+		return params, fmt.Errorf("cue number required")
+	}
+
+	rate, err := timecode.ParseRate(input.FrameRate)
+	if err != nil {
+		return params, err
+	}
+	tcIn, err := timecode.Parse(input.TimecodeIn, rate)
+	if err != nil {
+		return params, err
+	}
+	tcOut, err := timecode.Parse(input.TimecodeOut, rate)
+	if err != nil {
+		return params, err
+	}
+	if tcOut.Frames < tcIn.Frames {
+		return params, fmt.Errorf("cue ends before it starts")
+	}
+
+	params.Status, err = strToCueStatus(input.Status)
+	if err != nil {
+		return params, err
+	}
+
+	params.Reel = sql.NullString{String: input.Reel, Valid: input.Reel != ""}
+	params.FrameIn = tcIn.Frames
+	params.FrameOut = tcOut.Frames
+	params.FrameRate = rate.Name
+	params.Description = sql.NullString{String: input.Description, Valid: input.Description != ""}
+	part := strings.ToLower(strings.TrimSpace(input.Part))
+	params.Part = sql.NullString{String: part, Valid: part != ""}
+	return params, nil
+}
+
+// Cues go out with their timecodes formatted instead of frame counts
+type cueResponse struct {
+	ID          uuid.UUID    `json:"id"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+	EpisodeID   uuid.UUID    `json:"episode_id"`
+	CueNumber   string       `json:"cue_number"`
+	Reel        string       `json:"reel"`
+	TimecodeIn  string       `json:"timecode_in"`
+	TimecodeOut string       `json:"timecode_out"`
+	Duration    string       `json:"duration"`
+	FrameRate   string       `json:"frame_rate"`
+	Description string       `json:"description"`
+	Part        string       `json:"part"`
+	Status      db.CueStatus `json:"status"`
+}
+
+func cueToResponse(c db.Cue) cueResponse {
+	// The database only accepts supported frame rates
+	rate, _ := timecode.ParseRate(c.FrameRate)
+	return cueResponse{
+		ID:          c.ID,
+		CreatedAt:   c.CreatedAt,
+		UpdatedAt:   c.UpdatedAt,
+		EpisodeID:   c.EpisodeID,
+		CueNumber:   c.CueNumber,
+		Reel:        c.Reel.String,
+		TimecodeIn:  timecode.FromFrames(c.FrameIn, rate).String(),
+		TimecodeOut: timecode.FromFrames(c.FrameOut, rate).String(),
+		Duration:    timecode.FromFrames(c.FrameOut-c.FrameIn, rate).String(),
+		FrameRate:   c.FrameRate,
+		Description: c.Description.String,
+		Part:        c.Part.String,
+		Status:      c.Status,
+	}
+}
+
+func cuesToResponse(cues []db.Cue) []cueResponse {
+	list := []cueResponse{}
+	for _, c := range cues {
+		list = append(list, cueToResponse(c))
+	}
+	return list
+}
+
+func (cfg *apiConfig) checkCuePart(r *http.Request, part sql.NullString) error {
+	// Cues don't have to name a part, but if they do it has to exist
+	if !part.Valid {
+		return nil
+	}
+	_, err := cfg.db.GetPartByCode(r.Context(), part.String)
+	if err != nil {
+		return fmt.Errorf("part %s unknown", part.String)
+	}
+	return nil
+}
+
+func (cfg *apiConfig) getCueProject(ctx context.Context, cueID uuid.UUID) (db.Cue, uuid.UUID, error) {
+	// Cues belong to episodes, who may see or change them is up to the
+	// episode's project
+	cue, err := cfg.db.GetCue(ctx, cueID)
+	if err != nil {
+		return db.Cue{}, uuid.UUID{}, err
+	}
+	episode, err := cfg.db.GetEpisodeByID(ctx, cue.EpisodeID)
+	if err != nil {
+		return db.Cue{}, uuid.UUID{}, err
+	}
+	return cue, episode.ProjectID, nil
+}
+
+func (cfg *apiConfig) handlerCreateCue(w http.ResponseWriter, r *http.Request) {
+	// Adds a cue to an episode. Timecodes are given as HH:MM:SS:FF
+	// at the cue's frame rate
+	_, _, err := authenticateUser(r, cfg.secret)
+	if err != nil {
+		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+		return
+	}
+
+	episodeID, err := uuid.Parse(r.PathValue("episodeid"))
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+
+	cueInput := cueInputType{}
+	decoder := json.NewDecoder(r.Body)
+	err = decoder.Decode(&cueInput)
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+
+	createCueParams, err := parseCueInput(cueInput)
+	if err != nil {
+		respondWithError(w, err.Error(), http.StatusBadRequest, err)
+		return
+	}
+	createCueParams.EpisodeID = episodeID
+
+	episode, err := cfg.db.GetEpisodeByID(r.Context(), episodeID)
+	if err != nil {
+		respondWithError(w, "Episode not found", http.StatusNotFound, err)
+		return
+	}
+	if !cfg.requireProjectContribution(w, r, episode.ProjectID) {
+		return
+	}
+
+	err = cfg.checkCuePart(r, createCueParams.Part)
+	if err != nil {
+		respondWithError(w, err.Error(), http.StatusBadRequest, err)
+		return
+	}
+
+	cue, err := cfg.db.CreateCue(r.Context(), createCueParams)
+	if err != nil {
+		respondWithError(w, "Error creating cue", http.StatusInternalServerError, err)
+		return
+	}
+
+	err = respondWithJSON(w, http.StatusCreated, cueToResponse(cue))
+	if err != nil {
+		respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
+		return
+	}
+}
+
+func (cfg *apiConfig) handlerUpdateCue(w http.ResponseWriter, r *http.Request) {
+	_, _, err := authenticateUser(r, cfg.secret)
+	if err != nil {
+		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+		return
+	}
+
+	cueID, err := uuid.Parse(r.PathValue("cueid"))
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+
+	cueInput := cueInputType{}
+	decoder := json.NewDecoder(r.Body)
+	err = decoder.Decode(&cueInput)
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+
+	params, err := parseCueInput(cueInput)
+	if err != nil {
+		respondWithError(w, err.Error(), http.StatusBadRequest, err)
+		return
+	}
+
+	_, projectID, err := cfg.getCueProject(r.Context(), cueID)
+	if err != nil {
+		respondWithError(w, "Cue not found", http.StatusNotFound, err)
+		return
+	}
+	if !cfg.requireProjectContribution(w, r, projectID) {
+		return
+	}
+
+	err = cfg.checkCuePart(r, params.Part)
+	if err != nil {
+		respondWithError(w, err.Error(), http.StatusBadRequest, err)
+		return
+	}
+
+	updateCueParams := db.UpdateCueParams{
+		ID:          cueID,
+		CueNumber:   params.CueNumber,
+		Reel:        params.Reel,
+		FrameIn:     params.FrameIn,
+		FrameOut:    params.FrameOut,
+		FrameRate:   params.FrameRate,
+		Description: params.Description,
+		Part:        params.Part,
+		Status:      params.Status,
+	}
+	cue, err := cfg.db.UpdateCue(r.Context(), updateCueParams)
+	if err != nil {
+		respondWithError(w, "Cue not found", http.StatusNotFound, err)
+		return
+	}
+
+	err = respondWithJSON(w, http.StatusAccepted, cueToResponse(cue))
+	if err != nil {
+		respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
+		return
+	}
+}
+
+func (cfg *apiConfig) handlerGetCue(w http.ResponseWriter, r *http.Request) {
+	_, _, err := authenticateUser(r, cfg.secret)
+	if err != nil {
+		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+		return
+	}
+
+	cueID, err := uuid.Parse(r.PathValue("cueid"))
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+
+	cue, projectID, err := cfg.getCueProject(r.Context(), cueID)
+	if err != nil {
+		respondWithError(w, "Cue not found", http.StatusNotFound, err)
+		return
+	}
+	if !cfg.requireProjectVisible(w, r, projectID) {
+		return
+	}
+
+	err = respondWithJSON(w, http.StatusOK, cueToResponse(cue))
+	if err != nil {
+		respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
+		return
+	}
+}
+
+func (cfg *apiConfig) handlerGetCuesForEpisode(w http.ResponseWriter, r *http.Request) {
+	// Lists the cues of an episode with the sessions that covered them.
+	// A session's minutes are split evenly between the cues it covered
+	_, _, err := authenticateUser(r, cfg.secret)
+	if err != nil {
+		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+		return
+	}
+
+	episodeID, err := uuid.Parse(r.PathValue("episodeid"))
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+
+	episode, err := cfg.db.GetEpisodeByID(r.Context(), episodeID)
+	if err != nil {
+		respondWithError(w, "Episode not found", http.StatusNotFound, err)
+		return
+	}
+	if !cfg.requireProjectVisible(w, r, episode.ProjectID) {
+		return
+	}
+
+	cues, err := cfg.db.GetCuesForEpisode(r.Context(), episodeID)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+	progress, err := cfg.db.GetCueProgressForEpisode(r.Context(), episodeID)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+	progressByCue := map[uuid.UUID]db.GetCueProgressForEpisodeRow{}
+	for _, p := range progress {
+		progressByCue[p.ID] = p
+	}
+
+	type listItem struct {
+		cueResponse
+		SessionCount int64 `json:"session_count"`
+		Minutes      int64 `json:"minutes"`
+	}
+	list := []listItem{}
+	for _, c := range cues {
+		list = append(list, listItem{
+			cueResponse:  cueToResponse(c),
+			SessionCount: progressByCue[c.ID].SessionCount,
+			Minutes:      progressByCue[c.ID].Minutes,
+		})
+	}
+
+	err = respondWithJSON(w, http.StatusOK, list)
+	if err != nil {
+		respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
+		return
+	}
+}
+
+func (cfg *apiConfig) handlerDeleteCue(w http.ResponseWriter, r *http.Request) {
+	_, _, err := authenticateUser(r, cfg.secret)
+	if err != nil {
+		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+		return
+	}
+
+	cueID, err := uuid.Parse(r.PathValue("cueid"))
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+
+	_, projectID, err := cfg.getCueProject(r.Context(), cueID)
+	if err != nil {
+		respondWithError(w, "Cue not found", http.StatusNotFound, err)
+		return
+	}
+	if !cfg.requireProjectContribution(w, r, projectID) {
+		return
+	}
+
+	cue, err := cfg.db.DeleteCue(r.Context(), cueID)
+	if err != nil {
+		respondWithError(w, "Cue not found", http.StatusNotFound, err)
+		return
+	}
+
+	err = respondWithJSON(w, http.StatusAccepted, cueToResponse(cue))
+	if err != nil {
+		respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
+		return
+	}
+}
+
+func (cfg *apiConfig) handlerAddCuesToSession(w http.ResponseWriter, r *http.Request) {
+	// Records which cues a session covered. The cues have to belong
+	// to the session's episode
+	_, _, err := authenticateUser(r, cfg.secret)
+	if err != nil {
+		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+		return
+	}
+
+	sessionID, err := uuid.Parse(r.PathValue("sessionid"))
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+
+	input := struct {
+		CueIDs []string `json:"cue_ids"`
+	}{}
+	decoder := json.NewDecoder(r.Body)
+	err = decoder.Decode(&input)
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+
+	session, err := cfg.db.GetSession(r.Context(), sessionID)
+	if err != nil {
+		respondWithError(w, "Session not found", http.StatusNotFound, err)
+		return
+	}
+	if !cfg.requireSessionLogging(w, r, session.ProjectID) {
+		return
+	}
+	if sessionIsLocked(session.Status) {
+		respondWithError(w, "Session is locked by its timesheet", http.StatusConflict, nil)
+		return
+	}
+
+	cueIDs := []uuid.UUID{}
+	for _, c := range input.CueIDs {
+		cueID, err := uuid.Parse(c)
+		if err != nil {
+			respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+			return
+		}
+		cue, err := cfg.db.GetCue(r.Context(), cueID)
+		if err != nil {
+			respondWithError(w, "Cue not found", http.StatusNotFound, err)
+			return
+		}
+		if !session.EpisodeID.Valid || cue.EpisodeID != session.EpisodeID.UUID {
+			respondWithError(w, fmt.Sprintf("Cue %s belongs to another episode", cue.CueNumber), http.StatusBadRequest, nil)
+			return
+		}
+		cueIDs = append(cueIDs, cueID)
+	}
+
+	for _, cueID := range cueIDs {
+		addCueParams := db.AddCueToSessionParams{
+			SessionID: sessionID,
+			CueID:     cueID,
+		}
+		_, err = cfg.db.AddCueToSession(r.Context(), addCueParams)
+		if err != nil {
+			respondWithError(w, "Error adding cue to session", http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	cues, err := cfg.db.GetCuesForSession(r.Context(), sessionID)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+
+	err = respondWithJSON(w, http.StatusAccepted, cuesToResponse(cues))
+	if err != nil {
+		respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
+		return
+	}
+}
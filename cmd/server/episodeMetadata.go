@@ -0,0 +1,179 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Denisowiec/FoleyBookkeeper/internal/timecode"
+	"github.com/google/uuid"
+)
+
+func parseRuntime(input string) (int32, error) {
+	// Runtimes can be given as HH:MM:SS, MM:SS, a duration like 22m30s
+	// or a number of minutes
+	input = strings.TrimSpace(input)
+	if minutes, err := strconv.Atoi(input); err == nil && minutes > 0 {
+		return int32(minutes * 60), nil
+	}
+	if d, err := time.ParseDuration(input); err == nil && d > 0 {
+		return int32(d.Round(time.Second).Seconds()), nil
+	}
+	fields := strings.Split(input, ":")
+	if len(fields) == 2 || len(fields) == 3 {
+		seconds := 0
+		for i, f := range fields {
+			value, err := strconv.Atoi(f)
+			if err != nil || value < 0 || (i > 0 && value >= 60) {
+				return 0, fmt.Errorf("invalid runtime %s", input)
+			}
+			seconds = seconds*60 + value
+		}
+		if seconds > 0 {
+			return int32(seconds), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid runtime %s", input)
+}
+
+// Runtime, picture lock and frame rate given as null keep their values,
+// empty ones are cleared
+type episodeMetadataInput struct {
+	Runtime     *string `json:"runtime"`
+	PictureLock *string `json:"picture_lock"`
+	FrameRate   *string `json:"frame_rate"`
+}
+
+type episodeMetadata struct {
+	RuntimeSeconds sql.NullInt32
+	PictureLock    sql.NullTime
+	FrameRate      sql.NullString
+}
+
+func parseEpisodeMetadata(input episodeMetadataInput, metadata episodeMetadata) (episodeMetadata, error) {
+	if input.Runtime != nil {
+		metadata.RuntimeSeconds = sql.NullInt32{}
+		if *input.Runtime != "" {
+			seconds, err := parseRuntime(*input.Runtime)
+			if err != nil {
+				return episodeMetadata{}, err
+			}
+			metadata.RuntimeSeconds = sql.NullInt32{Int32: seconds, Valid: true}
+		}
+	}
+	if input.PictureLock != nil {
+		metadata.PictureLock = sql.NullTime{}
+		if *input.PictureLock != "" {
+			date, err := time.Parse(time.DateOnly, *input.PictureLock)
+			if err != nil {
+				return episodeMetadata{}, fmt.Errorf("invalid picture lock date %s", *input.PictureLock)
+			}
+			metadata.PictureLock = sql.NullTime{Time: date, Valid: true}
+		}
+	}
+	if input.FrameRate != nil {
+		metadata.FrameRate = sql.NullString{}
+		if *input.FrameRate != "" {
+			rate, err := timecode.ParseRate(*input.FrameRate)
+			if err != nil {
+				return episodeMetadata{}, err
+			}
+			metadata.FrameRate = sql.NullString{String: rate.Name, Valid: true}
+		}
+	}
+	return metadata, nil
+}
+
+func workPerRuntimeMinute(minutes int64, runtimeSeconds sql.NullInt32) *float64 {
+	// Episodes without a runtime have no ratio
+	if !runtimeSeconds.Valid || runtimeSeconds.Int32 <= 0 {
+		return nil
+	}
+	ratio := float64(minutes) / (float64(runtimeSeconds.Int32) / 60)
+	ratio = float64(int64(ratio*100+0.5)) / 100
+	return &ratio
+}
+
+func (cfg *apiConfig) handlerGetRuntimeReport(w http.ResponseWriter, r *http.Request) {
+	// Returns the minutes logged for every episode of a project against its
+	// runtime, with the totals of the episodes whose runtime is known
+	_, _, err := authenticateUser(r, cfg.secret)
+	if err != nil {
+		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+		return
+	}
+
+	projectID, err := uuid.Parse(r.PathValue("projectid"))
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+	if !cfg.requireProjectVisible(w, r, projectID) {
+		return
+	}
+	_, err = cfg.db.GetProjectByID(r.Context(), projectID)
+	if err != nil {
+		respondWithError(w, "Project not found", http.StatusNotFound, err)
+		return
+	}
+
+	rows, err := cfg.db.GetRuntimeReportForProject(r.Context(), projectID)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+	episodes, err := cfg.getProjectEpisodes(r.Context(), projectID)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+
+	type reportRow struct {
+		EpisodeID      uuid.UUID `json:"episode_id"`
+		Code           string    `json:"code"`
+		Title          string    `json:"title"`
+		RuntimeSeconds *int32    `json:"runtime_seconds"`
+		PictureLock    string    `json:"picture_lock"`
+		Minutes        int64     `json:"minutes"`
+		PerRuntimeMin  *float64  `json:"minutes_per_runtime_minute"`
+	}
+	report := struct {
+		ProjectID      uuid.UUID   `json:"project_id"`
+		Episodes       []reportRow `json:"episodes"`
+		RuntimeSeconds int32       `json:"runtime_seconds"`
+		Minutes        int64       `json:"minutes"`
+		PerRuntimeMin  *float64    `json:"minutes_per_runtime_minute"`
+	}{
+		ProjectID: projectID,
+		Episodes:  []reportRow{},
+	}
+
+	for _, row := range rows {
+		item := reportRow{
+			EpisodeID:     row.ID,
+			Title:         row.Title.String,
+			Minutes:       row.Minutes,
+			PerRuntimeMin: workPerRuntimeMinute(row.Minutes, row.RuntimeSeconds),
+		}
+		item.Code = episodes.seasonCode(row.SeasonID, row.EpisodeNumber).String()
+		if row.RuntimeSeconds.Valid {
+			item.RuntimeSeconds = &row.RuntimeSeconds.Int32
+			report.RuntimeSeconds += row.RuntimeSeconds.Int32
+			report.Minutes += row.Minutes
+		}
+		if row.PictureLock.Valid {
+			item.PictureLock = row.PictureLock.Time.Format(time.DateOnly)
+		}
+		report.Episodes = append(report.Episodes, item)
+	}
+	report.PerRuntimeMin = workPerRuntimeMinute(report.Minutes, sql.NullInt32{Int32: report.RuntimeSeconds, Valid: true})
+
+	err = respondWithJSON(w, http.StatusOK, report)
+	if err != nil {
+		respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
+		return
+	}
+}
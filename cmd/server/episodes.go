@@ -1,14 +1,54 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"net/http"
 
 	"github.com/Denisowiec/FoleyBookkeeper/internal/db"
+	"github.com/Denisowiec/FoleyBookkeeper/internal/episodecode"
 	"github.com/google/uuid"
 )
 
+// The season can be given by its ID, or together with the episode number
+// as a code like S02E05
+type episodeInputType struct {
+	Title         string `json:"title"`
+	EpisodeNumber int    `json:"episode_number"`
+	ProjectID     string `json:"project_id"`
+	SeasonID      string `json:"season_id"`
+	Code          string `json:"code"`
+	episodeMetadataInput
+}
+
+func (cfg *apiConfig) episodeNumberAndSeason(ctx context.Context, projectID uuid.UUID, input episodeInputType) (int32, uuid.NullUUID, error) {
+	seasonID, err := parseNullUUID(input.SeasonID)
+	if err != nil {
+		return 0, uuid.NullUUID{}, err
+	}
+	number := int32(input.EpisodeNumber)
+	if input.Code != "" {
+		code, err := episodecode.Parse(input.Code)
+		if err != nil {
+			return 0, uuid.NullUUID{}, err
+		}
+		number = int32(code.Episode)
+		if code.Season != 0 {
+			season, err := cfg.getSeason(ctx, projectID, code.Season)
+			if err != nil {
+				return 0, uuid.NullUUID{}, err
+			}
+			seasonID = uuid.NullUUID{UUID: season.ID, Valid: true}
+		}
+	}
+	err = cfg.checkEpisodeSeason(ctx, projectID, seasonID)
+	if err != nil {
+		return 0, uuid.NullUUID{}, err
+	}
+	return number, seasonID, nil
+}
+
 func (cfg *apiConfig) handlerCreateEpisode(w http.ResponseWriter, r *http.Request) {
 	_, _, err := authenticateUser(r, cfg.secret)
 	if err != nil {
@@ -16,12 +56,6 @@ func (cfg *apiConfig) handlerCreateEpisode(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	type episodeInputType struct {
-		Title         string `json:"title"`
-		EpisodeNumber int    `json:"episode_number"`
-		ProjectID     string `json:"project_id"`
-	}
-
 	episodeInput := episodeInputType{}
 	decoder := json.NewDecoder(r.Body)
 
@@ -37,9 +71,25 @@ func (cfg *apiConfig) handlerCreateEpisode(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	episodeNumber, seasonID, err := cfg.episodeNumberAndSeason(r.Context(), projectID, episodeInput)
+	if err != nil {
+		respondWithError(w, err.Error(), http.StatusBadRequest, err)
+		return
+	}
+
+	metadata, err := parseEpisodeMetadata(episodeInput.episodeMetadataInput, episodeMetadata{})
+	if err != nil {
+		respondWithError(w, err.Error(), http.StatusBadRequest, err)
+		return
+	}
+
 	createEpisodeParams := db.CreateEpisodeParams{}
 	createEpisodeParams.ProjectID = projectID
-	createEpisodeParams.EpisodeNumber = int32(episodeInput.EpisodeNumber)
+	createEpisodeParams.EpisodeNumber = episodeNumber
+	createEpisodeParams.SeasonID = seasonID
+	createEpisodeParams.RuntimeSeconds = metadata.RuntimeSeconds
+	createEpisodeParams.PictureLock = metadata.PictureLock
+	createEpisodeParams.FrameRate = metadata.FrameRate
 	if episodeInput.Title != "" {
 		createEpisodeParams.Title = sql.NullString{String: episodeInput.Title, Valid: true}
 	} else {
@@ -66,12 +116,6 @@ func (cfg *apiConfig) handlerUpdateEpisode(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	type episodeInputType struct {
-		Title         string `json:"title"`
-		EpisodeNumber int    `json:"episode_number"`
-		ProjectID     string `json:"project_id"`
-	}
-
 	episodeInput := episodeInputType{}
 	decoder := json.NewDecoder(r.Body)
 
@@ -92,10 +136,37 @@ func (cfg *apiConfig) handlerUpdateEpisode(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	episodeNumber, seasonID, err := cfg.episodeNumberAndSeason(r.Context(), projectID, episodeInput)
+	if err != nil {
+		respondWithError(w, err.Error(), http.StatusBadRequest, err)
+		return
+	}
+
+	// Runtime, picture lock and frame rate not given keep their values
+	previous, err := cfg.db.GetEpisodeByID(r.Context(), episodeID)
+	if err != nil {
+		respondWithError(w, "Episode not found", http.StatusNotFound, err)
+		return
+	}
+	metadata := episodeMetadata{
+		RuntimeSeconds: previous.RuntimeSeconds,
+		PictureLock:    previous.PictureLock,
+		FrameRate:      previous.FrameRate,
+	}
+	metadata, err = parseEpisodeMetadata(episodeInput.episodeMetadataInput, metadata)
+	if err != nil {
+		respondWithError(w, err.Error(), http.StatusBadRequest, err)
+		return
+	}
+
 	updateEpisodeParams := db.UpdateEpisodeParams{}
 	updateEpisodeParams.ID = episodeID
 	updateEpisodeParams.ProjectID = projectID
-	updateEpisodeParams.EpisodeNumber = int32(episodeInput.EpisodeNumber)
+	updateEpisodeParams.EpisodeNumber = episodeNumber
+	updateEpisodeParams.SeasonID = seasonID
+	updateEpisodeParams.RuntimeSeconds = metadata.RuntimeSeconds
+	updateEpisodeParams.PictureLock = metadata.PictureLock
+	updateEpisodeParams.FrameRate = metadata.FrameRate
 	if episodeInput.Title != "" {
 		updateEpisodeParams.Title = sql.NullString{String: episodeInput.Title, Valid: true}
 	} else {
@@ -132,6 +203,9 @@ func (cfg *apiConfig) handlerGetEpisodeByID(w http.ResponseWriter, r *http.Reque
 		respondWithError(w, "Episode not found", http.StatusNotFound, err)
 		return
 	}
+	if !cfg.requireProjectVisible(w, r, ep.ProjectID) {
+		return
+	}
 
 	err = respondWithJSON(w, http.StatusOK, ep)
 	if err != nil {
@@ -141,8 +215,8 @@ func (cfg *apiConfig) handlerGetEpisodeByID(w http.ResponseWriter, r *http.Reque
 }
 
 func (cfg *apiConfig) handlerGetEpisodesForProject(w http.ResponseWriter, r *http.Request) {
-	// Handles requests to list episodes for a project. An episode number can be provided
-	// in which case it will return a specific episode
+	// Handles requests to list episodes for a project. An episode number or a code
+	// like S02E05 can be provided in which case it will return a specific episode
 	_, _, err := authenticateUser(r, cfg.secret)
 	if err != nil {
 		respondWithError(w, "Operation unauthrized", http.StatusUnauthorized, err)
@@ -152,6 +226,7 @@ func (cfg *apiConfig) handlerGetEpisodesForProject(w http.ResponseWriter, r *htt
 	type episodesInputType struct {
 		ProjectID     string `json:"project_id"`
 		EpisodeNumber int    `json:"episode_number"`
+		Code          string `json:"code"`
 	}
 	episodesInput := episodesInputType{}
 
@@ -167,16 +242,23 @@ func (cfg *apiConfig) handlerGetEpisodesForProject(w http.ResponseWriter, r *htt
 		respondWithError(w, "Error processing user request", http.StatusBadRequest, err)
 		return
 	}
+	if !cfg.requireProjectVisible(w, r, projectID) {
+		return
+	}
 
-	if episodesInput.EpisodeNumber != 0 {
+	if episodesInput.EpisodeNumber != 0 || episodesInput.Code != "" {
 		// If a number is provided in the input it means that the client want to get as single episode of the given number
-		getEpisodeParams := db.GetEpisodeByNumberParams{
-			ProjectID:     projectID,
-			EpisodeNumber: int32(episodesInput.EpisodeNumber),
+		code := episodecode.Code{Episode: episodesInput.EpisodeNumber}
+		if episodesInput.Code != "" {
+			code, err = episodecode.Parse(episodesInput.Code)
+			if err != nil {
+				respondWithError(w, err.Error(), http.StatusBadRequest, err)
+				return
+			}
 		}
-		ep, err := cfg.db.GetEpisodeByNumber(r.Context(), getEpisodeParams)
+		ep, err := cfg.getEpisodeByCode(r.Context(), projectID, code)
 		if err != nil {
-			respondWithError(w, "Error getting data from database", http.StatusInternalServerError, err)
+			respondWithError(w, err.Error(), http.StatusNotFound, err)
 			return
 		}
 
@@ -202,6 +284,7 @@ func (cfg *apiConfig) handlerGetEpisodesForProject(w http.ResponseWriter, r *htt
 }
 
 func (cfg *apiConfig) handlerDeleteEpisode(w http.ResponseWriter, r *http.Request) {
+	// Moves an episode to the trash with its sessions
 	_, _, err := authenticateUser(r, cfg.secret)
 	if err != nil {
 		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
@@ -214,11 +297,29 @@ func (cfg *apiConfig) handlerDeleteEpisode(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	ep, err := cfg.db.DeleteEpisode(r.Context(), episodeID)
+	tx, err := cfg.conn.BeginTx(r.Context(), nil)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+	defer tx.Rollback()
+	qtx := cfg.db.WithTx(tx)
+
+	ep, err := qtx.SoftDeleteEpisode(r.Context(), episodeID)
 	if err != nil {
 		respondWithError(w, "Episode not found", http.StatusNotFound, err)
 		return
 	}
+	err = qtx.SoftDeleteSessionsForEpisode(r.Context(), uuid.NullUUID{UUID: episodeID, Valid: true})
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+	err = tx.Commit()
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
 	err = respondWithJSON(w, http.StatusAccepted, ep)
 	if err != nil {
 		respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
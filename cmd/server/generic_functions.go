@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"log"
 	"net/http"
+
+	"github.com/google/uuid"
 )
 
 type ErrorResponse struct {
@@ -43,3 +45,15 @@ func respondWithJSON[T any](w http.ResponseWriter, statusCode int, payload T) er
 	w.Write(dat)
 	return nil
 }
+
+func parseNullUUID(s string) (uuid.NullUUID, error) {
+	// Optional ids in user input come as empty strings, which translate to NULL
+	if s == "" {
+		return uuid.NullUUID{}, nil
+	}
+	id, err := uuid.Parse(s)
+	if err != nil {
+		return uuid.NullUUID{}, err
+	}
+	return uuid.NullUUID{UUID: id, Valid: true}, nil
+}
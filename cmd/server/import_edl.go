@@ -0,0 +1,234 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Denisowiec/FoleyBookkeeper/internal/db"
+	"github.com/Denisowiec/FoleyBookkeeper/internal/edl"
+	"github.com/Denisowiec/FoleyBookkeeper/internal/timecode"
+	"github.com/google/uuid"
+)
+
+// Reels of black and silence aren't worth a cue
+var edlFillerReels = map[string]bool{"BL": true, "BLK": true, "BLACK": true, "SILENCE": true}
+
+// Cues created by the EDL and Pro Tools imports
+type cueImportItem struct {
+	CueNumber   string `json:"cue_number"`
+	TimecodeIn  string `json:"timecode_in"`
+	TimecodeOut string `json:"timecode_out"`
+	Description string `json:"description"`
+	// Action is create, update or skip
+	Action string `json:"action"`
+	Reason string `json:"reason,omitempty"`
+
+	// FCM lines can change the rate between events
+	frameRate string
+	frameIn   int64
+	frameOut  int64
+}
+
+// Lines of an imported file that couldn't be understood
+type importUnparsedLine struct {
+	Line   int    `json:"line"`
+	Text   string `json:"text"`
+	Reason string `json:"reason"`
+}
+
+func edlCueItems(list edl.List, fromMarkers bool) []cueImportItem {
+	// Every event becomes a cue numbered after the event, or every locator
+	// becomes a cue numbered in the order they appear in the list
+	items := []cueImportItem{}
+	add := func(number string, in, out timecode.Timecode, description string) *cueImportItem {
+		items = append(items, cueImportItem{
+			CueNumber:   number,
+			TimecodeIn:  in.String(),
+			TimecodeOut: out.String(),
+			Description: description,
+			Action:      "create",
+			frameRate:   in.Rate.Name,
+			frameIn:     in.Frames,
+			frameOut:    out.Frames,
+		})
+		return &items[len(items)-1]
+	}
+
+	for _, e := range list.Events {
+		if fromMarkers {
+			for _, loc := range e.Locators {
+				add(fmt.Sprintf("M%03d", len(items)+1), loc.Timecode, loc.Timecode, loc.Comment)
+			}
+			continue
+		}
+
+		description := e.ClipName
+		if description == "" && len(e.Comments) > 0 {
+			description = e.Comments[0]
+		}
+		item := add(fmt.Sprintf("%03d", e.Number), e.RecordIn, e.RecordOut, description)
+		if edlFillerReels[strings.ToUpper(e.Reel)] {
+			item.Action, item.Reason = "skip", "event is filler"
+		} else if e.RecordOut.Frames == e.RecordIn.Frames {
+			item.Action, item.Reason = "skip", "event has no duration"
+		}
+	}
+	return items
+}
+
+func (cfg *apiConfig) handlerImportEDL(w http.ResponseWriter, r *http.Request) {
+	// Creates or updates the cues of an episode from a CMX3600 EDL.
+	// Without confirm set, it only returns a preview of what would be done.
+	// Cues are matched by their number, so importing a new version of the
+	// list updates the timecodes but keeps the part and status of each cue
+	_, _, err := authenticateUser(r, cfg.secret)
+	if err != nil {
+		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+		return
+	}
+
+	importInput := struct {
+		EDL       string `json:"edl"`
+		EpisodeID string `json:"episode_id"`
+		FrameRate string `json:"frame_rate"`
+		CuesFrom  string `json:"cues_from"`
+		Reel      string `json:"reel"`
+		Confirm   bool   `json:"confirm"`
+	}{}
+
+	decoder := json.NewDecoder(r.Body)
+	err = decoder.Decode(&importInput)
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+
+	fromMarkers := false
+	switch importInput.CuesFrom {
+	case "", "events":
+	case "markers":
+		fromMarkers = true
+	default:
+		respondWithError(w, "Cues can only come from events or markers", http.StatusBadRequest, nil)
+		return
+	}
+
+	episodeID, err := uuid.Parse(importInput.EpisodeID)
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+	episode, err := cfg.db.GetEpisodeByID(r.Context(), episodeID)
+	if err != nil {
+		respondWithError(w, "Episode not found", http.StatusNotFound, err)
+		return
+	}
+	if !cfg.requireProjectContribution(w, r, episode.ProjectID) {
+		return
+	}
+
+	// The rate can be left out if the list has a frame rate header
+	rate := timecode.Rate{}
+	if importInput.FrameRate != "" {
+		rate, err = timecode.ParseRate(importInput.FrameRate)
+		if err != nil {
+			respondWithError(w, err.Error(), http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	list, err := edl.Parse(strings.NewReader(importInput.EDL), rate)
+	if err != nil {
+		respondWithError(w, "Error parsing EDL", http.StatusBadRequest, err)
+		return
+	}
+
+	existing, err := cfg.db.GetCuesForEpisode(r.Context(), episodeID)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+	imported := map[string]bool{}
+	for _, c := range existing {
+		imported[c.CueNumber] = true
+	}
+
+	items := edlCueItems(list, fromMarkers)
+	for i := range items {
+		if items[i].Action != "skip" && imported[items[i].CueNumber] {
+			items[i].Action = "update"
+		}
+	}
+
+	unparsed := []importUnparsedLine{}
+	for _, l := range list.Unparsed {
+		unparsed = append(unparsed, importUnparsedLine{Line: l.Number, Text: l.Text, Reason: l.Reason})
+	}
+
+	type importResp struct {
+		Confirmed bool                 `json:"confirmed"`
+		Title     string               `json:"title"`
+		FrameRate string               `json:"frame_rate"`
+		Items     []cueImportItem      `json:"items"`
+		Unparsed  []importUnparsedLine `json:"unparsed"`
+	}
+	result := importResp{
+		Title:     list.Title,
+		FrameRate: list.Rate.Name,
+		Items:     items,
+		Unparsed:  unparsed,
+	}
+
+	if !importInput.Confirm {
+		err = respondWithJSON(w, http.StatusOK, result)
+		if err != nil {
+			respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	// Either the whole list gets imported or nothing does
+	tx, err := cfg.conn.BeginTx(r.Context(), nil)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+	defer tx.Rollback()
+	qtx := cfg.db.WithTx(tx)
+
+	for _, item := range items {
+		if item.Action == "skip" {
+			continue
+		}
+		importCueParams := db.ImportCueParams{
+			EpisodeID:   episodeID,
+			CueNumber:   item.CueNumber,
+			Reel:        sql.NullString{String: importInput.Reel, Valid: importInput.Reel != ""},
+			FrameIn:     item.frameIn,
+			FrameOut:    item.frameOut,
+			FrameRate:   item.frameRate,
+			Description: sql.NullString{String: item.Description, Valid: item.Description != ""},
+		}
+		_, err = qtx.ImportCue(r.Context(), importCueParams)
+		if err != nil {
+			respondWithError(w, fmt.Sprintf("Error importing cue %s", item.CueNumber), http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+
+	result.Confirmed = true
+	err = respondWithJSON(w, http.StatusCreated, result)
+	if err != nil {
+		respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
+		return
+	}
+}
@@ -0,0 +1,475 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Denisowiec/FoleyBookkeeper/internal/db"
+	"github.com/Denisowiec/FoleyBookkeeper/internal/episodecode"
+	"github.com/Denisowiec/FoleyBookkeeper/internal/ical"
+	"github.com/google/uuid"
+)
+
+// Event titles are matched against patterns with named groups. The project
+// group is required, episode, part and activity are optional.
+// The default pattern matches titles like "ProjectX E05 footsteps record"
+// or "ProjectX S02E05 footsteps record"
+const defaultTitlePattern = `^(?P<project>.+?)\s+(?:[Ss](?P<season>\d+))?[Ee](?P<episode>\d+)(?:\s+(?P<part>\S+))?(?:\s+(?P<activity>\S+))?\s*$`
+
+// Imported sessions without an activity in the title are recordings
+const defaultImportActivity = "record"
+
+type titleMatch struct {
+	Project       string
+	Season        int
+	EpisodeNumber int
+	Part          string
+	Activity      string
+}
+
+func compileTitlePatterns(patterns []string) ([]*regexp.Regexp, error) {
+	if len(patterns) == 0 {
+		patterns = []string{defaultTitlePattern}
+	}
+	compiled := []*regexp.Regexp{}
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, err
+		}
+		if re.SubexpIndex("project") < 0 {
+			return nil, fmt.Errorf("pattern %s has no project group", p)
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
+
+func matchEventTitle(patterns []*regexp.Regexp, title string) (titleMatch, bool) {
+	// Patterns are tried in order, the first one that matches wins
+	for _, re := range patterns {
+		groups := re.FindStringSubmatch(strings.TrimSpace(title))
+		if groups == nil {
+			continue
+		}
+		match := titleMatch{}
+		for i, name := range re.SubexpNames() {
+			switch name {
+			case "project":
+				match.Project = strings.TrimSpace(groups[i])
+			case "episode":
+				if groups[i] != "" {
+					number, err := strconv.Atoi(groups[i])
+					if err != nil {
+						return titleMatch{}, false
+					}
+					match.EpisodeNumber = number
+				}
+			case "season":
+				if groups[i] != "" {
+					number, err := strconv.Atoi(groups[i])
+					if err != nil {
+						return titleMatch{}, false
+					}
+					match.Season = number
+				}
+			case "part":
+				match.Part = strings.ToLower(groups[i])
+			case "activity":
+				match.Activity = strings.ToLower(groups[i])
+			}
+		}
+		if match.Project != "" {
+			return match, true
+		}
+	}
+	return titleMatch{}, false
+}
+
+type icsImportItem struct {
+	UID           string        `json:"uid"`
+	Summary       string        `json:"summary"`
+	StartsAt      time.Time     `json:"starts_at"`
+	EndsAt        time.Time     `json:"ends_at"`
+	ProjectID     uuid.UUID     `json:"project_id"`
+	ProjectTitle  string        `json:"project_title"`
+	EpisodeID     uuid.NullUUID `json:"episode_id"`
+	EpisodeNumber int           `json:"episode_number"`
+	EpisodeCode   string        `json:"episode_code"`
+	Part          string        `json:"part"`
+	Activity      string        `json:"activity"`
+	// Action is create, update or skip
+	Action string `json:"action"`
+	Reason string `json:"reason,omitempty"`
+	// Session rules in warn mode the imported session breaks
+	Warnings []sessionViolation `json:"warnings,omitempty"`
+}
+
+func (item *icsImportItem) applyViolations(violations []sessionViolation) {
+	// Sessions the create endpoint would refuse are skipped, the rest
+	// carry their warnings along
+	for _, v := range violations {
+		if v.Severity == ruleReject {
+			item.Action, item.Reason = "skip", v.Message
+			item.Warnings = nil
+			return
+		}
+	}
+	if len(violations) > 0 {
+		item.Warnings = violations
+	}
+}
+
+func (cfg *apiConfig) handlerImportICS(w http.ResponseWriter, r *http.Request) {
+	// Imports events of an iCalendar file as bookings or sessions.
+	// Without confirm set, it only returns a preview of what would be done.
+	// Events are identified by their UID, so importing the same file twice
+	// updates the bookings instead of duplicating them
+	_, _, err := authenticateUser(r, cfg.secret)
+	if err != nil {
+		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+		return
+	}
+
+	importInput := struct {
+		Calendar string   `json:"calendar"`
+		Create   string   `json:"create"`
+		Patterns []string `json:"patterns"`
+		RoomID   string   `json:"room_id"`
+		Confirm  bool     `json:"confirm"`
+	}{}
+
+	decoder := json.NewDecoder(r.Body)
+	err = decoder.Decode(&importInput)
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+
+	createSessions := false
+	switch importInput.Create {
+	case "", "bookings":
+	case "sessions":
+		createSessions = true
+	default:
+		respondWithError(w, "Import can only create bookings or sessions", http.StatusBadRequest, nil)
+		return
+	}
+
+	patterns, err := compileTitlePatterns(importInput.Patterns)
+	if err != nil {
+		respondWithError(w, "Invalid title pattern", http.StatusBadRequest, err)
+		return
+	}
+	roomID, err := parseNullUUID(importInput.RoomID)
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+
+	cal, err := ical.Parse(strings.NewReader(importInput.Calendar))
+	if err != nil {
+		respondWithError(w, "Error parsing calendar file", http.StatusBadRequest, err)
+		return
+	}
+
+	// Project titles are matched case-insensitively
+	projects, err := cfg.db.GetAllProjects(r.Context())
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+	projectsByTitle := map[string]db.Project{}
+	for _, p := range projects {
+		projectsByTitle[strings.ToLower(p.Title)] = p
+	}
+	episodesByProject := map[uuid.UUID]projectEpisodes{}
+	access, err := cfg.requestProjectAccess(r)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+
+	// Only active parts and activities can be used for new sessions
+	activeParts := map[string]bool{}
+	activeActivities := map[string]bool{}
+	if createSessions {
+		parts, err := cfg.db.GetAllParts(r.Context())
+		if err != nil {
+			respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+			return
+		}
+		for _, p := range parts {
+			activeParts[p.Code] = p.Active
+		}
+		activities, err := cfg.db.GetAllActivities(r.Context())
+		if err != nil {
+			respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+			return
+		}
+		for _, a := range activities {
+			activeActivities[a.Code] = a.Active
+		}
+	}
+
+	// We check which of the events were imported before
+	uids := []string{}
+	for _, e := range cal.Events {
+		uids = append(uids, e.UID)
+	}
+	imported := map[string]bool{}
+	locked := map[string]bool{}
+	trashed := map[string]bool{}
+	existingSessions := map[string]db.Session{}
+	// Imports overwrite the project of what they update, so the user has to
+	// be able to change the project it's on now too
+	previousProjects := map[string]uuid.UUID{}
+	// Sessions imported before are checked against the rules with their users
+	usersBySession := map[uuid.UUID][]db.GetUsersForSessionRow{}
+	if createSessions {
+		existing, err := cfg.db.GetSessionsByExternalUID(r.Context(), uids)
+		if err != nil {
+			respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+			return
+		}
+		for _, s := range existing {
+			imported[s.ExternalUid.String] = true
+			locked[s.ExternalUid.String] = sessionIsLocked(s.Status)
+			trashed[s.ExternalUid.String] = s.DeletedAt.Valid
+			existingSessions[s.ExternalUid.String] = s
+			previousProjects[s.ExternalUid.String] = s.ProjectID
+		}
+		usersBySession, err = cfg.getUsersForSessions(r.Context(), existing)
+		if err != nil {
+			respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+			return
+		}
+	} else {
+		existing, err := cfg.db.GetBookingsByExternalUID(r.Context(), uids)
+		if err != nil {
+			respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+			return
+		}
+		for _, b := range existing {
+			imported[b.ExternalUid.String] = true
+			previousProjects[b.ExternalUid.String] = b.ProjectID
+		}
+	}
+
+	items := []icsImportItem{}
+	for _, e := range cal.Events {
+		item := icsImportItem{
+			UID:      e.UID,
+			Summary:  e.Summary,
+			StartsAt: e.Start.Local(),
+			EndsAt:   e.End.Local(),
+			Action:   "create",
+		}
+		if imported[e.UID] {
+			item.Action = "update"
+		}
+		items = append(items, item)
+		current := &items[len(items)-1]
+
+		if e.UID == "" {
+			current.Action, current.Reason = "skip", "event has no UID"
+			continue
+		}
+		if e.Recurring {
+			// Occurrences of a series share the UID, each would overwrite the last
+			current.Action, current.Reason = "skip", "recurring events can't be imported"
+			continue
+		}
+		if locked[e.UID] {
+			current.Action, current.Reason = "skip", "session is locked by its timesheet"
+			continue
+		}
+		if trashed[e.UID] {
+			current.Action, current.Reason = "skip", "session is in the trash"
+			continue
+		}
+		if previous, ok := previousProjects[e.UID]; ok && !access.canContribute(previous) {
+			current.Action, current.Reason = "skip", "imported before into a project you can't change"
+			continue
+		}
+		if !current.EndsAt.After(current.StartsAt) {
+			current.Action, current.Reason = "skip", "event has no duration"
+			continue
+		}
+		match, ok := matchEventTitle(patterns, e.Summary)
+		if !ok {
+			current.Action, current.Reason = "skip", "title doesn't match any pattern"
+			continue
+		}
+		prj, ok := projectsByTitle[strings.ToLower(match.Project)]
+		if !ok || !access.canSee(prj.ID) {
+			current.Action, current.Reason = "skip", fmt.Sprintf("project %s not found", match.Project)
+			continue
+		}
+		if !access.canContribute(prj.ID) {
+			current.Action, current.Reason = "skip", fmt.Sprintf("you can't log sessions on %s", prj.Title)
+			if !createSessions {
+				current.Reason = fmt.Sprintf("you can't book %s", prj.Title)
+			}
+			continue
+		}
+		if createSessions && !projectAcceptsWork(prj.Status) {
+			current.Action, current.Reason = "skip", fmt.Sprintf("project %s is %s", prj.Title, prj.Status)
+			continue
+		}
+		current.ProjectID = prj.ID
+		current.ProjectTitle = prj.Title
+		current.Part = match.Part
+		current.Activity = match.Activity
+
+		if match.EpisodeNumber != 0 {
+			episodes, ok := episodesByProject[prj.ID]
+			if !ok {
+				episodes, err = cfg.getProjectEpisodes(r.Context(), prj.ID)
+				if err != nil {
+					respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+					return
+				}
+				episodesByProject[prj.ID] = episodes
+			}
+			code := episodecode.Code{Season: match.Season, Episode: match.EpisodeNumber}
+			ep, err := episodes.find(code)
+			if err != nil {
+				current.Action, current.Reason = "skip", fmt.Sprintf("%s of %s", err, prj.Title)
+				continue
+			}
+			current.EpisodeID = uuid.NullUUID{UUID: ep.ID, Valid: true}
+			current.EpisodeNumber = match.EpisodeNumber
+			current.EpisodeCode = episodes.code(ep).String()
+		}
+
+		if createSessions {
+			// Sessions need more information than bookings do
+			// Only series need the episode, other projects log sessions for the whole project
+			if !current.EpisodeID.Valid && prj.ProjectType == db.ProjectTypeSeries {
+				current.Action, current.Reason = "skip", "sessions of series need an episode"
+				continue
+			}
+			if !activeParts[current.Part] {
+				current.Action, current.Reason = "skip", fmt.Sprintf("part %q unknown", current.Part)
+				continue
+			}
+			if current.Activity == "" {
+				current.Activity = defaultImportActivity
+			}
+			if !activeActivities[current.Activity] {
+				current.Action, current.Reason = "skip", fmt.Sprintf("activity %q unknown", current.Activity)
+				continue
+			}
+
+			// The same rules as for sessions logged by hand
+			start := current.StartsAt
+			span := sessionSpan{
+				Date:      time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC),
+				StartTime: sql.NullTime{Time: start, Valid: true},
+				Duration:  int32(current.EndsAt.Sub(start).Minutes()),
+			}
+			userIDs := []uuid.UUID{}
+			if previous, ok := existingSessions[e.UID]; ok {
+				span.ID = previous.ID
+				for _, u := range usersBySession[previous.ID] {
+					userIDs = append(userIDs, u.UserID)
+				}
+			}
+			violations, err := cfg.validateSession(r.Context(), span, userIDs)
+			if err != nil {
+				respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+				return
+			}
+			current.applyViolations(violations)
+		}
+	}
+
+	if !importInput.Confirm {
+		preview := struct {
+			Confirmed bool            `json:"confirmed"`
+			Items     []icsImportItem `json:"items"`
+		}{
+			Confirmed: false,
+			Items:     items,
+		}
+		err = respondWithJSON(w, http.StatusOK, preview)
+		if err != nil {
+			respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	// Either everything gets imported or nothing does
+	tx, err := cfg.conn.BeginTx(r.Context(), nil)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+	defer tx.Rollback()
+	qtx := cfg.db.WithTx(tx)
+
+	for _, item := range items {
+		if item.Action == "skip" {
+			continue
+		}
+		uid := sql.NullString{String: item.UID, Valid: true}
+		if createSessions {
+			importSessionParams := db.ImportSessionParams{
+				Duration:     int32(item.EndsAt.Sub(item.StartsAt).Minutes()),
+				SessionDate:  item.StartsAt,
+				ProjectID:    item.ProjectID,
+				EpisodeID:    item.EpisodeID,
+				PartWorkedOn: item.Part,
+				ActivityDone: item.Activity,
+				ExternalUid:  uid,
+				StartTime:    sql.NullTime{Time: item.StartsAt, Valid: true},
+			}
+			var session db.Session
+			session, err = qtx.ImportSession(r.Context(), importSessionParams)
+			if err == nil {
+				err = advancePartStatus(r.Context(), qtx, session)
+			}
+		} else {
+			importBookingParams := db.ImportBookingParams{
+				Title:       item.Summary,
+				StartsAt:    item.StartsAt,
+				EndsAt:      item.EndsAt,
+				RoomID:      roomID,
+				ProjectID:   item.ProjectID,
+				EpisodeID:   item.EpisodeID,
+				ExternalUid: uid,
+			}
+			_, err = qtx.ImportBooking(r.Context(), importBookingParams)
+		}
+		if err != nil {
+			respondWithError(w, fmt.Sprintf("Error importing event %s", item.Summary), http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+
+	result := struct {
+		Confirmed bool            `json:"confirmed"`
+		Items     []icsImportItem `json:"items"`
+	}{
+		Confirmed: true,
+		Items:     items,
+	}
+	err = respondWithJSON(w, http.StatusCreated, result)
+	if err != nil {
+		respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
+		return
+	}
+}
@@ -0,0 +1,317 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Denisowiec/FoleyBookkeeper/internal/db"
+	"github.com/Denisowiec/FoleyBookkeeper/internal/protools"
+	"github.com/Denisowiec/FoleyBookkeeper/internal/timecode"
+	"github.com/google/uuid"
+)
+
+type trackResponse struct {
+	Name           string `json:"track_name"`
+	Comments       string `json:"comments"`
+	ClipCount      int32  `json:"clip_count"`
+	MutedClipCount int32  `json:"muted_clip_count"`
+	// Total length of the unmuted clips
+	ClipLength string `json:"clip_length"`
+}
+
+func trackToResponse(t db.EpisodeTrack) trackResponse {
+	// The rate comes from a parsed export, so it's supported
+	rate, _ := timecode.ParseRate(t.FrameRate)
+	return trackResponse{
+		Name:           t.TrackName,
+		Comments:       t.Comments.String,
+		ClipCount:      t.ClipCount,
+		MutedClipCount: t.MutedClipCount,
+		ClipLength:     timecode.FromFrames(t.ClipFrames, rate).String(),
+	}
+}
+
+func protoolsTrackStats(session protools.Session) []db.CreateEpisodeTrackParams {
+	// The episode is left for the caller to fill in
+	tracks := []db.CreateEpisodeTrackParams{}
+	for i, t := range session.Tracks {
+		params := db.CreateEpisodeTrackParams{
+			SessionName: session.Name,
+			Position:    int32(i + 1),
+			TrackName:   t.Name,
+			Comments:    sql.NullString{String: t.Comments, Valid: t.Comments != ""},
+			FrameRate:   session.Rate.Name,
+		}
+		for _, c := range t.Clips {
+			params.ClipCount++
+			if c.Muted {
+				params.MutedClipCount++
+				continue
+			}
+			params.ClipFrames += c.End.Frames - c.Start.Frames
+		}
+		tracks = append(tracks, params)
+	}
+	return tracks
+}
+
+func protoolsCueItems(session protools.Session) []cueImportItem {
+	// Markers named like "F101 door knock" become cue F101, markers
+	// without a cue number are numbered after the marker
+	items := []cueImportItem{}
+	for _, m := range session.Markers {
+		number := fmt.Sprintf("M%03d", m.Number)
+		description := m.Name
+		if first, rest, _ := strings.Cut(m.Name, " "); strings.ContainsAny(first, "0123456789") {
+			number = first
+			description = strings.TrimSpace(rest)
+		}
+		if m.Comments != "" {
+			description = strings.TrimSpace(description + " " + m.Comments)
+		}
+		items = append(items, cueImportItem{
+			CueNumber:   number,
+			TimecodeIn:  m.Location.String(),
+			TimecodeOut: m.Location.String(),
+			Description: description,
+			Action:      "create",
+			frameRate:   m.Location.Rate.Name,
+			frameIn:     m.Location.Frames,
+			frameOut:    m.Location.Frames,
+		})
+	}
+	return items
+}
+
+func (cfg *apiConfig) handlerImportProTools(w http.ResponseWriter, r *http.Request) {
+	// Imports a Pro Tools "Session Info as Text" export of an episode.
+	// Markers become cues and the tracks with their clip statistics
+	// replace the ones of the previous import.
+	// Without confirm set, it only returns a preview of what would be done
+	_, _, err := authenticateUser(r, cfg.secret)
+	if err != nil {
+		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+		return
+	}
+
+	importInput := struct {
+		SessionInfo string `json:"session_info"`
+		EpisodeID   string `json:"episode_id"`
+		FrameRate   string `json:"frame_rate"`
+		Confirm     bool   `json:"confirm"`
+	}{}
+
+	decoder := json.NewDecoder(r.Body)
+	err = decoder.Decode(&importInput)
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+
+	episodeID, err := uuid.Parse(importInput.EpisodeID)
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+	episode, err := cfg.db.GetEpisodeByID(r.Context(), episodeID)
+	if err != nil {
+		respondWithError(w, "Episode not found", http.StatusNotFound, err)
+		return
+	}
+	if !cfg.requireProjectContribution(w, r, episode.ProjectID) {
+		return
+	}
+
+	// The rate is only needed if the export doesn't name its timecode format
+	rate := timecode.Rate{}
+	if importInput.FrameRate != "" {
+		rate, err = timecode.ParseRate(importInput.FrameRate)
+		if err != nil {
+			respondWithError(w, err.Error(), http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	session, err := protools.Parse(strings.NewReader(importInput.SessionInfo), rate)
+	if err != nil {
+		respondWithError(w, "Error parsing session info", http.StatusBadRequest, err)
+		return
+	}
+
+	existing, err := cfg.db.GetCuesForEpisode(r.Context(), episodeID)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+	imported := map[string]bool{}
+	for _, c := range existing {
+		imported[c.CueNumber] = true
+	}
+
+	items := protoolsCueItems(session)
+	seen := map[string]bool{}
+	for i := range items {
+		switch {
+		case seen[items[i].CueNumber]:
+			items[i].Action, items[i].Reason = "skip", "cue number used by an earlier marker"
+		case imported[items[i].CueNumber]:
+			items[i].Action = "update"
+		}
+		seen[items[i].CueNumber] = true
+	}
+
+	trackParams := protoolsTrackStats(session)
+	tracks := []trackResponse{}
+	for _, t := range trackParams {
+		tracks = append(tracks, trackResponse{
+			Name:           t.TrackName,
+			Comments:       t.Comments.String,
+			ClipCount:      t.ClipCount,
+			MutedClipCount: t.MutedClipCount,
+			ClipLength:     timecode.FromFrames(t.ClipFrames, session.Rate).String(),
+		})
+	}
+
+	unparsed := []importUnparsedLine{}
+	for _, l := range session.Unparsed {
+		unparsed = append(unparsed, importUnparsedLine{Line: l.Number, Text: l.Text, Reason: l.Reason})
+	}
+
+	type importResp struct {
+		Confirmed    bool                 `json:"confirmed"`
+		SessionName  string               `json:"session_name"`
+		FrameRate    string               `json:"frame_rate"`
+		OnlineFiles  int                  `json:"online_files"`
+		OfflineFiles int                  `json:"offline_files"`
+		ClipCount    int                  `json:"clip_count"`
+		Items        []cueImportItem      `json:"items"`
+		Tracks       []trackResponse      `json:"tracks"`
+		Unparsed     []importUnparsedLine `json:"unparsed"`
+	}
+	result := importResp{
+		SessionName:  session.Name,
+		FrameRate:    session.Rate.Name,
+		OnlineFiles:  session.OnlineFiles,
+		OfflineFiles: session.OfflineFiles,
+		ClipCount:    session.ClipCount,
+		Items:        items,
+		Tracks:       tracks,
+		Unparsed:     unparsed,
+	}
+
+	if !importInput.Confirm {
+		err = respondWithJSON(w, http.StatusOK, result)
+		if err != nil {
+			respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	tx, err := cfg.conn.BeginTx(r.Context(), nil)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+	defer tx.Rollback()
+	qtx := cfg.db.WithTx(tx)
+
+	for _, item := range items {
+		if item.Action == "skip" {
+			continue
+		}
+		importCueParams := db.ImportCueParams{
+			EpisodeID:   episodeID,
+			CueNumber:   item.CueNumber,
+			FrameIn:     item.frameIn,
+			FrameOut:    item.frameOut,
+			FrameRate:   item.frameRate,
+			Description: sql.NullString{String: item.Description, Valid: item.Description != ""},
+		}
+		_, err = qtx.ImportCue(r.Context(), importCueParams)
+		if err != nil {
+			respondWithError(w, fmt.Sprintf("Error importing cue %s", item.CueNumber), http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	// The tracks always come from the latest export
+	err = qtx.DeleteEpisodeTracks(r.Context(), episodeID)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+	for _, t := range trackParams {
+		t.EpisodeID = episodeID
+		_, err = qtx.CreateEpisodeTrack(r.Context(), t)
+		if err != nil {
+			respondWithError(w, fmt.Sprintf("Error importing track %s", t.TrackName), http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+
+	result.Confirmed = true
+	err = respondWithJSON(w, http.StatusCreated, result)
+	if err != nil {
+		respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
+		return
+	}
+}
+
+func (cfg *apiConfig) handlerGetEpisodeTracks(w http.ResponseWriter, r *http.Request) {
+	// Lists the tracks of the latest Pro Tools export of an episode
+	_, _, err := authenticateUser(r, cfg.secret)
+	if err != nil {
+		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+		return
+	}
+
+	episodeID, err := uuid.Parse(r.PathValue("episodeid"))
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+
+	episode, err := cfg.db.GetEpisodeByID(r.Context(), episodeID)
+	if err != nil {
+		respondWithError(w, "Episode not found", http.StatusNotFound, err)
+		return
+	}
+	if !cfg.requireProjectVisible(w, r, episode.ProjectID) {
+		return
+	}
+
+	tracks, err := cfg.db.GetEpisodeTracks(r.Context(), episodeID)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+
+	tracksResp := struct {
+		SessionName string          `json:"session_name"`
+		ImportedAt  *time.Time      `json:"imported_at"`
+		Tracks      []trackResponse `json:"tracks"`
+	}{
+		Tracks: []trackResponse{},
+	}
+	for _, t := range tracks {
+		tracksResp.SessionName = t.SessionName
+		tracksResp.ImportedAt = &t.CreatedAt
+		tracksResp.Tracks = append(tracksResp.Tracks, trackToResponse(t))
+	}
+
+	err = respondWithJSON(w, http.StatusOK, tracksResp)
+	if err != nil {
+		respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
+		return
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/Denisowiec/FoleyBookkeeper/internal/db"
+	"github.com/google/uuid"
+)
+
+func canManageLogins(user, owner uuid.UUID, admin bool) bool {
+	// Users manage their own logins, admins everyone's
+	return user == owner || admin
+}
+
+func (cfg *apiConfig) loginsOwner(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	// Returns the user whose logins the request is about, if the requesting
+	// user may see them
+	ownerID, err := uuid.Parse(r.PathValue("userid"))
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return uuid.UUID{}, false
+	}
+	user, err := requestUser(r)
+	if err != nil {
+		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+		return uuid.UUID{}, false
+	}
+	if !canManageLogins(user.ID, ownerID, roleAllows(user.Role, permUsersManage)) {
+		respondWithError(w, "Only admins can manage other users' logins", http.StatusForbidden, nil)
+		return uuid.UUID{}, false
+	}
+	_, err = cfg.db.GetUserByID(r.Context(), ownerID)
+	if err != nil {
+		respondWithError(w, "User not found", http.StatusNotFound, err)
+		return uuid.UUID{}, false
+	}
+	return ownerID, true
+}
+
+func (cfg *apiConfig) handlerGetLogins(w http.ResponseWriter, r *http.Request) {
+	// Lists the devices the user is logged in on. A login is identified by
+	// its token family, the tokens themselves are never sent back.
+	// The last use is the last refresh: requests in between carry only the
+	// JWT, which doesn't say which login it came from
+	ownerID, ok := cfg.loginsOwner(w, r)
+	if !ok {
+		return
+	}
+
+	logins, err := cfg.db.GetActiveLogins(r.Context(), ownerID)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+	err = respondWithJSON(w, http.StatusOK, logins)
+	if err != nil {
+		respondWithError(w, "Error processing response data", http.StatusInternalServerError, err)
+		return
+	}
+}
+
+func (cfg *apiConfig) handlerRevokeLogin(w http.ResponseWriter, r *http.Request) {
+	// Logs the user out on one device. The JWT it holds keeps working
+	// until it expires
+	ownerID, ok := cfg.loginsOwner(w, r)
+	if !ok {
+		return
+	}
+	loginID, err := uuid.Parse(r.PathValue("loginid"))
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+
+	params := db.RevokeUserTokenFamilyParams{
+		FamilyID: loginID,
+		UserID:   ownerID,
+	}
+	revoked, err := cfg.db.RevokeUserTokenFamily(r.Context(), params)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+	if len(revoked) == 0 {
+		respondWithError(w, "No active login with this ID", http.StatusNotFound, nil)
+		return
+	}
+
+	resp := struct {
+		ID      uuid.UUID `json:"id"`
+		Revoked int       `json:"revoked"`
+	}{
+		ID:      loginID,
+		Revoked: len(revoked),
+	}
+	err = respondWithJSON(w, http.StatusAccepted, resp)
+	if err != nil {
+		respondWithError(w, "Error processing response data", http.StatusInternalServerError, err)
+		return
+	}
+}
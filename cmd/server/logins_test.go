@@ -0,0 +1,33 @@
+package main
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestCanManageLogins(t *testing.T) {
+	user, other := uuid.New(), uuid.New()
+	if !canManageLogins(user, user, false) {
+		t.Error("users should manage their own logins")
+	}
+	if canManageLogins(user, other, false) {
+		t.Error("users shouldn't manage other users' logins")
+	}
+	if !canManageLogins(user, other, true) {
+		t.Error("admins should manage anyone's logins")
+	}
+}
+
+func TestRequestIP(t *testing.T) {
+	r := httptest.NewRequest("POST", "/api/login", nil)
+	r.RemoteAddr = "192.0.2.7:51234"
+	if ip := requestIP(r); ip != "192.0.2.7" {
+		t.Errorf("expected the port to be dropped, got %s", ip)
+	}
+	r.RemoteAddr = "[2001:db8::1]:443"
+	if ip := requestIP(r); ip != "2001:db8::1" {
+		t.Errorf("expected the IPv6 address without brackets, got %s", ip)
+	}
+}
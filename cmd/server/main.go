@@ -18,10 +18,13 @@ import (
 type apiConfig struct {
 	db_url                 string
 	db                     db.Queries
+	conn                   *sql.DB
 	secret                 string
 	jwtExpirationTime      time.Duration
 	refTokenExpirationTime time.Duration
 	listen_port            string
+	sessionRules           sessionRules
+	trashRetentionDays     int32
 }
 
 func main() {
@@ -54,6 +57,18 @@ func main() {
 	}
 	cfg.refTokenExpirationTime = time.Duration(refTokenExpirationSeconds) * time.Second
 
+	// Session validation rules are optional, unset rules keep their defaults
+	cfg.sessionRules, err = loadSessionRules()
+	if err != nil {
+		log.Fatal("Error processing session rules: ", err)
+	}
+
+	// Deleted items are purged from the trash after TRASH_RETENTION_DAYS
+	cfg.trashRetentionDays, err = parseTrashRetention(os.Getenv("TRASH_RETENTION_DAYS"))
+	if err != nil {
+		log.Fatal("Error processing TRASH_RETENTION_DAYS env variable: ", err)
+	}
+
 	// cfg also contains an pointer to the database queries
 	dbase, err := sql.Open("postgres", cfg.db_url)
 	if err != nil {
@@ -61,6 +76,10 @@ func main() {
 	}
 	dbQueries := db.New(dbase)
 	cfg.db = *dbQueries
+	// The connection itself is needed for operations that run in a transaction
+	cfg.conn = dbase
+
+	go cfg.runTrashPurge(24 * time.Hour)
 
 	// Here the api handlers are set up
 	mux := http.NewServeMux()
@@ -68,11 +87,16 @@ func main() {
 	// User and login related
 	mux.HandleFunc("POST /api/login", cfg.handlerLogin)
 	mux.HandleFunc("POST /api/refresh", cfg.handlerRefreshToken)
+	mux.HandleFunc("POST /api/logout", cfg.handlerLogout)
+	mux.HandleFunc("POST /api/logout-everywhere", cfg.handlerLogoutEverywhere)
 	mux.HandleFunc("POST /api/users", cfg.handlerCreateUser)
 	//mux.HandleFunc("PUT /api/users/{userid}", cfg.handlerUpdateUser)
 	mux.HandleFunc("PUT /api/users", cfg.handlerUpdateUserSelf)
 	mux.HandleFunc("GET /api/users/{userid}", cfg.handlerGetUser)
 	mux.HandleFunc("GET /api/users", cfg.handlerGetUsers)
+	mux.HandleFunc("PUT /api/users/{userid}/role", cfg.handlerSetUserRole)
+	mux.HandleFunc("GET /api/users/{userid}/logins", cfg.handlerGetLogins)
+	mux.HandleFunc("DELETE /api/users/{userid}/logins/{loginid}", cfg.handlerRevokeLogin)
 
 	// Client related
 	mux.HandleFunc("POST /api/clients", cfg.handlerCreateClient)
@@ -80,13 +104,34 @@ func main() {
 	mux.HandleFunc("GET /api/clients/{clientid}", cfg.handlerGetClientByID)
 	mux.HandleFunc("DELETE /api/clients/{clientid}", cfg.handlerDeleteClient)
 	mux.HandleFunc("GET /api/clients", cfg.handlerGetClientByName)
+	mux.HandleFunc("POST /api/clients/{clientid}/merge", cfg.handlerMergeClient)
+	mux.HandleFunc("PUT /api/clients/{clientid}/billing", cfg.handlerSetClientBilling)
+	mux.HandleFunc("PUT /api/clients/{clientid}/addresses/{kind}", cfg.handlerSetClientAddress)
+	mux.HandleFunc("DELETE /api/clients/{clientid}/addresses/{kind}", cfg.handlerDeleteClientAddress)
+	mux.HandleFunc("POST /api/clients/{clientid}/contacts", cfg.handlerCreateClientContact)
+	mux.HandleFunc("GET /api/clients/{clientid}/contacts", cfg.handlerGetClientContacts)
+	mux.HandleFunc("PUT /api/contacts/{contactid}", cfg.handlerUpdateClientContact)
+	mux.HandleFunc("DELETE /api/contacts/{contactid}", cfg.handlerDeleteClientContact)
 
 	// Project related
 	mux.HandleFunc("POST /api/projects", cfg.handlerCreateProject)
 	mux.HandleFunc("PUT /api/projects/{projectid}", cfg.handlerUpdateProject)
 	mux.HandleFunc("GET /api/projects/{projectid}", cfg.handlerGetProjectByID)
 	mux.HandleFunc("DELETE /api/projects/{projectid}", cfg.handlerDeleteProject)
+	mux.HandleFunc("PUT /api/projects/{projectid}/status", cfg.handlerSetProjectStatus)
+	mux.HandleFunc("POST /api/projects/{projectid}/units", cfg.handlerCreateProjectUnit)
+	mux.HandleFunc("GET /api/projects/{projectid}/units", cfg.handlerGetUnitsForProject)
+	mux.HandleFunc("GET /api/projects/{projectid}/seasons/{season}", cfg.handlerGetSeason)
+	mux.HandleFunc("GET /api/projects/{projectid}/episodes/{code}", cfg.handlerGetEpisodeByCode)
+	mux.HandleFunc("POST /api/projects/{projectid}/episodes", cfg.handlerCreateEpisodes)
+	mux.HandleFunc("GET /api/projects/{projectid}/runtime-report", cfg.handlerGetRuntimeReport)
+	mux.HandleFunc("PUT /api/units/{unitid}", cfg.handlerUpdateProjectUnit)
+	mux.HandleFunc("DELETE /api/units/{unitid}", cfg.handlerDeleteProjectUnit)
+	mux.HandleFunc("GET /api/projects/{projectid}/status-matrix", cfg.handlerGetStatusMatrix)
 	mux.HandleFunc("GET /api/projects", cfg.handlerGetProjectByTitle)
+	mux.HandleFunc("GET /api/projects/{projectid}/members", cfg.handlerGetProjectMembers)
+	mux.HandleFunc("PUT /api/projects/{projectid}/members/{userid}", cfg.handlerSetProjectMember)
+	mux.HandleFunc("DELETE /api/projects/{projectid}/members/{userid}", cfg.handlerRemoveProjectMember)
 
 	// Episode related
 	mux.HandleFunc("POST /api/episodes", cfg.handlerCreateEpisode)
@@ -95,21 +140,92 @@ func main() {
 	mux.HandleFunc("DELETE /api/episodes/{episodeid}", cfg.handlerDeleteEpisode)
 	mux.HandleFunc("GET /api/episodes", cfg.handlerGetEpisodesForProject)
 
+	// Cue related
+	mux.HandleFunc("POST /api/episodes/{episodeid}/cues", cfg.handlerCreateCue)
+	mux.HandleFunc("GET /api/episodes/{episodeid}/cues", cfg.handlerGetCuesForEpisode)
+	mux.HandleFunc("GET /api/episodes/{episodeid}/tracks", cfg.handlerGetEpisodeTracks)
+	mux.HandleFunc("GET /api/episodes/{episodeid}/cue-sheet", cfg.handlerGetCueSheet)
+	mux.HandleFunc("PUT /api/episodes/{episodeid}/parts/{part}/status", cfg.handlerSetEpisodePartStatus)
+	mux.HandleFunc("PUT /api/cues/{cueid}", cfg.handlerUpdateCue)
+	mux.HandleFunc("GET /api/cues/{cueid}", cfg.handlerGetCue)
+	mux.HandleFunc("DELETE /api/cues/{cueid}", cfg.handlerDeleteCue)
+
 	// Session related
 	mux.HandleFunc("POST /api/sessions", cfg.handlerCreateSession)
+	mux.HandleFunc("PUT /api/sessions/{sessionid}", cfg.handlerUpdateSession)
 	mux.HandleFunc("POST /api/sessions/{sessionid}", cfg.handlerAddUsersToSession)
 	mux.HandleFunc("GET /api/sessions/{sessionid}", cfg.handlerGetSession)
+	mux.HandleFunc("DELETE /api/sessions/{sessionid}", cfg.handlerDeleteSession)
+	mux.HandleFunc("POST /api/sessions/{sessionid}/cues", cfg.handlerAddCuesToSession)
 	mux.HandleFunc("GET /api/sessions", cfg.handlerGetSessions)
+	mux.HandleFunc("GET /api/reports/session-anomalies", cfg.handlerGetSessionAnomalies)
+
+	// Timesheets
+	mux.HandleFunc("POST /api/timesheets", cfg.handlerSubmitTimesheet)
+	mux.HandleFunc("GET /api/timesheets", cfg.handlerGetTimesheets)
+	mux.HandleFunc("GET /api/timesheets/pending", cfg.handlerGetPendingTimesheets)
+	mux.HandleFunc("GET /api/timesheets/{timesheetid}", cfg.handlerGetTimesheet)
+	mux.HandleFunc("POST /api/timesheets/{timesheetid}/approve", cfg.handlerApproveTimesheet)
+	mux.HandleFunc("POST /api/timesheets/{timesheetid}/reject", cfg.handlerRejectTimesheet)
+
+	// Parts and activities sessions are logged with
+	mux.HandleFunc("POST /api/parts", cfg.handlerCreatePart)
+	mux.HandleFunc("PUT /api/parts/{partid}", cfg.handlerUpdatePart)
+	mux.HandleFunc("DELETE /api/parts/{partid}", cfg.handlerDeletePart)
+	mux.HandleFunc("GET /api/parts", cfg.handlerGetParts)
+	mux.HandleFunc("POST /api/activities", cfg.handlerCreateActivity)
+	mux.HandleFunc("PUT /api/activities/{activityid}", cfg.handlerUpdateActivity)
+	mux.HandleFunc("DELETE /api/activities/{activityid}", cfg.handlerDeleteActivity)
+	mux.HandleFunc("GET /api/activities", cfg.handlerGetActivities)
 
 	// Calculation related
 	mux.HandleFunc("POST /api/calculations", cfg.handlerCreateCalculation)
 	mux.HandleFunc("POST /api/calculations/{calcid}", cfg.handlerAddEpisodesToCalculation)
 	mux.HandleFunc("GET /api/calculations/{calcid}", cfg.handlerGetCalculation)
-
-	// Here we create the server
+	mux.HandleFunc("DELETE /api/calculations/{calcid}", cfg.handlerDeleteCalculation)
+
+	// Room related
+	mux.HandleFunc("POST /api/rooms", cfg.handlerCreateRoom)
+	mux.HandleFunc("PUT /api/rooms/{roomid}", cfg.handlerUpdateRoom)
+	mux.HandleFunc("GET /api/rooms/{roomid}", cfg.handlerGetRoomByID)
+	mux.HandleFunc("DELETE /api/rooms/{roomid}", cfg.handlerDeleteRoom)
+	mux.HandleFunc("GET /api/rooms", cfg.handlerGetRoomByName)
+
+	// Booking related
+	mux.HandleFunc("POST /api/bookings", cfg.handlerCreateBooking)
+	mux.HandleFunc("PUT /api/bookings/{bookingid}", cfg.handlerUpdateBooking)
+	mux.HandleFunc("POST /api/bookings/{bookingid}", cfg.handlerAddUsersToBooking)
+	mux.HandleFunc("GET /api/bookings/{bookingid}", cfg.handlerGetBooking)
+	mux.HandleFunc("DELETE /api/bookings/{bookingid}", cfg.handlerDeleteBooking)
+	mux.HandleFunc("GET /api/bookings", cfg.handlerGetBookings)
+
+	// Deadlines and milestones
+	mux.HandleFunc("POST /api/milestones", cfg.handlerCreateMilestone)
+	mux.HandleFunc("PUT /api/milestones/{milestoneid}", cfg.handlerUpdateMilestone)
+	mux.HandleFunc("GET /api/milestones/{milestoneid}", cfg.handlerGetMilestone)
+	mux.HandleFunc("DELETE /api/milestones/{milestoneid}", cfg.handlerDeleteMilestone)
+	mux.HandleFunc("GET /api/milestones", cfg.handlerGetDueMilestones)
+	mux.HandleFunc("GET /api/projects/{projectid}/milestones", cfg.handlerGetMilestonesForProject)
+
+	// Calendar feeds
+	mux.HandleFunc("POST /api/calendar-feeds", cfg.handlerCreateCalendarFeed)
+	mux.HandleFunc("GET /api/calendar-feeds", cfg.handlerGetCalendarFeeds)
+	mux.HandleFunc("DELETE /api/calendar-feeds/{token}", cfg.handlerDeleteCalendarFeed)
+	mux.HandleFunc("GET /api/calendar/{token}", cfg.handlerCalendarFeed)
+
+	// Trash
+	mux.HandleFunc("GET /api/trash", cfg.handlerGetTrash)
+	mux.HandleFunc("POST /api/trash/{kind}/{id}/restore", cfg.handlerRestoreFromTrash)
+
+	// Imports
+	mux.HandleFunc("POST /api/imports/ics", cfg.handlerImportICS)
+	mux.HandleFunc("POST /api/imports/edl", cfg.handlerImportEDL)
+	mux.HandleFunc("POST /api/imports/protools", cfg.handlerImportProTools)
+
+	// Here we create the server. Every request passes the role check first
 	s := &http.Server{
 		Addr:    fmt.Sprintf(":%s", cfg.listen_port),
-		Handler: mux,
+		Handler: cfg.authorize(mux),
 	}
 
 	defer s.Shutdown(context.Background())
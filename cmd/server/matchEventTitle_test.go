@@ -0,0 +1,55 @@
+package main
+
+import "testing"
+
+func TestMatchEventTitle(t *testing.T) {
+	patterns, err := compileTitlePatterns(nil)
+	if err != nil {
+		t.Fatalf("error compiling the default pattern: %s", err)
+	}
+
+	match, ok := matchEventTitle(patterns, "ProjectX E05 footsteps")
+	if !ok {
+		t.Fatalf("title 'ProjectX E05 footsteps' should match the default pattern")
+	}
+	if match.Project != "ProjectX" || match.EpisodeNumber != 5 || match.Part != "footsteps" {
+		t.Errorf("title matched incorrectly: %+v", match)
+	}
+
+	match, ok = matchEventTitle(patterns, "The Long Show e12 Props edit")
+	if !ok {
+		t.Fatalf("title with a multi-word project should match the default pattern")
+	}
+	if match.Project != "The Long Show" || match.EpisodeNumber != 12 || match.Part != "props" || match.Activity != "edit" {
+		t.Errorf("title matched incorrectly: %+v", match)
+	}
+
+	match, ok = matchEventTitle(patterns, "The Long Show S02E05 foley")
+	if !ok {
+		t.Fatalf("title with a season should match the default pattern")
+	}
+	if match.Project != "The Long Show" || match.Season != 2 || match.EpisodeNumber != 5 || match.Part != "foley" {
+		t.Errorf("title matched incorrectly: %+v", match)
+	}
+
+	_, ok = matchEventTitle(patterns, "Lunch")
+	if ok {
+		t.Errorf("title without an episode shouldn't match the default pattern")
+	}
+}
+
+func TestCompileTitlePatterns(t *testing.T) {
+	patterns, err := compileTitlePatterns([]string{`^\[(?P<project>[^\]]+)\] ep(?P<episode>\d+)$`})
+	if err != nil {
+		t.Fatalf("error compiling a custom pattern: %s", err)
+	}
+	match, ok := matchEventTitle(patterns, "[ProjectX] ep7")
+	if !ok || match.Project != "ProjectX" || match.EpisodeNumber != 7 {
+		t.Errorf("custom pattern matched incorrectly: %+v", match)
+	}
+
+	_, err = compileTitlePatterns([]string{`^(?P<episode>\d+)$`})
+	if err == nil {
+		t.Errorf("pattern without a project group should be rejected")
+	}
+}
@@ -0,0 +1,369 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/Denisowiec/FoleyBookkeeper/internal/db"
+	"github.com/google/uuid"
+)
+
+// Milestones are delivery deadlines, client review dates and internal
+// targets of a project, or of one of its episodes. Due dates are whole days
+var milestoneKinds = []db.MilestoneKind{
+	db.MilestoneKindDelivery,
+	db.MilestoneKindClientReview,
+	db.MilestoneKindInternal,
+}
+
+type milestoneInputType struct {
+	ProjectID string `json:"project_id"`
+	EpisodeID string `json:"episode_id"`
+	Kind      string `json:"kind"`
+	Title     string `json:"title"`
+	DueDate   string `json:"due_date"`
+	Notes     string `json:"notes"`
+	Completed *bool  `json:"completed"`
+}
+
+func parseMilestoneInput(input milestoneInputType) (db.CreateMilestoneParams, error) {
+	// Converts the json input into the parameters of a milestone
+	params := db.CreateMilestoneParams{}
+	var err error
+
+	if input.Title == "" {
+		return params, fmt.Errorf("milestone title required")
+	}
+	params.Title = input.Title
+
+	params.Kind = db.MilestoneKindDelivery
+	if input.Kind != "" {
+		params.Kind = db.MilestoneKind(input.Kind)
+		if !slices.Contains(milestoneKinds, params.Kind) {
+			return params, fmt.Errorf("milestone kind %s unknown", input.Kind)
+		}
+	}
+
+	params.DueDate, err = time.Parse(time.DateOnly, input.DueDate)
+	if err != nil {
+		return params, err
+	}
+	params.ProjectID, err = uuid.Parse(input.ProjectID)
+	if err != nil {
+		return params, err
+	}
+	params.EpisodeID, err = parseNullUUID(input.EpisodeID)
+	if err != nil {
+		return params, err
+	}
+	params.Notes = sql.NullString{String: input.Notes, Valid: input.Notes != ""}
+
+	return params, nil
+}
+
+func (cfg *apiConfig) checkMilestoneEpisode(r *http.Request, params db.CreateMilestoneParams) error {
+	// Milestones of an episode have to belong to the episode's project
+	if !params.EpisodeID.Valid {
+		return nil
+	}
+	ep, err := cfg.db.GetEpisodeByID(r.Context(), params.EpisodeID.UUID)
+	if err != nil {
+		return err
+	}
+	if ep.ProjectID != params.ProjectID {
+		return fmt.Errorf("episode doesn't belong to the project")
+	}
+	return nil
+}
+
+func (cfg *apiConfig) handlerCreateMilestone(w http.ResponseWriter, r *http.Request) {
+	_, _, err := authenticateUser(r, cfg.secret)
+	if err != nil {
+		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+		return
+	}
+
+	milestoneInput := milestoneInputType{}
+	decoder := json.NewDecoder(r.Body)
+	err = decoder.Decode(&milestoneInput)
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+
+	createMilestoneParams, err := parseMilestoneInput(milestoneInput)
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+	err = cfg.checkMilestoneEpisode(r, createMilestoneParams)
+	if err != nil {
+		respondWithError(w, "Episode not found in project", http.StatusBadRequest, err)
+		return
+	}
+
+	milestone, err := cfg.db.CreateMilestone(r.Context(), createMilestoneParams)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+
+	err = respondWithJSON(w, http.StatusCreated, milestone)
+	if err != nil {
+		respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
+		return
+	}
+}
+
+func (cfg *apiConfig) handlerUpdateMilestone(w http.ResponseWriter, r *http.Request) {
+	// Replaces the milestone's fields. Marking it completed stamps the current
+	// time, completed milestones don't show as due anymore
+	_, _, err := authenticateUser(r, cfg.secret)
+	if err != nil {
+		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+		return
+	}
+
+	milestoneID, err := uuid.Parse(r.PathValue("milestoneid"))
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+	milestone, err := cfg.db.GetMilestoneByID(r.Context(), milestoneID)
+	if err != nil {
+		respondWithError(w, "Milestone not found", http.StatusNotFound, err)
+		return
+	}
+
+	milestoneInput := milestoneInputType{}
+	decoder := json.NewDecoder(r.Body)
+	err = decoder.Decode(&milestoneInput)
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+	// Milestones stay with their project
+	milestoneInput.ProjectID = milestone.ProjectID.String()
+
+	params, err := parseMilestoneInput(milestoneInput)
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+	err = cfg.checkMilestoneEpisode(r, params)
+	if err != nil {
+		respondWithError(w, "Episode not found in project", http.StatusBadRequest, err)
+		return
+	}
+
+	updateMilestoneParams := db.UpdateMilestoneParams{
+		ID:          milestoneID,
+		EpisodeID:   params.EpisodeID,
+		Kind:        params.Kind,
+		Title:       params.Title,
+		DueDate:     params.DueDate,
+		Notes:       params.Notes,
+		CompletedAt: milestone.CompletedAt,
+	}
+	if milestoneInput.Completed != nil {
+		if !*milestoneInput.Completed {
+			updateMilestoneParams.CompletedAt = sql.NullTime{}
+		} else if !milestone.CompletedAt.Valid {
+			updateMilestoneParams.CompletedAt = sql.NullTime{Time: time.Now(), Valid: true}
+		}
+	}
+
+	milestone, err = cfg.db.UpdateMilestone(r.Context(), updateMilestoneParams)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+
+	err = respondWithJSON(w, http.StatusAccepted, milestone)
+	if err != nil {
+		respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
+		return
+	}
+}
+
+func (cfg *apiConfig) handlerGetMilestone(w http.ResponseWriter, r *http.Request) {
+	_, _, err := authenticateUser(r, cfg.secret)
+	if err != nil {
+		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+		return
+	}
+
+	milestoneID, err := uuid.Parse(r.PathValue("milestoneid"))
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+
+	milestone, err := cfg.db.GetMilestoneByID(r.Context(), milestoneID)
+	if err != nil {
+		respondWithError(w, "Milestone not found", http.StatusNotFound, err)
+		return
+	}
+	if !cfg.requireProjectVisible(w, r, milestone.ProjectID) {
+		return
+	}
+
+	err = respondWithJSON(w, http.StatusOK, milestone)
+	if err != nil {
+		respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
+		return
+	}
+}
+
+func (cfg *apiConfig) handlerGetMilestonesForProject(w http.ResponseWriter, r *http.Request) {
+	// Returns all milestones of a project, completed ones included
+	_, _, err := authenticateUser(r, cfg.secret)
+	if err != nil {
+		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+		return
+	}
+
+	projectID, err := uuid.Parse(r.PathValue("projectid"))
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+	if !cfg.requireProjectVisible(w, r, projectID) {
+		return
+	}
+
+	list, err := cfg.db.GetMilestonesForProject(r.Context(), projectID)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+
+	err = respondWithJSON(w, http.StatusOK, list)
+	if err != nil {
+		respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
+		return
+	}
+}
+
+type dueMilestone struct {
+	db.GetOpenMilestonesDueBeforeRow
+	DaysLeft int `json:"days_left"`
+}
+
+func splitDueMilestones(rows []db.GetOpenMilestonesDueBeforeRow, today time.Time) (overdue, upcoming []dueMilestone) {
+	// Milestones due today are upcoming, they only become overdue tomorrow
+	overdue, upcoming = []dueMilestone{}, []dueMilestone{}
+	for _, row := range rows {
+		item := dueMilestone{
+			GetOpenMilestonesDueBeforeRow: row,
+			DaysLeft:                      int(row.DueDate.Sub(today).Hours() / 24),
+		}
+		if row.DueDate.Before(today) {
+			overdue = append(overdue, item)
+		} else {
+			upcoming = append(upcoming, item)
+		}
+	}
+	return overdue, upcoming
+}
+
+func (cfg *apiConfig) handlerGetDueMilestones(w http.ResponseWriter, r *http.Request) {
+	// Returns the open milestones of the projects the user can see that are
+	// overdue or due by the given date, with the minutes logged on each so far.
+	// Without input it looks two weeks ahead
+	_, _, err := authenticateUser(r, cfg.secret)
+	if err != nil {
+		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+		return
+	}
+
+	reqInput := struct {
+		Until string `json:"until"`
+	}{}
+	decoder := json.NewDecoder(r.Body)
+	err = decoder.Decode(&reqInput)
+	if err != nil && err != io.EOF {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+
+	today, err := time.Parse(time.DateOnly, time.Now().Format(time.DateOnly))
+	if err != nil {
+		respondWithError(w, "Error processing dates", http.StatusInternalServerError, err)
+		return
+	}
+	until := today.AddDate(0, 0, 14)
+	if reqInput.Until != "" {
+		until, err = time.Parse(time.DateOnly, reqInput.Until)
+		if err != nil {
+			respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	// The end date is inclusive
+	rows, err := cfg.db.GetOpenMilestonesDueBefore(r.Context(), until.AddDate(0, 0, 1))
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+	access, err := cfg.requestProjectAccess(r)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+	visible := rows[:0]
+	for _, row := range rows {
+		if access.canSee(row.ProjectID) {
+			visible = append(visible, row)
+		}
+	}
+
+	overdue, upcoming := splitDueMilestones(visible, today)
+	dueResp := struct {
+		Until    string         `json:"until"`
+		Overdue  []dueMilestone `json:"overdue"`
+		Upcoming []dueMilestone `json:"upcoming"`
+	}{
+		Until:    until.Format(time.DateOnly),
+		Overdue:  overdue,
+		Upcoming: upcoming,
+	}
+
+	err = respondWithJSON(w, http.StatusOK, dueResp)
+	if err != nil {
+		respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
+		return
+	}
+}
+
+func (cfg *apiConfig) handlerDeleteMilestone(w http.ResponseWriter, r *http.Request) {
+	_, _, err := authenticateUser(r, cfg.secret)
+	if err != nil {
+		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+		return
+	}
+
+	milestoneID, err := uuid.Parse(r.PathValue("milestoneid"))
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+
+	milestone, err := cfg.db.DeleteMilestone(r.Context(), milestoneID)
+	if err != nil {
+		respondWithError(w, "Milestone not found", http.StatusNotFound, err)
+		return
+	}
+
+	err = respondWithJSON(w, http.StatusAccepted, milestone)
+	if err != nil {
+		respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
+		return
+	}
+}
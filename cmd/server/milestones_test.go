@@ -0,0 +1,25 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Denisowiec/FoleyBookkeeper/internal/db"
+)
+
+func TestSplitDueMilestones(t *testing.T) {
+	today := time.Date(2024, 3, 13, 0, 0, 0, 0, time.UTC)
+	rows := []db.GetOpenMilestonesDueBeforeRow{
+		{Title: "Client review", DueDate: today.AddDate(0, 0, -3)},
+		{Title: "Premix", DueDate: today},
+		{Title: "Delivery", DueDate: today.AddDate(0, 0, 5)},
+	}
+
+	overdue, upcoming := splitDueMilestones(rows, today)
+	if len(overdue) != 1 || overdue[0].Title != "Client review" || overdue[0].DaysLeft != -3 {
+		t.Errorf("unexpected overdue milestones %+v", overdue)
+	}
+	if len(upcoming) != 2 || upcoming[0].DaysLeft != 0 || upcoming[1].DaysLeft != 5 {
+		t.Errorf("unexpected upcoming milestones %+v", upcoming)
+	}
+}
@@ -0,0 +1,241 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Denisowiec/FoleyBookkeeper/internal/db"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// Parts and activities share the same shape of input
+type taxonomyInputType struct {
+	Code          string          `json:"code"`
+	DisplayName   string          `json:"display_name"`
+	SortOrder     *int32          `json:"sort_order"`
+	Active        *bool           `json:"active"`
+	BillingWeight clearableString `json:"billing_weight"`
+	// Only activities use this, see advancePartStatus
+	AdvancesTo *string `json:"advances_to"`
+}
+
+// clearableString tells a field that was left out from one set to null
+type clearableString struct {
+	Set   bool
+	Value string
+}
+
+func (c *clearableString) UnmarshalJSON(data []byte) error {
+	c.Set = true
+	if string(data) == "null" {
+		c.Value = ""
+		return nil
+	}
+	return json.Unmarshal(data, &c.Value)
+}
+
+func parseBillingWeight(input string) (sql.NullString, error) {
+	// Billing weights are optional, sessions without one count at face value
+	if input == "" {
+		return sql.NullString{}, nil
+	}
+	weight, err := decimal.NewFromString(input)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+	if !weight.IsPositive() {
+		return sql.NullString{}, fmt.Errorf("billing weight has to be positive")
+	}
+	return sql.NullString{String: weight.String(), Valid: true}, nil
+}
+
+func (cfg *apiConfig) handlerCreatePart(w http.ResponseWriter, r *http.Request) {
+	// Adds a new part sessions can be recorded for, e.g. cloth or premix
+	// The code is what sessions refer to and can't be changed later
+	_, _, err := authenticateUser(r, cfg.secret)
+	if err != nil {
+		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+		return
+	}
+
+	partInput := taxonomyInputType{}
+	decoder := json.NewDecoder(r.Body)
+	err = decoder.Decode(&partInput)
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+
+	code := strings.ToLower(strings.TrimSpace(partInput.Code))
+	if code == "" {
+		respondWithError(w, "Part code required", http.StatusBadRequest, nil)
+		return
+	}
+	if partInput.DisplayName == "" {
+		partInput.DisplayName = code
+	}
+	weight, err := parseBillingWeight(partInput.BillingWeight.Value)
+	if err != nil {
+		respondWithError(w, "Invalid billing weight", http.StatusBadRequest, err)
+		return
+	}
+
+	var sortOrder int32
+	if partInput.SortOrder != nil {
+		sortOrder = *partInput.SortOrder
+	}
+
+	createPartParams := db.CreatePartParams{
+		Code:          code,
+		DisplayName:   partInput.DisplayName,
+		SortOrder:     sortOrder,
+		Active:        partInput.Active == nil || *partInput.Active,
+		BillingWeight: weight,
+	}
+	part, err := cfg.db.CreatePart(r.Context(), createPartParams)
+	if err != nil {
+		respondWithError(w, "Error creating part", http.StatusInternalServerError, err)
+		return
+	}
+
+	err = respondWithJSON(w, http.StatusCreated, part)
+	if err != nil {
+		respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
+		return
+	}
+}
+
+func (cfg *apiConfig) handlerUpdatePart(w http.ResponseWriter, r *http.Request) {
+	// Changes the display name, ordering, active flag or billing weight of a part
+	// Fields not given keep their values
+	_, _, err := authenticateUser(r, cfg.secret)
+	if err != nil {
+		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+		return
+	}
+
+	partID, err := uuid.Parse(r.PathValue("partid"))
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+
+	part, err := cfg.db.GetPartByID(r.Context(), partID)
+	if err != nil {
+		respondWithError(w, "Part not found", http.StatusNotFound, err)
+		return
+	}
+
+	partInput := taxonomyInputType{}
+	decoder := json.NewDecoder(r.Body)
+	err = decoder.Decode(&partInput)
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+
+	updatePartParams := db.UpdatePartParams{
+		ID:            part.ID,
+		DisplayName:   part.DisplayName,
+		SortOrder:     part.SortOrder,
+		Active:        part.Active,
+		BillingWeight: part.BillingWeight,
+	}
+	if partInput.DisplayName != "" {
+		updatePartParams.DisplayName = partInput.DisplayName
+	}
+	if partInput.SortOrder != nil {
+		updatePartParams.SortOrder = *partInput.SortOrder
+	}
+	if partInput.Active != nil {
+		updatePartParams.Active = *partInput.Active
+	}
+	// Null or an empty value takes the weight away, so the part counts at face value again
+	if partInput.BillingWeight.Set {
+		updatePartParams.BillingWeight, err = parseBillingWeight(partInput.BillingWeight.Value)
+		if err != nil {
+			respondWithError(w, "Invalid billing weight", http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	part, err = cfg.db.UpdatePart(r.Context(), updatePartParams)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+
+	err = respondWithJSON(w, http.StatusAccepted, part)
+	if err != nil {
+		respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
+		return
+	}
+}
+
+func (cfg *apiConfig) handlerGetParts(w http.ResponseWriter, r *http.Request) {
+	// Lists all parts, inactive ones included, in their display order
+	_, _, err := authenticateUser(r, cfg.secret)
+	if err != nil {
+		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+		return
+	}
+
+	list, err := cfg.db.GetAllParts(r.Context())
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+
+	err = respondWithJSON(w, http.StatusOK, list)
+	if err != nil {
+		respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
+		return
+	}
+}
+
+func (cfg *apiConfig) handlerDeletePart(w http.ResponseWriter, r *http.Request) {
+	// Parts already used by sessions can't be deleted, only deactivated
+	_, _, err := authenticateUser(r, cfg.secret)
+	if err != nil {
+		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+		return
+	}
+
+	partID, err := uuid.Parse(r.PathValue("partid"))
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+
+	part, err := cfg.db.GetPartByID(r.Context(), partID)
+	if err != nil {
+		respondWithError(w, "Part not found", http.StatusNotFound, err)
+		return
+	}
+
+	count, err := cfg.db.CountSessionsForPart(r.Context(), part.Code)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+	if count > 0 {
+		respondWithError(w, fmt.Sprintf("Part is used by %d sessions, deactivate it instead", count), http.StatusConflict, nil)
+		return
+	}
+
+	part, err = cfg.db.DeletePart(r.Context(), partID)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+
+	err = respondWithJSON(w, http.StatusAccepted, part)
+	if err != nil {
+		respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
+		return
+	}
+}
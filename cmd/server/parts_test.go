@@ -0,0 +1,34 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestClearableString(t *testing.T) {
+	cases := []struct {
+		body  string
+		set   bool
+		value string
+	}{
+		{`{}`, false, ""},
+		{`{"billing_weight": null}`, true, ""},
+		{`{"billing_weight": ""}`, true, ""},
+		{`{"billing_weight": "1.5"}`, true, "1.5"},
+	}
+	for _, c := range cases {
+		input := taxonomyInputType{}
+		err := json.Unmarshal([]byte(c.body), &input)
+		if err != nil {
+			t.Fatalf("%s: %v", c.body, err)
+		}
+		if input.BillingWeight.Set != c.set || input.BillingWeight.Value != c.value {
+			t.Errorf("%s: got %+v", c.body, input.BillingWeight)
+		}
+	}
+
+	weight, err := parseBillingWeight("")
+	if err != nil || weight.Valid {
+		t.Errorf("an empty weight should clear it, got %+v, %v", weight, err)
+	}
+}
@@ -0,0 +1,341 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/Denisowiec/FoleyBookkeeper/internal/db"
+	"github.com/google/uuid"
+)
+
+// permission is what a route requires from the user's role
+type permission string
+
+const (
+	// Open to anyone, like logging in or registering
+	permPublic permission = "public"
+	// Open to every logged in user whatever their role, like editing
+	// their own profile or submitting their own timesheets
+	permLoggedIn permission = "logged_in"
+
+	permClientsRead       permission = "clients:read"
+	permClientsWrite      permission = "clients:write"
+	permClientsDelete     permission = "clients:delete"
+	permProjectsRead      permission = "projects:read"
+	permProjectsWrite     permission = "projects:write"
+	permProjectsDelete    permission = "projects:delete"
+	permProductionWrite   permission = "production:write"
+	permSessionsRead      permission = "sessions:read"
+	permSessionsWrite     permission = "sessions:write"
+	permTimesheetsReview  permission = "timesheets:review"
+	permCalculationsRead  permission = "calculations:read"
+	permCalculationsWrite permission = "calculations:write"
+	permCatalogWrite      permission = "catalog:write"
+	permBookingsWrite     permission = "bookings:write"
+	permTrashManage       permission = "trash:manage"
+	permUsersManage       permission = "users:manage"
+)
+
+var userRoles = []db.UserRole{db.UserRoleAdmin, db.UserRoleManager, db.UserRoleEngineer, db.UserRoleAccountant}
+
+func strToUserRole(input string) (db.UserRole, error) {
+	role := db.UserRole(strings.ToLower(strings.TrimSpace(input)))
+	if !slices.Contains(userRoles, role) {
+		return "", fmt.Errorf("role %s unknown", input)
+	}
+	return role, nil
+}
+
+// rolePermissions is the permission matrix, admins can do everything
+var rolePermissions = map[db.UserRole][]permission{
+	db.UserRoleAdmin: {
+		permClientsRead, permClientsWrite, permClientsDelete,
+		permProjectsRead, permProjectsWrite, permProjectsDelete, permProductionWrite,
+		permSessionsRead, permSessionsWrite, permTimesheetsReview,
+		permCalculationsRead, permCalculationsWrite,
+		permCatalogWrite, permBookingsWrite, permTrashManage, permUsersManage,
+	},
+	db.UserRoleManager: {
+		permClientsRead, permClientsWrite, permClientsDelete,
+		permProjectsRead, permProjectsWrite, permProjectsDelete, permProductionWrite,
+		permSessionsRead, permSessionsWrite, permTimesheetsReview,
+		permCalculationsRead, permCalculationsWrite,
+		permCatalogWrite, permBookingsWrite, permTrashManage,
+	},
+	db.UserRoleEngineer: {
+		permClientsRead, permProjectsRead, permProductionWrite,
+		permSessionsRead, permSessionsWrite, permBookingsWrite,
+	},
+	db.UserRoleAccountant: {
+		permClientsRead, permClientsWrite, permProjectsRead, permSessionsRead,
+		permCalculationsRead, permCalculationsWrite,
+	},
+}
+
+// endpointPermissions maps every route registered in main.go to what it
+// requires. Routes missing from here are refused
+var endpointPermissions = map[string]permission{
+	"POST /api/login":   permPublic,
+	"POST /api/refresh": permPublic,
+	// The refresh token is the proof, the JWT may well have expired
+	"POST /api/logout":             permPublic,
+	"POST /api/logout-everywhere":  permLoggedIn,
+	"POST /api/users":              permPublic,
+	"PUT /api/users":               permLoggedIn,
+	"GET /api/users/{userid}":      permLoggedIn,
+	"GET /api/users":               permLoggedIn,
+	"PUT /api/users/{userid}/role": permUsersManage,
+	// Users manage their own logins, the handlers let admins manage anyone's
+	"GET /api/users/{userid}/logins":              permLoggedIn,
+	"DELETE /api/users/{userid}/logins/{loginid}": permLoggedIn,
+
+	"POST /api/clients":                               permClientsWrite,
+	"PUT /api/clients/{clientid}":                     permClientsWrite,
+	"GET /api/clients/{clientid}":                     permClientsRead,
+	"DELETE /api/clients/{clientid}":                  permClientsDelete,
+	"GET /api/clients":                                permClientsRead,
+	"POST /api/clients/{clientid}/merge":              permClientsDelete,
+	"PUT /api/clients/{clientid}/billing":             permClientsWrite,
+	"PUT /api/clients/{clientid}/addresses/{kind}":    permClientsWrite,
+	"DELETE /api/clients/{clientid}/addresses/{kind}": permClientsWrite,
+	"POST /api/clients/{clientid}/contacts":           permClientsWrite,
+	"GET /api/clients/{clientid}/contacts":            permClientsRead,
+	"PUT /api/contacts/{contactid}":                   permClientsWrite,
+	"DELETE /api/contacts/{contactid}":                permClientsWrite,
+
+	"POST /api/projects":                             permProjectsWrite,
+	"PUT /api/projects/{projectid}":                  permProjectsWrite,
+	"GET /api/projects/{projectid}":                  permProjectsRead,
+	"DELETE /api/projects/{projectid}":               permProjectsDelete,
+	"PUT /api/projects/{projectid}/status":           permProjectsWrite,
+	"POST /api/projects/{projectid}/units":           permProjectsWrite,
+	"GET /api/projects/{projectid}/units":            permProjectsRead,
+	"GET /api/projects/{projectid}/seasons/{season}": permProjectsRead,
+	"GET /api/projects/{projectid}/episodes/{code}":  permProjectsRead,
+	"POST /api/projects/{projectid}/episodes":        permProjectsWrite,
+	"GET /api/projects/{projectid}/runtime-report":   permProjectsRead,
+	"PUT /api/units/{unitid}":                        permProjectsWrite,
+	"DELETE /api/units/{unitid}":                     permProjectsDelete,
+	"GET /api/projects/{projectid}/status-matrix":    permProjectsRead,
+	"GET /api/projects":                              permProjectsRead,
+	"POST /api/episodes":                             permProjectsWrite,
+	"PUT /api/episodes/{episodeid}":                  permProjectsWrite,
+	"GET /api/episodes/{episodeid}":                  permProjectsRead,
+	"DELETE /api/episodes/{episodeid}":               permProjectsDelete,
+	"GET /api/episodes":                              permProjectsRead,
+	"POST /api/milestones":                           permProjectsWrite,
+	"PUT /api/milestones/{milestoneid}":              permProjectsWrite,
+	"GET /api/milestones/{milestoneid}":              permProjectsRead,
+	"DELETE /api/milestones/{milestoneid}":           permProjectsWrite,
+	"GET /api/milestones":                            permProjectsRead,
+	"GET /api/projects/{projectid}/milestones":       permProjectsRead,
+	"GET /api/projects/{projectid}/members":          permProjectsRead,
+	// Project leads manage members too, the handlers check for that
+	"PUT /api/projects/{projectid}/members/{userid}":    permLoggedIn,
+	"DELETE /api/projects/{projectid}/members/{userid}": permLoggedIn,
+
+	"POST /api/episodes/{episodeid}/cues":               permProductionWrite,
+	"GET /api/episodes/{episodeid}/cues":                permProjectsRead,
+	"GET /api/episodes/{episodeid}/tracks":              permProjectsRead,
+	"GET /api/episodes/{episodeid}/cue-sheet":           permProjectsRead,
+	"PUT /api/episodes/{episodeid}/parts/{part}/status": permProductionWrite,
+	"PUT /api/cues/{cueid}":                             permProductionWrite,
+	"GET /api/cues/{cueid}":                             permProjectsRead,
+	"DELETE /api/cues/{cueid}":                          permProductionWrite,
+	"POST /api/imports/edl":                             permProductionWrite,
+	"POST /api/imports/protools":                        permProductionWrite,
+
+	"POST /api/sessions":                         permSessionsWrite,
+	"PUT /api/sessions/{sessionid}":              permSessionsWrite,
+	"POST /api/sessions/{sessionid}":             permSessionsWrite,
+	"GET /api/sessions/{sessionid}":              permSessionsRead,
+	"DELETE /api/sessions/{sessionid}":           permSessionsWrite,
+	"POST /api/sessions/{sessionid}/cues":        permSessionsWrite,
+	"GET /api/sessions":                          permSessionsRead,
+	"POST /api/imports/ics":                      permSessionsWrite,
+	"GET /api/reports/session-anomalies":         permTimesheetsReview,
+	"POST /api/timesheets":                       permLoggedIn,
+	"GET /api/timesheets":                        permLoggedIn,
+	"GET /api/timesheets/pending":                permTimesheetsReview,
+	"GET /api/timesheets/{timesheetid}":          permLoggedIn,
+	"POST /api/timesheets/{timesheetid}/approve": permTimesheetsReview,
+	"POST /api/timesheets/{timesheetid}/reject":  permTimesheetsReview,
+
+	"POST /api/parts":                     permCatalogWrite,
+	"PUT /api/parts/{partid}":             permCatalogWrite,
+	"DELETE /api/parts/{partid}":          permCatalogWrite,
+	"GET /api/parts":                      permLoggedIn,
+	"POST /api/activities":                permCatalogWrite,
+	"PUT /api/activities/{activityid}":    permCatalogWrite,
+	"DELETE /api/activities/{activityid}": permCatalogWrite,
+	"GET /api/activities":                 permLoggedIn,
+	"POST /api/rooms":                     permCatalogWrite,
+	"PUT /api/rooms/{roomid}":             permCatalogWrite,
+	"GET /api/rooms/{roomid}":             permLoggedIn,
+	"DELETE /api/rooms/{roomid}":          permCatalogWrite,
+	"GET /api/rooms":                      permLoggedIn,
+
+	"POST /api/calculations":            permCalculationsWrite,
+	"POST /api/calculations/{calcid}":   permCalculationsWrite,
+	"GET /api/calculations/{calcid}":    permCalculationsRead,
+	"DELETE /api/calculations/{calcid}": permCalculationsWrite,
+
+	"POST /api/bookings":                 permBookingsWrite,
+	"PUT /api/bookings/{bookingid}":      permBookingsWrite,
+	"POST /api/bookings/{bookingid}":     permBookingsWrite,
+	"GET /api/bookings/{bookingid}":      permLoggedIn,
+	"DELETE /api/bookings/{bookingid}":   permBookingsWrite,
+	"GET /api/bookings":                  permLoggedIn,
+	"POST /api/calendar-feeds":           permLoggedIn,
+	"GET /api/calendar-feeds":            permLoggedIn,
+	"DELETE /api/calendar-feeds/{token}": permLoggedIn,
+	// The feed URL is the secret, calendar apps can't log in
+	"GET /api/calendar/{token}": permPublic,
+
+	"GET /api/trash":                      permTrashManage,
+	"POST /api/trash/{kind}/{id}/restore": permTrashManage,
+}
+
+type contextKey string
+
+// The user making the request, put in the context once their role is checked
+const userContextKey contextKey = "user"
+
+func requestUser(r *http.Request) (db.User, error) {
+	user, ok := r.Context().Value(userContextKey).(db.User)
+	if !ok {
+		return db.User{}, errors.New("request has no logged in user")
+	}
+	return user, nil
+}
+
+func roleAllows(role db.UserRole, perm permission) bool {
+	if perm == permPublic || perm == permLoggedIn {
+		return true
+	}
+	return slices.Contains(rolePermissions[role], perm)
+}
+
+func (cfg *apiConfig) authorize(mux *http.ServeMux) http.Handler {
+	// Checks the user's role against the route the mux would send the
+	// request to, before it gets there. Requests that match no route are
+	// left for the mux to answer with a 404 or 405
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, pattern := mux.Handler(r)
+		if pattern == "" {
+			mux.ServeHTTP(w, r)
+			return
+		}
+		perm, ok := endpointPermissions[pattern]
+		if !ok {
+			log.Printf("No permission set for %s, refusing the request\n", pattern)
+			respondWithError(w, "Operation not permitted", http.StatusForbidden, nil)
+			return
+		}
+		if perm == permPublic {
+			mux.ServeHTTP(w, r)
+			return
+		}
+
+		userID, _, err := authenticateUser(r, cfg.secret)
+		if err != nil {
+			respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+			return
+		}
+		// The role is read on every request, so a changed role applies
+		// right away instead of when the JWT expires
+		user, err := cfg.db.GetUserByID(r.Context(), userID)
+		if err != nil {
+			respondWithError(w, "User not found", http.StatusUnauthorized, err)
+			return
+		}
+		if !roleAllows(user.Role, perm) {
+			respondWithError(w, fmt.Sprintf("Users with the %s role aren't permitted to do this", user.Role), http.StatusForbidden, nil)
+			return
+		}
+		mux.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), userContextKey, user)))
+	})
+}
+
+func (cfg *apiConfig) handlerSetUserRole(w http.ResponseWriter, r *http.Request) {
+	// Admins assign roles here. The last admin can't give up the role, or
+	// nobody would be left to assign them
+	userID, err := uuid.Parse(r.PathValue("userid"))
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+
+	roleInput := struct {
+		Role string `json:"role"`
+	}{}
+	decoder := json.NewDecoder(r.Body)
+	err = decoder.Decode(&roleInput)
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+	role, err := strToUserRole(roleInput.Role)
+	if err != nil {
+		respondWithError(w, fmt.Sprintf("Invalid role, valid ones are %v", userRoles), http.StatusBadRequest, err)
+		return
+	}
+
+	tx, err := cfg.conn.BeginTx(r.Context(), nil)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+	defer tx.Rollback()
+	qtx := cfg.db.WithTx(tx)
+
+	// The admins stay locked until the end, so two admins demoting each
+	// other at once can't both go through
+	admins, err := qtx.LockAdmins(r.Context())
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+	if role != db.UserRoleAdmin && slices.Equal(admins, []uuid.UUID{userID}) {
+		respondWithError(w, "The last admin can't be given another role", http.StatusConflict, nil)
+		return
+	}
+	user, err := qtx.SetUserRole(r.Context(), db.SetUserRoleParams{ID: userID, Role: role})
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, "User not found", http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+	err = tx.Commit()
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+
+	resp := struct {
+		ID       uuid.UUID   `json:"id"`
+		Username string      `json:"username"`
+		Email    string      `json:"email"`
+		Role     db.UserRole `json:"role"`
+	}{
+		ID:       user.ID,
+		Username: user.Username,
+		Email:    user.Email,
+		Role:     user.Role,
+	}
+	err = respondWithJSON(w, http.StatusAccepted, resp)
+	if err != nil {
+		respondWithError(w, "Error processing response data", http.StatusInternalServerError, err)
+		return
+	}
+}
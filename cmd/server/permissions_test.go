@@ -0,0 +1,118 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"regexp"
+	"testing"
+
+	"github.com/Denisowiec/FoleyBookkeeper/internal/db"
+	"github.com/google/uuid"
+)
+
+func TestEveryRouteHasPermission(t *testing.T) {
+	// A route without an entry would be refused, so catch it here instead
+	src, err := os.ReadFile("main.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	routes := regexp.MustCompile(`(?m)^\s*mux\.HandleFunc\("([^"]+)"`).FindAllStringSubmatch(string(src), -1)
+	if len(routes) == 0 {
+		t.Fatal("no routes found in main.go")
+	}
+	registered := map[string]bool{}
+	for _, route := range routes {
+		registered[route[1]] = true
+		if _, ok := endpointPermissions[route[1]]; !ok {
+			t.Errorf("route %s has no permission set", route[1])
+		}
+	}
+	for pattern := range endpointPermissions {
+		if !registered[pattern] {
+			t.Errorf("permission set for %s, which isn't a route", pattern)
+		}
+	}
+}
+
+func TestRoleAllows(t *testing.T) {
+	cases := []struct {
+		role    db.UserRole
+		perm    permission
+		allowed bool
+	}{
+		{db.UserRoleAdmin, permUsersManage, true},
+		{db.UserRoleManager, permUsersManage, false},
+		{db.UserRoleManager, permTimesheetsReview, true},
+		{db.UserRoleEngineer, permSessionsWrite, true},
+		{db.UserRoleEngineer, permClientsDelete, false},
+		{db.UserRoleEngineer, permCalculationsRead, false},
+		{db.UserRoleAccountant, permCalculationsWrite, true},
+		{db.UserRoleAccountant, permSessionsWrite, false},
+		{db.UserRoleAccountant, permLoggedIn, true},
+		{db.UserRole("guest"), permProjectsRead, false},
+	}
+	for _, c := range cases {
+		if got := roleAllows(c.role, c.perm); got != c.allowed {
+			t.Errorf("%s with %s: got %v, expected %v", c.role, c.perm, got, c.allowed)
+		}
+	}
+
+	// Admins can do whatever any other role can
+	for role, perms := range rolePermissions {
+		for _, perm := range perms {
+			if !roleAllows(db.UserRoleAdmin, perm) {
+				t.Errorf("admins lack %s, which %s has", perm, role)
+			}
+		}
+	}
+}
+
+func TestAuthorizeRefusesWithoutLogin(t *testing.T) {
+	cfg := apiConfig{secret: "test"}
+	mux := http.NewServeMux()
+	reached := false
+	mux.HandleFunc("POST /api/login", func(w http.ResponseWriter, r *http.Request) { reached = true })
+	mux.HandleFunc("GET /api/trash", func(w http.ResponseWriter, r *http.Request) { reached = true })
+	mux.HandleFunc("GET /api/unlisted", func(w http.ResponseWriter, r *http.Request) { reached = true })
+	handler := cfg.authorize(mux)
+
+	cases := []struct {
+		method, path string
+		code         int
+		reached      bool
+	}{
+		{"POST", "/api/login", http.StatusOK, true},
+		{"GET", "/api/trash", http.StatusUnauthorized, false},
+		{"GET", "/api/unlisted", http.StatusForbidden, false},
+		{"GET", "/api/nothing", http.StatusNotFound, false},
+	}
+	for _, c := range cases {
+		reached = false
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(c.method, c.path, nil))
+		if rec.Code != c.code || reached != c.reached {
+			t.Errorf("%s %s: got %d, handler reached %v", c.method, c.path, rec.Code, reached)
+		}
+	}
+}
+
+func TestCanReadTimesheet(t *testing.T) {
+	owner := db.User{ID: uuid.New(), Role: db.UserRoleEngineer}
+	timesheet := db.Timesheet{ID: uuid.New(), UserID: owner.ID}
+	cases := []struct {
+		user    db.User
+		allowed bool
+	}{
+		{owner, true},
+		{db.User{ID: uuid.New(), Role: db.UserRoleEngineer}, false},
+		{db.User{ID: uuid.New(), Role: db.UserRoleAccountant}, false},
+		{db.User{ID: uuid.New(), Role: db.UserRoleManager}, true},
+		{db.User{ID: uuid.New(), Role: db.UserRoleAdmin}, true},
+	}
+	for _, c := range cases {
+		if got := canReadTimesheet(c.user, timesheet); got != c.allowed {
+			t.Errorf("%s reading someone's timesheet: got %v, expected %v", c.user.Role, got, c.allowed)
+		}
+	}
+}
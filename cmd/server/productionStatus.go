@@ -0,0 +1,305 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Denisowiec/FoleyBookkeeper/internal/db"
+	"github.com/google/uuid"
+)
+
+// Production statuses in the order parts go through them
+var productionStatuses = []db.ProductionStatus{
+	db.ProductionStatusNotStarted,
+	db.ProductionStatusSpotted,
+	db.ProductionStatusRecorded,
+	db.ProductionStatusEdited,
+	db.ProductionStatusPremixed,
+	db.ProductionStatusDelivered,
+	db.ProductionStatusApproved,
+}
+
+func strToProductionStatus(input string) (db.ProductionStatus, error) {
+	input = strings.ReplaceAll(strings.ToLower(strings.TrimSpace(input)), " ", "_")
+	for _, s := range productionStatuses {
+		if string(s) == input {
+			return s, nil
+		}
+	}
+	return "", fmt.Errorf("production status %s unknown", input)
+}
+
+func advancesToFromInput(input string) (db.NullProductionStatus, error) {
+	// Activities don't have to advance anything, an empty input clears it
+	if input == "" {
+		return db.NullProductionStatus{}, nil
+	}
+	status, err := strToProductionStatus(input)
+	if err != nil {
+		return db.NullProductionStatus{}, err
+	}
+	return db.NullProductionStatus{ProductionStatus: status, Valid: true}, nil
+}
+
+func advancePartStatus(ctx context.Context, q *db.Queries, session db.Session) error {
+	// Moves the session's part of its episode forward to the status its
+	// activity leads to. Parts that are already further along stay put,
+	// and sessions above the episode level have no status to advance
+	if !session.EpisodeID.Valid {
+		return nil
+	}
+	activity, err := q.GetActivityByCode(ctx, session.ActivityDone)
+	if err != nil {
+		return err
+	}
+	if !activity.AdvancesTo.Valid {
+		return nil
+	}
+
+	advanceParams := db.AdvanceEpisodePartStatusParams{
+		EpisodeID:  session.EpisodeID.UUID,
+		Part:       session.PartWorkedOn,
+		Status:     activity.AdvancesTo.ProductionStatus,
+		StatusDate: sql.NullTime{Time: session.SessionDate, Valid: true},
+	}
+	_, err = q.AdvanceEpisodePartStatus(ctx, advanceParams)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	return err
+}
+
+type statusCell struct {
+	Status     db.ProductionStatus `json:"status"`
+	AssigneeID uuid.NullUUID       `json:"assignee_id"`
+	Assignee   string              `json:"assignee"`
+	Date       string              `json:"date"`
+	Notes      string              `json:"notes"`
+}
+
+func (cfg *apiConfig) handlerSetEpisodePartStatus(w http.ResponseWriter, r *http.Request) {
+	// Sets the production status of a part of an episode, with its assignee,
+	// date and notes. Fields not given keep their values, empty ones are cleared.
+	// The date is today's when the status changes without one
+	_, _, err := authenticateUser(r, cfg.secret)
+	if err != nil {
+		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+		return
+	}
+
+	episodeID, err := uuid.Parse(r.PathValue("episodeid"))
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+	episode, err := cfg.db.GetEpisodeByID(r.Context(), episodeID)
+	if err != nil {
+		respondWithError(w, "Episode not found", http.StatusNotFound, err)
+		return
+	}
+	if !cfg.requireProjectContribution(w, r, episode.ProjectID) {
+		return
+	}
+	part, err := cfg.db.GetPartByCode(r.Context(), strings.ToLower(r.PathValue("part")))
+	if err != nil {
+		respondWithError(w, "Part not found", http.StatusNotFound, err)
+		return
+	}
+
+	statusInput := struct {
+		Status     *string `json:"status"`
+		AssigneeID *string `json:"assignee_id"`
+		Date       *string `json:"date"`
+		Notes      *string `json:"notes"`
+	}{}
+	decoder := json.NewDecoder(r.Body)
+	err = decoder.Decode(&statusInput)
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+
+	params := db.SetEpisodePartStatusParams{
+		EpisodeID: episodeID,
+		Part:      part.Code,
+		Status:    db.ProductionStatusNotStarted,
+	}
+	previous, err := cfg.db.GetEpisodePartStatus(r.Context(), db.GetEpisodePartStatusParams{EpisodeID: episodeID, Part: part.Code})
+	if err == nil {
+		params.Status = previous.Status
+		params.AssigneeID = previous.AssigneeID
+		params.StatusDate = previous.StatusDate
+		params.Notes = previous.Notes
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+
+	if statusInput.Status != nil {
+		status, err := strToProductionStatus(*statusInput.Status)
+		if err != nil {
+			respondWithError(w, err.Error(), http.StatusBadRequest, err)
+			return
+		}
+		if status != params.Status && statusInput.Date == nil {
+			params.StatusDate = sql.NullTime{Time: time.Now(), Valid: true}
+		}
+		params.Status = status
+	}
+	if statusInput.AssigneeID != nil {
+		params.AssigneeID, err = parseNullUUID(*statusInput.AssigneeID)
+		if err != nil {
+			respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+			return
+		}
+		if params.AssigneeID.Valid {
+			_, err = cfg.db.GetUserByID(r.Context(), params.AssigneeID.UUID)
+			if err != nil {
+				respondWithError(w, "Assignee not found", http.StatusBadRequest, err)
+				return
+			}
+		}
+	}
+	if statusInput.Date != nil {
+		params.StatusDate = sql.NullTime{}
+		if *statusInput.Date != "" {
+			date, err := time.Parse(time.DateOnly, *statusInput.Date)
+			if err != nil {
+				respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+				return
+			}
+			params.StatusDate = sql.NullTime{Time: date, Valid: true}
+		}
+	}
+	if statusInput.Notes != nil {
+		params.Notes = sql.NullString{String: *statusInput.Notes, Valid: *statusInput.Notes != ""}
+	}
+
+	status, err := cfg.db.SetEpisodePartStatus(r.Context(), params)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+
+	err = respondWithJSON(w, http.StatusAccepted, status)
+	if err != nil {
+		respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
+		return
+	}
+}
+
+func (cfg *apiConfig) handlerGetStatusMatrix(w http.ResponseWriter, r *http.Request) {
+	// Returns the production status of every part of every episode of a project.
+	// Active parts are always listed, inactive ones only if they have a status
+	_, _, err := authenticateUser(r, cfg.secret)
+	if err != nil {
+		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+		return
+	}
+
+	projectID, err := uuid.Parse(r.PathValue("projectid"))
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+	if !cfg.requireProjectVisible(w, r, projectID) {
+		return
+	}
+	_, err = cfg.db.GetProjectByID(r.Context(), projectID)
+	if err != nil {
+		respondWithError(w, "Project not found", http.StatusNotFound, err)
+		return
+	}
+
+	episodes, err := cfg.getProjectEpisodes(r.Context(), projectID)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+	parts, err := cfg.db.GetAllParts(r.Context())
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+	rows, err := cfg.db.GetStatusMatrixForProject(r.Context(), projectID)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+
+	type cellKey struct {
+		episodeID uuid.UUID
+		part      string
+	}
+	cells := map[cellKey]statusCell{}
+	usedParts := map[string]bool{}
+	for _, row := range rows {
+		cell := statusCell{
+			Status:     row.Status,
+			AssigneeID: row.AssigneeID,
+			Assignee:   row.Assignee.String,
+			Notes:      row.Notes.String,
+		}
+		if row.StatusDate.Valid {
+			cell.Date = row.StatusDate.Time.Format(time.DateOnly)
+		}
+		cells[cellKey{row.EpisodeID, row.Part}] = cell
+		usedParts[row.Part] = true
+	}
+
+	type matrixPart struct {
+		Code        string `json:"code"`
+		DisplayName string `json:"display_name"`
+	}
+	type matrixEpisode struct {
+		EpisodeID     uuid.UUID             `json:"episode_id"`
+		EpisodeNumber int32                 `json:"episode_number"`
+		Code          string                `json:"code"`
+		Title         string                `json:"title"`
+		Parts         map[string]statusCell `json:"parts"`
+	}
+	matrix := struct {
+		ProjectID uuid.UUID       `json:"project_id"`
+		Parts     []matrixPart    `json:"parts"`
+		Episodes  []matrixEpisode `json:"episodes"`
+	}{
+		ProjectID: projectID,
+		Parts:     []matrixPart{},
+		Episodes:  []matrixEpisode{},
+	}
+
+	for _, p := range parts {
+		if p.Active || usedParts[p.Code] {
+			matrix.Parts = append(matrix.Parts, matrixPart{Code: p.Code, DisplayName: p.DisplayName})
+		}
+	}
+	for _, ep := range episodes.list {
+		item := matrixEpisode{
+			EpisodeID:     ep.ID,
+			EpisodeNumber: ep.EpisodeNumber,
+			Code:          episodes.code(ep).String(),
+			Title:         ep.Title.String,
+			Parts:         map[string]statusCell{},
+		}
+		for _, p := range matrix.Parts {
+			cell, ok := cells[cellKey{ep.ID, p.Code}]
+			if !ok {
+				cell = statusCell{Status: db.ProductionStatusNotStarted}
+			}
+			item.Parts[p.Code] = cell
+		}
+		matrix.Episodes = append(matrix.Episodes, item)
+	}
+
+	err = respondWithJSON(w, http.StatusOK, matrix)
+	if err != nil {
+		respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
+		return
+	}
+}
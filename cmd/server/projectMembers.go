@@ -0,0 +1,316 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/Denisowiec/FoleyBookkeeper/internal/db"
+	"github.com/google/uuid"
+)
+
+var projectRoles = []db.ProjectRole{db.ProjectRoleLead, db.ProjectRoleMember, db.ProjectRoleViewer}
+
+func strToProjectRole(input string) (db.ProjectRole, error) {
+	role := db.ProjectRole(strings.ToLower(strings.TrimSpace(input)))
+	if !slices.Contains(projectRoles, role) {
+		return "", fmt.Errorf("project role %s unknown", input)
+	}
+	return role, nil
+}
+
+// projectAccess is what a user may do on which projects. Admins and
+// managers see every project, everyone else only the ones they're members of
+type projectAccess struct {
+	userID uuid.UUID
+	global bool
+	roles  map[uuid.UUID]db.ProjectRole
+}
+
+func (a projectAccess) canSee(projectID uuid.UUID) bool {
+	_, ok := a.roles[projectID]
+	return a.global || ok
+}
+
+func (a projectAccess) canContribute(projectID uuid.UUID) bool {
+	// Leads and members work on the project, viewers only look at it
+	role, ok := a.roles[projectID]
+	return a.global || (ok && role != db.ProjectRoleViewer)
+}
+
+func (a projectAccess) canManageMembers(projectID uuid.UUID) bool {
+	return a.global || a.roles[projectID] == db.ProjectRoleLead
+}
+
+func (a projectAccess) canSeeSchedule(userID uuid.UUID) bool {
+	// Another user's schedule spans projects the user may not be on
+	return a.global || a.userID == userID
+}
+
+func (a projectAccess) visibleProjects(list []db.Project) []db.Project {
+	if a.global {
+		return list
+	}
+	visible := []db.Project{}
+	for _, p := range list {
+		if a.canSee(p.ID) {
+			visible = append(visible, p)
+		}
+	}
+	return visible
+}
+
+func (a projectAccess) visibleBookings(list []db.Booking) []db.Booking {
+	if a.global {
+		return list
+	}
+	visible := []db.Booking{}
+	for _, b := range list {
+		if a.canSee(b.ProjectID) {
+			visible = append(visible, b)
+		}
+	}
+	return visible
+}
+
+func (cfg *apiConfig) getProjectAccess(ctx context.Context, user db.User) (projectAccess, error) {
+	access := projectAccess{
+		userID: user.ID,
+		global: user.Role == db.UserRoleAdmin || user.Role == db.UserRoleManager,
+		roles:  map[uuid.UUID]db.ProjectRole{},
+	}
+	if access.global {
+		return access, nil
+	}
+	memberships, err := cfg.db.GetProjectRolesForUser(ctx, user.ID)
+	if err != nil {
+		return projectAccess{}, err
+	}
+	for _, m := range memberships {
+		access.roles[m.ProjectID] = m.Role
+	}
+	return access, nil
+}
+
+func (cfg *apiConfig) requestProjectAccess(r *http.Request) (projectAccess, error) {
+	user, err := requestUser(r)
+	if err != nil {
+		return projectAccess{}, err
+	}
+	return cfg.getProjectAccess(r.Context(), user)
+}
+
+func (cfg *apiConfig) requireProjectVisible(w http.ResponseWriter, r *http.Request, projectID uuid.UUID) bool {
+	// Projects the user can't see are reported as not found, so their
+	// existence isn't given away either
+	access, err := cfg.requestProjectAccess(r)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return false
+	}
+	if !access.canSee(projectID) {
+		respondWithError(w, "Project not found", http.StatusNotFound, nil)
+		return false
+	}
+	return true
+}
+
+func (cfg *apiConfig) requireSessionLogging(w http.ResponseWriter, r *http.Request, projectIDs ...uuid.UUID) bool {
+	// Sessions are only logged by members of the project they're on
+	access, err := cfg.requestProjectAccess(r)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return false
+	}
+	for _, id := range projectIDs {
+		if !access.canContribute(id) {
+			respondWithError(w, "Only members of the project can log sessions on it", http.StatusForbidden, nil)
+			return false
+		}
+	}
+	return true
+}
+
+func (a projectAccess) contributionRefusal(projectIDs ...uuid.UUID) (int, string) {
+	// Projects the user can't see are checked first, so a refusal on one
+	// project doesn't give away the existence of another
+	for _, id := range projectIDs {
+		if !a.canSee(id) {
+			return http.StatusNotFound, "Project not found"
+		}
+	}
+	for _, id := range projectIDs {
+		if !a.canContribute(id) {
+			return http.StatusForbidden, "Only members of the project can change its production data"
+		}
+	}
+	return 0, ""
+}
+
+func (cfg *apiConfig) requireProjectContribution(w http.ResponseWriter, r *http.Request, projectIDs ...uuid.UUID) bool {
+	// Cues, imports, bookings and production statuses are changed by the
+	// people working on the project, like sessions
+	access, err := cfg.requestProjectAccess(r)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return false
+	}
+	if code, msg := access.contributionRefusal(projectIDs...); code != 0 {
+		respondWithError(w, msg, code, nil)
+		return false
+	}
+	return true
+}
+
+func (cfg *apiConfig) handlerGetProjectMembers(w http.ResponseWriter, r *http.Request) {
+	projectID, err := uuid.Parse(r.PathValue("projectid"))
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+	if !cfg.requireProjectVisible(w, r, projectID) {
+		return
+	}
+
+	members, err := cfg.db.GetProjectMembers(r.Context(), projectID)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+	err = respondWithJSON(w, http.StatusOK, members)
+	if err != nil {
+		respondWithError(w, "Error processing response data", http.StatusInternalServerError, err)
+		return
+	}
+}
+
+func (cfg *apiConfig) handlerSetProjectMember(w http.ResponseWriter, r *http.Request) {
+	// Adds a user to the project or changes their role on it.
+	// Admins, managers and the project's leads are allowed to
+	projectID, err := uuid.Parse(r.PathValue("projectid"))
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+	userID, err := uuid.Parse(r.PathValue("userid"))
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+
+	memberInput := struct {
+		Role string `json:"role"`
+	}{}
+	decoder := json.NewDecoder(r.Body)
+	err = decoder.Decode(&memberInput)
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+	role := db.ProjectRoleMember
+	if memberInput.Role != "" {
+		role, err = strToProjectRole(memberInput.Role)
+		if err != nil {
+			respondWithError(w, fmt.Sprintf("Invalid project role, valid ones are %v", projectRoles), http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	access, err := cfg.requestProjectAccess(r)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+	if !access.canSee(projectID) {
+		respondWithError(w, "Project not found", http.StatusNotFound, nil)
+		return
+	}
+	if !access.canManageMembers(projectID) {
+		respondWithError(w, "Only the project's leads can manage its members", http.StatusForbidden, nil)
+		return
+	}
+
+	_, err = cfg.db.GetProjectByID(r.Context(), projectID)
+	if err != nil {
+		respondWithError(w, "Project not found", http.StatusNotFound, err)
+		return
+	}
+	user, err := cfg.db.GetUserByID(r.Context(), userID)
+	if err != nil {
+		respondWithError(w, "User not found", http.StatusNotFound, err)
+		return
+	}
+
+	params := db.SetProjectMemberParams{
+		ProjectID: projectID,
+		UserID:    userID,
+		Role:      role,
+	}
+	member, err := cfg.db.SetProjectMember(r.Context(), params)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+
+	resp := db.GetProjectMembersRow{
+		UserID:    member.UserID,
+		Username:  user.Username,
+		Role:      member.Role,
+		CreatedAt: member.CreatedAt,
+	}
+	err = respondWithJSON(w, http.StatusAccepted, resp)
+	if err != nil {
+		respondWithError(w, "Error processing response data", http.StatusInternalServerError, err)
+		return
+	}
+}
+
+func (cfg *apiConfig) handlerRemoveProjectMember(w http.ResponseWriter, r *http.Request) {
+	// Sessions the user already logged on the project stay where they are
+	projectID, err := uuid.Parse(r.PathValue("projectid"))
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+	userID, err := uuid.Parse(r.PathValue("userid"))
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+
+	access, err := cfg.requestProjectAccess(r)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+	if !access.canSee(projectID) {
+		respondWithError(w, "Project not found", http.StatusNotFound, nil)
+		return
+	}
+	if !access.canManageMembers(projectID) {
+		respondWithError(w, "Only the project's leads can manage its members", http.StatusForbidden, nil)
+		return
+	}
+
+	params := db.RemoveProjectMemberParams{ProjectID: projectID, UserID: userID}
+	member, err := cfg.db.RemoveProjectMember(r.Context(), params)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, "The user isn't a member of this project", http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+
+	err = respondWithJSON(w, http.StatusAccepted, member)
+	if err != nil {
+		respondWithError(w, "Error processing response data", http.StatusInternalServerError, err)
+		return
+	}
+}
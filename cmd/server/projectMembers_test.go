@@ -0,0 +1,104 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/Denisowiec/FoleyBookkeeper/internal/db"
+	"github.com/google/uuid"
+)
+
+func TestProjectAccess(t *testing.T) {
+	led, joined, watched, other := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	access := projectAccess{
+		userID: uuid.New(),
+		roles: map[uuid.UUID]db.ProjectRole{
+			led:     db.ProjectRoleLead,
+			joined:  db.ProjectRoleMember,
+			watched: db.ProjectRoleViewer,
+		},
+	}
+
+	cases := []struct {
+		project                        uuid.UUID
+		see, contribute, manageMembers bool
+	}{
+		{led, true, true, true},
+		{joined, true, true, false},
+		{watched, true, false, false},
+		{other, false, false, false},
+	}
+	for _, c := range cases {
+		if access.canSee(c.project) != c.see || access.canContribute(c.project) != c.contribute ||
+			access.canManageMembers(c.project) != c.manageMembers {
+			t.Errorf("access to %s with role %q isn't what it should be", c.project, access.roles[c.project])
+		}
+	}
+
+	list := []db.Project{{ID: led}, {ID: other}, {ID: watched}}
+	if visible := access.visibleProjects(list); len(visible) != 2 || visible[1].ID != watched {
+		t.Errorf("only the member's projects should be listed, got %v", visible)
+	}
+
+	if !access.canSeeSchedule(access.userID) || access.canSeeSchedule(other) {
+		t.Error("members should only see their own schedule")
+	}
+
+	bookings := []db.Booking{{ProjectID: other}, {ProjectID: joined}}
+	if visible := access.visibleBookings(bookings); len(visible) != 1 || visible[0].ProjectID != joined {
+		t.Errorf("only bookings of the member's projects should be listed, got %v", visible)
+	}
+
+	global := projectAccess{global: true}
+	if !global.canSee(other) || !global.canContribute(other) || !global.canManageMembers(other) ||
+		!global.canSeeSchedule(other) {
+		t.Error("admins and managers should have access to every project")
+	}
+	if len(global.visibleProjects(list)) != len(list) || len(global.visibleBookings(bookings)) != len(bookings) {
+		t.Error("admins and managers should see every project")
+	}
+}
+
+func TestContributionRefusal(t *testing.T) {
+	joined, watched, other := uuid.New(), uuid.New(), uuid.New()
+	access := projectAccess{
+		userID: uuid.New(),
+		roles: map[uuid.UUID]db.ProjectRole{
+			joined:  db.ProjectRoleMember,
+			watched: db.ProjectRoleViewer,
+		},
+	}
+
+	cases := []struct {
+		name     string
+		projects []uuid.UUID
+		code     int
+	}{
+		{"member", []uuid.UUID{joined}, 0},
+		{"viewer", []uuid.UUID{watched}, http.StatusForbidden},
+		{"outsider", []uuid.UUID{other}, http.StatusNotFound},
+		{"moving to a watched project", []uuid.UUID{joined, watched}, http.StatusForbidden},
+		{"moving from a watched project", []uuid.UUID{watched, joined}, http.StatusForbidden},
+		{"moving to a hidden project", []uuid.UUID{watched, other}, http.StatusNotFound},
+	}
+	for _, c := range cases {
+		if code, _ := access.contributionRefusal(c.projects...); code != c.code {
+			t.Errorf("%s: got %d, want %d", c.name, code, c.code)
+		}
+	}
+
+	global := projectAccess{global: true}
+	if code, _ := global.contributionRefusal(other, watched); code != 0 {
+		t.Errorf("admins and managers should change every project, got %d", code)
+	}
+}
+
+func TestStrToProjectRole(t *testing.T) {
+	role, err := strToProjectRole(" Lead ")
+	if err != nil || role != db.ProjectRoleLead {
+		t.Errorf("got %q, %v", role, err)
+	}
+	if _, err := strToProjectRole("owner"); err == nil {
+		t.Error("unknown project roles should be rejected")
+	}
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/Denisowiec/FoleyBookkeeper/internal/db"
+	"github.com/google/uuid"
+)
+
+// The statuses a project can move to from each status. Delivered projects
+// can go back to active for revisions, closed ones can be reopened
+var projectTransitions = map[db.ProjectStatus][]db.ProjectStatus{
+	db.ProjectStatusBidding:   {db.ProjectStatusActive, db.ProjectStatusClosed},
+	db.ProjectStatusActive:    {db.ProjectStatusOnHold, db.ProjectStatusDelivered, db.ProjectStatusClosed},
+	db.ProjectStatusOnHold:    {db.ProjectStatusActive, db.ProjectStatusClosed},
+	db.ProjectStatusDelivered: {db.ProjectStatusActive, db.ProjectStatusClosed},
+	db.ProjectStatusClosed:    {db.ProjectStatusActive, db.ProjectStatusArchived},
+	db.ProjectStatusArchived:  {db.ProjectStatusClosed},
+}
+
+func strToProjectStatus(input string) (db.ProjectStatus, error) {
+	status := db.ProjectStatus(strings.ReplaceAll(strings.ToLower(strings.TrimSpace(input)), " ", "_"))
+	if _, ok := projectTransitions[status]; !ok {
+		return "", fmt.Errorf("project status %s unknown", input)
+	}
+	return status, nil
+}
+
+func canTransitionProject(from, to db.ProjectStatus) bool {
+	return slices.Contains(projectTransitions[from], to)
+}
+
+// No more work can be logged or budgeted on closed and archived projects
+func projectAcceptsWork(status db.ProjectStatus) bool {
+	return status != db.ProjectStatusClosed && status != db.ProjectStatusArchived
+}
+
+func filterProjects(list []db.Project, statuses []db.ProjectStatus) []db.Project {
+	// Without statuses given, all but archived projects are kept
+	filtered := []db.Project{}
+	for _, p := range list {
+		if len(statuses) == 0 && p.Status == db.ProjectStatusArchived {
+			continue
+		}
+		if len(statuses) > 0 && !slices.Contains(statuses, p.Status) {
+			continue
+		}
+		filtered = append(filtered, p)
+	}
+	return filtered
+}
+
+func parseProjectStatusFilter(input string) ([]db.ProjectStatus, error) {
+	// Takes a comma separated list of statuses, or "all"
+	statuses := []db.ProjectStatus{}
+	if input == "" {
+		return statuses, nil
+	}
+	if input == "all" {
+		for status := range projectTransitions {
+			statuses = append(statuses, status)
+		}
+		return statuses, nil
+	}
+	for _, s := range strings.Split(input, ",") {
+		status, err := strToProjectStatus(s)
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+func (cfg *apiConfig) checkProjectAcceptsWork(ctx context.Context, projectID uuid.UUID) error {
+	prj, err := cfg.db.GetProjectByID(ctx, projectID)
+	if err != nil {
+		return fmt.Errorf("project not found: %w", err)
+	}
+	if !projectAcceptsWork(prj.Status) {
+		return fmt.Errorf("project %s is %s", prj.Title, prj.Status)
+	}
+	return nil
+}
+
+func (cfg *apiConfig) handlerSetProjectStatus(w http.ResponseWriter, r *http.Request) {
+	// Moves a project to another status, if the transition is allowed
+	_, _, err := authenticateUser(r, cfg.secret)
+	if err != nil {
+		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+		return
+	}
+
+	projectID, err := uuid.Parse(r.PathValue("projectid"))
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+	prj, err := cfg.db.GetProjectByID(r.Context(), projectID)
+	if err != nil {
+		respondWithError(w, "Project not found", http.StatusNotFound, err)
+		return
+	}
+
+	statusInput := struct {
+		Status string `json:"status"`
+	}{}
+	decoder := json.NewDecoder(r.Body)
+	err = decoder.Decode(&statusInput)
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+	status, err := strToProjectStatus(statusInput.Status)
+	if err != nil {
+		respondWithError(w, err.Error(), http.StatusBadRequest, err)
+		return
+	}
+	if !canTransitionProject(prj.Status, status) {
+		msg := fmt.Sprintf("Project can't go from %s to %s", prj.Status, status)
+		respondWithError(w, msg, http.StatusConflict, nil)
+		return
+	}
+
+	setStatusParams := db.SetProjectStatusParams{
+		ID:     projectID,
+		Status: status,
+	}
+	prj, err = cfg.db.SetProjectStatus(r.Context(), setStatusParams)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+
+	err = respondWithJSON(w, http.StatusAccepted, prj)
+	if err != nil {
+		respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
+		return
+	}
+}
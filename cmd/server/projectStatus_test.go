@@ -0,0 +1,64 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/Denisowiec/FoleyBookkeeper/internal/db"
+)
+
+func TestProjectTransitions(t *testing.T) {
+	cases := []struct {
+		from, to db.ProjectStatus
+		allowed  bool
+	}{
+		{db.ProjectStatusBidding, db.ProjectStatusActive, true},
+		{db.ProjectStatusActive, db.ProjectStatusDelivered, true},
+		{db.ProjectStatusDelivered, db.ProjectStatusActive, true},
+		{db.ProjectStatusClosed, db.ProjectStatusArchived, true},
+		{db.ProjectStatusActive, db.ProjectStatusArchived, false},
+		{db.ProjectStatusArchived, db.ProjectStatusActive, false},
+		{db.ProjectStatusBidding, db.ProjectStatusDelivered, false},
+	}
+	for _, c := range cases {
+		if got := canTransitionProject(c.from, c.to); got != c.allowed {
+			t.Errorf("%s -> %s: expected %v, got %v", c.from, c.to, c.allowed, got)
+		}
+	}
+}
+
+func TestFilterProjects(t *testing.T) {
+	list := []db.Project{
+		{Title: "A", Status: db.ProjectStatusActive},
+		{Title: "B", Status: db.ProjectStatusArchived},
+		{Title: "C", Status: db.ProjectStatusOnHold},
+	}
+
+	statuses, err := parseProjectStatusFilter("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := filterProjects(list, statuses); len(got) != 2 || got[1].Title != "C" {
+		t.Errorf("archived projects should be hidden by default, got %v", got)
+	}
+
+	statuses, err = parseProjectStatusFilter("archived")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := filterProjects(list, statuses); len(got) != 1 || got[0].Title != "B" {
+		t.Errorf("expected only the archived project, got %v", got)
+	}
+
+	statuses, err = parseProjectStatusFilter("all")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := filterProjects(list, statuses); len(got) != 3 {
+		t.Errorf("expected all projects, got %v", got)
+	}
+
+	_, err = parseProjectStatusFilter("active,finished")
+	if err == nil {
+		t.Error("expected an error for an unknown status")
+	}
+}
@@ -0,0 +1,483 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Denisowiec/FoleyBookkeeper/internal/db"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// Series are made of episodes. Features, commercials and games are broken
+// down into units instead, e.g. reels or deliverables, if at all
+var projectTypes = []db.ProjectType{
+	db.ProjectTypeSeries,
+	db.ProjectTypeFeature,
+	db.ProjectTypeCommercial,
+	db.ProjectTypeGame,
+}
+
+var unitKinds = []db.UnitKind{
+	db.UnitKindSeason,
+	db.UnitKindReel,
+	db.UnitKindDeliverable,
+}
+
+func strToProjectType(input string) (db.ProjectType, error) {
+	projectType := db.ProjectType(strings.ToLower(strings.TrimSpace(input)))
+	if !slices.Contains(projectTypes, projectType) {
+		return "", fmt.Errorf("project type %s unknown", input)
+	}
+	return projectType, nil
+}
+
+func strToUnitKind(input string) (db.UnitKind, error) {
+	kind := db.UnitKind(strings.ToLower(strings.TrimSpace(input)))
+	if !slices.Contains(unitKinds, kind) {
+		return "", fmt.Errorf("unit kind %s unknown", input)
+	}
+	return kind, nil
+}
+
+func unitParentCreatesCycle(units []db.ProjectUnit, unitID, parentID uuid.UUID) bool {
+	// Walks up from the new parent, a unit can't end up among its own ancestors
+	parents := map[uuid.UUID]uuid.NullUUID{}
+	for _, u := range units {
+		parents[u.ID] = u.ParentID
+	}
+	current := uuid.NullUUID{UUID: parentID, Valid: true}
+	for steps := 0; current.Valid && steps <= len(units); steps++ {
+		if current.UUID == unitID {
+			return true
+		}
+		current = parents[current.UUID]
+	}
+	return false
+}
+
+// Fields given as null keep their values on updates, empty ones are cleared
+type unitInputType struct {
+	ParentID     string  `json:"parent_id"`
+	Kind         string  `json:"kind"`
+	Name         string  `json:"name"`
+	SortOrder    *int32  `json:"sort_order"`
+	Number       *int32  `json:"number"`
+	StartsOn     *string `json:"starts_on"`
+	EndsOn       *string `json:"ends_on"`
+	Budget       *string `json:"budget"`
+	Currency     *string `json:"currency"`
+	ExchangeRate *string `json:"exchange_rate"`
+}
+
+// Seasons need a number, other units can have one. Dates and calculation
+// defaults are optional for all units
+type unitDetails struct {
+	Number       sql.NullInt32
+	StartsOn     sql.NullTime
+	EndsOn       sql.NullTime
+	Budget       sql.NullString
+	Currency     sql.NullString
+	ExchangeRate sql.NullString
+}
+
+func parseUnitDetails(input unitInputType, details unitDetails) (unitDetails, error) {
+	if input.Number != nil {
+		if *input.Number < 0 {
+			return unitDetails{}, fmt.Errorf("unit number can't be negative")
+		}
+		details.Number = sql.NullInt32{Int32: *input.Number, Valid: *input.Number != 0}
+	}
+	parseDate := func(input *string, date *sql.NullTime) error {
+		if input == nil {
+			return nil
+		}
+		*date = sql.NullTime{}
+		if *input == "" {
+			return nil
+		}
+		parsed, err := time.Parse(time.DateOnly, *input)
+		if err != nil {
+			return fmt.Errorf("invalid date %s", *input)
+		}
+		*date = sql.NullTime{Time: parsed, Valid: true}
+		return nil
+	}
+	err := parseDate(input.StartsOn, &details.StartsOn)
+	if err != nil {
+		return unitDetails{}, err
+	}
+	err = parseDate(input.EndsOn, &details.EndsOn)
+	if err != nil {
+		return unitDetails{}, err
+	}
+	if details.StartsOn.Valid && details.EndsOn.Valid && details.EndsOn.Time.Before(details.StartsOn.Time) {
+		return unitDetails{}, fmt.Errorf("unit can't end before it starts")
+	}
+	parseDecimal := func(input *string, value *sql.NullString) error {
+		if input == nil {
+			return nil
+		}
+		*value = sql.NullString{}
+		if *input == "" {
+			return nil
+		}
+		parsed, err := decimal.NewFromString(*input)
+		if err != nil {
+			return fmt.Errorf("invalid amount %s", *input)
+		}
+		*value = sql.NullString{String: parsed.String(), Valid: true}
+		return nil
+	}
+	err = parseDecimal(input.Budget, &details.Budget)
+	if err != nil {
+		return unitDetails{}, err
+	}
+	err = parseDecimal(input.ExchangeRate, &details.ExchangeRate)
+	if err != nil {
+		return unitDetails{}, err
+	}
+	if input.Currency != nil {
+		currency := strings.ToUpper(strings.TrimSpace(*input.Currency))
+		details.Currency = sql.NullString{String: currency, Valid: currency != ""}
+	}
+	return details, nil
+}
+
+var errUnitNumberTaken = errors.New("another unit of this kind already has this number")
+
+func (cfg *apiConfig) checkUnitNumber(ctx context.Context, projectID, unitID uuid.UUID, kind db.UnitKind, number sql.NullInt32) error {
+	// Numbers are unique among units of the same kind, and seasons need one
+	// for codes like S02E05
+	if !number.Valid {
+		if kind == db.UnitKindSeason {
+			return fmt.Errorf("seasons need a number")
+		}
+		return nil
+	}
+	getUnitParams := db.GetUnitByNumberParams{
+		ProjectID: projectID,
+		Kind:      kind,
+		Number:    number,
+	}
+	other, err := cfg.db.GetUnitByNumber(ctx, getUnitParams)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if other.ID != unitID {
+		return errUnitNumberTaken
+	}
+	return nil
+}
+
+func (cfg *apiConfig) checkUnitParent(ctx context.Context, projectID uuid.UUID, parentID uuid.NullUUID) error {
+	// Parents have to be units of the same project
+	if !parentID.Valid {
+		return nil
+	}
+	parent, err := cfg.db.GetProjectUnitByID(ctx, parentID.UUID)
+	if err != nil {
+		return fmt.Errorf("parent unit not found")
+	}
+	if parent.ProjectID != projectID {
+		return fmt.Errorf("parent unit belongs to another project")
+	}
+	return nil
+}
+
+func (cfg *apiConfig) handlerCreateProjectUnit(w http.ResponseWriter, r *http.Request) {
+	// Adds a season, reel or deliverable to a project, optionally under another unit
+	_, _, err := authenticateUser(r, cfg.secret)
+	if err != nil {
+		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+		return
+	}
+
+	projectID, err := uuid.Parse(r.PathValue("projectid"))
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+	_, err = cfg.db.GetProjectByID(r.Context(), projectID)
+	if err != nil {
+		respondWithError(w, "Project not found", http.StatusNotFound, err)
+		return
+	}
+
+	unitInput := unitInputType{}
+	decoder := json.NewDecoder(r.Body)
+	err = decoder.Decode(&unitInput)
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+
+	name := strings.TrimSpace(unitInput.Name)
+	if name == "" {
+		respondWithError(w, "Unit name required", http.StatusBadRequest, nil)
+		return
+	}
+	kind, err := strToUnitKind(unitInput.Kind)
+	if err != nil {
+		respondWithError(w, err.Error(), http.StatusBadRequest, err)
+		return
+	}
+	parentID, err := parseNullUUID(unitInput.ParentID)
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+	err = cfg.checkUnitParent(r.Context(), projectID, parentID)
+	if err != nil {
+		respondWithError(w, err.Error(), http.StatusBadRequest, err)
+		return
+	}
+	var sortOrder int32
+	if unitInput.SortOrder != nil {
+		sortOrder = *unitInput.SortOrder
+	}
+	details, err := parseUnitDetails(unitInput, unitDetails{})
+	if err != nil {
+		respondWithError(w, err.Error(), http.StatusBadRequest, err)
+		return
+	}
+	err = cfg.checkUnitNumber(r.Context(), projectID, uuid.Nil, kind, details.Number)
+	if errors.Is(err, errUnitNumberTaken) {
+		respondWithError(w, err.Error(), http.StatusConflict, err)
+		return
+	}
+	if err != nil {
+		respondWithError(w, err.Error(), http.StatusBadRequest, err)
+		return
+	}
+
+	createUnitParams := db.CreateProjectUnitParams{
+		ProjectID:    projectID,
+		ParentID:     parentID,
+		Kind:         kind,
+		Name:         name,
+		SortOrder:    sortOrder,
+		Number:       details.Number,
+		StartsOn:     details.StartsOn,
+		EndsOn:       details.EndsOn,
+		Budget:       details.Budget,
+		Currency:     details.Currency,
+		ExchangeRate: details.ExchangeRate,
+	}
+	unit, err := cfg.db.CreateProjectUnit(r.Context(), createUnitParams)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+
+	err = respondWithJSON(w, http.StatusCreated, unit)
+	if err != nil {
+		respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
+		return
+	}
+}
+
+func (cfg *apiConfig) handlerUpdateProjectUnit(w http.ResponseWriter, r *http.Request) {
+	// Renames, reorders, moves or renumbers a unit. Fields not given keep their
+	// values, an empty parent_id moves the unit to the top level
+	_, _, err := authenticateUser(r, cfg.secret)
+	if err != nil {
+		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+		return
+	}
+
+	unitID, err := uuid.Parse(r.PathValue("unitid"))
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+	unit, err := cfg.db.GetProjectUnitByID(r.Context(), unitID)
+	if err != nil {
+		respondWithError(w, "Unit not found", http.StatusNotFound, err)
+		return
+	}
+
+	unitInput := struct {
+		unitInputType
+		ParentID *string `json:"parent_id"`
+	}{}
+	decoder := json.NewDecoder(r.Body)
+	err = decoder.Decode(&unitInput)
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+
+	updateUnitParams := db.UpdateProjectUnitParams{
+		ID:        unit.ID,
+		ParentID:  unit.ParentID,
+		Kind:      unit.Kind,
+		Name:      unit.Name,
+		SortOrder: unit.SortOrder,
+	}
+	if name := strings.TrimSpace(unitInput.Name); name != "" {
+		updateUnitParams.Name = name
+	}
+	if unitInput.Kind != "" {
+		updateUnitParams.Kind, err = strToUnitKind(unitInput.Kind)
+		if err != nil {
+			respondWithError(w, err.Error(), http.StatusBadRequest, err)
+			return
+		}
+	}
+	if unitInput.SortOrder != nil {
+		updateUnitParams.SortOrder = *unitInput.SortOrder
+	}
+	if updateUnitParams.Kind != unit.Kind && unit.Kind == db.UnitKindSeason {
+		count, err := cfg.db.CountEpisodesForSeason(r.Context(), uuid.NullUUID{UUID: unit.ID, Valid: true})
+		if err != nil {
+			respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+			return
+		}
+		if count > 0 {
+			respondWithError(w, fmt.Sprintf("Season has %d episodes, it has to stay a season", count), http.StatusConflict, nil)
+			return
+		}
+	}
+	details := unitDetails{
+		Number:       unit.Number,
+		StartsOn:     unit.StartsOn,
+		EndsOn:       unit.EndsOn,
+		Budget:       unit.Budget,
+		Currency:     unit.Currency,
+		ExchangeRate: unit.ExchangeRate,
+	}
+	details, err = parseUnitDetails(unitInput.unitInputType, details)
+	if err != nil {
+		respondWithError(w, err.Error(), http.StatusBadRequest, err)
+		return
+	}
+	err = cfg.checkUnitNumber(r.Context(), unit.ProjectID, unit.ID, updateUnitParams.Kind, details.Number)
+	if errors.Is(err, errUnitNumberTaken) {
+		respondWithError(w, err.Error(), http.StatusConflict, err)
+		return
+	}
+	if err != nil {
+		respondWithError(w, err.Error(), http.StatusBadRequest, err)
+		return
+	}
+	updateUnitParams.Number = details.Number
+	updateUnitParams.StartsOn = details.StartsOn
+	updateUnitParams.EndsOn = details.EndsOn
+	updateUnitParams.Budget = details.Budget
+	updateUnitParams.Currency = details.Currency
+	updateUnitParams.ExchangeRate = details.ExchangeRate
+	if unitInput.ParentID != nil {
+		updateUnitParams.ParentID, err = parseNullUUID(*unitInput.ParentID)
+		if err != nil {
+			respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+			return
+		}
+		err = cfg.checkUnitParent(r.Context(), unit.ProjectID, updateUnitParams.ParentID)
+		if err != nil {
+			respondWithError(w, err.Error(), http.StatusBadRequest, err)
+			return
+		}
+		if updateUnitParams.ParentID.Valid {
+			units, err := cfg.db.GetUnitsForProject(r.Context(), unit.ProjectID)
+			if err != nil {
+				respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+				return
+			}
+			if unitParentCreatesCycle(units, unit.ID, updateUnitParams.ParentID.UUID) {
+				respondWithError(w, "A unit can't be moved under itself", http.StatusBadRequest, nil)
+				return
+			}
+		}
+	}
+
+	unit, err = cfg.db.UpdateProjectUnit(r.Context(), updateUnitParams)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+
+	err = respondWithJSON(w, http.StatusAccepted, unit)
+	if err != nil {
+		respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
+		return
+	}
+}
+
+func (cfg *apiConfig) handlerGetUnitsForProject(w http.ResponseWriter, r *http.Request) {
+	// Returns the units of a project as a flat list, parent_id links them
+	// into the hierarchy
+	_, _, err := authenticateUser(r, cfg.secret)
+	if err != nil {
+		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+		return
+	}
+
+	projectID, err := uuid.Parse(r.PathValue("projectid"))
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+	if !cfg.requireProjectVisible(w, r, projectID) {
+		return
+	}
+
+	list, err := cfg.db.GetUnitsForProject(r.Context(), projectID)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+
+	err = respondWithJSON(w, http.StatusOK, list)
+	if err != nil {
+		respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
+		return
+	}
+}
+
+func (cfg *apiConfig) handlerDeleteProjectUnit(w http.ResponseWriter, r *http.Request) {
+	// Deleting a unit deletes the units below it and the sessions logged for them.
+	// Seasons with episodes can't be deleted
+	_, _, err := authenticateUser(r, cfg.secret)
+	if err != nil {
+		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+		return
+	}
+
+	unitID, err := uuid.Parse(r.PathValue("unitid"))
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+	count, err := cfg.db.CountEpisodesForSeason(r.Context(), uuid.NullUUID{UUID: unitID, Valid: true})
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+	if count > 0 {
+		respondWithError(w, fmt.Sprintf("Season has %d episodes, move or delete them first", count), http.StatusConflict, nil)
+		return
+	}
+
+	unit, err := cfg.db.DeleteProjectUnit(r.Context(), unitID)
+	if err != nil {
+		respondWithError(w, "Unit not found", http.StatusNotFound, err)
+		return
+	}
+
+	err = respondWithJSON(w, http.StatusAccepted, unit)
+	if err != nil {
+		respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
+		return
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/Denisowiec/FoleyBookkeeper/internal/db"
+	"github.com/google/uuid"
+)
+
+func TestUnitParentCreatesCycle(t *testing.T) {
+	season := db.ProjectUnit{ID: uuid.New()}
+	reel := db.ProjectUnit{ID: uuid.New(), ParentID: uuid.NullUUID{UUID: season.ID, Valid: true}}
+	deliverable := db.ProjectUnit{ID: uuid.New(), ParentID: uuid.NullUUID{UUID: reel.ID, Valid: true}}
+	other := db.ProjectUnit{ID: uuid.New()}
+	units := []db.ProjectUnit{season, reel, deliverable, other}
+
+	if !unitParentCreatesCycle(units, season.ID, deliverable.ID) {
+		t.Error("moving a unit under its own descendant should be a cycle")
+	}
+	if !unitParentCreatesCycle(units, reel.ID, reel.ID) {
+		t.Error("a unit can't be its own parent")
+	}
+	if unitParentCreatesCycle(units, deliverable.ID, other.ID) {
+		t.Error("moving a unit under an unrelated one isn't a cycle")
+	}
+}
+
+func TestStrToProjectType(t *testing.T) {
+	projectType, err := strToProjectType(" Feature ")
+	if err != nil || projectType != db.ProjectTypeFeature {
+		t.Errorf("expected feature, got %s, %v", projectType, err)
+	}
+	_, err = strToProjectType("documentary")
+	if err == nil {
+		t.Error("expected an error for an unknown project type")
+	}
+}
+
+func TestParseUnitDetails(t *testing.T) {
+	number, starts, ends, budget, currency := int32(2), "2025-01-06", "2025-06-30", "12000.50", " eur "
+	input := unitInputType{Number: &number, StartsOn: &starts, EndsOn: &ends, Budget: &budget, Currency: &currency}
+	details, err := parseUnitDetails(input, unitDetails{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if details.Number.Int32 != 2 || !details.StartsOn.Valid || details.Budget.String != "12000.5" || details.Currency.String != "EUR" {
+		t.Errorf("details parsed incorrectly: %+v", details)
+	}
+
+	// Fields not given keep their values, empty ones are cleared
+	empty := ""
+	updated, err := parseUnitDetails(unitInputType{Budget: &empty}, details)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Budget.Valid || updated.Number != details.Number || updated.Currency != details.Currency {
+		t.Errorf("details updated incorrectly: %+v", updated)
+	}
+
+	_, err = parseUnitDetails(unitInputType{StartsOn: &ends, EndsOn: &starts}, unitDetails{})
+	if err == nil {
+		t.Error("expected an error for a unit ending before it starts")
+	}
+}
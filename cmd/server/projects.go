@@ -12,7 +12,8 @@ import (
 func (cfg *apiConfig) handlerCreateProject(w http.ResponseWriter, r *http.Request) {
 	// Function for handling requests to create projects
 	// Requires authentication
-	// Takes title (string) and client (string) as json input
+	// Takes title (string), client (string) and optionally the status and
+	// project type as json input. New projects are active series by default
 	_, _, err := authenticateUser(r, cfg.secret)
 	if err != nil {
 		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
@@ -20,8 +21,10 @@ func (cfg *apiConfig) handlerCreateProject(w http.ResponseWriter, r *http.Reques
 	}
 
 	type projectInputType struct {
-		Title    string `json:"title"`
-		ClientID string `json:"client_id"`
+		Title       string `json:"title"`
+		ClientID    string `json:"client_id"`
+		Status      string `json:"status"`
+		ProjectType string `json:"project_type"`
 	}
 
 	projectInput := projectInputType{}
@@ -38,9 +41,29 @@ func (cfg *apiConfig) handlerCreateProject(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	status := db.ProjectStatusActive
+	if projectInput.Status != "" {
+		status, err = strToProjectStatus(projectInput.Status)
+		if err != nil {
+			respondWithError(w, err.Error(), http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	projectType := db.ProjectTypeSeries
+	if projectInput.ProjectType != "" {
+		projectType, err = strToProjectType(projectInput.ProjectType)
+		if err != nil {
+			respondWithError(w, err.Error(), http.StatusBadRequest, err)
+			return
+		}
+	}
+
 	createProjectParams := db.CreateProjectParams{
-		Title:    projectInput.Title,
-		ClientID: clientID,
+		Title:       projectInput.Title,
+		ClientID:    clientID,
+		Status:      status,
+		ProjectType: projectType,
 	}
 
 	prj, err := cfg.db.CreateProject(r.Context(), createProjectParams)
@@ -57,7 +80,8 @@ func (cfg *apiConfig) handlerCreateProject(w http.ResponseWriter, r *http.Reques
 
 func (cfg *apiConfig) handlerGetProjectByTitle(w http.ResponseWriter, r *http.Request) {
 	// This function returns a single project referenced by title provided in JSON input
-	// If no title is provided, it returns all projects
+	// If no title is provided, it returns all projects but the archived ones.
+	// The list can be filtered with ?status=active,on_hold or ?status=all
 	_, _, err := authenticateUser(r, cfg.secret)
 	if err != nil {
 		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
@@ -75,12 +99,22 @@ func (cfg *apiConfig) handlerGetProjectByTitle(w http.ResponseWriter, r *http.Re
 	switch {
 	case err == io.EOF:
 		// If the body is empty we return a list of all projects
+		statuses, err := parseProjectStatusFilter(r.URL.Query().Get("status"))
+		if err != nil {
+			respondWithError(w, err.Error(), http.StatusBadRequest, err)
+			return
+		}
 		list, err := cfg.db.GetAllProjects(r.Context())
 		if err != nil {
 			respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
 			return
 		}
-		err = respondWithJSON(w, http.StatusOK, list)
+		access, err := cfg.requestProjectAccess(r)
+		if err != nil {
+			respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+			return
+		}
+		err = respondWithJSON(w, http.StatusOK, filterProjects(access.visibleProjects(list), statuses))
 		if err != nil {
 			respondWithError(w, "Unable to process response data", http.StatusInternalServerError, err)
 			return
@@ -95,6 +129,9 @@ func (cfg *apiConfig) handlerGetProjectByTitle(w http.ResponseWriter, r *http.Re
 			respondWithError(w, "Project not found", http.StatusNotFound, err)
 			return
 		}
+		if !cfg.requireProjectVisible(w, r, prj.ID) {
+			return
+		}
 		err = respondWithJSON(w, http.StatusOK, prj)
 		if err != nil {
 			respondWithError(w, "Unable to process response data", http.StatusInternalServerError, err)
@@ -124,6 +161,9 @@ func (cfg *apiConfig) handlerGetProjectByID(w http.ResponseWriter, r *http.Reque
 		respondWithError(w, "Project not found", http.StatusNotFound, err)
 		return
 	}
+	if !cfg.requireProjectVisible(w, r, prj.ID) {
+		return
+	}
 
 	err = respondWithJSON(w, http.StatusOK, prj)
 	if err != nil {
@@ -135,7 +175,7 @@ func (cfg *apiConfig) handlerGetProjectByID(w http.ResponseWriter, r *http.Reque
 func (cfg *apiConfig) handlerUpdateProject(w http.ResponseWriter, r *http.Request) {
 	// This handler requests for modifying project data
 	// Requires authentification
-	// Takes title (string) and client (string) as input
+	// Takes title (string), client (string) and optionally the project type as input
 	_, _, err := authenticateUser(r, cfg.secret)
 	if err != nil {
 		respondWithError(w, "Operation unauthrized", http.StatusUnauthorized, err)
@@ -143,9 +183,10 @@ func (cfg *apiConfig) handlerUpdateProject(w http.ResponseWriter, r *http.Reques
 	}
 
 	type projectInputType struct {
-		ID       uuid.UUID `json:"id"`
-		Title    string    `json:"title"`
-		ClientID string    `json:"client_id"`
+		ID          uuid.UUID `json:"id"`
+		Title       string    `json:"title"`
+		ClientID    string    `json:"client_id"`
+		ProjectType string    `json:"project_type"`
 	}
 	projectInput := projectInputType{}
 
@@ -161,10 +202,24 @@ func (cfg *apiConfig) handlerUpdateProject(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	previous, err := cfg.db.GetProjectByID(r.Context(), projectInput.ID)
+	if err != nil {
+		respondWithError(w, "Project not found", http.StatusNotFound, err)
+		return
+	}
+
 	updateProjectParams := db.UpdateProjectParams{
-		ID:       projectInput.ID,
-		Title:    projectInput.Title,
-		ClientID: clientID,
+		ID:          projectInput.ID,
+		Title:       projectInput.Title,
+		ClientID:    clientID,
+		ProjectType: previous.ProjectType,
+	}
+	if projectInput.ProjectType != "" {
+		updateProjectParams.ProjectType, err = strToProjectType(projectInput.ProjectType)
+		if err != nil {
+			respondWithError(w, err.Error(), http.StatusBadRequest, err)
+			return
+		}
 	}
 
 	prj, err := cfg.db.UpdateProject(r.Context(), updateProjectParams)
@@ -181,6 +236,7 @@ func (cfg *apiConfig) handlerUpdateProject(w http.ResponseWriter, r *http.Reques
 }
 
 func (cfg *apiConfig) handlerDeleteProject(w http.ResponseWriter, r *http.Request) {
+	// Moves a project to the trash with its episodes, sessions and calculations
 	_, _, err := authenticateUser(r, cfg.secret)
 	if err != nil {
 		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
@@ -193,11 +249,29 @@ func (cfg *apiConfig) handlerDeleteProject(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	prj, err := cfg.db.DeleteProject(r.Context(), projectID)
+	tx, err := cfg.conn.BeginTx(r.Context(), nil)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+	defer tx.Rollback()
+	qtx := cfg.db.WithTx(tx)
+
+	prj, err := qtx.SoftDeleteProject(r.Context(), projectID)
 	if err != nil {
 		respondWithError(w, "Project not found", http.StatusNotFound, err)
 		return
 	}
+	err = softDeleteProjectContents(r.Context(), qtx, projectID)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+	err = tx.Commit()
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
 	err = respondWithJSON(w, http.StatusAccepted, prj)
 	if err != nil {
 		respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/Denisowiec/FoleyBookkeeper/internal/auth"
+	"github.com/Denisowiec/FoleyBookkeeper/internal/db"
+)
+
+var (
+	errRefTokenExpired = errors.New("refresh token expired")
+	errRefTokenRevoked = errors.New("refresh token revoked")
+	errRefTokenReused  = errors.New("refresh token was already used")
+)
+
+const maxDeviceLabelLength = 100
+
+func requestIP(r *http.Request) string {
+	// The address the request came from. Behind a proxy that's the proxy
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func checkRefToken(token db.RefreshToken, now time.Time) error {
+	// A revoked token that was rotated into another one should never be
+	// seen again. If it is, someone else has a copy of it
+	switch {
+	case token.RevokedAt.Valid && token.ReplacedBy.Valid:
+		return errRefTokenReused
+	case token.RevokedAt.Valid:
+		return errRefTokenRevoked
+	case !now.Before(token.ExpiresAt):
+		return errRefTokenExpired
+	}
+	return nil
+}
+
+func readRefToken(r *http.Request) (string, error) {
+	// The refresh token comes in the body, or as the bearer token for
+	// clients that send it the way they send JWTs
+	input := struct {
+		RefreshToken string `json:"refresh_token"`
+	}{}
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&input)
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	if input.RefreshToken != "" {
+		return input.RefreshToken, nil
+	}
+	return auth.GetBearerToken(r.Header)
+}
+
+func (cfg *apiConfig) handlerRefreshToken(w http.ResponseWriter, r *http.Request) {
+	// Trades a refresh token for a new JWT and a new refresh token. The old
+	// token is revoked, so each one works once
+	inputToken, err := readRefToken(r)
+	if err != nil {
+		respondWithError(w, "Refresh token required", http.StatusUnauthorized, err)
+		return
+	}
+
+	tx, err := cfg.conn.BeginTx(r.Context(), nil)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+	defer tx.Rollback()
+	qtx := cfg.db.WithTx(tx)
+
+	oldToken, err := qtx.GetRefToken(r.Context(), inputToken)
+	if err != nil {
+		respondWithError(w, "Token invalid", http.StatusUnauthorized, err)
+		return
+	}
+	err = checkRefToken(oldToken, time.Now())
+	if err != nil && !errors.Is(err, errRefTokenReused) {
+		respondWithError(w, "Token invalid", http.StatusUnauthorized, err)
+		return
+	}
+
+	newToken := auth.MakeRefreshToken()
+	if err == nil {
+		rotateParams := db.RotateRefTokenParams{
+			Token:      inputToken,
+			ReplacedBy: sql.NullString{String: newToken, Valid: true},
+		}
+		_, err = qtx.RotateRefToken(r.Context(), rotateParams)
+		// Another request rotated it in the meantime, so it was used twice
+		if errors.Is(err, sql.ErrNoRows) {
+			err = errRefTokenReused
+		}
+	}
+	if errors.Is(err, errRefTokenReused) {
+		revoked, err := qtx.RevokeTokenFamily(r.Context(), oldToken.FamilyID)
+		if err == nil {
+			err = tx.Commit()
+		}
+		if err != nil {
+			respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+			return
+		}
+		log.Printf("Refresh token of user %s reused, revoked %d tokens of its login\n", oldToken.UserID, len(revoked))
+		respondWithError(w, "Refresh token was already used, log in again", http.StatusUnauthorized, errRefTokenReused)
+		return
+	}
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+
+	setRefTokenParams := db.SetRefTokenParams{
+		Token:     newToken,
+		UserID:    oldToken.UserID,
+		ExpiresAt: time.Now().Add(cfg.refTokenExpirationTime),
+		FamilyID:  oldToken.FamilyID,
+		// The label stays, the rest is from where the login was used last
+		DeviceLabel: oldToken.DeviceLabel,
+		UserAgent:   nullString(r.UserAgent()),
+		IpAddress:   nullString(requestIP(r)),
+	}
+	_, err = qtx.SetRefToken(r.Context(), setRefTokenParams)
+	if err != nil {
+		respondWithError(w, "Error creating a refresh token", http.StatusInternalServerError, err)
+		return
+	}
+	err = tx.Commit()
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+
+	jwt, err := auth.MakeJWT(oldToken.UserID, cfg.secret, cfg.jwtExpirationTime)
+	if err != nil {
+		respondWithError(w, "Error generating a JWT", http.StatusInternalServerError, err)
+		return
+	}
+
+	respBody := struct {
+		JWT          string `json:"jwt"`
+		RefreshToken string `json:"refresh_token"`
+	}{
+		JWT:          jwt,
+		RefreshToken: newToken,
+	}
+	err = respondWithJSON(w, http.StatusOK, respBody)
+	if err != nil {
+		respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
+		return
+	}
+}
+
+func (cfg *apiConfig) handlerLogout(w http.ResponseWriter, r *http.Request) {
+	// Ends the login the refresh token belongs to. JWTs already handed out
+	// stay valid until they expire, which is why they're short-lived
+	inputToken, err := readRefToken(r)
+	if err != nil {
+		respondWithError(w, "Refresh token required", http.StatusUnauthorized, err)
+		return
+	}
+
+	token, err := cfg.db.GetRefToken(r.Context(), inputToken)
+	if err != nil {
+		respondWithError(w, "Token invalid", http.StatusUnauthorized, err)
+		return
+	}
+	revoked, err := cfg.db.RevokeTokenFamily(r.Context(), token.FamilyID)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+
+	resp := struct {
+		Revoked int `json:"revoked"`
+	}{
+		Revoked: len(revoked),
+	}
+	err = respondWithJSON(w, http.StatusAccepted, resp)
+	if err != nil {
+		respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
+		return
+	}
+}
+
+func (cfg *apiConfig) handlerLogoutEverywhere(w http.ResponseWriter, r *http.Request) {
+	// Revokes every refresh token of the user, on all devices
+	userID, _, err := authenticateUser(r, cfg.secret)
+	if err != nil {
+		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+		return
+	}
+
+	revoked, err := cfg.db.RevokeUserTokens(r.Context(), userID)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+
+	resp := struct {
+		Revoked int `json:"revoked"`
+	}{
+		Revoked: len(revoked),
+	}
+	err = respondWithJSON(w, http.StatusAccepted, resp)
+	if err != nil {
+		respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
+		return
+	}
+}
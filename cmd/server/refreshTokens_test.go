@@ -0,0 +1,53 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Denisowiec/FoleyBookkeeper/internal/db"
+)
+
+func TestCheckRefToken(t *testing.T) {
+	now := time.Now()
+	revoked := sql.NullTime{Time: now.Add(-time.Hour), Valid: true}
+	rotated := sql.NullString{String: "next", Valid: true}
+
+	cases := map[string]struct {
+		token    db.RefreshToken
+		expected error
+	}{
+		"valid":   {db.RefreshToken{ExpiresAt: now.Add(time.Hour)}, nil},
+		"expired": {db.RefreshToken{ExpiresAt: now.Add(-time.Minute)}, errRefTokenExpired},
+		"revoked": {db.RefreshToken{ExpiresAt: now.Add(time.Hour), RevokedAt: revoked}, errRefTokenRevoked},
+		"reused":  {db.RefreshToken{ExpiresAt: now.Add(time.Hour), RevokedAt: revoked, ReplacedBy: rotated}, errRefTokenReused},
+		// Reuse is reported even after the token expired
+		"reused late": {db.RefreshToken{ExpiresAt: now.Add(-time.Hour), RevokedAt: revoked, ReplacedBy: rotated}, errRefTokenReused},
+	}
+	for name, c := range cases {
+		if err := checkRefToken(c.token, now); !errors.Is(err, c.expected) {
+			t.Errorf("%s: got %v, expected %v", name, err, c.expected)
+		}
+	}
+}
+
+func TestReadRefToken(t *testing.T) {
+	r := httptest.NewRequest("POST", "/api/refresh", strings.NewReader(`{"refresh_token": "abc"}`))
+	if token, err := readRefToken(r); err != nil || token != "abc" {
+		t.Errorf("token in the body: got %q, %v", token, err)
+	}
+
+	r = httptest.NewRequest("POST", "/api/refresh", nil)
+	r.Header.Set("Authorization", "Bearer def")
+	if token, err := readRefToken(r); err != nil || token != "def" {
+		t.Errorf("token in the header: got %q, %v", token, err)
+	}
+
+	r = httptest.NewRequest("POST", "/api/refresh", nil)
+	if _, err := readRefToken(r); err == nil {
+		t.Error("a request without a token should be refused")
+	}
+}
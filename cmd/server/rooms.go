@@ -0,0 +1,202 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/Denisowiec/FoleyBookkeeper/internal/db"
+	"github.com/google/uuid"
+)
+
+func (cfg *apiConfig) handlerCreateRoom(w http.ResponseWriter, r *http.Request) {
+	// Function for handling requests to create studio rooms
+	// Requires authentication
+	// Takes a name for the room (string) and optionally some notes
+	_, _, err := authenticateUser(r, cfg.secret)
+	if err != nil {
+		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+		return
+	}
+
+	type roomInputType struct {
+		RoomName string `json:"room_name"`
+		Notes    string `json:"notes"`
+	}
+
+	roomInput := roomInputType{}
+	decoder := json.NewDecoder(r.Body)
+
+	err = decoder.Decode(&roomInput)
+	if err != nil {
+		respondWithError(w, "Error decoding user request", http.StatusBadRequest, err)
+		return
+	}
+	if roomInput.RoomName == "" {
+		respondWithError(w, "Room name required", http.StatusBadRequest, nil)
+		return
+	}
+
+	createRoomParams := db.CreateRoomParams{
+		RoomName: roomInput.RoomName,
+		Notes:    sql.NullString{String: roomInput.Notes, Valid: roomInput.Notes != ""},
+	}
+
+	room, err := cfg.db.CreateRoom(r.Context(), createRoomParams)
+	if err != nil {
+		respondWithError(w, "Error creating room", http.StatusInternalServerError, err)
+		return
+	}
+	err = respondWithJSON(w, http.StatusCreated, room)
+	if err != nil {
+		respondWithError(w, "Error processing return data", http.StatusInternalServerError, err)
+		return
+	}
+}
+
+func (cfg *apiConfig) handlerGetRoomByID(w http.ResponseWriter, r *http.Request) {
+	_, _, err := authenticateUser(r, cfg.secret)
+	if err != nil {
+		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+		return
+	}
+
+	roomID, err := uuid.Parse(r.PathValue("roomid"))
+	if err != nil {
+		respondWithError(w, "Error parsing request", http.StatusBadRequest, err)
+		return
+	}
+
+	room, err := cfg.db.GetRoomByID(r.Context(), roomID)
+	if err != nil {
+		respondWithError(w, "Room not found", http.StatusNotFound, err)
+		return
+	}
+
+	err = respondWithJSON(w, http.StatusOK, room)
+	if err != nil {
+		respondWithError(w, "Unable to process response data", http.StatusInternalServerError, err)
+		return
+	}
+}
+
+func (cfg *apiConfig) handlerGetRoomByName(w http.ResponseWriter, r *http.Request) {
+	// This returns room info by it's name given in the json input
+	// If no name is provided, it returns a list of all rooms
+	_, _, err := authenticateUser(r, cfg.secret)
+	if err != nil {
+		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+		return
+	}
+
+	type roomInputType struct {
+		RoomName string `json:"room_name"`
+	}
+	roomInput := roomInputType{}
+
+	decoder := json.NewDecoder(r.Body)
+
+	err = decoder.Decode(&roomInput)
+
+	switch {
+	case err == io.EOF:
+		// If the body is empty we return a list of all rooms
+		list, err := cfg.db.GetAllRooms(r.Context())
+		if err != nil {
+			respondWithError(w, "Error contacting the database", http.StatusInternalServerError, err)
+			return
+		}
+		err = respondWithJSON(w, http.StatusOK, list)
+		if err != nil {
+			respondWithError(w, "Error processing data", http.StatusInternalServerError, err)
+			return
+		}
+	case err != nil:
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	default:
+		room, err := cfg.db.GetRoomByName(r.Context(), roomInput.RoomName)
+		if err != nil {
+			respondWithError(w, "Room not found", http.StatusNotFound, err)
+			return
+		}
+		err = respondWithJSON(w, http.StatusOK, room)
+		if err != nil {
+			respondWithError(w, "Error processing data", http.StatusInternalServerError, err)
+			return
+		}
+	}
+}
+
+func (cfg *apiConfig) handlerUpdateRoom(w http.ResponseWriter, r *http.Request) {
+	_, _, err := authenticateUser(r, cfg.secret)
+	if err != nil {
+		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+		return
+	}
+
+	roomID, err := uuid.Parse(r.PathValue("roomid"))
+	if err != nil {
+		respondWithError(w, "Error processing user request", http.StatusBadRequest, err)
+		return
+	}
+
+	type roomInputType struct {
+		RoomName string `json:"room_name"`
+		Notes    string `json:"notes"`
+	}
+
+	roomInput := roomInputType{}
+	decoder := json.NewDecoder(r.Body)
+
+	err = decoder.Decode(&roomInput)
+	if err != nil {
+		respondWithError(w, "Error decoding user request", http.StatusBadRequest, err)
+		return
+	}
+
+	updateRoomParams := db.UpdateRoomParams{
+		ID:       roomID,
+		RoomName: roomInput.RoomName,
+		Notes:    sql.NullString{String: roomInput.Notes, Valid: roomInput.Notes != ""},
+	}
+
+	room, err := cfg.db.UpdateRoom(r.Context(), updateRoomParams)
+	if err != nil {
+		respondWithError(w, "Error updating room", http.StatusInternalServerError, err)
+		return
+	}
+
+	err = respondWithJSON(w, http.StatusAccepted, room)
+	if err != nil {
+		respondWithError(w, "Error processing response data", http.StatusInternalServerError, err)
+		return
+	}
+}
+
+func (cfg *apiConfig) handlerDeleteRoom(w http.ResponseWriter, r *http.Request) {
+	_, _, err := authenticateUser(r, cfg.secret)
+	if err != nil {
+		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+		return
+	}
+
+	roomID, err := uuid.Parse(r.PathValue("roomid"))
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+
+	room, err := cfg.db.DeleteRoom(r.Context(), roomID)
+	if err != nil {
+		respondWithError(w, "Room not found", http.StatusNotFound, err)
+		return
+	}
+
+	err = respondWithJSON(w, http.StatusAccepted, room)
+	if err != nil {
+		respondWithError(w, "Error processing response data", http.StatusInternalServerError, err)
+		return
+	}
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/Denisowiec/FoleyBookkeeper/internal/db"
+	"github.com/Denisowiec/FoleyBookkeeper/internal/episodecode"
+	"github.com/google/uuid"
+)
+
+func pickEpisode(candidates []db.Episode, number int) (db.Episode, error) {
+	// Candidates share an episode number. A plain number means the episode
+	// without a season, or the only episode with that number if all have one
+	for _, ep := range candidates {
+		if !ep.SeasonID.Valid {
+			return ep, nil
+		}
+	}
+	switch len(candidates) {
+	case 0:
+		return db.Episode{}, fmt.Errorf("episode %d not found", number)
+	case 1:
+		return candidates[0], nil
+	}
+	return db.Episode{}, fmt.Errorf("episode %d is in several seasons, give the season too, e.g. S02E%02d", number, number)
+}
+
+// projectEpisodes looks episodes of a project up by their codes without
+// going back to the database, e.g. for imports
+type projectEpisodes struct {
+	list []db.Episode
+	// Season numbers by the IDs of their units
+	seasons map[uuid.UUID]int
+}
+
+func (cfg *apiConfig) getProjectEpisodes(ctx context.Context, projectID uuid.UUID) (projectEpisodes, error) {
+	list, err := cfg.db.GetAllEpisodesForProject(ctx, projectID)
+	if err != nil {
+		return projectEpisodes{}, err
+	}
+	units, err := cfg.db.GetUnitsForProject(ctx, projectID)
+	if err != nil {
+		return projectEpisodes{}, err
+	}
+	episodes := projectEpisodes{list: list, seasons: map[uuid.UUID]int{}}
+	for _, u := range units {
+		if u.Kind == db.UnitKindSeason && u.Number.Valid {
+			episodes.seasons[u.ID] = int(u.Number.Int32)
+		}
+	}
+	return episodes, nil
+}
+
+func (p projectEpisodes) code(ep db.Episode) episodecode.Code {
+	return p.seasonCode(ep.SeasonID, ep.EpisodeNumber)
+}
+
+func (p projectEpisodes) seasonCode(seasonID uuid.NullUUID, number int32) episodecode.Code {
+	return episodecode.Code{Season: p.seasons[seasonID.UUID], Episode: int(number)}
+}
+
+func (p projectEpisodes) find(code episodecode.Code) (db.Episode, error) {
+	candidates := []db.Episode{}
+	for _, ep := range p.list {
+		if int(ep.EpisodeNumber) != code.Episode {
+			continue
+		}
+		if code.Season != 0 && p.code(ep) == code {
+			return ep, nil
+		}
+		candidates = append(candidates, ep)
+	}
+	if code.Season != 0 {
+		return db.Episode{}, fmt.Errorf("episode %s not found", code)
+	}
+	return pickEpisode(candidates, code.Episode)
+}
+
+func (cfg *apiConfig) getSeason(ctx context.Context, projectID uuid.UUID, number int) (db.ProjectUnit, error) {
+	getSeasonParams := db.GetUnitByNumberParams{
+		ProjectID: projectID,
+		Kind:      db.UnitKindSeason,
+		Number:    sql.NullInt32{Int32: int32(number), Valid: true},
+	}
+	season, err := cfg.db.GetUnitByNumber(ctx, getSeasonParams)
+	if errors.Is(err, sql.ErrNoRows) {
+		return db.ProjectUnit{}, fmt.Errorf("season %d not found", number)
+	}
+	return season, err
+}
+
+func (cfg *apiConfig) getEpisodeByCode(ctx context.Context, projectID uuid.UUID, code episodecode.Code) (db.Episode, error) {
+	if code.Season != 0 {
+		season, err := cfg.getSeason(ctx, projectID, code.Season)
+		if err != nil {
+			return db.Episode{}, err
+		}
+		getEpisodeParams := db.GetEpisodeInSeasonParams{
+			SeasonID:      season.ID,
+			EpisodeNumber: int32(code.Episode),
+		}
+		ep, err := cfg.db.GetEpisodeInSeason(ctx, getEpisodeParams)
+		if errors.Is(err, sql.ErrNoRows) {
+			return db.Episode{}, fmt.Errorf("episode %s not found", code)
+		}
+		return ep, err
+	}
+
+	getEpisodesParams := db.GetEpisodesByNumberParams{
+		ProjectID:     projectID,
+		EpisodeNumber: int32(code.Episode),
+	}
+	candidates, err := cfg.db.GetEpisodesByNumber(ctx, getEpisodesParams)
+	if err != nil {
+		return db.Episode{}, err
+	}
+	return pickEpisode(candidates, code.Episode)
+}
+
+func (cfg *apiConfig) checkEpisodeSeason(ctx context.Context, projectID uuid.UUID, seasonID uuid.NullUUID) error {
+	// Seasons have to be season units of the episode's project
+	if !seasonID.Valid {
+		return nil
+	}
+	season, err := cfg.db.GetProjectUnitByID(ctx, seasonID.UUID)
+	if err != nil || season.ProjectID != projectID {
+		return fmt.Errorf("season not found in the episode's project")
+	}
+	if season.Kind != db.UnitKindSeason {
+		return fmt.Errorf("unit %s isn't a season", season.Name)
+	}
+	return nil
+}
+
+func (cfg *apiConfig) episodeCode(ctx context.Context, ep db.Episode) episodecode.Code {
+	// Episodes whose season can't be read are shown without it
+	code := episodecode.Code{Episode: int(ep.EpisodeNumber)}
+	if ep.SeasonID.Valid {
+		season, err := cfg.db.GetProjectUnitByID(ctx, ep.SeasonID.UUID)
+		if err == nil && season.Number.Valid {
+			code.Season = int(season.Number.Int32)
+		}
+	}
+	return code
+}
+
+func (cfg *apiConfig) handlerGetEpisodeByCode(w http.ResponseWriter, r *http.Request) {
+	// Returns an episode of a project by its code, e.g. S02E05, E05 or 5
+	_, _, err := authenticateUser(r, cfg.secret)
+	if err != nil {
+		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+		return
+	}
+
+	projectID, err := uuid.Parse(r.PathValue("projectid"))
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+	if !cfg.requireProjectVisible(w, r, projectID) {
+		return
+	}
+	code, err := episodecode.Parse(r.PathValue("code"))
+	if err != nil {
+		respondWithError(w, err.Error(), http.StatusBadRequest, err)
+		return
+	}
+
+	ep, err := cfg.getEpisodeByCode(r.Context(), projectID, code)
+	if err != nil {
+		respondWithError(w, err.Error(), http.StatusNotFound, err)
+		return
+	}
+
+	err = respondWithJSON(w, http.StatusOK, ep)
+	if err != nil {
+		respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
+		return
+	}
+}
+
+func (cfg *apiConfig) handlerGetSeason(w http.ResponseWriter, r *http.Request) {
+	// Returns a season of a project, e.g. S02 or 2, with its episodes
+	_, _, err := authenticateUser(r, cfg.secret)
+	if err != nil {
+		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+		return
+	}
+
+	projectID, err := uuid.Parse(r.PathValue("projectid"))
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+	if !cfg.requireProjectVisible(w, r, projectID) {
+		return
+	}
+	number, err := episodecode.ParseSeason(r.PathValue("season"))
+	if err != nil {
+		respondWithError(w, err.Error(), http.StatusBadRequest, err)
+		return
+	}
+
+	season, err := cfg.getSeason(r.Context(), projectID, number)
+	if err != nil {
+		respondWithError(w, err.Error(), http.StatusNotFound, err)
+		return
+	}
+	episodes, err := cfg.db.GetEpisodesForSeason(r.Context(), season.ID)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+
+	ret := struct {
+		db.ProjectUnit
+		Episodes []db.Episode `json:"episodes"`
+	}{
+		ProjectUnit: season,
+		Episodes:    episodes,
+	}
+	err = respondWithJSON(w, http.StatusOK, ret)
+	if err != nil {
+		respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
+		return
+	}
+}
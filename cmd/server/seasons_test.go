@@ -0,0 +1,45 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/Denisowiec/FoleyBookkeeper/internal/db"
+	"github.com/Denisowiec/FoleyBookkeeper/internal/episodecode"
+	"github.com/google/uuid"
+)
+
+func TestProjectEpisodesFind(t *testing.T) {
+	season1, season2 := uuid.New(), uuid.New()
+	episodes := projectEpisodes{
+		list: []db.Episode{
+			{ID: uuid.New(), EpisodeNumber: 5, SeasonID: uuid.NullUUID{UUID: season1, Valid: true}},
+			{ID: uuid.New(), EpisodeNumber: 5, SeasonID: uuid.NullUUID{UUID: season2, Valid: true}},
+			{ID: uuid.New(), EpisodeNumber: 6, SeasonID: uuid.NullUUID{UUID: season2, Valid: true}},
+		},
+		seasons: map[uuid.UUID]int{season1: 1, season2: 2},
+	}
+
+	ep, err := episodes.find(episodecode.Code{Season: 2, Episode: 5})
+	if err != nil || ep.ID != episodes.list[1].ID {
+		t.Errorf("S02E05 found the wrong episode: %+v, %v", ep, err)
+	}
+	ep, err = episodes.find(episodecode.Code{Episode: 6})
+	if err != nil || ep.ID != episodes.list[2].ID {
+		t.Errorf("a number used in one season only should find its episode: %+v, %v", ep, err)
+	}
+	_, err = episodes.find(episodecode.Code{Episode: 5})
+	if err == nil {
+		t.Error("a number used in several seasons should be ambiguous")
+	}
+	_, err = episodes.find(episodecode.Code{Season: 1, Episode: 6})
+	if err == nil {
+		t.Error("S01E06 doesn't exist")
+	}
+
+	// Episodes without a season win over the numbers in seasons
+	episodes.list = append(episodes.list, db.Episode{ID: uuid.New(), EpisodeNumber: 5})
+	ep, err = episodes.find(episodecode.Code{Episode: 5})
+	if err != nil || ep.ID != episodes.list[3].ID {
+		t.Errorf("expected the episode without a season: %+v, %v", ep, err)
+	}
+}
@@ -0,0 +1,360 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Denisowiec/FoleyBookkeeper/internal/db"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const (
+	defaultSessionPageSize = 50
+	maxSessionPageSize     = 500
+)
+
+// Columns of db.Session, in the order they get scanned
+const sessionColumns = `sessions.id, sessions.session_date, sessions.created_at, sessions.updated_at,
+sessions.episode_id, sessions.project_id, sessions.duration, sessions.part_worked_on,
+sessions.activity_done, sessions.external_uid, sessions.start_time, sessions.status, sessions.timesheet_id,
+sessions.unit_id, sessions.deleted_at`
+
+// How the creation time of the last row is stored in the cursor
+const sessionCursorTimeLayout = "2006-01-02 15:04:05.999999"
+
+// Sessions can only be sorted by these. The cast is used for the cursor value
+var sessionSortColumns = map[string]struct {
+	column string
+	cast   string
+}{
+	"date":     {"sessions.session_date", "date"},
+	"duration": {"sessions.duration", "integer"},
+	"created":  {"sessions.created_at", "timestamp"},
+}
+
+type sessionFilter struct {
+	From       string
+	To         string
+	UserIDs    []uuid.UUID
+	ProjectIDs []uuid.UUID
+	EpisodeIDs []uuid.UUID
+	UnitIDs    []uuid.UUID
+	ClientIDs  []uuid.UUID
+	Parts      []string
+	Activities []string
+	Statuses   []string
+	// Set for users who only see the projects they're members of
+	MemberID uuid.NullUUID
+	// Sort is a column name, prefixed with - for descending order
+	Sort   string
+	Limit  int
+	Cursor *sessionCursor
+}
+
+// The cursor remembers where the previous page ended
+type sessionCursor struct {
+	Sort  string    `json:"s"`
+	Value string    `json:"v"`
+	ID    uuid.UUID `json:"id"`
+}
+
+func (c sessionCursor) encode() string {
+	dat, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(dat)
+}
+
+func decodeSessionCursor(s string) (*sessionCursor, error) {
+	dat, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	cursor := sessionCursor{}
+	err = json.Unmarshal(dat, &cursor)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	return &cursor, nil
+}
+
+func parseUUIDList(values []string) ([]uuid.UUID, error) {
+	ids := []uuid.UUID{}
+	for _, v := range values {
+		// Lists can come as repeated parameters or comma separated
+		for _, part := range strings.Split(v, ",") {
+			id, err := uuid.Parse(strings.TrimSpace(part))
+			if err != nil {
+				return nil, err
+			}
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func parseStringList(values []string) []string {
+	list := []string{}
+	for _, v := range values {
+		for _, part := range strings.Split(v, ",") {
+			if part = strings.ToLower(strings.TrimSpace(part)); part != "" {
+				list = append(list, part)
+			}
+		}
+	}
+	return list
+}
+
+func parseSessionFilter(query url.Values) (sessionFilter, error) {
+	// Reads the filters from the query string, e.g.
+	// ?from=2024-03-01&to=2024-03-31&user=<id>&part=footsteps,props&sort=-duration
+	filter := sessionFilter{
+		From:       query.Get("from"),
+		To:         query.Get("to"),
+		Parts:      parseStringList(query["part"]),
+		Activities: parseStringList(query["activity"]),
+		Statuses:   parseStringList(query["status"]),
+		Sort:       query.Get("sort"),
+		Limit:      defaultSessionPageSize,
+	}
+	var err error
+
+	for _, date := range []string{filter.From, filter.To} {
+		if date == "" {
+			continue
+		}
+		_, err = time.Parse(time.DateOnly, date)
+		if err != nil {
+			return sessionFilter{}, err
+		}
+	}
+
+	if filter.UserIDs, err = parseUUIDList(query["user"]); err != nil {
+		return sessionFilter{}, err
+	}
+	if filter.ProjectIDs, err = parseUUIDList(query["project"]); err != nil {
+		return sessionFilter{}, err
+	}
+	if filter.EpisodeIDs, err = parseUUIDList(query["episode"]); err != nil {
+		return sessionFilter{}, err
+	}
+	if filter.UnitIDs, err = parseUUIDList(query["unit"]); err != nil {
+		return sessionFilter{}, err
+	}
+	if filter.ClientIDs, err = parseUUIDList(query["client"]); err != nil {
+		return sessionFilter{}, err
+	}
+
+	if filter.Sort == "" {
+		filter.Sort = "-date"
+	}
+	if _, ok := sessionSortColumns[strings.TrimPrefix(filter.Sort, "-")]; !ok {
+		return sessionFilter{}, fmt.Errorf("sessions can't be sorted by %s", filter.Sort)
+	}
+
+	if limit := query.Get("limit"); limit != "" {
+		filter.Limit, err = strconv.Atoi(limit)
+		if err != nil || filter.Limit <= 0 {
+			return sessionFilter{}, fmt.Errorf("invalid limit %s", limit)
+		}
+		if filter.Limit > maxSessionPageSize {
+			filter.Limit = maxSessionPageSize
+		}
+	}
+
+	if cursor := query.Get("cursor"); cursor != "" {
+		filter.Cursor, err = decodeSessionCursor(cursor)
+		if err != nil {
+			return sessionFilter{}, err
+		}
+		if filter.Cursor.Sort != filter.Sort {
+			return sessionFilter{}, fmt.Errorf("cursor belongs to a different sort order")
+		}
+		// The value goes to the database cast to the column's type, so a
+		// tampered one has to be caught here
+		if err = checkSessionSortValue(filter.Cursor.Value, filter.Sort); err != nil {
+			return sessionFilter{}, fmt.Errorf("invalid cursor")
+		}
+	}
+
+	return filter, nil
+}
+
+// sessionQuery collects the conditions of the WHERE clause. Conditions are
+// always constants from this file, user input only ever goes in as arguments
+type sessionQuery struct {
+	conditions []string
+	args       []any
+}
+
+func (q *sessionQuery) where(condition string, args ...any) {
+	// Each %s in the condition gets the placeholder of the matching argument
+	placeholders := []any{}
+	for _, arg := range args {
+		q.args = append(q.args, arg)
+		placeholders = append(placeholders, fmt.Sprintf("$%d", len(q.args)))
+	}
+	q.conditions = append(q.conditions, fmt.Sprintf(condition, placeholders...))
+}
+
+func (q *sessionQuery) whereClause() string {
+	if len(q.conditions) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(q.conditions, " AND ")
+}
+
+func buildSessionQueries(filter sessionFilter) (string, []any, string, []any) {
+	// Returns the query for the page and the query counting all matches
+	q := sessionQuery{}
+	// Sessions in the trash never show up
+	q.where("sessions.deleted_at IS NULL")
+	if filter.MemberID.Valid {
+		q.where("sessions.project_id IN (SELECT project_members.project_id FROM project_members WHERE project_members.user_id = %s)", filter.MemberID.UUID)
+	}
+	if filter.From != "" {
+		q.where("sessions.session_date >= %s::date", filter.From)
+	}
+	if filter.To != "" {
+		q.where("sessions.session_date <= %s::date", filter.To)
+	}
+	if len(filter.UserIDs) > 0 {
+		q.where("EXISTS (SELECT 1 FROM user_session WHERE user_session.session_id = sessions.id AND user_session.user_id = ANY(%s::uuid[]))", pq.Array(filter.UserIDs))
+	}
+	if len(filter.ProjectIDs) > 0 {
+		q.where("sessions.project_id = ANY(%s::uuid[])", pq.Array(filter.ProjectIDs))
+	}
+	if len(filter.EpisodeIDs) > 0 {
+		q.where("sessions.episode_id = ANY(%s::uuid[])", pq.Array(filter.EpisodeIDs))
+	}
+	if len(filter.UnitIDs) > 0 {
+		q.where("sessions.unit_id = ANY(%s::uuid[])", pq.Array(filter.UnitIDs))
+	}
+	if len(filter.ClientIDs) > 0 {
+		q.where("sessions.project_id IN (SELECT projects.id FROM projects WHERE projects.client_id = ANY(%s::uuid[]))", pq.Array(filter.ClientIDs))
+	}
+	if len(filter.Parts) > 0 {
+		q.where("sessions.part_worked_on = ANY(%s::text[])", pq.Array(filter.Parts))
+	}
+	if len(filter.Activities) > 0 {
+		q.where("sessions.activity_done = ANY(%s::text[])", pq.Array(filter.Activities))
+	}
+	if len(filter.Statuses) > 0 {
+		q.where("sessions.status::text = ANY(%s::text[])", pq.Array(filter.Statuses))
+	}
+
+	// The total ignores the cursor, it counts all pages
+	countQuery := "SELECT COUNT(*) FROM sessions" + q.whereClause()
+	countArgs := append([]any{}, q.args...)
+
+	descending := strings.HasPrefix(filter.Sort, "-")
+	sortColumn := sessionSortColumns[strings.TrimPrefix(filter.Sort, "-")]
+	direction, comparison := "ASC", ">"
+	if descending {
+		direction, comparison = "DESC", "<"
+	}
+
+	// Keyset pagination: the page starts right after the last row of the
+	// previous one. The id breaks ties between rows with equal sort values
+	if filter.Cursor != nil {
+		q.where(fmt.Sprintf("(%s, sessions.id) %s (%%s::%s, %%s::uuid)", sortColumn.column, comparison, sortColumn.cast),
+			filter.Cursor.Value, filter.Cursor.ID)
+	}
+
+	pageQuery := fmt.Sprintf("SELECT %s FROM sessions%s ORDER BY %s %s, sessions.id %s LIMIT %d",
+		sessionColumns, q.whereClause(), sortColumn.column, direction, direction, filter.Limit+1)
+
+	return pageQuery, q.args, countQuery, countArgs
+}
+
+func sessionSortValue(s db.Session, sort string) string {
+	// The value of the sort column as the cursor stores it
+	switch strings.TrimPrefix(sort, "-") {
+	case "duration":
+		return strconv.Itoa(int(s.Duration))
+	case "created":
+		return s.CreatedAt.Format(sessionCursorTimeLayout)
+	default:
+		return s.SessionDate.Format(time.DateOnly)
+	}
+}
+
+func checkSessionSortValue(value, sort string) error {
+	// Parses a cursor value the way sessionSortValue wrote it
+	var err error
+	switch strings.TrimPrefix(sort, "-") {
+	case "duration":
+		_, err = strconv.ParseInt(value, 10, 32)
+	case "created":
+		_, err = time.Parse(sessionCursorTimeLayout, value)
+	default:
+		_, err = time.Parse(time.DateOnly, value)
+	}
+	return err
+}
+
+func (cfg *apiConfig) querySessions(ctx context.Context, filter sessionFilter) ([]db.Session, int64, string, error) {
+	// Returns a page of sessions, the number of all matching sessions
+	// and the cursor of the next page, empty on the last page
+	pageQuery, args, countQuery, countArgs := buildSessionQueries(filter)
+
+	var total int64
+	err := cfg.conn.QueryRowContext(ctx, countQuery, countArgs...).Scan(&total)
+	if err != nil {
+		return nil, 0, "", err
+	}
+
+	rows, err := cfg.conn.QueryContext(ctx, pageQuery, args...)
+	if err != nil {
+		return nil, 0, "", err
+	}
+	defer rows.Close()
+
+	sessions := []db.Session{}
+	for rows.Next() {
+		var i db.Session
+		err := rows.Scan(
+			&i.ID,
+			&i.SessionDate,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.EpisodeID,
+			&i.ProjectID,
+			&i.Duration,
+			&i.PartWorkedOn,
+			&i.ActivityDone,
+			&i.ExternalUid,
+			&i.StartTime,
+			&i.Status,
+			&i.TimesheetID,
+			&i.UnitID,
+			&i.DeletedAt,
+		)
+		if err != nil {
+			return nil, 0, "", err
+		}
+		sessions = append(sessions, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, "", err
+	}
+
+	// One row more than the limit was fetched to see if there's another page
+	nextCursor := ""
+	if len(sessions) > filter.Limit {
+		sessions = sessions[:filter.Limit]
+		last := sessions[len(sessions)-1]
+		nextCursor = sessionCursor{
+			Sort:  filter.Sort,
+			Value: sessionSortValue(last, filter.Sort),
+			ID:    last.ID,
+		}.encode()
+	}
+
+	return sessions, total, nextCursor, nil
+}
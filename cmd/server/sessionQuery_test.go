@@ -0,0 +1,132 @@
+package main
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestParseSessionFilter(t *testing.T) {
+	userID := uuid.New()
+	query := url.Values{}
+	query.Set("from", "2024-03-01")
+	query.Add("user", userID.String())
+	query.Add("part", "footsteps,Props")
+	query.Set("sort", "-duration")
+	query.Set("limit", "10000")
+
+	filter, err := parseSessionFilter(query)
+	if err != nil {
+		t.Fatalf("error parsing filter: %s", err)
+	}
+	if len(filter.UserIDs) != 1 || filter.UserIDs[0] != userID {
+		t.Errorf("user filter parsed as %v", filter.UserIDs)
+	}
+	if len(filter.Parts) != 2 || filter.Parts[1] != "props" {
+		t.Errorf("part filter parsed as %v", filter.Parts)
+	}
+	if filter.Limit != maxSessionPageSize {
+		t.Errorf("limit should be capped at %d, got %d", maxSessionPageSize, filter.Limit)
+	}
+
+	bad := []url.Values{
+		{"sort": {"id; DROP TABLE sessions"}},
+		{"from": {"yesterday"}},
+		{"user": {"not-a-uuid"}},
+		{"limit": {"-1"}},
+		{"cursor": {"???"}},
+	}
+	for _, b := range bad {
+		_, err := parseSessionFilter(b)
+		if err == nil {
+			t.Errorf("filter %v should give an error", b)
+		}
+	}
+
+	// Cursors only work with the sort order they were made for
+	cursor := sessionCursor{Sort: "date", Value: "2024-03-01", ID: uuid.New()}.encode()
+	_, err = parseSessionFilter(url.Values{"cursor": {cursor}, "sort": {"-date"}})
+	if err == nil {
+		t.Error("cursor of another sort order should give an error")
+	}
+
+	// Values that don't fit the sort column are refused before the query
+	tampered := []sessionCursor{
+		{Sort: "-date", Value: "2024-13-45", ID: uuid.New()},
+		{Sort: "duration", Value: "1; DROP TABLE sessions", ID: uuid.New()},
+		{Sort: "created", Value: "yesterday", ID: uuid.New()},
+	}
+	for _, c := range tampered {
+		_, err = parseSessionFilter(url.Values{"cursor": {c.encode()}, "sort": {c.Sort}})
+		if err == nil || err.Error() != "invalid cursor" {
+			t.Errorf("cursor %v should be invalid, got %v", c, err)
+		}
+	}
+	valid := sessionCursor{Sort: "created", Value: time.Now().Format(sessionCursorTimeLayout), ID: uuid.New()}
+	_, err = parseSessionFilter(url.Values{"cursor": {valid.encode()}, "sort": {"created"}})
+	if err != nil {
+		t.Errorf("cursor %v should be valid, got %v", valid, err)
+	}
+}
+
+func TestBuildSessionQueries(t *testing.T) {
+	filter := sessionFilter{
+		From:       "2024-03-01",
+		ProjectIDs: []uuid.UUID{uuid.New()},
+		Parts:      []string{"footsteps"},
+		Sort:       "-date",
+		Limit:      20,
+		Cursor:     &sessionCursor{Sort: "-date", Value: "2024-03-10", ID: uuid.New()},
+	}
+
+	pageQuery, args, countQuery, countArgs := buildSessionQueries(filter)
+
+	if !strings.Contains(countQuery, "sessions.deleted_at IS NULL") || !strings.Contains(pageQuery, "sessions.deleted_at IS NULL") {
+		t.Errorf("queries should leave out the sessions in the trash: %s", pageQuery)
+	}
+	if len(countArgs) != 3 {
+		t.Errorf("count query should have 3 arguments, got %d", len(countArgs))
+	}
+	if strings.Contains(countQuery, "$4") {
+		t.Errorf("count query shouldn't use the cursor: %s", countQuery)
+	}
+	if len(args) != 5 {
+		t.Errorf("page query should have 5 arguments, got %d", len(args))
+	}
+	if !strings.Contains(pageQuery, "(sessions.session_date, sessions.id) < ($4::date, $5::uuid)") {
+		t.Errorf("page query doesn't continue after the cursor: %s", pageQuery)
+	}
+	if !strings.HasSuffix(pageQuery, "ORDER BY sessions.session_date DESC, sessions.id DESC LIMIT 21") {
+		t.Errorf("page query is sorted or limited wrong: %s", pageQuery)
+	}
+	for _, arg := range args {
+		if s, ok := arg.(string); ok && strings.Contains(pageQuery, s) {
+			t.Errorf("argument %s ended up in the query text", s)
+		}
+	}
+}
+
+func TestBuildSessionQueriesForMember(t *testing.T) {
+	memberID := uuid.New()
+	filter := sessionFilter{
+		MemberID: uuid.NullUUID{UUID: memberID, Valid: true},
+		Sort:     "-date",
+		Limit:    20,
+	}
+
+	pageQuery, args, countQuery, _ := buildSessionQueries(filter)
+	if !strings.Contains(pageQuery, "project_members.user_id = $1") || !strings.Contains(countQuery, "project_members.user_id = $1") {
+		t.Errorf("members should only get sessions of their projects: %s", pageQuery)
+	}
+	if len(args) != 1 || args[0] != memberID {
+		t.Errorf("the member should be the only argument, got %v", args)
+	}
+
+	pageQuery, _, _, _ = buildSessionQueries(sessionFilter{Sort: "-date", Limit: 20})
+	if strings.Contains(pageQuery, "project_members") {
+		t.Errorf("users who see every project shouldn't be filtered: %s", pageQuery)
+	}
+}
@@ -0,0 +1,288 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Denisowiec/FoleyBookkeeper/internal/db"
+	"github.com/google/uuid"
+)
+
+// A rule either stays silent, warns the user or rejects the session
+type ruleMode string
+
+const (
+	ruleOff    ruleMode = "off"
+	ruleWarn   ruleMode = "warn"
+	ruleReject ruleMode = "reject"
+)
+
+type sessionRule struct {
+	Mode  ruleMode
+	Limit int
+}
+
+type sessionRules struct {
+	// Maximum minutes a single user can log on one day
+	DailyMaximum sessionRule
+	// The same user in two sessions at once
+	Overlap sessionRule
+	// Sessions dated after today
+	FutureDate sessionRule
+	// Maximum minutes of a single session
+	MaxDuration sessionRule
+}
+
+var defaultSessionRules = sessionRules{
+	DailyMaximum: sessionRule{Mode: ruleWarn, Limit: 720},
+	Overlap:      sessionRule{Mode: ruleReject},
+	FutureDate:   sessionRule{Mode: ruleWarn},
+	MaxDuration:  sessionRule{Mode: ruleWarn, Limit: 480},
+}
+
+func parseSessionRule(input string, fallback sessionRule) (sessionRule, error) {
+	// Rules are configured as <mode> or <mode>:<limit>, e.g. reject:720
+	// An empty value keeps the default
+	if input == "" {
+		return fallback, nil
+	}
+
+	modeStr, limitStr, hasLimit := strings.Cut(input, ":")
+	rule := sessionRule{
+		Mode:  ruleMode(strings.ToLower(strings.TrimSpace(modeStr))),
+		Limit: fallback.Limit,
+	}
+	switch rule.Mode {
+	case ruleOff, ruleWarn, ruleReject:
+	default:
+		return sessionRule{}, fmt.Errorf("rule mode %s unknown", modeStr)
+	}
+
+	if hasLimit {
+		limit, err := strconv.Atoi(strings.TrimSpace(limitStr))
+		if err != nil || limit <= 0 {
+			return sessionRule{}, fmt.Errorf("invalid rule limit %s", limitStr)
+		}
+		rule.Limit = limit
+	}
+	return rule, nil
+}
+
+func loadSessionRules() (sessionRules, error) {
+	rules := defaultSessionRules
+	settings := []struct {
+		env  string
+		rule *sessionRule
+	}{
+		{"SESSION_RULE_DAILY_MAXIMUM", &rules.DailyMaximum},
+		{"SESSION_RULE_OVERLAP", &rules.Overlap},
+		{"SESSION_RULE_FUTURE_DATE", &rules.FutureDate},
+		{"SESSION_RULE_MAX_DURATION", &rules.MaxDuration},
+	}
+	for _, s := range settings {
+		rule, err := parseSessionRule(os.Getenv(s.env), *s.rule)
+		if err != nil {
+			return sessionRules{}, fmt.Errorf("%s: %w", s.env, err)
+		}
+		*s.rule = rule
+	}
+	return rules, nil
+}
+
+type sessionViolation struct {
+	Rule       string        `json:"rule"`
+	Severity   ruleMode      `json:"severity"`
+	Message    string        `json:"message"`
+	Date       string        `json:"date"`
+	UserID     uuid.NullUUID `json:"user_id"`
+	SessionIDs []uuid.UUID   `json:"session_ids"`
+}
+
+// The part of a session the rules look at
+type sessionSpan struct {
+	ID        uuid.UUID
+	Date      time.Time
+	StartTime sql.NullTime
+	Duration  int32
+}
+
+func (s sessionSpan) interval() (time.Time, time.Time, bool) {
+	// Sessions without a start time can't be placed on the clock
+	if !s.StartTime.Valid {
+		return time.Time{}, time.Time{}, false
+	}
+	start := time.Date(s.Date.Year(), s.Date.Month(), s.Date.Day(),
+		s.StartTime.Time.Hour(), s.StartTime.Time.Minute(), 0, 0, time.UTC)
+	return start, start.Add(time.Duration(s.Duration) * time.Minute), true
+}
+
+func (rules sessionRules) checkSession(s sessionSpan, today time.Time) []sessionViolation {
+	// Rules that only need the session itself
+	violations := []sessionViolation{}
+	date := s.Date.Format(time.DateOnly)
+
+	if rules.FutureDate.Mode != ruleOff && date > today.Format(time.DateOnly) {
+		violations = append(violations, sessionViolation{
+			Rule:       "future_date",
+			Severity:   rules.FutureDate.Mode,
+			Message:    fmt.Sprintf("session is dated in the future (%s)", date),
+			Date:       date,
+			SessionIDs: []uuid.UUID{s.ID},
+		})
+	}
+	if rules.MaxDuration.Mode != ruleOff && int(s.Duration) > rules.MaxDuration.Limit {
+		violations = append(violations, sessionViolation{
+			Rule:       "max_duration",
+			Severity:   rules.MaxDuration.Mode,
+			Message:    fmt.Sprintf("session lasts %d minutes, the limit is %d", s.Duration, rules.MaxDuration.Limit),
+			Date:       date,
+			SessionIDs: []uuid.UUID{s.ID},
+		})
+	}
+	return violations
+}
+
+func (rules sessionRules) checkUserDay(userID uuid.UUID, spans []sessionSpan) []sessionViolation {
+	// Rules that look at all sessions of one user on one day
+	violations := []sessionViolation{}
+	if len(spans) == 0 {
+		return violations
+	}
+	date := spans[0].Date.Format(time.DateOnly)
+	user := uuid.NullUUID{UUID: userID, Valid: true}
+
+	if rules.DailyMaximum.Mode != ruleOff {
+		total := 0
+		ids := []uuid.UUID{}
+		for _, s := range spans {
+			total += int(s.Duration)
+			ids = append(ids, s.ID)
+		}
+		if total > rules.DailyMaximum.Limit {
+			violations = append(violations, sessionViolation{
+				Rule:       "daily_maximum",
+				Severity:   rules.DailyMaximum.Mode,
+				Message:    fmt.Sprintf("%d minutes logged on %s, the limit is %d", total, date, rules.DailyMaximum.Limit),
+				Date:       date,
+				UserID:     user,
+				SessionIDs: ids,
+			})
+		}
+	}
+
+	if rules.Overlap.Mode != ruleOff {
+		for i := range spans {
+			startA, endA, ok := spans[i].interval()
+			if !ok {
+				continue
+			}
+			for j := i + 1; j < len(spans); j++ {
+				startB, endB, ok := spans[j].interval()
+				if !ok {
+					continue
+				}
+				if startA.Before(endB) && startB.Before(endA) {
+					violations = append(violations, sessionViolation{
+						Rule:       "overlap",
+						Severity:   rules.Overlap.Mode,
+						Message:    fmt.Sprintf("sessions overlap on %s", date),
+						Date:       date,
+						UserID:     user,
+						SessionIDs: []uuid.UUID{spans[i].ID, spans[j].ID},
+					})
+				}
+			}
+		}
+	}
+	return violations
+}
+
+func hasRejection(violations []sessionViolation) bool {
+	for _, v := range violations {
+		if v.Severity == ruleReject {
+			return true
+		}
+	}
+	return false
+}
+
+func (cfg *apiConfig) validateSession(ctx context.Context, s sessionSpan, userIDs []uuid.UUID) ([]sessionViolation, error) {
+	// Checks a session about to be saved against the rules. The session
+	// is compared with the other sessions its users have on the same day
+	violations := cfg.sessionRules.checkSession(s, time.Now())
+	if len(userIDs) == 0 {
+		return violations, nil
+	}
+
+	params := db.GetSessionsForUsersOnDateParams{
+		UserIds:     userIDs,
+		SessionDate: s.Date,
+	}
+	others, err := cfg.db.GetSessionsForUsersOnDate(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, userID := range userIDs {
+		spans := []sessionSpan{s}
+		for _, o := range others {
+			// The saved version of the session itself gets replaced
+			if o.UserID != userID || o.ID == s.ID {
+				continue
+			}
+			spans = append(spans, sessionSpan{ID: o.ID, Date: o.SessionDate, StartTime: o.StartTime, Duration: o.Duration})
+		}
+		for _, v := range cfg.sessionRules.checkUserDay(userID, spans) {
+			// Overlaps between other sessions aren't this session's problem
+			if v.Rule == "overlap" && v.SessionIDs[0] != s.ID {
+				continue
+			}
+			violations = append(violations, v)
+		}
+	}
+	return violations, nil
+}
+
+func (rules sessionRules) report(rows []db.GetSessionsForAnomalyReportRow, today time.Time) []sessionViolation {
+	// Scans existing sessions for violations of all rules that aren't off
+	violations := []sessionViolation{}
+	checked := map[uuid.UUID]bool{}
+	type userDay struct {
+		userID uuid.UUID
+		date   string
+	}
+	days := map[userDay][]sessionSpan{}
+	order := []userDay{}
+
+	for _, row := range rows {
+		span := sessionSpan{ID: row.ID, Date: row.SessionDate, StartTime: row.StartTime, Duration: row.Duration}
+		// A session with several users shows up once per user
+		if !checked[row.ID] {
+			checked[row.ID] = true
+			violations = append(violations, rules.checkSession(span, today)...)
+		}
+		if !row.UserID.Valid {
+			continue
+		}
+		key := userDay{userID: row.UserID.UUID, date: row.SessionDate.Format(time.DateOnly)}
+		if _, ok := days[key]; !ok {
+			order = append(order, key)
+		}
+		days[key] = append(days[key], span)
+	}
+
+	for _, key := range order {
+		violations = append(violations, rules.checkUserDay(key.userID, days[key])...)
+	}
+
+	sort.SliceStable(violations, func(i, j int) bool {
+		return violations[i].Date < violations[j].Date
+	})
+	return violations
+}
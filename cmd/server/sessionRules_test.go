@@ -0,0 +1,133 @@
+package main
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func testSpan(date string, start string, duration int32) sessionSpan {
+	d, _ := time.Parse(time.DateOnly, date)
+	span := sessionSpan{ID: uuid.New(), Date: d, Duration: duration}
+	if start != "" {
+		st, _ := time.Parse(sessionStartTimeLayout, start)
+		span.StartTime = sql.NullTime{Time: st, Valid: true}
+	}
+	return span
+}
+
+func TestParseSessionRule(t *testing.T) {
+	fallback := sessionRule{Mode: ruleWarn, Limit: 480}
+
+	rule, err := parseSessionRule("", fallback)
+	if err != nil || rule != fallback {
+		t.Errorf("empty setting should keep the default, got %+v", rule)
+	}
+
+	rule, err = parseSessionRule("reject:600", fallback)
+	if err != nil || rule.Mode != ruleReject || rule.Limit != 600 {
+		t.Errorf("'reject:600' parsed as %+v, %v", rule, err)
+	}
+
+	rule, err = parseSessionRule("off", fallback)
+	if err != nil || rule.Mode != ruleOff || rule.Limit != 480 {
+		t.Errorf("'off' parsed as %+v, %v", rule, err)
+	}
+
+	_, err = parseSessionRule("maybe", fallback)
+	if err == nil {
+		t.Error("unknown mode should give an error")
+	}
+
+	_, err = parseSessionRule("warn:-5", fallback)
+	if err == nil {
+		t.Error("negative limit should give an error")
+	}
+}
+
+func TestCheckSession(t *testing.T) {
+	rules := defaultSessionRules
+	today, _ := time.Parse(time.DateOnly, "2024-03-04")
+
+	violations := rules.checkSession(testSpan("2024-03-04", "", 240), today)
+	if len(violations) != 0 {
+		t.Errorf("ordinary session got violations: %+v", violations)
+	}
+
+	violations = rules.checkSession(testSpan("2024-03-05", "", 600), today)
+	if len(violations) != 2 {
+		t.Fatalf("long session in the future should break 2 rules, got %+v", violations)
+	}
+	if violations[0].Rule != "future_date" || violations[1].Rule != "max_duration" {
+		t.Errorf("wrong rules broken: %+v", violations)
+	}
+
+	rules.MaxDuration.Mode = ruleOff
+	violations = rules.checkSession(testSpan("2024-03-04", "", 600), today)
+	if len(violations) != 0 {
+		t.Errorf("rules that are off shouldn't report anything: %+v", violations)
+	}
+}
+
+func TestCheckUserDay(t *testing.T) {
+	rules := defaultSessionRules
+	userID := uuid.New()
+
+	spans := []sessionSpan{
+		testSpan("2024-03-04", "09:00", 240),
+		testSpan("2024-03-04", "13:00", 240),
+		testSpan("2024-03-04", "", 120),
+	}
+	violations := rules.checkUserDay(userID, spans)
+	if len(violations) != 0 {
+		t.Errorf("back to back sessions shouldn't overlap: %+v", violations)
+	}
+
+	spans = append(spans, testSpan("2024-03-04", "16:30", 240))
+	violations = rules.checkUserDay(userID, spans)
+	if len(violations) != 2 {
+		t.Fatalf("expected daily maximum and overlap violations, got %+v", violations)
+	}
+	if violations[0].Rule != "daily_maximum" || len(violations[0].SessionIDs) != 4 {
+		t.Errorf("daily maximum should list all sessions of the day: %+v", violations[0])
+	}
+	if violations[1].Rule != "overlap" || violations[1].Severity != ruleReject {
+		t.Errorf("overlap should be rejected by default: %+v", violations[1])
+	}
+	if violations[1].SessionIDs[0] != spans[1].ID || violations[1].SessionIDs[1] != spans[3].ID {
+		t.Errorf("overlap reported for the wrong sessions: %+v", violations[1])
+	}
+}
+
+func TestImportItemViolations(t *testing.T) {
+	today := time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC)
+	long := testSpan("2024-03-04", "09:00", 600)
+	future := testSpan("2024-03-10", "09:00", 60)
+
+	// Warnings don't stop the import, they come with the item
+	warning := sessionRules{MaxDuration: sessionRule{Mode: ruleWarn, Limit: 480}}
+	item := icsImportItem{Action: "create"}
+	item.applyViolations(warning.checkSession(long, today))
+	if item.Action != "create" || len(item.Warnings) != 1 || item.Warnings[0].Rule != "max_duration" {
+		t.Errorf("warned session should be imported with its warning, got %+v", item)
+	}
+
+	// Rejections skip the event, as the create endpoint would refuse it
+	rejecting := sessionRules{
+		MaxDuration: sessionRule{Mode: ruleWarn, Limit: 480},
+		FutureDate:  sessionRule{Mode: ruleReject},
+	}
+	item = icsImportItem{Action: "update"}
+	item.applyViolations(rejecting.checkSession(future, today))
+	if item.Action != "skip" || item.Reason == "" || item.Warnings != nil {
+		t.Errorf("rejected session should be skipped with a reason, got %+v", item)
+	}
+
+	item = icsImportItem{Action: "create"}
+	item.applyViolations(rejecting.checkSession(testSpan("2024-03-04", "09:00", 60), today))
+	if item.Action != "create" || item.Warnings != nil {
+		t.Errorf("session breaking no rules should be imported as is, got %+v", item)
+	}
+}
@@ -1,54 +1,170 @@
 package main
 
 import (
+	"context"
+	"database/sql"
 	"encoding/json"
 	"fmt"
+	"io"
+	"log"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/Denisowiec/FoleyBookkeeper/internal/db"
 	"github.com/google/uuid"
 )
 
-// These functions convert string values in json input into enums
-func strToPart(input string) (db.Part, error) {
-	switch input {
-	case "props":
-		return db.PartProps, nil
-	case "footsteps":
-		return db.PartFootsteps, nil
-	case "movements":
-		return db.PartMovements, nil
-	case "dialogue":
-		return db.PartDialogue, nil
-	case "adr":
-		return db.PartAdr, nil
-	case "music":
-		return db.PartMusic, nil
-	case "background":
-		return db.PartBackground, nil
-	case "other":
-		return db.PartOther, nil
-	default:
-		return "", fmt.Errorf("part unknown")
+func (cfg *apiConfig) checkPartAndActivity(ctx context.Context, part, activity, previousPart, previousActivity string) error {
+	// Sessions can only use parts and activities that exist and are active.
+	// A session that already uses an inactive one can keep it
+	p, err := cfg.db.GetPartByCode(ctx, part)
+	if err != nil {
+		return fmt.Errorf("part %s unknown", part)
+	}
+	if !p.Active && p.Code != previousPart {
+		return fmt.Errorf("part %s is no longer in use", part)
+	}
+	a, err := cfg.db.GetActivityByCode(ctx, activity)
+	if err != nil {
+		return fmt.Errorf("activity %s unknown", activity)
+	}
+	if !a.Active && a.Code != previousActivity {
+		return fmt.Errorf("activity %s is no longer in use", activity)
+	}
+	return nil
+}
+
+type sessionInputType struct {
+	Duration     int32  `json:"duration"`
+	SessionDate  string `json:"session_date"`
+	StartTime    string `json:"start_time"`
+	ProjectID    string `json:"project_id"`
+	EpisodeID    string `json:"episode_id"`
+	UnitID       string `json:"unit_id"`
+	PartWorkedOn string `json:"part_worked_on"`
+	ActivityDone string `json:"activity_done"`
+}
+
+// Start times are optional and given as hours and minutes
+const sessionStartTimeLayout = "15:04"
+
+func parseSessionInput(input sessionInputType) (db.CreateSessionParams, error) {
+	// Converts the json input into parameters ready for the database
+	params := db.CreateSessionParams{}
+	var err error
+
+	params.Duration = input.Duration
+	if params.Duration <= 0 {
+		return params, fmt.Errorf("duration has to be positive")
+	}
+	params.SessionDate, err = time.Parse(time.DateOnly, input.SessionDate)
+	if err != nil {
+		return params, err
+	}
+	if input.StartTime != "" {
+		startTime, err := time.Parse(sessionStartTimeLayout, input.StartTime)
+		if err != nil {
+			return params, err
+		}
+		params.StartTime = sql.NullTime{Time: startTime, Valid: true}
 	}
+	if input.ProjectID != "" {
+		params.ProjectID, err = uuid.Parse(input.ProjectID)
+		if err != nil {
+			return params, err
+		}
+	}
+	params.EpisodeID, err = parseNullUUID(input.EpisodeID)
+	if err != nil {
+		return params, err
+	}
+	params.UnitID, err = parseNullUUID(input.UnitID)
+	if err != nil {
+		return params, err
+	}
+	params.PartWorkedOn = strings.ToLower(strings.TrimSpace(input.PartWorkedOn))
+	params.ActivityDone = strings.ToLower(strings.TrimSpace(input.ActivityDone))
+	return params, nil
 }
 
-func strToActivity(input string) (db.Activity, error) {
-	switch input {
-	case "record":
-		return db.ActivityRecord, nil
-	case "edit":
-		return db.ActivityEdit, nil
-	case "service":
-		return db.ActivityService, nil
-	case "spotting":
-		return db.ActivitySpotting, nil
-	case "other":
-		return db.ActivityOther, nil
+func (cfg *apiConfig) resolveSessionLevel(ctx context.Context, params *db.CreateSessionParams) error {
+	// Sessions are logged for an episode, a unit of a project, or the project
+	// itself. The project follows from the episode or unit when one is given
+	var projectID uuid.UUID
+	switch {
+	case params.EpisodeID.Valid && params.UnitID.Valid:
+		return fmt.Errorf("sessions can't be logged for both an episode and a unit")
+	case params.EpisodeID.Valid:
+		ep, err := cfg.db.GetEpisodeByID(ctx, params.EpisodeID.UUID)
+		if err != nil {
+			return fmt.Errorf("episode not found")
+		}
+		projectID = ep.ProjectID
+	case params.UnitID.Valid:
+		unit, err := cfg.db.GetProjectUnitByID(ctx, params.UnitID.UUID)
+		if err != nil {
+			return fmt.Errorf("unit not found")
+		}
+		projectID = unit.ProjectID
 	default:
-		return "", fmt.Errorf("activity unknown")
+		if params.ProjectID == uuid.Nil {
+			return fmt.Errorf("sessions need a project, unit or episode")
+		}
+		return nil
 	}
+
+	if params.ProjectID != uuid.Nil && params.ProjectID != projectID {
+		return fmt.Errorf("episode or unit doesn't belong to the project")
+	}
+	params.ProjectID = projectID
+	return nil
+}
+
+func (cfg *apiConfig) getUsersForSessions(ctx context.Context, sessions []db.Session) (map[uuid.UUID][]db.GetUsersForSessionRow, error) {
+	// Loads the users of all the sessions in a single query
+	ids := []uuid.UUID{}
+	usersBySession := map[uuid.UUID][]db.GetUsersForSessionRow{}
+	for _, s := range sessions {
+		ids = append(ids, s.ID)
+		// Sessions without users get an empty list rather than null
+		usersBySession[s.ID] = []db.GetUsersForSessionRow{}
+	}
+	if len(ids) == 0 {
+		return usersBySession, nil
+	}
+
+	rows, err := cfg.db.GetUsersForSessions(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		usersBySession[row.SessionID] = append(usersBySession[row.SessionID], db.GetUsersForSessionRow{
+			UserID:   row.UserID,
+			Username: row.Username,
+		})
+	}
+	return usersBySession, nil
+}
+
+func respondWithViolations(w http.ResponseWriter, violations []sessionViolation) {
+	// Rejected sessions get the list of broken rules, so the user knows what to fix
+	rejection := struct {
+		Error      string             `json:"error"`
+		Violations []sessionViolation `json:"violations"`
+	}{
+		Error:      "Session breaks the validation rules",
+		Violations: violations,
+	}
+	err := respondWithJSON(w, http.StatusUnprocessableEntity, rejection)
+	if err != nil {
+		respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
+	}
+}
+
+type sessionRespType struct {
+	db.Session
+	Warnings []sessionViolation `json:"warnings"`
 }
 
 func (cfg *apiConfig) handlerCreateSession(w http.ResponseWriter, r *http.Request) {
@@ -58,12 +174,89 @@ func (cfg *apiConfig) handlerCreateSession(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	type sessionInputType struct {
-		Duration     int32  `json:"duration"`
-		SessionDate  string `json:"session_date"`
-		EpisodeID    string `json:"episode_id"`
-		PartWorkedOn string `json:"part_worked_on"`
-		ActivityDone string `json:"activity_done"`
+	sessionInput := sessionInputType{}
+
+	decoder := json.NewDecoder(r.Body)
+	err = decoder.Decode(&sessionInput)
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+
+	createSessionParams, err := parseSessionInput(sessionInput)
+	if err != nil {
+		respondWithError(w, "error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+	err = cfg.resolveSessionLevel(r.Context(), &createSessionParams)
+	if err != nil {
+		respondWithError(w, err.Error(), http.StatusBadRequest, err)
+		return
+	}
+	err = cfg.checkPartAndActivity(r.Context(), createSessionParams.PartWorkedOn, createSessionParams.ActivityDone, "", "")
+	if err != nil {
+		respondWithError(w, err.Error(), http.StatusBadRequest, err)
+		return
+	}
+	if !cfg.requireSessionLogging(w, r, createSessionParams.ProjectID) {
+		return
+	}
+	err = cfg.checkProjectAcceptsWork(r.Context(), createSessionParams.ProjectID)
+	if err != nil {
+		respondWithError(w, err.Error(), http.StatusConflict, err)
+		return
+	}
+
+	// A new session has no users yet, so only the rules about the session itself apply here
+	span := sessionSpan{
+		Date:      createSessionParams.SessionDate,
+		StartTime: createSessionParams.StartTime,
+		Duration:  createSessionParams.Duration,
+	}
+	violations, err := cfg.validateSession(r.Context(), span, nil)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+	if hasRejection(violations) {
+		respondWithViolations(w, violations)
+		return
+	}
+
+	session, err := cfg.db.CreateSession(r.Context(), createSessionParams)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+	// The session is logged either way, a part that didn't advance can be set by hand
+	err = advancePartStatus(r.Context(), &cfg.db, session)
+	if err != nil {
+		log.Println("Error advancing production status", err)
+	}
+
+	sessionResp := sessionRespType{
+		Session:  session,
+		Warnings: violations,
+	}
+
+	err = respondWithJSON(w, http.StatusCreated, sessionResp)
+	if err != nil {
+		respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
+		return
+	}
+}
+
+func (cfg *apiConfig) handlerUpdateSession(w http.ResponseWriter, r *http.Request) {
+	_, _, err := authenticateUser(r, cfg.secret)
+	if err != nil {
+		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+		return
+	}
+
+	sessionID, err := uuid.Parse(r.PathValue("sessionid"))
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
 	}
 
 	sessionInput := sessionInputType{}
@@ -75,36 +268,98 @@ func (cfg *apiConfig) handlerCreateSession(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	createSessionParams := db.CreateSessionParams{}
-	createSessionParams.Duration = sessionInput.Duration
-	createSessionParams.SessionDate, err = time.Parse(time.DateOnly, sessionInput.SessionDate)
+	params, err := parseSessionInput(sessionInput)
 	if err != nil {
 		respondWithError(w, "error decoding user input", http.StatusBadRequest, err)
 		return
 	}
-	createSessionParams.EpisodeID, err = uuid.Parse(sessionInput.EpisodeID)
+	err = cfg.resolveSessionLevel(r.Context(), &params)
 	if err != nil {
-		respondWithError(w, "error decoding user input", http.StatusBadRequest, err)
+		respondWithError(w, err.Error(), http.StatusBadRequest, err)
 		return
 	}
-	createSessionParams.PartWorkedOn, err = strToPart(sessionInput.PartWorkedOn)
+
+	previous, err := cfg.db.GetSession(r.Context(), sessionID)
 	if err != nil {
-		respondWithError(w, "error decoding user input", http.StatusBadRequest, err)
+		respondWithError(w, "Session not found", http.StatusNotFound, err)
+		return
+	}
+	if !cfg.requireSessionLogging(w, r, previous.ProjectID, params.ProjectID) {
+		return
+	}
+	if sessionIsLocked(previous.Status) {
+		respondWithError(w, "Session is locked by its timesheet", http.StatusConflict, nil)
 		return
 	}
-	createSessionParams.ActivityDone, err = strToActivity(sessionInput.ActivityDone)
+	err = cfg.checkPartAndActivity(r.Context(), params.PartWorkedOn, params.ActivityDone, previous.PartWorkedOn, previous.ActivityDone)
 	if err != nil {
-		respondWithError(w, "error decoding user input", http.StatusBadRequest, err)
+		respondWithError(w, err.Error(), http.StatusBadRequest, err)
+		return
+	}
+	// Sessions can neither be changed on nor moved to a closed project
+	err = cfg.checkProjectAcceptsWork(r.Context(), previous.ProjectID)
+	if err == nil {
+		err = cfg.checkProjectAcceptsWork(r.Context(), params.ProjectID)
+	}
+	if err != nil {
+		respondWithError(w, err.Error(), http.StatusConflict, err)
 		return
 	}
 
-	session, err := cfg.db.CreateSession(r.Context(), createSessionParams)
+	// The updated session is checked against the other sessions of its users
+	users, err := cfg.db.GetUsersForSession(r.Context(), sessionID)
 	if err != nil {
 		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
 		return
 	}
+	userIDs := []uuid.UUID{}
+	for _, u := range users {
+		userIDs = append(userIDs, u.UserID)
+	}
+
+	span := sessionSpan{
+		ID:        sessionID,
+		Date:      params.SessionDate,
+		StartTime: params.StartTime,
+		Duration:  params.Duration,
+	}
+	violations, err := cfg.validateSession(r.Context(), span, userIDs)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+	if hasRejection(violations) {
+		respondWithViolations(w, violations)
+		return
+	}
+
+	updateSessionParams := db.UpdateSessionParams{
+		ID:           sessionID,
+		Duration:     params.Duration,
+		SessionDate:  params.SessionDate,
+		ProjectID:    params.ProjectID,
+		EpisodeID:    params.EpisodeID,
+		UnitID:       params.UnitID,
+		PartWorkedOn: params.PartWorkedOn,
+		ActivityDone: params.ActivityDone,
+		StartTime:    params.StartTime,
+	}
+	session, err := cfg.db.UpdateSession(r.Context(), updateSessionParams)
+	if err != nil {
+		respondWithError(w, "Session not found", http.StatusNotFound, err)
+		return
+	}
+	err = advancePartStatus(r.Context(), &cfg.db, session)
+	if err != nil {
+		log.Println("Error advancing production status", err)
+	}
+
+	sessionResp := sessionRespType{
+		Session:  session,
+		Warnings: violations,
+	}
 
-	err = respondWithJSON(w, http.StatusCreated, session)
+	err = respondWithJSON(w, http.StatusAccepted, sessionResp)
 	if err != nil {
 		respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
 		return
@@ -137,12 +392,51 @@ func (cfg *apiConfig) handlerAddUsersToSession(w http.ResponseWriter, r *http.Re
 		return
 	}
 
+	userIDs := []uuid.UUID{}
 	for _, user := range input.UserIDs {
 		id, err := uuid.Parse(user)
 		if err != nil {
 			respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
 			return
 		}
+		userIDs = append(userIDs, id)
+	}
+
+	// The new users' other sessions on that day may clash with this one
+	session, err := cfg.db.GetSession(r.Context(), sessionID)
+	if err != nil {
+		respondWithError(w, "Session not found", http.StatusNotFound, err)
+		return
+	}
+	if !cfg.requireSessionLogging(w, r, session.ProjectID) {
+		return
+	}
+	if sessionIsLocked(session.Status) {
+		respondWithError(w, "Session is locked by its timesheet", http.StatusConflict, nil)
+		return
+	}
+	err = cfg.checkProjectAcceptsWork(r.Context(), session.ProjectID)
+	if err != nil {
+		respondWithError(w, err.Error(), http.StatusConflict, err)
+		return
+	}
+	span := sessionSpan{
+		ID:        session.ID,
+		Date:      session.SessionDate,
+		StartTime: session.StartTime,
+		Duration:  session.Duration,
+	}
+	violations, err := cfg.validateSession(r.Context(), span, userIDs)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+	if hasRejection(violations) {
+		respondWithViolations(w, violations)
+		return
+	}
+
+	for _, id := range userIDs {
 		addusersParams := db.AddUserToSessionParams{
 			UserID:    id,
 			SessionID: sessionID,
@@ -154,7 +448,15 @@ func (cfg *apiConfig) handlerAddUsersToSession(w http.ResponseWriter, r *http.Re
 		}
 	}
 
-	err = respondWithJSON(w, http.StatusAccepted, input)
+	addUsersResp := struct {
+		UserIDs  []string           `json:"user_ids"`
+		Warnings []sessionViolation `json:"warnings"`
+	}{
+		UserIDs:  input.UserIDs,
+		Warnings: violations,
+	}
+
+	err = respondWithJSON(w, http.StatusAccepted, addUsersResp)
 	if err != nil {
 		respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
 		return
@@ -179,6 +481,9 @@ func (cfg *apiConfig) handlerGetSession(w http.ResponseWriter, r *http.Request)
 		respondWithError(w, "Session not found", http.StatusNotFound, err)
 		return
 	}
+	if !cfg.requireProjectVisible(w, r, session.ProjectID) {
+		return
+	}
 
 	users, err := cfg.db.GetUsersForSession(r.Context(), sessionID)
 	if err != nil {
@@ -186,14 +491,22 @@ func (cfg *apiConfig) handlerGetSession(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	cues, err := cfg.db.GetCuesForSession(r.Context(), sessionID)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+
 	type getSesRespType struct {
 		db.GetSessionRow
 		Users []db.GetUsersForSessionRow `json:"users"`
+		Cues  []cueResponse              `json:"cues"`
 	}
 
 	getSesResp := getSesRespType{
 		GetSessionRow: session,
 		Users:         users,
+		Cues:          cuesToResponse(cues),
 	}
 
 	err = respondWithJSON(w, http.StatusOK, getSesResp)
@@ -204,91 +517,167 @@ func (cfg *apiConfig) handlerGetSession(w http.ResponseWriter, r *http.Request)
 }
 
 func (cfg *apiConfig) handlerGetSessions(w http.ResponseWriter, r *http.Request) {
+	// Lists sessions matching the filters in the query string. All filters
+	// can be combined, see parseSessionFilter for the parameters.
+	// Results come in pages, the next page is requested with the returned cursor
 	_, _, err := authenticateUser(r, cfg.secret)
 	if err != nil {
 		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
 		return
 	}
 
-	type reqInputType struct {
-		ProjectID string `json:"project_id"`
-		EpisodeID string `json:"episode_id"`
-		Limit     int    `json:"limit"`
+	filter, err := parseSessionFilter(r.URL.Query())
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+	access, err := cfg.requestProjectAccess(r)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+	if !access.global {
+		filter.MemberID = uuid.NullUUID{UUID: access.userID, Valid: true}
 	}
-	reqInput := reqInputType{}
 
-	decoder := json.NewDecoder(r.Body)
-	err = decoder.Decode(&reqInput)
+	list, total, nextCursor, err := cfg.querySessions(r.Context(), filter)
 	if err != nil {
-		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
 		return
 	}
 
-	var list []db.Session
+	type listItem struct {
+		db.Session `json:"session"`
+		Users      []db.GetUsersForSessionRow `json:"users"`
+	}
+	finalList := []listItem{}
 
-	switch {
-	case reqInput.ProjectID != "":
-		projectID, err := uuid.Parse(reqInput.ProjectID)
-		if err != nil {
-			respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
-			return
-		}
-		getSesParams := db.GetSessionsForProjectParams{
-			ProjectID: projectID,
-			Limit:     int32(reqInput.Limit),
-		}
+	usersBySession, err := cfg.getUsersForSessions(r.Context(), list)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
 
-		list, err = cfg.db.GetSessionsForProject(r.Context(), getSesParams)
-		if err != nil {
-			respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
-			return
+	for _, ses := range list {
+		item := listItem{
+			Session: ses,
+			Users:   usersBySession[ses.ID],
 		}
+		finalList = append(finalList, item)
+	}
 
-	case reqInput.EpisodeID != "":
-		episodeID, err := uuid.Parse(reqInput.EpisodeID)
-		if err != nil {
-			respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
-			return
-		}
-		getSesParams := db.GetSessionsForEpisodeParams{
-			EpisodeID: episodeID,
-			Limit:     int32(reqInput.Limit),
-		}
+	sessionsResp := struct {
+		Sessions   []listItem `json:"sessions"`
+		Total      int64      `json:"total"`
+		NextCursor string     `json:"next_cursor"`
+	}{
+		Sessions:   finalList,
+		Total:      total,
+		NextCursor: nextCursor,
+	}
 
-		list, err = cfg.db.GetSessionsForEpisode(r.Context(), getSesParams)
+	err = respondWithJSON(w, http.StatusOK, sessionsResp)
+	if err != nil {
+		respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
+		return
+	}
+}
+
+func (cfg *apiConfig) handlerGetSessionAnomalies(w http.ResponseWriter, r *http.Request) {
+	// Scans the sessions in a date range for violations of the validation rules
+	// Without a range, the last 90 days are scanned
+	_, _, err := authenticateUser(r, cfg.secret)
+	if err != nil {
+		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+		return
+	}
+
+	reportInput := struct {
+		From string `json:"from"`
+		To   string `json:"to"`
+	}{}
+
+	decoder := json.NewDecoder(r.Body)
+	err = decoder.Decode(&reportInput)
+	if err != nil && err != io.EOF {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+
+	today := time.Now()
+	params := db.GetSessionsForAnomalyReportParams{
+		DateFrom: today.AddDate(0, 0, -90),
+		// Future sessions are anomalies too, so they're included by default
+		DateTo: today.AddDate(1, 0, 0),
+	}
+	if reportInput.From != "" {
+		params.DateFrom, err = time.Parse(time.DateOnly, reportInput.From)
 		if err != nil {
-			respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+			respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
 			return
 		}
-	default:
-		list, err = cfg.db.GetSessions(r.Context(), int32(reqInput.Limit))
+	}
+	if reportInput.To != "" {
+		params.DateTo, err = time.Parse(time.DateOnly, reportInput.To)
 		if err != nil {
-			respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+			respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
 			return
 		}
 	}
 
-	type listItem struct {
-		db.Session `json:"session"`
-		Users      []db.GetUsersForSessionRow `json:"users"`
+	rows, err := cfg.db.GetSessionsForAnomalyReport(r.Context(), params)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
 	}
-	finalList := []listItem{}
 
-	for _, ses := range list {
-		us, err := cfg.db.GetUsersForSession(r.Context(), ses.ID)
-		if err != nil {
-			respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
-			return
-		}
+	err = respondWithJSON(w, http.StatusOK, cfg.sessionRules.report(rows, today))
+	if err != nil {
+		respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
+		return
+	}
+}
 
-		item := listItem{
-			Session: ses,
-			Users:   us,
-		}
-		finalList = append(finalList, item)
+func (cfg *apiConfig) handlerDeleteSession(w http.ResponseWriter, r *http.Request) {
+	// Moves a session to the trash. Sessions locked by a timesheet or on a
+	// closed project stay put
+	_, _, err := authenticateUser(r, cfg.secret)
+	if err != nil {
+		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+		return
+	}
+
+	sessionID, err := uuid.Parse(r.PathValue("sessionid"))
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+
+	previous, err := cfg.db.GetSession(r.Context(), sessionID)
+	if err != nil {
+		respondWithError(w, "Session not found", http.StatusNotFound, err)
+		return
+	}
+	if !cfg.requireSessionLogging(w, r, previous.ProjectID) {
+		return
+	}
+	if sessionIsLocked(previous.Status) {
+		respondWithError(w, "Session is locked by its timesheet", http.StatusConflict, nil)
+		return
+	}
+	err = cfg.checkProjectAcceptsWork(r.Context(), previous.ProjectID)
+	if err != nil {
+		respondWithError(w, err.Error(), http.StatusConflict, err)
+		return
+	}
+
+	session, err := cfg.db.SoftDeleteSession(r.Context(), sessionID)
+	if err != nil {
+		respondWithError(w, "Session not found", http.StatusNotFound, err)
+		return
 	}
 
-	err = respondWithJSON(w, http.StatusOK, finalList)
+	err = respondWithJSON(w, http.StatusAccepted, session)
 	if err != nil {
 		respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
 		return
@@ -0,0 +1,324 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/Denisowiec/FoleyBookkeeper/internal/db"
+	"github.com/google/uuid"
+)
+
+func weekStart(t time.Time) time.Time {
+	// Timesheets cover weeks from Monday to Sunday
+	date := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	offset := (int(date.Weekday()) + 6) % 7
+	return date.AddDate(0, 0, -offset)
+}
+
+func sessionIsLocked(status db.ApprovalStatus) bool {
+	// Sessions waiting for a review or already approved can't be changed
+	return status == db.ApprovalStatusSubmitted || status == db.ApprovalStatusApproved
+}
+
+func canReadTimesheet(user db.User, timesheet db.Timesheet) bool {
+	return timesheet.UserID == user.ID || roleAllows(user.Role, permTimesheetsReview)
+}
+
+func (cfg *apiConfig) handlerSubmitTimesheet(w http.ResponseWriter, r *http.Request) {
+	// Submits the logged in user's draft and rejected sessions of a week for approval
+	// Takes any date within the week, the current week if none is given
+	userID, _, err := authenticateUser(r, cfg.secret)
+	if err != nil {
+		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+		return
+	}
+
+	submitInput := struct {
+		Week string `json:"week"`
+	}{}
+
+	decoder := json.NewDecoder(r.Body)
+	err = decoder.Decode(&submitInput)
+	if err != nil && err != io.EOF {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+
+	date := time.Now()
+	if submitInput.Week != "" {
+		date, err = time.Parse(time.DateOnly, submitInput.Week)
+		if err != nil {
+			respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+			return
+		}
+	}
+	start := weekStart(date)
+
+	// The timesheet and its sessions change together
+	tx, err := cfg.conn.BeginTx(r.Context(), nil)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+	defer tx.Rollback()
+	qtx := cfg.db.WithTx(tx)
+
+	submitParams := db.SubmitTimesheetParams{
+		UserID:    userID,
+		WeekStart: start,
+	}
+	timesheet, err := qtx.SubmitTimesheet(r.Context(), submitParams)
+	// Submitted and approved weeks stay as they are until they're rejected
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, "Timesheet for this week is already submitted or approved", http.StatusConflict, err)
+		return
+	}
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+
+	attachParams := db.AttachSessionsToTimesheetParams{
+		TimesheetID: uuid.NullUUID{UUID: timesheet.ID, Valid: true},
+		WeekStart:   start,
+		WeekEnd:     start.AddDate(0, 0, 7),
+		UserID:      userID,
+	}
+	sessions, err := qtx.AttachSessionsToTimesheet(r.Context(), attachParams)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+	if len(sessions) == 0 {
+		respondWithError(w, "No sessions to submit for this week", http.StatusBadRequest, nil)
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+
+	submitResp := struct {
+		db.Timesheet
+		Sessions []db.Session `json:"sessions"`
+	}{
+		Timesheet: timesheet,
+		Sessions:  sessions,
+	}
+
+	err = respondWithJSON(w, http.StatusCreated, submitResp)
+	if err != nil {
+		respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
+		return
+	}
+}
+
+func (cfg *apiConfig) handlerGetTimesheets(w http.ResponseWriter, r *http.Request) {
+	// Lists the logged in user's timesheets, newest first
+	userID, _, err := authenticateUser(r, cfg.secret)
+	if err != nil {
+		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+		return
+	}
+
+	list, err := cfg.db.GetTimesheetsForUser(r.Context(), userID)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+
+	err = respondWithJSON(w, http.StatusOK, list)
+	if err != nil {
+		respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
+		return
+	}
+}
+
+func (cfg *apiConfig) handlerGetPendingTimesheets(w http.ResponseWriter, r *http.Request) {
+	// Lists the timesheets waiting for approval, oldest submissions first
+	_, _, err := authenticateUser(r, cfg.secret)
+	if err != nil {
+		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+		return
+	}
+
+	list, err := cfg.db.GetPendingTimesheets(r.Context())
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+
+	err = respondWithJSON(w, http.StatusOK, list)
+	if err != nil {
+		respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
+		return
+	}
+}
+
+func (cfg *apiConfig) handlerGetTimesheet(w http.ResponseWriter, r *http.Request) {
+	// Timesheets are seen by their owners and by those who review them
+	user, err := requestUser(r)
+	if err != nil {
+		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+		return
+	}
+
+	timesheetID, err := uuid.Parse(r.PathValue("timesheetid"))
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+
+	timesheet, err := cfg.db.GetTimesheet(r.Context(), timesheetID)
+	if err != nil {
+		respondWithError(w, "Timesheet not found", http.StatusNotFound, err)
+		return
+	}
+	if !canReadTimesheet(user, timesheet) {
+		respondWithError(w, "Timesheet not found", http.StatusNotFound, nil)
+		return
+	}
+
+	sessions, err := cfg.db.GetSessionsForTimesheet(r.Context(), uuid.NullUUID{UUID: timesheet.ID, Valid: true})
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+	usersBySession, err := cfg.getUsersForSessions(r.Context(), sessions)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+
+	type sessionItem struct {
+		db.Session
+		Users []db.GetUsersForSessionRow `json:"users"`
+	}
+	timesheetResp := struct {
+		db.Timesheet
+		Sessions []sessionItem `json:"sessions"`
+	}{
+		Timesheet: timesheet,
+		Sessions:  []sessionItem{},
+	}
+	for _, s := range sessions {
+		timesheetResp.Sessions = append(timesheetResp.Sessions, sessionItem{Session: s, Users: usersBySession[s.ID]})
+	}
+
+	err = respondWithJSON(w, http.StatusOK, timesheetResp)
+	if err != nil {
+		respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
+		return
+	}
+}
+
+func (cfg *apiConfig) handlerApproveTimesheet(w http.ResponseWriter, r *http.Request) {
+	cfg.reviewTimesheet(w, r, db.ApprovalStatusApproved)
+}
+
+func (cfg *apiConfig) handlerRejectTimesheet(w http.ResponseWriter, r *http.Request) {
+	cfg.reviewTimesheet(w, r, db.ApprovalStatusRejected)
+}
+
+func (cfg *apiConfig) reviewTimesheet(w http.ResponseWriter, r *http.Request, status db.ApprovalStatus) {
+	// Approves or rejects a submitted timesheet together with its sessions.
+	// Approved sessions get locked and start counting in calculations,
+	// rejected ones go back to their users with the comment
+	reviewerID, _, err := authenticateUser(r, cfg.secret)
+	if err != nil {
+		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+		return
+	}
+
+	timesheetID, err := uuid.Parse(r.PathValue("timesheetid"))
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+
+	reviewInput := struct {
+		Comment string `json:"comment"`
+	}{}
+
+	decoder := json.NewDecoder(r.Body)
+	err = decoder.Decode(&reviewInput)
+	if err != nil && err != io.EOF {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+	if status == db.ApprovalStatusRejected && reviewInput.Comment == "" {
+		respondWithError(w, "Rejections need a comment", http.StatusBadRequest, nil)
+		return
+	}
+
+	timesheet, err := cfg.db.GetTimesheet(r.Context(), timesheetID)
+	if err != nil {
+		respondWithError(w, "Timesheet not found", http.StatusNotFound, err)
+		return
+	}
+	if timesheet.UserID == reviewerID {
+		respondWithError(w, "Users can't review their own timesheets", http.StatusForbidden, nil)
+		return
+	}
+	if timesheet.Status != db.ApprovalStatusSubmitted {
+		respondWithError(w, "Timesheet isn't waiting for a review", http.StatusConflict, nil)
+		return
+	}
+
+	tx, err := cfg.conn.BeginTx(r.Context(), nil)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+	defer tx.Rollback()
+	qtx := cfg.db.WithTx(tx)
+
+	reviewParams := db.ReviewTimesheetParams{
+		ID:            timesheet.ID,
+		Status:        status,
+		ReviewedBy:    uuid.NullUUID{UUID: reviewerID, Valid: true},
+		ReviewComment: sql.NullString{String: reviewInput.Comment, Valid: reviewInput.Comment != ""},
+	}
+	timesheet, err = qtx.ReviewTimesheet(r.Context(), reviewParams)
+	if err != nil {
+		// Someone else reviewed it in the meantime
+		respondWithError(w, "Timesheet isn't waiting for a review", http.StatusConflict, err)
+		return
+	}
+
+	statusParams := db.SetTimesheetSessionsStatusParams{
+		TimesheetID: uuid.NullUUID{UUID: timesheet.ID, Valid: true},
+		Status:      status,
+	}
+	sessions, err := qtx.SetTimesheetSessionsStatus(r.Context(), statusParams)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+
+	reviewResp := struct {
+		db.Timesheet
+		Sessions []db.Session `json:"sessions"`
+	}{
+		Timesheet: timesheet,
+		Sessions:  sessions,
+	}
+
+	err = respondWithJSON(w, http.StatusAccepted, reviewResp)
+	if err != nil {
+		respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
+		return
+	}
+}
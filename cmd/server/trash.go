@@ -0,0 +1,283 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/Denisowiec/FoleyBookkeeper/internal/db"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// Days deleted items stay in the trash before the purge removes them for good
+const defaultTrashRetentionDays = 30
+
+var trashKinds = []string{"client", "project", "episode", "session", "calculation"}
+
+var errParentDeleted = errors.New("the item it belongs to is in the trash, restore that first")
+
+func parseTrashRetention(input string) (int32, error) {
+	// Set with TRASH_RETENTION_DAYS, 0 purges deleted items on the next run
+	if input == "" {
+		return defaultTrashRetentionDays, nil
+	}
+	days, err := strconv.Atoi(input)
+	if err != nil || days < 0 {
+		return 0, fmt.Errorf("invalid number of days %s", input)
+	}
+	return int32(days), nil
+}
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
+
+func softDeleteProjectContents(ctx context.Context, q *db.Queries, projectID uuid.UUID) error {
+	// Called in the transaction that deleted the project, so the contents
+	// get the same deleted_at as the project itself
+	err := q.SoftDeleteEpisodesForProject(ctx, projectID)
+	if err != nil {
+		return err
+	}
+	err = q.SoftDeleteSessionsForProject(ctx, projectID)
+	if err != nil {
+		return err
+	}
+	return q.SoftDeleteCalculationsForProject(ctx, projectID)
+}
+
+func restoreProjectContents(ctx context.Context, q *db.Queries, projectID uuid.UUID) error {
+	// Has to run before the project itself is restored, as it matches the
+	// contents by the project's deleted_at
+	err := q.RestoreEpisodesForProject(ctx, projectID)
+	if err != nil {
+		return err
+	}
+	err = q.RestoreSessionsForProject(ctx, projectID)
+	if err != nil {
+		return err
+	}
+	return q.RestoreCalculationsForProject(ctx, projectID)
+}
+
+func restoreItem(ctx context.Context, q *db.Queries, kind string, id uuid.UUID) (any, error) {
+	// Restores an item with everything that was deleted together with it.
+	// Items whose parent is still in the trash can't be restored
+	switch kind {
+	case "client":
+		projects, err := q.GetDeletedProjectsForClient(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range projects {
+			err = restoreProjectContents(ctx, q, p.ID)
+			if err != nil {
+				return nil, err
+			}
+			_, err = q.RestoreProject(ctx, p.ID)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return q.RestoreClient(ctx, id)
+	case "project":
+		err := restoreProjectContents(ctx, q, id)
+		if err != nil {
+			return nil, err
+		}
+		prj, err := q.RestoreProject(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		_, err = q.GetClientByID(ctx, prj.ClientID)
+		if err != nil {
+			return nil, errParentDeleted
+		}
+		return prj, nil
+	case "episode":
+		err := q.RestoreSessionsForEpisode(ctx, uuid.NullUUID{UUID: id, Valid: true})
+		if err != nil {
+			return nil, err
+		}
+		ep, err := q.RestoreEpisode(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		_, err = q.GetProjectByID(ctx, ep.ProjectID)
+		if err != nil {
+			return nil, errParentDeleted
+		}
+		return ep, nil
+	case "session":
+		session, err := q.RestoreSession(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		_, err = q.GetProjectByID(ctx, session.ProjectID)
+		if err != nil {
+			return nil, errParentDeleted
+		}
+		if session.EpisodeID.Valid {
+			_, err = q.GetEpisodeByID(ctx, session.EpisodeID.UUID)
+			if err != nil {
+				return nil, errParentDeleted
+			}
+		}
+		return session, nil
+	case "calculation":
+		calc, err := q.RestoreCalculation(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		_, err = q.GetProjectByID(ctx, calc.ProjectID)
+		if err != nil {
+			return nil, errParentDeleted
+		}
+		return calc, nil
+	}
+	return nil, fmt.Errorf("items of kind %s aren't kept in the trash", kind)
+}
+
+func (cfg *apiConfig) handlerGetTrash(w http.ResponseWriter, r *http.Request) {
+	// Lists the deleted items, newest first. Items deleted together with
+	// another one are counted as its dependents instead of listed
+	_, _, err := authenticateUser(r, cfg.secret)
+	if err != nil {
+		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+		return
+	}
+
+	list, err := cfg.db.GetTrash(r.Context())
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+
+	resp := struct {
+		RetentionDays int32            `json:"retention_days"`
+		Items         []db.GetTrashRow `json:"items"`
+	}{
+		RetentionDays: cfg.trashRetentionDays,
+		Items:         list,
+	}
+	err = respondWithJSON(w, http.StatusOK, resp)
+	if err != nil {
+		respondWithError(w, "Error processing response data", http.StatusInternalServerError, err)
+		return
+	}
+}
+
+func (cfg *apiConfig) handlerRestoreFromTrash(w http.ResponseWriter, r *http.Request) {
+	_, _, err := authenticateUser(r, cfg.secret)
+	if err != nil {
+		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
+		return
+	}
+
+	kind := r.PathValue("kind")
+	if !slices.Contains(trashKinds, kind) {
+		respondWithError(w, fmt.Sprintf("Items of kind %s aren't kept in the trash", kind), http.StatusBadRequest, nil)
+		return
+	}
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, "Error decoding user input", http.StatusBadRequest, err)
+		return
+	}
+
+	tx, err := cfg.conn.BeginTx(r.Context(), nil)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+	defer tx.Rollback()
+	qtx := cfg.db.WithTx(tx)
+
+	item, err := restoreItem(r.Context(), qtx, kind, id)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		respondWithError(w, fmt.Sprintf("No %s with this ID in the trash", kind), http.StatusNotFound, err)
+		return
+	case errors.Is(err, errParentDeleted):
+		respondWithError(w, fmt.Sprintf("Can't restore the %s, %s", kind, err), http.StatusConflict, err)
+		return
+	case isUniqueViolation(err):
+		respondWithError(w, fmt.Sprintf("Can't restore the %s, its name or number is taken", kind), http.StatusConflict, err)
+		return
+	case err != nil:
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+
+	err = respondWithJSON(w, http.StatusAccepted, item)
+	if err != nil {
+		respondWithError(w, "Error processing response data", http.StatusInternalServerError, err)
+		return
+	}
+}
+
+type purgeReport struct {
+	Clients      int `json:"clients"`
+	Projects     int `json:"projects"`
+	Episodes     int `json:"episodes"`
+	Sessions     int `json:"sessions"`
+	Calculations int `json:"calculations"`
+}
+
+func (cfg *apiConfig) purgeTrash(ctx context.Context) (purgeReport, error) {
+	// Permanently deletes the items that have been in the trash longer than
+	// the retention period. Children go first, as clients don't cascade
+	tx, err := cfg.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return purgeReport{}, err
+	}
+	defer tx.Rollback()
+	qtx := cfg.db.WithTx(tx)
+
+	report := purgeReport{}
+	steps := []struct {
+		purge func(context.Context, int32) ([]uuid.UUID, error)
+		count *int
+	}{
+		{qtx.PurgeSessions, &report.Sessions},
+		{qtx.PurgeCalculations, &report.Calculations},
+		{qtx.PurgeEpisodes, &report.Episodes},
+		{qtx.PurgeProjects, &report.Projects},
+		{qtx.PurgeClients, &report.Clients},
+	}
+	for _, step := range steps {
+		ids, err := step.purge(ctx, cfg.trashRetentionDays)
+		if err != nil {
+			return purgeReport{}, err
+		}
+		*step.count = len(ids)
+	}
+	return report, tx.Commit()
+}
+
+func (cfg *apiConfig) runTrashPurge(interval time.Duration) {
+	// Runs in the background for as long as the server does
+	for {
+		report, err := cfg.purgeTrash(context.Background())
+		if err != nil {
+			log.Println("Error purging the trash", err)
+		} else if report != (purgeReport{}) {
+			log.Printf("Purged from the trash: %+v\n", report)
+		}
+		time.Sleep(interval)
+	}
+}
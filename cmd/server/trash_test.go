@@ -0,0 +1,34 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/lib/pq"
+)
+
+func TestParseTrashRetention(t *testing.T) {
+	days, err := parseTrashRetention("")
+	if err != nil || days != defaultTrashRetentionDays {
+		t.Errorf("unset retention should default to %d days, got %d, %v", defaultTrashRetentionDays, days, err)
+	}
+	days, err = parseTrashRetention("0")
+	if err != nil || days != 0 {
+		t.Errorf("retention of 0 days should be allowed, got %d, %v", days, err)
+	}
+	for _, input := range []string{"-1", "week", "1.5"} {
+		_, err = parseTrashRetention(input)
+		if err == nil {
+			t.Errorf("retention %q should be rejected", input)
+		}
+	}
+}
+
+func TestIsUniqueViolation(t *testing.T) {
+	if !isUniqueViolation(&pq.Error{Code: "23505"}) {
+		t.Error("unique violations should be recognized")
+	}
+	if isUniqueViolation(&pq.Error{Code: "23503"}) || isUniqueViolation(errors.New("other")) || isUniqueViolation(nil) {
+		t.Error("other errors aren't unique violations")
+	}
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"encoding/json"
+	"fmt"
 	"io"
 	"log"
 	"net/http"
@@ -70,9 +71,30 @@ func (cfg *apiConfig) handlerCreateUser(w http.ResponseWriter, r *http.Request)
 		HashedPassword: hashedPassword,
 	}
 
-	createdUser, err := cfg.db.CreateUser(r.Context(), createUserParams)
+	// The first user becomes an admin. The table is locked while the user is
+	// created, so concurrent sign-ups can't both take that role
+	tx, err := cfg.conn.BeginTx(r.Context(), nil)
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+	defer tx.Rollback()
+	qtx := cfg.db.WithTx(tx)
+
+	err = qtx.LockUsersForSignup(r.Context())
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
+	}
+	createdUser, err := qtx.CreateUser(r.Context(), createUserParams)
 	if err != nil {
 		respondWithError(w, "Error creating user", http.StatusInternalServerError, err)
+		return
+	}
+	err = tx.Commit()
+	if err != nil {
+		respondWithError(w, "Error contacting database", http.StatusInternalServerError, err)
+		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -80,11 +102,12 @@ func (cfg *apiConfig) handlerCreateUser(w http.ResponseWriter, r *http.Request)
 
 	// We respond with all the user's data, except the password hash
 	type createUserResponse struct {
-		ID        uuid.UUID `json:"id"`
-		CreatedAt time.Time `json:"created_at"`
-		UpdatedAt time.Time `json:"updated_at"`
-		Username  string    `json:"username"`
-		Email     string    `json:"email"`
+		ID        uuid.UUID   `json:"id"`
+		CreatedAt time.Time   `json:"created_at"`
+		UpdatedAt time.Time   `json:"updated_at"`
+		Username  string      `json:"username"`
+		Email     string      `json:"email"`
+		Role      db.UserRole `json:"role"`
 	}
 	resp := createUserResponse{
 		ID:        createdUser.ID,
@@ -92,6 +115,7 @@ func (cfg *apiConfig) handlerCreateUser(w http.ResponseWriter, r *http.Request)
 		UpdatedAt: createdUser.UpdatedAt,
 		Username:  createdUser.Username,
 		Email:     createdUser.Email,
+		Role:      createdUser.Role,
 	}
 
 	err = respondWithJSON(w, http.StatusCreated, resp)
@@ -102,9 +126,12 @@ func (cfg *apiConfig) handlerCreateUser(w http.ResponseWriter, r *http.Request)
 }
 
 func (cfg *apiConfig) handlerLogin(w http.ResponseWriter, r *http.Request) {
+	// The device is an optional label, like "studio laptop", shown when
+	// the user lists their logins
 	type userInputType struct {
 		Email    string `json:"email"`
 		Password string `json:"password"`
+		Device   string `json:"device"`
 	}
 
 	decoder := json.NewDecoder(r.Body)
@@ -122,6 +149,10 @@ func (cfg *apiConfig) handlerLogin(w http.ResponseWriter, r *http.Request) {
 		respondWithError(w, "Password required", http.StatusBadRequest, nil)
 		return
 	}
+	if len(userInput.Device) > maxDeviceLabelLength {
+		respondWithError(w, fmt.Sprintf("Device label can be at most %d characters long", maxDeviceLabelLength), http.StatusBadRequest, nil)
+		return
+	}
 
 	user, err := cfg.db.GetUserByEmail(r.Context(), userInput.Email)
 	if err != nil {
@@ -157,6 +188,11 @@ func (cfg *apiConfig) handlerLogin(w http.ResponseWriter, r *http.Request) {
 		Token:     refToken,
 		UserID:    user.ID,
 		ExpiresAt: refTokenExpirationDate,
+		// Every login starts a new family of rotated tokens
+		FamilyID:    uuid.New(),
+		DeviceLabel: nullString(userInput.Device),
+		UserAgent:   nullString(r.UserAgent()),
+		IpAddress:   nullString(requestIP(r)),
 	}
 	_, err = cfg.db.SetRefToken(r.Context(), setRefTokenParams)
 	if err != nil {
@@ -166,13 +202,14 @@ func (cfg *apiConfig) handlerLogin(w http.ResponseWriter, r *http.Request) {
 
 	// User information is sent back as a response to a successful login attempt
 	type loginResponseType struct {
-		ID           uuid.UUID `json:"id"`
-		CreatedAt    time.Time `json:"created_at"`
-		UpdatedAt    time.Time `json:"updated_at"`
-		Username     string    `json:"username"`
-		Email        string    `json:"email"`
-		JWT          string    `json:"jwt"`
-		RefreshToken string    `json:"refresh_token"`
+		ID           uuid.UUID   `json:"id"`
+		CreatedAt    time.Time   `json:"created_at"`
+		UpdatedAt    time.Time   `json:"updated_at"`
+		Username     string      `json:"username"`
+		Email        string      `json:"email"`
+		Role         db.UserRole `json:"role"`
+		JWT          string      `json:"jwt"`
+		RefreshToken string      `json:"refresh_token"`
 	}
 	loginResponse := loginResponseType{
 		ID:           user.ID,
@@ -180,6 +217,7 @@ func (cfg *apiConfig) handlerLogin(w http.ResponseWriter, r *http.Request) {
 		UpdatedAt:    user.UpdatedAt,
 		Username:     user.Username,
 		Email:        user.Email,
+		Role:         user.Role,
 		JWT:          jwt,
 		RefreshToken: refToken,
 	}
@@ -275,11 +313,12 @@ func (cfg *apiConfig) handlerUpdateUserSelf(w http.ResponseWriter, r *http.Reque
 
 	// We respond with all the user's data, except the password hash
 	type updateUserResponse struct {
-		ID        uuid.UUID `json:"id"`
-		CreatedAt time.Time `json:"created_at"`
-		UpdatedAt time.Time `json:"updated_at"`
-		Username  string    `json:"username"`
-		Email     string    `json:"email"`
+		ID        uuid.UUID   `json:"id"`
+		CreatedAt time.Time   `json:"created_at"`
+		UpdatedAt time.Time   `json:"updated_at"`
+		Username  string      `json:"username"`
+		Email     string      `json:"email"`
+		Role      db.UserRole `json:"role"`
 	}
 	resp := updateUserResponse{
 		ID:        newUser.ID,
@@ -287,6 +326,7 @@ func (cfg *apiConfig) handlerUpdateUserSelf(w http.ResponseWriter, r *http.Reque
 		UpdatedAt: newUser.UpdatedAt,
 		Username:  newUser.Username,
 		Email:     newUser.Email,
+		Role:      newUser.Role,
 	}
 
 	err = respondWithJSON(w, http.StatusAccepted, resp)
@@ -306,48 +346,6 @@ func (cfg *apiConfig) handlerUpdateUserSelf(w http.ResponseWriter, r *http.Reque
 	w.Write(dat)
 }
 
-func (cfg *apiConfig) handlerRefreshToken(w http.ResponseWriter, r *http.Request) {
-	// This function generates a new JWT as long as there's a non-revoked refresh
-	// token in the database
-	userID, inputToken, err := authenticateUser(r, cfg.secret)
-	if err != nil {
-		respondWithError(w, "Operation unauthorized", http.StatusUnauthorized, err)
-		return
-	}
-
-	// We fetch the old token from the db. This also makes us sure, the user exists
-	// since otherwise all of the user's refresh tokens would get deleted
-	oldtoken, err := cfg.db.GetRefToken(r.Context(), inputToken)
-	if err != nil {
-		respondWithError(w, "Token invalid", http.StatusUnauthorized, err)
-		return
-	}
-
-	// If RevokedAt is not null, the token was revoked
-	if oldtoken.RevokedAt.Valid {
-		respondWithError(w, "Refresh token revoked", http.StatusUnauthorized, nil)
-		return
-	}
-
-	// If it's not revoked, we generate a JWT
-	jwt, err := auth.MakeJWT(userID, cfg.secret, cfg.jwtExpirationTime)
-	if err != nil {
-		respondWithError(w, "Error generating a JWT", http.StatusInternalServerError, err)
-		return
-	}
-
-	var respBody struct {
-		JWT string `json:"jwt"`
-	}
-	respBody.JWT = jwt
-
-	err = respondWithJSON(w, http.StatusOK, respBody)
-	if err != nil {
-		respondWithError(w, "Error processing user response", http.StatusInternalServerError, err)
-		return
-	}
-}
-
 func (cfg *apiConfig) handlerGetUser(w http.ResponseWriter, r *http.Request) {
 	// Function handling fetching individual user's information from the database
 	_, _, err := authenticateUser(r, cfg.secret)
@@ -371,11 +369,12 @@ func (cfg *apiConfig) handlerGetUser(w http.ResponseWriter, r *http.Request) {
 
 	// We send back all of the user's information, sans the password hash
 	type getUserResponseType struct {
-		ID        uuid.UUID `json:"id"`
-		CreatedAt time.Time `json:"created_at"`
-		UpdatedAt time.Time `json:"updated_at"`
-		Username  string    `json:"username"`
-		Email     string    `json:"email"`
+		ID        uuid.UUID   `json:"id"`
+		CreatedAt time.Time   `json:"created_at"`
+		UpdatedAt time.Time   `json:"updated_at"`
+		Username  string      `json:"username"`
+		Email     string      `json:"email"`
+		Role      db.UserRole `json:"role"`
 	}
 	getUserResponse := getUserResponseType{
 		ID:        user.ID,
@@ -383,6 +382,7 @@ func (cfg *apiConfig) handlerGetUser(w http.ResponseWriter, r *http.Request) {
 		UpdatedAt: user.UpdatedAt,
 		Username:  user.Username,
 		Email:     user.Email,
+		Role:      user.Role,
 	}
 
 	err = respondWithJSON(w, http.StatusOK, getUserResponse)
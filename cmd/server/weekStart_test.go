@@ -0,0 +1,25 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWeekStart(t *testing.T) {
+	monday := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	cases := []time.Time{
+		time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 3, 6, 15, 30, 0, 0, time.UTC),
+		time.Date(2024, 3, 10, 23, 59, 0, 0, time.UTC),
+	}
+	for _, c := range cases {
+		if !weekStart(c).Equal(monday) {
+			t.Errorf("week of %s starts on %s, expected %s", c, weekStart(c), monday)
+		}
+	}
+
+	nextMonday := time.Date(2024, 3, 11, 8, 0, 0, 0, time.UTC)
+	if weekStart(nextMonday).Equal(monday) {
+		t.Errorf("%s belongs to the next week", nextMonday)
+	}
+}
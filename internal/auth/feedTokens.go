@@ -0,0 +1,14 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// Feed tokens end up in calendar subscription URLs, so they are the only
+// thing protecting a feed. They're as long as refresh tokens.
+func MakeFeedToken() string {
+	key := make([]byte, 32)
+	rand.Read(key)
+	return hex.EncodeToString(key)
+}
@@ -0,0 +1,108 @@
+// Package cuesheet writes foley cue sheets as CSV, Avid ALE, printable
+// HTML and PDF. Cues arrive already sorted and grouped, formatting is all
+// this package does.
+package cuesheet
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+)
+
+type Cue struct {
+	Number      string
+	Reel        string
+	TimecodeIn  string
+	TimecodeOut string
+	Duration    string
+	Part        string
+	Description string
+	Status      string
+}
+
+// Group is a run of cues under a common heading, e.g. all footsteps cues.
+// Sheets that aren't grouped have a single group without a name
+type Group struct {
+	Name string
+	Cues []Cue
+}
+
+type Sheet struct {
+	Title     string
+	Details   []string
+	FrameRate string
+	Groups    []Group
+}
+
+var header = []string{"Cue", "Reel", "In", "Out", "Duration", "Part", "Description", "Status"}
+
+func (c Cue) fields() []string {
+	return []string{c.Number, c.Reel, c.TimecodeIn, c.TimecodeOut, c.Duration, c.Part, c.Description, c.Status}
+}
+
+// WriteCSV writes one row per cue. Groups only show in the order of the rows
+func WriteCSV(w io.Writer, sheet Sheet) error {
+	writer := csv.NewWriter(w)
+	err := writer.Write(header)
+	if err != nil {
+		return err
+	}
+	for _, g := range sheet.Groups {
+		for _, c := range g.Cues {
+			err = writer.Write(c.fields())
+			if err != nil {
+				return err
+			}
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func aleValue(s string) string {
+	// Tabs and line breaks would break the columns apart
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// WriteALE writes an Avid Log Exchange file, so the cues can be brought
+// into a bin as subclips or markers
+func WriteALE(w io.Writer, sheet Sheet) error {
+	lines := []string{
+		"Heading",
+		"FIELD_DELIM\tTABS",
+		"VIDEO_FORMAT\t1080",
+		"AUDIO_FORMAT\t48khz",
+		"FPS\t" + strings.TrimSuffix(sheet.FrameRate, "df"),
+		"",
+		"Column",
+		"Name\tTracks\tStart\tEnd\tTape\tPart\tComments\tStatus",
+		"",
+		"Data",
+	}
+	for _, g := range sheet.Groups {
+		for _, c := range g.Cues {
+			row := []string{c.Number, "A1", c.TimecodeIn, c.TimecodeOut, c.Reel, c.Part, c.Description, c.Status}
+			for i := range row {
+				row[i] = aleValue(row[i])
+			}
+			lines = append(lines, strings.Join(row, "\t"))
+		}
+	}
+	// Avid expects CRLF line endings
+	_, err := io.WriteString(w, strings.Join(lines, "\r\n")+"\r\n")
+	return err
+}
+
+// Count returns the number of cues in the sheet
+func (s Sheet) Count() int {
+	count := 0
+	for _, g := range s.Groups {
+		count += len(g.Cues)
+	}
+	return count
+}
+
+func (s Sheet) summary() string {
+	return fmt.Sprintf("%d cues at %s fps", s.Count(), s.FrameRate)
+}
@@ -0,0 +1,98 @@
+package cuesheet
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+var testSheet = Sheet{
+	Title:     "ProjectX, episode 5",
+	Details:   []string{"Reel R1"},
+	FrameRate: "23.976",
+	Groups: []Group{
+		{Name: "Footsteps", Cues: []Cue{
+			{Number: "F101", Reel: "R1", TimecodeIn: "01:00:01:12", TimecodeOut: "01:00:03:04", Duration: "00:00:01:16",
+				Part: "Footsteps", Description: "Hero, leather\tshoes, gravel", Status: "pending"},
+		}},
+		{Name: "Props", Cues: []Cue{
+			{Number: "F102", Reel: "R1", TimecodeIn: "01:00:05:00", TimecodeOut: "01:00:06:00", Duration: "00:00:01:00",
+				Part: "Props", Description: "Keys <jingle> (soft)", Status: "recorded"},
+		}},
+	},
+}
+
+func TestWriteCSV(t *testing.T) {
+	var out bytes.Buffer
+	err := WriteCSV(&out, testSheet)
+	if err != nil {
+		t.Fatalf("error writing CSV: %s", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 || lines[0] != "Cue,Reel,In,Out,Duration,Part,Description,Status" {
+		t.Fatalf("CSV written as %q", out.String())
+	}
+	if !strings.Contains(lines[1], `"Hero, leather`) {
+		t.Errorf("description with a comma not quoted: %s", lines[1])
+	}
+}
+
+func TestWriteALE(t *testing.T) {
+	var out bytes.Buffer
+	err := WriteALE(&out, testSheet)
+	if err != nil {
+		t.Fatalf("error writing ALE: %s", err)
+	}
+	lines := strings.Split(out.String(), "\r\n")
+	if lines[0] != "Heading" || lines[4] != "FPS\t23.976" || lines[9] != "Data" {
+		t.Fatalf("ALE written as %q", out.String())
+	}
+	row := strings.Split(lines[10], "\t")
+	if len(row) != 8 || row[0] != "F101" || row[6] != "Hero, leather shoes, gravel" {
+		t.Errorf("ALE row written as %q", lines[10])
+	}
+}
+
+func TestWriteHTML(t *testing.T) {
+	var out bytes.Buffer
+	err := WriteHTML(&out, testSheet)
+	if err != nil {
+		t.Fatalf("error writing HTML: %s", err)
+	}
+	if !strings.Contains(out.String(), "Keys &lt;jingle&gt;") || !strings.Contains(out.String(), "Footsteps (1)") {
+		t.Errorf("HTML written as %s", out.String())
+	}
+}
+
+func TestWritePDF(t *testing.T) {
+	// Enough cues to need several pages
+	sheet := Sheet{Title: "Long sheet", FrameRate: "25", Groups: []Group{{}}}
+	for i := 0; i < 100; i++ {
+		sheet.Groups[0].Cues = append(sheet.Groups[0].Cues, Cue{Number: fmt.Sprintf("F%d", i), Description: "Door (slam)"})
+	}
+
+	var out bytes.Buffer
+	err := WritePDF(&out, sheet)
+	if err != nil {
+		t.Fatalf("error writing PDF: %s", err)
+	}
+	pdf := out.String()
+	if !strings.HasPrefix(pdf, "%PDF-1.4") || !strings.HasSuffix(pdf, "%%EOF\n") {
+		t.Fatal("PDF header or trailer missing")
+	}
+	if !strings.Contains(pdf, "/Count 3") || !strings.Contains(pdf, `(Door \(slam\))`) {
+		t.Error("PDF pages or escaped text missing")
+	}
+
+	// Every cross-reference entry has to point at its object
+	xref := regexp.MustCompile(`(\d{10}) 00000 n`).FindAllStringSubmatch(pdf, -1)
+	for i, entry := range xref {
+		offset, _ := strconv.Atoi(entry[1])
+		if !strings.HasPrefix(pdf[offset:], fmt.Sprintf("%d 0 obj", i+1)) {
+			t.Errorf("cross-reference of object %d points at the wrong offset", i+1)
+		}
+	}
+}
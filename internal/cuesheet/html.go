@@ -0,0 +1,57 @@
+package cuesheet
+
+import (
+	"html/template"
+	"io"
+)
+
+var htmlTemplate = template.Must(template.New("cuesheet").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Sheet.Title}}</title>
+<style>
+body { font-family: Helvetica, Arial, sans-serif; font-size: 10pt; margin: 2em; }
+h1 { font-size: 16pt; margin-bottom: 0.2em; }
+p.details { margin: 0 0 1em 0; color: #444; }
+table { border-collapse: collapse; width: 100%; }
+th, td { border-bottom: 1px solid #ccc; padding: 3px 6px; text-align: left; vertical-align: top; }
+th { border-bottom: 2px solid #000; }
+td.tc { font-family: Menlo, Consolas, monospace; white-space: nowrap; }
+tr.group td { font-weight: bold; padding-top: 1em; border-bottom: 1px solid #000; }
+@page { size: landscape; margin: 1.5cm; }
+@media print { body { margin: 0; } thead { display: table-header-group; } tr { page-break-inside: avoid; } }
+</style>
+</head>
+<body>
+<h1>{{.Sheet.Title}}</h1>
+<p class="details">{{range .Sheet.Details}}{{.}}<br>{{end}}{{.Summary}}</p>
+<table>
+<thead><tr>{{range .Header}}<th>{{.}}</th>{{end}}</tr></thead>
+<tbody>
+{{- range .Sheet.Groups}}
+{{- if .Name}}
+<tr class="group"><td colspan="8">{{.Name}} ({{len .Cues}})</td></tr>
+{{- end}}
+{{- range .Cues}}
+<tr><td>{{.Number}}</td><td>{{.Reel}}</td><td class="tc">{{.TimecodeIn}}</td><td class="tc">{{.TimecodeOut}}</td><td class="tc">{{.Duration}}</td><td>{{.Part}}</td><td>{{.Description}}</td><td>{{.Status}}</td></tr>
+{{- end}}
+{{- end}}
+</tbody>
+</table>
+</body>
+</html>
+`))
+
+// WriteHTML writes a page meant to be printed, or saved as PDF from a browser
+func WriteHTML(w io.Writer, sheet Sheet) error {
+	return htmlTemplate.Execute(w, struct {
+		Sheet   Sheet
+		Header  []string
+		Summary string
+	}{
+		Sheet:   sheet,
+		Header:  header,
+		Summary: sheet.summary(),
+	})
+}
@@ -0,0 +1,179 @@
+package cuesheet
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// A4 landscape in points, with the built-in Helvetica fonts, so no fonts
+// have to be embedded
+const (
+	pageWidth    = 842
+	pageHeight   = 595
+	margin       = 40
+	fontSize     = 9
+	rowHeight    = 12
+	footerHeight = 30
+	// Average width of a Helvetica character relative to the font size,
+	// used to cut off text that wouldn't fit its column
+	charWidth = 0.5
+)
+
+// Column widths in points, in the order of the header
+var columnWidths = []float64{50, 40, 70, 70, 70, 70, 330, 62}
+
+func pdfString(s string) string {
+	// Strings are WinAnsi encoded, which covers Latin-1
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteRune('\\')
+			b.WriteRune(r)
+		case r < 32:
+			b.WriteByte(' ')
+		case r < 127:
+			b.WriteRune(r)
+		case r >= 160 && r < 256:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+func fitText(s string, width float64) string {
+	maxChars := int(width / (fontSize * charWidth))
+	runes := []rune(s)
+	if len(runes) <= maxChars {
+		return s
+	}
+	if maxChars < 2 {
+		return ""
+	}
+	return string(runes[:maxChars-1]) + "..."
+}
+
+type pdfPage struct {
+	content bytes.Buffer
+}
+
+func (p *pdfPage) text(font string, size float64, x, y float64, s string) {
+	fmt.Fprintf(&p.content, "BT /%s %.1f Tf %.1f %.1f Td (%s) Tj ET\n", font, size, x, y, pdfString(s))
+}
+
+func (p *pdfPage) line(x1, y1, x2, y2 float64) {
+	fmt.Fprintf(&p.content, "%.1f %.1f m %.1f %.1f l S\n", x1, y1, x2, y2)
+}
+
+func (p *pdfPage) row(font string, y float64, fields []string) {
+	x := float64(margin)
+	for i, f := range fields {
+		p.text(font, fontSize, x, y, fitText(f, columnWidths[i]-4))
+		x += columnWidths[i]
+	}
+}
+
+// pdfLayout places the sheet on pages, starting a new one when the
+// current one fills up
+type pdfLayout struct {
+	sheet Sheet
+	pages []*pdfPage
+	y     float64
+}
+
+func (l *pdfLayout) newPage() {
+	page := &pdfPage{}
+	l.pages = append(l.pages, page)
+	l.y = pageHeight - margin
+
+	if len(l.pages) == 1 {
+		l.y -= 16
+		page.text("F2", 16, margin, l.y, l.sheet.Title)
+		l.y -= 6
+		details := append(append([]string{}, l.sheet.Details...), l.sheet.summary())
+		for _, d := range details {
+			l.y -= rowHeight
+			page.text("F1", fontSize, margin, l.y, d)
+		}
+		l.y -= rowHeight
+	} else {
+		l.y -= rowHeight
+		page.text("F1", fontSize, margin, l.y, l.sheet.Title)
+		l.y -= rowHeight / 2
+	}
+
+	l.y -= rowHeight
+	page.row("F2", l.y, header)
+	page.line(margin, l.y-3, pageWidth-margin, l.y-3)
+	l.y -= 3
+}
+
+func (l *pdfLayout) next(lines int) *pdfPage {
+	// Returns the page to draw the next lines on
+	if len(l.pages) == 0 || l.y-float64(lines*rowHeight) < margin+footerHeight {
+		l.newPage()
+	}
+	return l.pages[len(l.pages)-1]
+}
+
+// WritePDF writes a printable cue sheet. Long descriptions are cut off,
+// the HTML sheet has them in full
+func WritePDF(w io.Writer, sheet Sheet) error {
+	layout := &pdfLayout{sheet: sheet}
+	layout.next(1)
+	for _, g := range sheet.Groups {
+		if g.Name != "" {
+			// A group heading never ends up alone at the bottom of a page
+			page := layout.next(2)
+			layout.y -= rowHeight * 1.5
+			page.text("F2", fontSize, margin, layout.y, fmt.Sprintf("%s (%d)", g.Name, len(g.Cues)))
+		}
+		for _, c := range g.Cues {
+			page := layout.next(1)
+			layout.y -= rowHeight
+			page.row("F1", layout.y, c.fields())
+		}
+	}
+	for i, page := range layout.pages {
+		footer := fmt.Sprintf("Page %d of %d", i+1, len(layout.pages))
+		page.text("F1", 8, pageWidth-margin-60, margin-10, footer)
+	}
+
+	// Objects 1 to 4 are the catalog, the page tree and the two fonts,
+	// then each page is followed by its content stream
+	var out bytes.Buffer
+	offsets := []int{}
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n")
+	kids := []string{}
+	for i := range layout.pages {
+		kids = append(kids, fmt.Sprintf("%d 0 R", 5+2*i))
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(kids)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	for i, page := range layout.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] "+
+			"/Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>", pageWidth, pageHeight, 6+2*i))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.content.Len(), page.content.String()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, o := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", o)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	_, err := w.Write(out.Bytes())
+	return err
+}
@@ -39,18 +39,48 @@ func (q *Queries) AddEpisodeToCalculation(ctx context.Context, arg AddEpisodeToC
 	return i, err
 }
 
+const addUnitToCalculation = `-- name: AddUnitToCalculation :one
+INSERT INTO unit_calc (
+    unit_id,
+    calc_id
+) VALUES (
+    $1,
+    $2
+) RETURNING id, created_at, updated_at, unit_id, calc_id
+`
+
+type AddUnitToCalculationParams struct {
+	UnitID uuid.UUID `json:"unit_id"`
+	CalcID uuid.UUID `json:"calc_id"`
+}
+
+func (q *Queries) AddUnitToCalculation(ctx context.Context, arg AddUnitToCalculationParams) (UnitCalc, error) {
+	row := q.db.QueryRowContext(ctx, addUnitToCalculation, arg.UnitID, arg.CalcID)
+	var i UnitCalc
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UnitID,
+		&i.CalcID,
+	)
+	return i, err
+}
+
 const createCalculation = `-- name: CreateCalculation :one
 INSERT INTO calculations (
     project_id,
     budget,
     currency,
-    exchange_rate
+    exchange_rate,
+    whole_project
 ) VALUES (
     $1,
     $2,
     $3,
-    $4
-) RETURNING id, created_at, updated_at, project_id, budget, currency, exchange_rate
+    $4,
+    $5
+) RETURNING id, created_at, updated_at, project_id, budget, currency, exchange_rate, boss_tribute, manager_commission, tax_rate, tax_multiplier, deleted_at, whole_project
 `
 
 type CreateCalculationParams struct {
@@ -58,6 +88,7 @@ type CreateCalculationParams struct {
 	Budget       string    `json:"budget"`
 	Currency     string    `json:"currency"`
 	ExchangeRate string    `json:"exchange_rate"`
+	WholeProject bool      `json:"whole_project"`
 }
 
 func (q *Queries) CreateCalculation(ctx context.Context, arg CreateCalculationParams) (Calculation, error) {
@@ -66,6 +97,7 @@ func (q *Queries) CreateCalculation(ctx context.Context, arg CreateCalculationPa
 		arg.Budget,
 		arg.Currency,
 		arg.ExchangeRate,
+		arg.WholeProject,
 	)
 	var i Calculation
 	err := row.Scan(
@@ -76,12 +108,18 @@ func (q *Queries) CreateCalculation(ctx context.Context, arg CreateCalculationPa
 		&i.Budget,
 		&i.Currency,
 		&i.ExchangeRate,
+		&i.BossTribute,
+		&i.ManagerCommission,
+		&i.TaxRate,
+		&i.TaxMultiplier,
+		&i.DeletedAt,
+		&i.WholeProject,
 	)
 	return i, err
 }
 
 const getAllCalculationsForProject = `-- name: GetAllCalculationsForProject :many
-SELECT id, created_at, updated_at, project_id, budget, currency, exchange_rate FROM calculations WHERE project_id = $1
+SELECT id, created_at, updated_at, project_id, budget, currency, exchange_rate, boss_tribute, manager_commission, tax_rate, tax_multiplier, deleted_at, whole_project FROM calculations WHERE project_id = $1 AND deleted_at IS NULL
 `
 
 func (q *Queries) GetAllCalculationsForProject(ctx context.Context, projectID uuid.UUID) ([]Calculation, error) {
@@ -101,6 +139,12 @@ func (q *Queries) GetAllCalculationsForProject(ctx context.Context, projectID uu
 			&i.Budget,
 			&i.Currency,
 			&i.ExchangeRate,
+			&i.BossTribute,
+			&i.ManagerCommission,
+			&i.TaxRate,
+			&i.TaxMultiplier,
+			&i.DeletedAt,
+			&i.WholeProject,
 		); err != nil {
 			return nil, err
 		}
@@ -116,7 +160,7 @@ func (q *Queries) GetAllCalculationsForProject(ctx context.Context, projectID uu
 }
 
 const getCalculation = `-- name: GetCalculation :one
-SELECT id, created_at, updated_at, project_id, budget, currency, exchange_rate FROM calculations WHERE id = $1
+SELECT id, created_at, updated_at, project_id, budget, currency, exchange_rate, boss_tribute, manager_commission, tax_rate, tax_multiplier, deleted_at, whole_project FROM calculations WHERE id = $1 AND deleted_at IS NULL
 `
 
 func (q *Queries) GetCalculation(ctx context.Context, id uuid.UUID) (Calculation, error) {
@@ -130,6 +174,12 @@ func (q *Queries) GetCalculation(ctx context.Context, id uuid.UUID) (Calculation
 		&i.Budget,
 		&i.Currency,
 		&i.ExchangeRate,
+		&i.BossTribute,
+		&i.ManagerCommission,
+		&i.TaxRate,
+		&i.TaxMultiplier,
+		&i.DeletedAt,
+		&i.WholeProject,
 	)
 	return i, err
 }
@@ -162,9 +212,23 @@ func (q *Queries) GetEpisodesForCalculation(ctx context.Context, calcID uuid.UUI
 }
 
 const getMinutesForCalculation = `-- name: GetMinutesForCalculation :one
-SELECT SUM(sessions.duration) AS minutes FROM sessions
-JOIN episode_calc ON episode_calc.episode_id = sessions.episode_id
-WHERE episode_calc.calc_id = $1
+WITH RECURSIVE calc_units AS (
+    SELECT unit_calc.unit_id AS id FROM unit_calc WHERE unit_calc.calc_id = $1
+    UNION
+    SELECT project_units.id FROM project_units JOIN calc_units ON project_units.parent_id = calc_units.id
+)
+SELECT COALESCE(ROUND(SUM(
+    sessions.duration * COALESCE(parts.billing_weight, 1) * COALESCE(activities.billing_weight, 1)
+)), 0)::bigint AS minutes FROM sessions
+JOIN calculations ON calculations.id = $1 AND calculations.project_id = sessions.project_id
+JOIN parts ON parts.code = sessions.part_worked_on
+JOIN activities ON activities.code = sessions.activity_done
+WHERE sessions.status = 'approved' AND sessions.deleted_at IS NULL AND (
+    sessions.episode_id IN (SELECT episode_calc.episode_id FROM episode_calc WHERE episode_calc.calc_id = $1)
+    OR sessions.unit_id IN (SELECT calc_units.id FROM calc_units)
+    OR sessions.episode_id IN (SELECT episodes.id FROM episodes WHERE episodes.season_id IN (SELECT calc_units.id FROM calc_units))
+    OR calculations.whole_project
+)
 `
 
 func (q *Queries) GetMinutesForCalculation(ctx context.Context, calcID uuid.UUID) (int64, error) {
@@ -174,6 +238,33 @@ func (q *Queries) GetMinutesForCalculation(ctx context.Context, calcID uuid.UUID
 	return minutes, err
 }
 
+const getUnitsForCalculation = `-- name: GetUnitsForCalculation :many
+SELECT unit_id FROM unit_calc WHERE calc_id = $1
+`
+
+func (q *Queries) GetUnitsForCalculation(ctx context.Context, calcID uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := q.db.QueryContext(ctx, getUnitsForCalculation, calcID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var unit_id uuid.UUID
+		if err := rows.Scan(&unit_id); err != nil {
+			return nil, err
+		}
+		items = append(items, unit_id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const removeEpisodeFromCalculation = `-- name: RemoveEpisodeFromCalculation :one
 DELETE FROM episode_calc WHERE calc_id = $1 AND episode_id = $2 RETURNING id, created_at, updated_at, episode_id, calc_id
 `
@@ -203,7 +294,7 @@ UPDATE calculations SET
     currency = $4,
     exchange_rate = $5,
     updated_at = NOW()
-WHERE id = $1 RETURNING id, created_at, updated_at, project_id, budget, currency, exchange_rate
+WHERE id = $1 AND deleted_at IS NULL RETURNING id, created_at, updated_at, project_id, budget, currency, exchange_rate, boss_tribute, manager_commission, tax_rate, tax_multiplier, deleted_at, whole_project
 `
 
 type UpdateCalculationParams struct {
@@ -231,6 +322,12 @@ func (q *Queries) UpdateCalculation(ctx context.Context, arg UpdateCalculationPa
 		&i.Budget,
 		&i.Currency,
 		&i.ExchangeRate,
+		&i.BossTribute,
+		&i.ManagerCommission,
+		&i.TaxRate,
+		&i.TaxMultiplier,
+		&i.DeletedAt,
+		&i.WholeProject,
 	)
 	return i, err
 }
@@ -21,7 +21,7 @@ INSERT INTO clients (
     $1,
     $2,
     $3
-) RETURNING id, created_at, updated_at, client_name, email, notes
+) RETURNING id, created_at, updated_at, client_name, email, notes, tax_id, default_currency, payment_terms_days, invoice_language, deleted_at
 `
 
 type CreateClientParams struct {
@@ -40,12 +40,63 @@ func (q *Queries) CreateClient(ctx context.Context, arg CreateClientParams) (Cli
 		&i.ClientName,
 		&i.Email,
 		&i.Notes,
+		&i.TaxID,
+		&i.DefaultCurrency,
+		&i.PaymentTermsDays,
+		&i.InvoiceLanguage,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const createClientContact = `-- name: CreateClientContact :one
+INSERT INTO client_contacts (
+    client_id,
+    name,
+    role,
+    email,
+    phone
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5
+) RETURNING id, created_at, updated_at, client_id, name, role, email, phone
+`
+
+type CreateClientContactParams struct {
+	ClientID uuid.UUID      `json:"client_id"`
+	Name     string         `json:"name"`
+	Role     sql.NullString `json:"role"`
+	Email    sql.NullString `json:"email"`
+	Phone    sql.NullString `json:"phone"`
+}
+
+func (q *Queries) CreateClientContact(ctx context.Context, arg CreateClientContactParams) (ClientContact, error) {
+	row := q.db.QueryRowContext(ctx, createClientContact,
+		arg.ClientID,
+		arg.Name,
+		arg.Role,
+		arg.Email,
+		arg.Phone,
+	)
+	var i ClientContact
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ClientID,
+		&i.Name,
+		&i.Role,
+		&i.Email,
+		&i.Phone,
 	)
 	return i, err
 }
 
 const deleteClient = `-- name: DeleteClient :one
-DELETE FROM clients WHERE id=$1 RETURNING id, created_at, updated_at, client_name, email, notes
+DELETE FROM clients WHERE id=$1 RETURNING id, created_at, updated_at, client_name, email, notes, tax_id, default_currency, payment_terms_days, invoice_language, deleted_at
 `
 
 func (q *Queries) DeleteClient(ctx context.Context, id uuid.UUID) (Client, error) {
@@ -58,12 +109,102 @@ func (q *Queries) DeleteClient(ctx context.Context, id uuid.UUID) (Client, error
 		&i.ClientName,
 		&i.Email,
 		&i.Notes,
+		&i.TaxID,
+		&i.DefaultCurrency,
+		&i.PaymentTermsDays,
+		&i.InvoiceLanguage,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const deleteClientAddress = `-- name: DeleteClientAddress :one
+DELETE FROM client_addresses WHERE client_id=$1 AND kind=$2 RETURNING id, created_at, updated_at, client_id, kind, line1, line2, postal_code, city, country
+`
+
+type DeleteClientAddressParams struct {
+	ClientID uuid.UUID   `json:"client_id"`
+	Kind     AddressKind `json:"kind"`
+}
+
+func (q *Queries) DeleteClientAddress(ctx context.Context, arg DeleteClientAddressParams) (ClientAddress, error) {
+	row := q.db.QueryRowContext(ctx, deleteClientAddress, arg.ClientID, arg.Kind)
+	var i ClientAddress
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ClientID,
+		&i.Kind,
+		&i.Line1,
+		&i.Line2,
+		&i.PostalCode,
+		&i.City,
+		&i.Country,
 	)
 	return i, err
 }
 
+const deleteClientContact = `-- name: DeleteClientContact :one
+DELETE FROM client_contacts WHERE id=$1 RETURNING id, created_at, updated_at, client_id, name, role, email, phone
+`
+
+func (q *Queries) DeleteClientContact(ctx context.Context, id uuid.UUID) (ClientContact, error) {
+	row := q.db.QueryRowContext(ctx, deleteClientContact, id)
+	var i ClientContact
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ClientID,
+		&i.Name,
+		&i.Role,
+		&i.Email,
+		&i.Phone,
+	)
+	return i, err
+}
+
+const getAddressesForClient = `-- name: GetAddressesForClient :many
+SELECT id, created_at, updated_at, client_id, kind, line1, line2, postal_code, city, country FROM client_addresses WHERE client_id=$1 ORDER BY kind ASC
+`
+
+func (q *Queries) GetAddressesForClient(ctx context.Context, clientID uuid.UUID) ([]ClientAddress, error) {
+	rows, err := q.db.QueryContext(ctx, getAddressesForClient, clientID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ClientAddress
+	for rows.Next() {
+		var i ClientAddress
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ClientID,
+			&i.Kind,
+			&i.Line1,
+			&i.Line2,
+			&i.PostalCode,
+			&i.City,
+			&i.Country,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getAllClients = `-- name: GetAllClients :many
-SELECT id, created_at, updated_at, client_name, email, notes FROM clients
+SELECT id, created_at, updated_at, client_name, email, notes, tax_id, default_currency, payment_terms_days, invoice_language, deleted_at FROM clients WHERE deleted_at IS NULL
 `
 
 func (q *Queries) GetAllClients(ctx context.Context) ([]Client, error) {
@@ -82,6 +223,11 @@ func (q *Queries) GetAllClients(ctx context.Context) ([]Client, error) {
 			&i.ClientName,
 			&i.Email,
 			&i.Notes,
+			&i.TaxID,
+			&i.DefaultCurrency,
+			&i.PaymentTermsDays,
+			&i.InvoiceLanguage,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -97,7 +243,7 @@ func (q *Queries) GetAllClients(ctx context.Context) ([]Client, error) {
 }
 
 const getClientByID = `-- name: GetClientByID :one
-SELECT id, created_at, updated_at, client_name, email, notes FROM clients WHERE id=$1
+SELECT id, created_at, updated_at, client_name, email, notes, tax_id, default_currency, payment_terms_days, invoice_language, deleted_at FROM clients WHERE id=$1 AND deleted_at IS NULL
 `
 
 func (q *Queries) GetClientByID(ctx context.Context, id uuid.UUID) (Client, error) {
@@ -110,12 +256,17 @@ func (q *Queries) GetClientByID(ctx context.Context, id uuid.UUID) (Client, erro
 		&i.ClientName,
 		&i.Email,
 		&i.Notes,
+		&i.TaxID,
+		&i.DefaultCurrency,
+		&i.PaymentTermsDays,
+		&i.InvoiceLanguage,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
 const getClientByName = `-- name: GetClientByName :one
-SELECT id, created_at, updated_at, client_name, email, notes FROM clients WHERE client_name=$1
+SELECT id, created_at, updated_at, client_name, email, notes, tax_id, default_currency, payment_terms_days, invoice_language, deleted_at FROM clients WHERE client_name=$1 AND deleted_at IS NULL
 `
 
 func (q *Queries) GetClientByName(ctx context.Context, clientName string) (Client, error) {
@@ -128,6 +279,323 @@ func (q *Queries) GetClientByName(ctx context.Context, clientName string) (Clien
 		&i.ClientName,
 		&i.Email,
 		&i.Notes,
+		&i.TaxID,
+		&i.DefaultCurrency,
+		&i.PaymentTermsDays,
+		&i.InvoiceLanguage,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const getClientContactByID = `-- name: GetClientContactByID :one
+SELECT id, created_at, updated_at, client_id, name, role, email, phone FROM client_contacts WHERE id=$1
+`
+
+func (q *Queries) GetClientContactByID(ctx context.Context, id uuid.UUID) (ClientContact, error) {
+	row := q.db.QueryRowContext(ctx, getClientContactByID, id)
+	var i ClientContact
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ClientID,
+		&i.Name,
+		&i.Role,
+		&i.Email,
+		&i.Phone,
+	)
+	return i, err
+}
+
+const getClientDependencies = `-- name: GetClientDependencies :one
+SELECT
+    (SELECT COUNT(*) FROM projects p WHERE p.client_id=$1 AND p.deleted_at IS NULL) AS projects,
+    (SELECT COUNT(*) FROM episodes e JOIN projects p ON e.project_id=p.id
+        WHERE p.client_id=$1 AND p.deleted_at IS NULL AND e.deleted_at IS NULL) AS episodes,
+    (SELECT COUNT(*) FROM sessions s JOIN projects p ON s.project_id=p.id
+        WHERE p.client_id=$1 AND p.deleted_at IS NULL AND s.deleted_at IS NULL) AS sessions,
+    (SELECT COUNT(*) FROM calculations c JOIN projects p ON c.project_id=p.id
+        WHERE p.client_id=$1 AND p.deleted_at IS NULL AND c.deleted_at IS NULL) AS calculations,
+    (SELECT COUNT(*) FROM bookings b JOIN projects p ON b.project_id=p.id
+        WHERE p.client_id=$1 AND p.deleted_at IS NULL) AS bookings
+`
+
+type GetClientDependenciesRow struct {
+	Projects     int64 `json:"projects"`
+	Episodes     int64 `json:"episodes"`
+	Sessions     int64 `json:"sessions"`
+	Calculations int64 `json:"calculations"`
+	Bookings     int64 `json:"bookings"`
+}
+
+func (q *Queries) GetClientDependencies(ctx context.Context, clientID uuid.UUID) (GetClientDependenciesRow, error) {
+	row := q.db.QueryRowContext(ctx, getClientDependencies, clientID)
+	var i GetClientDependenciesRow
+	err := row.Scan(
+		&i.Projects,
+		&i.Episodes,
+		&i.Sessions,
+		&i.Calculations,
+		&i.Bookings,
+	)
+	return i, err
+}
+
+const getContactsForClient = `-- name: GetContactsForClient :many
+SELECT id, created_at, updated_at, client_id, name, role, email, phone FROM client_contacts WHERE client_id=$1 ORDER BY name ASC
+`
+
+func (q *Queries) GetContactsForClient(ctx context.Context, clientID uuid.UUID) ([]ClientContact, error) {
+	rows, err := q.db.QueryContext(ctx, getContactsForClient, clientID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ClientContact
+	for rows.Next() {
+		var i ClientContact
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ClientID,
+			&i.Name,
+			&i.Role,
+			&i.Email,
+			&i.Phone,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getMovableClientDependencies = `-- name: GetMovableClientDependencies :one
+SELECT
+    (SELECT COUNT(*) FROM projects p WHERE p.client_id=$1) AS projects,
+    (SELECT COUNT(*) FROM episodes e JOIN projects p ON e.project_id=p.id WHERE p.client_id=$1) AS episodes,
+    (SELECT COUNT(*) FROM sessions s JOIN projects p ON s.project_id=p.id WHERE p.client_id=$1) AS sessions,
+    (SELECT COUNT(*) FROM calculations c JOIN projects p ON c.project_id=p.id WHERE p.client_id=$1) AS calculations,
+    (SELECT COUNT(*) FROM bookings b JOIN projects p ON b.project_id=p.id WHERE p.client_id=$1) AS bookings
+`
+
+type GetMovableClientDependenciesRow struct {
+	Projects     int64 `json:"projects"`
+	Episodes     int64 `json:"episodes"`
+	Sessions     int64 `json:"sessions"`
+	Calculations int64 `json:"calculations"`
+	Bookings     int64 `json:"bookings"`
+}
+
+func (q *Queries) GetMovableClientDependencies(ctx context.Context, clientID uuid.UUID) (GetMovableClientDependenciesRow, error) {
+	row := q.db.QueryRowContext(ctx, getMovableClientDependencies, clientID)
+	var i GetMovableClientDependenciesRow
+	err := row.Scan(
+		&i.Projects,
+		&i.Episodes,
+		&i.Sessions,
+		&i.Calculations,
+		&i.Bookings,
+	)
+	return i, err
+}
+
+const moveAddressesToClient = `-- name: MoveAddressesToClient :many
+UPDATE client_addresses SET updated_at=NOW(), client_id=$2
+WHERE client_id=$1 AND kind NOT IN (SELECT kind FROM client_addresses WHERE client_id=$2)
+RETURNING id, created_at, updated_at, client_id, kind, line1, line2, postal_code, city, country
+`
+
+type MoveAddressesToClientParams struct {
+	FromClientID uuid.UUID `json:"from_client_id"`
+	ToClientID   uuid.UUID `json:"to_client_id"`
+}
+
+func (q *Queries) MoveAddressesToClient(ctx context.Context, arg MoveAddressesToClientParams) ([]ClientAddress, error) {
+	rows, err := q.db.QueryContext(ctx, moveAddressesToClient, arg.FromClientID, arg.ToClientID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ClientAddress
+	for rows.Next() {
+		var i ClientAddress
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ClientID,
+			&i.Kind,
+			&i.Line1,
+			&i.Line2,
+			&i.PostalCode,
+			&i.City,
+			&i.Country,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const moveContactsToClient = `-- name: MoveContactsToClient :many
+UPDATE client_contacts SET updated_at=NOW(), client_id=$2 WHERE client_id=$1 RETURNING id, created_at, updated_at, client_id, name, role, email, phone
+`
+
+type MoveContactsToClientParams struct {
+	FromClientID uuid.UUID `json:"from_client_id"`
+	ToClientID   uuid.UUID `json:"to_client_id"`
+}
+
+func (q *Queries) MoveContactsToClient(ctx context.Context, arg MoveContactsToClientParams) ([]ClientContact, error) {
+	rows, err := q.db.QueryContext(ctx, moveContactsToClient, arg.FromClientID, arg.ToClientID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ClientContact
+	for rows.Next() {
+		var i ClientContact
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ClientID,
+			&i.Name,
+			&i.Role,
+			&i.Email,
+			&i.Phone,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setClientAddress = `-- name: SetClientAddress :one
+INSERT INTO client_addresses (
+    client_id,
+    kind,
+    line1,
+    line2,
+    postal_code,
+    city,
+    country
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7
+) ON CONFLICT (client_id, kind) DO UPDATE SET
+    updated_at=NOW(),
+    line1=EXCLUDED.line1,
+    line2=EXCLUDED.line2,
+    postal_code=EXCLUDED.postal_code,
+    city=EXCLUDED.city,
+    country=EXCLUDED.country
+RETURNING id, created_at, updated_at, client_id, kind, line1, line2, postal_code, city, country
+`
+
+type SetClientAddressParams struct {
+	ClientID   uuid.UUID      `json:"client_id"`
+	Kind       AddressKind    `json:"kind"`
+	Line1      string         `json:"line1"`
+	Line2      sql.NullString `json:"line2"`
+	PostalCode string         `json:"postal_code"`
+	City       string         `json:"city"`
+	Country    string         `json:"country"`
+}
+
+func (q *Queries) SetClientAddress(ctx context.Context, arg SetClientAddressParams) (ClientAddress, error) {
+	row := q.db.QueryRowContext(ctx, setClientAddress,
+		arg.ClientID,
+		arg.Kind,
+		arg.Line1,
+		arg.Line2,
+		arg.PostalCode,
+		arg.City,
+		arg.Country,
+	)
+	var i ClientAddress
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ClientID,
+		&i.Kind,
+		&i.Line1,
+		&i.Line2,
+		&i.PostalCode,
+		&i.City,
+		&i.Country,
+	)
+	return i, err
+}
+
+const setClientBilling = `-- name: SetClientBilling :one
+UPDATE clients SET
+    updated_at=NOW(),
+    tax_id=$2,
+    default_currency=$3,
+    payment_terms_days=$4,
+    invoice_language=$5
+WHERE id=$1 AND deleted_at IS NULL RETURNING id, created_at, updated_at, client_name, email, notes, tax_id, default_currency, payment_terms_days, invoice_language, deleted_at
+`
+
+type SetClientBillingParams struct {
+	ID               uuid.UUID      `json:"id"`
+	TaxID            sql.NullString `json:"tax_id"`
+	DefaultCurrency  sql.NullString `json:"default_currency"`
+	PaymentTermsDays sql.NullInt32  `json:"payment_terms_days"`
+	InvoiceLanguage  sql.NullString `json:"invoice_language"`
+}
+
+func (q *Queries) SetClientBilling(ctx context.Context, arg SetClientBillingParams) (Client, error) {
+	row := q.db.QueryRowContext(ctx, setClientBilling,
+		arg.ID,
+		arg.TaxID,
+		arg.DefaultCurrency,
+		arg.PaymentTermsDays,
+		arg.InvoiceLanguage,
+	)
+	var i Client
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ClientName,
+		&i.Email,
+		&i.Notes,
+		&i.TaxID,
+		&i.DefaultCurrency,
+		&i.PaymentTermsDays,
+		&i.InvoiceLanguage,
+		&i.DeletedAt,
 	)
 	return i, err
 }
@@ -138,7 +606,7 @@ UPDATE clients SET
     client_name=$2,
     email=$3,
     notes=$4
-WHERE id=$1 RETURNING id, created_at, updated_at, client_name, email, notes
+WHERE id=$1 AND deleted_at IS NULL RETURNING id, created_at, updated_at, client_name, email, notes, tax_id, default_currency, payment_terms_days, invoice_language, deleted_at
 `
 
 type UpdateClientParams struct {
@@ -163,6 +631,51 @@ func (q *Queries) UpdateClient(ctx context.Context, arg UpdateClientParams) (Cli
 		&i.ClientName,
 		&i.Email,
 		&i.Notes,
+		&i.TaxID,
+		&i.DefaultCurrency,
+		&i.PaymentTermsDays,
+		&i.InvoiceLanguage,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const updateClientContact = `-- name: UpdateClientContact :one
+UPDATE client_contacts SET
+    updated_at=NOW(),
+    name=$2,
+    role=$3,
+    email=$4,
+    phone=$5
+WHERE id=$1 RETURNING id, created_at, updated_at, client_id, name, role, email, phone
+`
+
+type UpdateClientContactParams struct {
+	ID    uuid.UUID      `json:"id"`
+	Name  string         `json:"name"`
+	Role  sql.NullString `json:"role"`
+	Email sql.NullString `json:"email"`
+	Phone sql.NullString `json:"phone"`
+}
+
+func (q *Queries) UpdateClientContact(ctx context.Context, arg UpdateClientContactParams) (ClientContact, error) {
+	row := q.db.QueryRowContext(ctx, updateClientContact,
+		arg.ID,
+		arg.Name,
+		arg.Role,
+		arg.Email,
+		arg.Phone,
+	)
+	var i ClientContact
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ClientID,
+		&i.Name,
+		&i.Role,
+		&i.Email,
+		&i.Phone,
 	)
 	return i, err
 }
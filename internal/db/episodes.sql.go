@@ -16,22 +16,42 @@ const createEpisode = `-- name: CreateEpisode :one
 INSERT INTO episodes (
     title,
     episode_number,
-    project_id
+    project_id,
+    season_id,
+    runtime_seconds,
+    picture_lock,
+    frame_rate
 ) VALUES (
     $1,
     $2,
-    $3
-) RETURNING id, created_at, updated_at, title, episode_number, project_id
+    $3,
+    $4,
+    $5,
+    $6,
+    $7
+) RETURNING id, created_at, updated_at, title, episode_number, project_id, season_id, runtime_seconds, picture_lock, frame_rate, deleted_at
 `
 
 type CreateEpisodeParams struct {
-	Title         sql.NullString `json:"title"`
-	EpisodeNumber int32          `json:"episode_number"`
-	ProjectID     uuid.UUID      `json:"project_id"`
+	Title          sql.NullString `json:"title"`
+	EpisodeNumber  int32          `json:"episode_number"`
+	ProjectID      uuid.UUID      `json:"project_id"`
+	SeasonID       uuid.NullUUID  `json:"season_id"`
+	RuntimeSeconds sql.NullInt32  `json:"runtime_seconds"`
+	PictureLock    sql.NullTime   `json:"picture_lock"`
+	FrameRate      sql.NullString `json:"frame_rate"`
 }
 
 func (q *Queries) CreateEpisode(ctx context.Context, arg CreateEpisodeParams) (Episode, error) {
-	row := q.db.QueryRowContext(ctx, createEpisode, arg.Title, arg.EpisodeNumber, arg.ProjectID)
+	row := q.db.QueryRowContext(ctx, createEpisode,
+		arg.Title,
+		arg.EpisodeNumber,
+		arg.ProjectID,
+		arg.SeasonID,
+		arg.RuntimeSeconds,
+		arg.PictureLock,
+		arg.FrameRate,
+	)
 	var i Episode
 	err := row.Scan(
 		&i.ID,
@@ -40,12 +60,17 @@ func (q *Queries) CreateEpisode(ctx context.Context, arg CreateEpisodeParams) (E
 		&i.Title,
 		&i.EpisodeNumber,
 		&i.ProjectID,
+		&i.SeasonID,
+		&i.RuntimeSeconds,
+		&i.PictureLock,
+		&i.FrameRate,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
 const deleteEpisode = `-- name: DeleteEpisode :one
-DELETE FROM episodes WHERE id = $1 RETURNING id, created_at, updated_at, title, episode_number, project_id
+DELETE FROM episodes WHERE id = $1 RETURNING id, created_at, updated_at, title, episode_number, project_id, season_id, runtime_seconds, picture_lock, frame_rate, deleted_at
 `
 
 func (q *Queries) DeleteEpisode(ctx context.Context, id uuid.UUID) (Episode, error) {
@@ -58,12 +83,17 @@ func (q *Queries) DeleteEpisode(ctx context.Context, id uuid.UUID) (Episode, err
 		&i.Title,
 		&i.EpisodeNumber,
 		&i.ProjectID,
+		&i.SeasonID,
+		&i.RuntimeSeconds,
+		&i.PictureLock,
+		&i.FrameRate,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
 const getAllEpisodes = `-- name: GetAllEpisodes :many
-SELECT id, created_at, updated_at, title, episode_number, project_id FROM episodes
+SELECT id, created_at, updated_at, title, episode_number, project_id, season_id, runtime_seconds, picture_lock, frame_rate, deleted_at FROM episodes WHERE deleted_at IS NULL
 `
 
 func (q *Queries) GetAllEpisodes(ctx context.Context) ([]Episode, error) {
@@ -82,6 +112,11 @@ func (q *Queries) GetAllEpisodes(ctx context.Context) ([]Episode, error) {
 			&i.Title,
 			&i.EpisodeNumber,
 			&i.ProjectID,
+			&i.SeasonID,
+			&i.RuntimeSeconds,
+			&i.PictureLock,
+			&i.FrameRate,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -97,7 +132,10 @@ func (q *Queries) GetAllEpisodes(ctx context.Context) ([]Episode, error) {
 }
 
 const getAllEpisodesForProject = `-- name: GetAllEpisodesForProject :many
-SELECT id, created_at, updated_at, title, episode_number, project_id FROM episodes WHERE project_id = $1 ORDER BY episode_number ASC
+SELECT episodes.id, episodes.created_at, episodes.updated_at, episodes.title, episodes.episode_number, episodes.project_id, episodes.season_id, episodes.runtime_seconds, episodes.picture_lock, episodes.frame_rate, episodes.deleted_at FROM episodes
+LEFT JOIN project_units ON project_units.id = episodes.season_id
+WHERE episodes.project_id = $1 AND episodes.deleted_at IS NULL
+ORDER BY project_units.number ASC NULLS FIRST, episodes.episode_number ASC
 `
 
 func (q *Queries) GetAllEpisodesForProject(ctx context.Context, projectID uuid.UUID) ([]Episode, error) {
@@ -116,6 +154,11 @@ func (q *Queries) GetAllEpisodesForProject(ctx context.Context, projectID uuid.U
 			&i.Title,
 			&i.EpisodeNumber,
 			&i.ProjectID,
+			&i.SeasonID,
+			&i.RuntimeSeconds,
+			&i.PictureLock,
+			&i.FrameRate,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -131,7 +174,7 @@ func (q *Queries) GetAllEpisodesForProject(ctx context.Context, projectID uuid.U
 }
 
 const getEpisodeByID = `-- name: GetEpisodeByID :one
-SELECT id, created_at, updated_at, title, episode_number, project_id FROM episodes WHERE id = $1
+SELECT id, created_at, updated_at, title, episode_number, project_id, season_id, runtime_seconds, picture_lock, frame_rate, deleted_at FROM episodes WHERE id = $1 AND deleted_at IS NULL
 `
 
 func (q *Queries) GetEpisodeByID(ctx context.Context, id uuid.UUID) (Episode, error) {
@@ -144,21 +187,26 @@ func (q *Queries) GetEpisodeByID(ctx context.Context, id uuid.UUID) (Episode, er
 		&i.Title,
 		&i.EpisodeNumber,
 		&i.ProjectID,
+		&i.SeasonID,
+		&i.RuntimeSeconds,
+		&i.PictureLock,
+		&i.FrameRate,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
-const getEpisodeByNumber = `-- name: GetEpisodeByNumber :one
-SELECT id, created_at, updated_at, title, episode_number, project_id FROM episodes WHERE project_id = $1 AND episode_number = $2
+const getEpisodeInSeason = `-- name: GetEpisodeInSeason :one
+SELECT id, created_at, updated_at, title, episode_number, project_id, season_id, runtime_seconds, picture_lock, frame_rate, deleted_at FROM episodes WHERE season_id = $1 AND episode_number = $2 AND deleted_at IS NULL
 `
 
-type GetEpisodeByNumberParams struct {
-	ProjectID     uuid.UUID `json:"project_id"`
+type GetEpisodeInSeasonParams struct {
+	SeasonID      uuid.UUID `json:"season_id"`
 	EpisodeNumber int32     `json:"episode_number"`
 }
 
-func (q *Queries) GetEpisodeByNumber(ctx context.Context, arg GetEpisodeByNumberParams) (Episode, error) {
-	row := q.db.QueryRowContext(ctx, getEpisodeByNumber, arg.ProjectID, arg.EpisodeNumber)
+func (q *Queries) GetEpisodeInSeason(ctx context.Context, arg GetEpisodeInSeasonParams) (Episode, error) {
+	row := q.db.QueryRowContext(ctx, getEpisodeInSeason, arg.SeasonID, arg.EpisodeNumber)
 	var i Episode
 	err := row.Scan(
 		&i.ID,
@@ -167,24 +215,178 @@ func (q *Queries) GetEpisodeByNumber(ctx context.Context, arg GetEpisodeByNumber
 		&i.Title,
 		&i.EpisodeNumber,
 		&i.ProjectID,
+		&i.SeasonID,
+		&i.RuntimeSeconds,
+		&i.PictureLock,
+		&i.FrameRate,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
+const getEpisodesByNumber = `-- name: GetEpisodesByNumber :many
+SELECT id, created_at, updated_at, title, episode_number, project_id, season_id, runtime_seconds, picture_lock, frame_rate, deleted_at FROM episodes WHERE project_id = $1 AND episode_number = $2 AND deleted_at IS NULL
+`
+
+type GetEpisodesByNumberParams struct {
+	ProjectID     uuid.UUID `json:"project_id"`
+	EpisodeNumber int32     `json:"episode_number"`
+}
+
+func (q *Queries) GetEpisodesByNumber(ctx context.Context, arg GetEpisodesByNumberParams) ([]Episode, error) {
+	rows, err := q.db.QueryContext(ctx, getEpisodesByNumber, arg.ProjectID, arg.EpisodeNumber)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Episode
+	for rows.Next() {
+		var i Episode
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Title,
+			&i.EpisodeNumber,
+			&i.ProjectID,
+			&i.SeasonID,
+			&i.RuntimeSeconds,
+			&i.PictureLock,
+			&i.FrameRate,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getEpisodesForSeason = `-- name: GetEpisodesForSeason :many
+SELECT id, created_at, updated_at, title, episode_number, project_id, season_id, runtime_seconds, picture_lock, frame_rate, deleted_at FROM episodes WHERE season_id = $1 AND deleted_at IS NULL ORDER BY episode_number ASC
+`
+
+func (q *Queries) GetEpisodesForSeason(ctx context.Context, seasonID uuid.UUID) ([]Episode, error) {
+	rows, err := q.db.QueryContext(ctx, getEpisodesForSeason, seasonID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Episode
+	for rows.Next() {
+		var i Episode
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Title,
+			&i.EpisodeNumber,
+			&i.ProjectID,
+			&i.SeasonID,
+			&i.RuntimeSeconds,
+			&i.PictureLock,
+			&i.FrameRate,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRuntimeReportForProject = `-- name: GetRuntimeReportForProject :many
+SELECT
+    episodes.id,
+    episodes.episode_number,
+    episodes.title,
+    episodes.season_id,
+    episodes.runtime_seconds,
+    episodes.picture_lock,
+    COALESCE(SUM(sessions.duration), 0)::bigint AS minutes
+FROM episodes
+LEFT JOIN project_units ON project_units.id = episodes.season_id
+LEFT JOIN sessions ON sessions.episode_id = episodes.id AND sessions.deleted_at IS NULL
+WHERE episodes.project_id = $1 AND episodes.deleted_at IS NULL
+GROUP BY episodes.id, project_units.number
+ORDER BY project_units.number ASC NULLS FIRST, episodes.episode_number ASC
+`
+
+type GetRuntimeReportForProjectRow struct {
+	ID             uuid.UUID      `json:"id"`
+	EpisodeNumber  int32          `json:"episode_number"`
+	Title          sql.NullString `json:"title"`
+	SeasonID       uuid.NullUUID  `json:"season_id"`
+	RuntimeSeconds sql.NullInt32  `json:"runtime_seconds"`
+	PictureLock    sql.NullTime   `json:"picture_lock"`
+	Minutes        int64          `json:"minutes"`
+}
+
+func (q *Queries) GetRuntimeReportForProject(ctx context.Context, projectID uuid.UUID) ([]GetRuntimeReportForProjectRow, error) {
+	rows, err := q.db.QueryContext(ctx, getRuntimeReportForProject, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetRuntimeReportForProjectRow
+	for rows.Next() {
+		var i GetRuntimeReportForProjectRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.EpisodeNumber,
+			&i.Title,
+			&i.SeasonID,
+			&i.RuntimeSeconds,
+			&i.PictureLock,
+			&i.Minutes,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const updateEpisode = `-- name: UpdateEpisode :one
 UPDATE episodes SET
     title = $2,
     episode_number = $3,
     project_id = $4,
+    season_id = $5,
+    runtime_seconds = $6,
+    picture_lock = $7,
+    frame_rate = $8,
     updated_at = NOW()
-WHERE id = $1 RETURNING id, created_at, updated_at, title, episode_number, project_id
+WHERE id = $1 AND deleted_at IS NULL RETURNING id, created_at, updated_at, title, episode_number, project_id, season_id, runtime_seconds, picture_lock, frame_rate, deleted_at
 `
 
 type UpdateEpisodeParams struct {
-	ID            uuid.UUID      `json:"id"`
-	Title         sql.NullString `json:"title"`
-	EpisodeNumber int32          `json:"episode_number"`
-	ProjectID     uuid.UUID      `json:"project_id"`
+	ID             uuid.UUID      `json:"id"`
+	Title          sql.NullString `json:"title"`
+	EpisodeNumber  int32          `json:"episode_number"`
+	ProjectID      uuid.UUID      `json:"project_id"`
+	SeasonID       uuid.NullUUID  `json:"season_id"`
+	RuntimeSeconds sql.NullInt32  `json:"runtime_seconds"`
+	PictureLock    sql.NullTime   `json:"picture_lock"`
+	FrameRate      sql.NullString `json:"frame_rate"`
 }
 
 func (q *Queries) UpdateEpisode(ctx context.Context, arg UpdateEpisodeParams) (Episode, error) {
@@ -193,6 +395,10 @@ func (q *Queries) UpdateEpisode(ctx context.Context, arg UpdateEpisodeParams) (E
 		arg.Title,
 		arg.EpisodeNumber,
 		arg.ProjectID,
+		arg.SeasonID,
+		arg.RuntimeSeconds,
+		arg.PictureLock,
+		arg.FrameRate,
 	)
 	var i Episode
 	err := row.Scan(
@@ -202,6 +408,11 @@ func (q *Queries) UpdateEpisode(ctx context.Context, arg UpdateEpisodeParams) (E
 		&i.Title,
 		&i.EpisodeNumber,
 		&i.ProjectID,
+		&i.SeasonID,
+		&i.RuntimeSeconds,
+		&i.PictureLock,
+		&i.FrameRate,
+		&i.DeletedAt,
 	)
 	return i, err
 }
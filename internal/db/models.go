@@ -13,125 +13,606 @@ import (
 	"github.com/google/uuid"
 )
 
-type Activity string
+type AddressKind string
 
 const (
-	ActivityRecord   Activity = "record"
-	ActivityEdit     Activity = "edit"
-	ActivityService  Activity = "service"
-	ActivitySpotting Activity = "spotting"
-	ActivityOther    Activity = "other"
+	AddressKindBilling AddressKind = "billing"
+	AddressKindPostal  AddressKind = "postal"
 )
 
-func (e *Activity) Scan(src interface{}) error {
+func (e *AddressKind) Scan(src interface{}) error {
 	switch s := src.(type) {
 	case []byte:
-		*e = Activity(s)
+		*e = AddressKind(s)
 	case string:
-		*e = Activity(s)
+		*e = AddressKind(s)
 	default:
-		return fmt.Errorf("unsupported scan type for Activity: %T", src)
+		return fmt.Errorf("unsupported scan type for AddressKind: %T", src)
 	}
 	return nil
 }
 
-type NullActivity struct {
-	Activity Activity `json:"activity"`
-	Valid    bool     `json:"valid"` // Valid is true if Activity is not NULL
+type NullAddressKind struct {
+	AddressKind AddressKind `json:"address_kind"`
+	Valid       bool        `json:"valid"` // Valid is true if AddressKind is not NULL
 }
 
 // Scan implements the Scanner interface.
-func (ns *NullActivity) Scan(value interface{}) error {
+func (ns *NullAddressKind) Scan(value interface{}) error {
 	if value == nil {
-		ns.Activity, ns.Valid = "", false
+		ns.AddressKind, ns.Valid = "", false
 		return nil
 	}
 	ns.Valid = true
-	return ns.Activity.Scan(value)
+	return ns.AddressKind.Scan(value)
 }
 
 // Value implements the driver Valuer interface.
-func (ns NullActivity) Value() (driver.Value, error) {
+func (ns NullAddressKind) Value() (driver.Value, error) {
 	if !ns.Valid {
 		return nil, nil
 	}
-	return string(ns.Activity), nil
+	return string(ns.AddressKind), nil
 }
 
-type Part string
+type ApprovalStatus string
 
 const (
-	PartProps      Part = "props"
-	PartFootsteps  Part = "footsteps"
-	PartMovements  Part = "movements"
-	PartDialogue   Part = "dialogue"
-	PartAdr        Part = "adr"
-	PartMusic      Part = "music"
-	PartBackground Part = "background"
-	PartOther      Part = "other"
+	ApprovalStatusDraft     ApprovalStatus = "draft"
+	ApprovalStatusSubmitted ApprovalStatus = "submitted"
+	ApprovalStatusApproved  ApprovalStatus = "approved"
+	ApprovalStatusRejected  ApprovalStatus = "rejected"
 )
 
-func (e *Part) Scan(src interface{}) error {
+func (e *ApprovalStatus) Scan(src interface{}) error {
 	switch s := src.(type) {
 	case []byte:
-		*e = Part(s)
+		*e = ApprovalStatus(s)
 	case string:
-		*e = Part(s)
+		*e = ApprovalStatus(s)
 	default:
-		return fmt.Errorf("unsupported scan type for Part: %T", src)
+		return fmt.Errorf("unsupported scan type for ApprovalStatus: %T", src)
 	}
 	return nil
 }
 
-type NullPart struct {
-	Part  Part `json:"part"`
-	Valid bool `json:"valid"` // Valid is true if Part is not NULL
+type NullApprovalStatus struct {
+	ApprovalStatus ApprovalStatus `json:"approval_status"`
+	Valid          bool           `json:"valid"` // Valid is true if ApprovalStatus is not NULL
 }
 
 // Scan implements the Scanner interface.
-func (ns *NullPart) Scan(value interface{}) error {
+func (ns *NullApprovalStatus) Scan(value interface{}) error {
 	if value == nil {
-		ns.Part, ns.Valid = "", false
+		ns.ApprovalStatus, ns.Valid = "", false
 		return nil
 	}
 	ns.Valid = true
-	return ns.Part.Scan(value)
+	return ns.ApprovalStatus.Scan(value)
 }
 
 // Value implements the driver Valuer interface.
-func (ns NullPart) Value() (driver.Value, error) {
+func (ns NullApprovalStatus) Value() (driver.Value, error) {
 	if !ns.Valid {
 		return nil, nil
 	}
-	return string(ns.Part), nil
+	return string(ns.ApprovalStatus), nil
+}
+
+type CueStatus string
+
+const (
+	CueStatusPending  CueStatus = "pending"
+	CueStatusRecorded CueStatus = "recorded"
+	CueStatusEdited   CueStatus = "edited"
+	CueStatusApproved CueStatus = "approved"
+	CueStatusOmitted  CueStatus = "omitted"
+)
+
+func (e *CueStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = CueStatus(s)
+	case string:
+		*e = CueStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for CueStatus: %T", src)
+	}
+	return nil
+}
+
+type NullCueStatus struct {
+	CueStatus CueStatus `json:"cue_status"`
+	Valid     bool      `json:"valid"` // Valid is true if CueStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullCueStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.CueStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.CueStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullCueStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.CueStatus), nil
+}
+
+type FeedScope string
+
+const (
+	FeedScopeUser    FeedScope = "user"
+	FeedScopeRoom    FeedScope = "room"
+	FeedScopeProject FeedScope = "project"
+)
+
+func (e *FeedScope) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = FeedScope(s)
+	case string:
+		*e = FeedScope(s)
+	default:
+		return fmt.Errorf("unsupported scan type for FeedScope: %T", src)
+	}
+	return nil
+}
+
+type NullFeedScope struct {
+	FeedScope FeedScope `json:"feed_scope"`
+	Valid     bool      `json:"valid"` // Valid is true if FeedScope is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullFeedScope) Scan(value interface{}) error {
+	if value == nil {
+		ns.FeedScope, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.FeedScope.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullFeedScope) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.FeedScope), nil
+}
+
+type MilestoneKind string
+
+const (
+	MilestoneKindDelivery     MilestoneKind = "delivery"
+	MilestoneKindClientReview MilestoneKind = "client_review"
+	MilestoneKindInternal     MilestoneKind = "internal"
+)
+
+func (e *MilestoneKind) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = MilestoneKind(s)
+	case string:
+		*e = MilestoneKind(s)
+	default:
+		return fmt.Errorf("unsupported scan type for MilestoneKind: %T", src)
+	}
+	return nil
+}
+
+type NullMilestoneKind struct {
+	MilestoneKind MilestoneKind `json:"milestone_kind"`
+	Valid         bool          `json:"valid"` // Valid is true if MilestoneKind is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullMilestoneKind) Scan(value interface{}) error {
+	if value == nil {
+		ns.MilestoneKind, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.MilestoneKind.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullMilestoneKind) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.MilestoneKind), nil
+}
+
+type ProductionStatus string
+
+const (
+	ProductionStatusNotStarted ProductionStatus = "not_started"
+	ProductionStatusSpotted    ProductionStatus = "spotted"
+	ProductionStatusRecorded   ProductionStatus = "recorded"
+	ProductionStatusEdited     ProductionStatus = "edited"
+	ProductionStatusPremixed   ProductionStatus = "premixed"
+	ProductionStatusDelivered  ProductionStatus = "delivered"
+	ProductionStatusApproved   ProductionStatus = "approved"
+)
+
+func (e *ProductionStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ProductionStatus(s)
+	case string:
+		*e = ProductionStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ProductionStatus: %T", src)
+	}
+	return nil
+}
+
+type NullProductionStatus struct {
+	ProductionStatus ProductionStatus `json:"production_status"`
+	Valid            bool             `json:"valid"` // Valid is true if ProductionStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullProductionStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.ProductionStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ProductionStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullProductionStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ProductionStatus), nil
+}
+
+type ProjectRole string
+
+const (
+	ProjectRoleLead   ProjectRole = "lead"
+	ProjectRoleMember ProjectRole = "member"
+	ProjectRoleViewer ProjectRole = "viewer"
+)
+
+func (e *ProjectRole) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ProjectRole(s)
+	case string:
+		*e = ProjectRole(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ProjectRole: %T", src)
+	}
+	return nil
+}
+
+type NullProjectRole struct {
+	ProjectRole ProjectRole `json:"project_role"`
+	Valid       bool        `json:"valid"` // Valid is true if ProjectRole is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullProjectRole) Scan(value interface{}) error {
+	if value == nil {
+		ns.ProjectRole, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ProjectRole.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullProjectRole) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ProjectRole), nil
+}
+
+type ProjectStatus string
+
+const (
+	ProjectStatusBidding   ProjectStatus = "bidding"
+	ProjectStatusActive    ProjectStatus = "active"
+	ProjectStatusOnHold    ProjectStatus = "on_hold"
+	ProjectStatusDelivered ProjectStatus = "delivered"
+	ProjectStatusClosed    ProjectStatus = "closed"
+	ProjectStatusArchived  ProjectStatus = "archived"
+)
+
+func (e *ProjectStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ProjectStatus(s)
+	case string:
+		*e = ProjectStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ProjectStatus: %T", src)
+	}
+	return nil
+}
+
+type NullProjectStatus struct {
+	ProjectStatus ProjectStatus `json:"project_status"`
+	Valid         bool          `json:"valid"` // Valid is true if ProjectStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullProjectStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.ProjectStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ProjectStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullProjectStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ProjectStatus), nil
+}
+
+type ProjectType string
+
+const (
+	ProjectTypeSeries     ProjectType = "series"
+	ProjectTypeFeature    ProjectType = "feature"
+	ProjectTypeCommercial ProjectType = "commercial"
+	ProjectTypeGame       ProjectType = "game"
+)
+
+func (e *ProjectType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ProjectType(s)
+	case string:
+		*e = ProjectType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ProjectType: %T", src)
+	}
+	return nil
+}
+
+type NullProjectType struct {
+	ProjectType ProjectType `json:"project_type"`
+	Valid       bool        `json:"valid"` // Valid is true if ProjectType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullProjectType) Scan(value interface{}) error {
+	if value == nil {
+		ns.ProjectType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ProjectType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullProjectType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ProjectType), nil
+}
+
+type UnitKind string
+
+const (
+	UnitKindSeason      UnitKind = "season"
+	UnitKindReel        UnitKind = "reel"
+	UnitKindDeliverable UnitKind = "deliverable"
+)
+
+func (e *UnitKind) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = UnitKind(s)
+	case string:
+		*e = UnitKind(s)
+	default:
+		return fmt.Errorf("unsupported scan type for UnitKind: %T", src)
+	}
+	return nil
+}
+
+type NullUnitKind struct {
+	UnitKind UnitKind `json:"unit_kind"`
+	Valid    bool     `json:"valid"` // Valid is true if UnitKind is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullUnitKind) Scan(value interface{}) error {
+	if value == nil {
+		ns.UnitKind, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.UnitKind.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullUnitKind) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.UnitKind), nil
+}
+
+type UserRole string
+
+const (
+	UserRoleAdmin      UserRole = "admin"
+	UserRoleManager    UserRole = "manager"
+	UserRoleEngineer   UserRole = "engineer"
+	UserRoleAccountant UserRole = "accountant"
+)
+
+func (e *UserRole) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = UserRole(s)
+	case string:
+		*e = UserRole(s)
+	default:
+		return fmt.Errorf("unsupported scan type for UserRole: %T", src)
+	}
+	return nil
+}
+
+type NullUserRole struct {
+	UserRole UserRole `json:"user_role"`
+	Valid    bool     `json:"valid"` // Valid is true if UserRole is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullUserRole) Scan(value interface{}) error {
+	if value == nil {
+		ns.UserRole, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.UserRole.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullUserRole) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.UserRole), nil
+}
+
+type Activity struct {
+	ID            uuid.UUID            `json:"id"`
+	CreatedAt     time.Time            `json:"created_at"`
+	UpdatedAt     time.Time            `json:"updated_at"`
+	Code          string               `json:"code"`
+	DisplayName   string               `json:"display_name"`
+	SortOrder     int32                `json:"sort_order"`
+	Active        bool                 `json:"active"`
+	BillingWeight sql.NullString       `json:"billing_weight"`
+	AdvancesTo    NullProductionStatus `json:"advances_to"`
+}
+
+type Booking struct {
+	ID          uuid.UUID      `json:"id"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	Title       string         `json:"title"`
+	StartsAt    time.Time      `json:"starts_at"`
+	EndsAt      time.Time      `json:"ends_at"`
+	RoomID      uuid.NullUUID  `json:"room_id"`
+	ProjectID   uuid.UUID      `json:"project_id"`
+	EpisodeID   uuid.NullUUID  `json:"episode_id"`
+	Notes       sql.NullString `json:"notes"`
+	ExternalUid sql.NullString `json:"external_uid"`
 }
 
 type Calculation struct {
-	ID           uuid.UUID `json:"id"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
-	ProjectID    uuid.UUID `json:"project_id"`
-	Budget       string    `json:"budget"`
-	Currency     string    `json:"currency"`
-	ExchangeRate string    `json:"exchange_rate"`
+	ID                uuid.UUID    `json:"id"`
+	CreatedAt         time.Time    `json:"created_at"`
+	UpdatedAt         time.Time    `json:"updated_at"`
+	ProjectID         uuid.UUID    `json:"project_id"`
+	Budget            string       `json:"budget"`
+	Currency          string       `json:"currency"`
+	ExchangeRate      string       `json:"exchange_rate"`
+	BossTribute       string       `json:"boss_tribute"`
+	ManagerCommission string       `json:"manager_commission"`
+	TaxRate           string       `json:"tax_rate"`
+	TaxMultiplier     string       `json:"tax_multiplier"`
+	DeletedAt         sql.NullTime `json:"deleted_at"`
+	WholeProject      bool         `json:"whole_project"`
+}
+
+type CalendarFeed struct {
+	Token     string    `json:"token"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	OwnerID   uuid.UUID `json:"owner_id"`
+	Scope     FeedScope `json:"scope"`
+	TargetID  uuid.UUID `json:"target_id"`
 }
 
 type Client struct {
+	ID               uuid.UUID      `json:"id"`
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+	ClientName       string         `json:"client_name"`
+	Email            sql.NullString `json:"email"`
+	Notes            sql.NullString `json:"notes"`
+	TaxID            sql.NullString `json:"tax_id"`
+	DefaultCurrency  sql.NullString `json:"default_currency"`
+	PaymentTermsDays sql.NullInt32  `json:"payment_terms_days"`
+	InvoiceLanguage  sql.NullString `json:"invoice_language"`
+	DeletedAt        sql.NullTime   `json:"deleted_at"`
+}
+
+type ClientAddress struct {
 	ID         uuid.UUID      `json:"id"`
 	CreatedAt  time.Time      `json:"created_at"`
 	UpdatedAt  time.Time      `json:"updated_at"`
-	ClientName string         `json:"client_name"`
-	Email      sql.NullString `json:"email"`
-	Notes      sql.NullString `json:"notes"`
+	ClientID   uuid.UUID      `json:"client_id"`
+	Kind       AddressKind    `json:"kind"`
+	Line1      string         `json:"line1"`
+	Line2      sql.NullString `json:"line2"`
+	PostalCode string         `json:"postal_code"`
+	City       string         `json:"city"`
+	Country    string         `json:"country"`
+}
+
+type ClientContact struct {
+	ID        uuid.UUID      `json:"id"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	ClientID  uuid.UUID      `json:"client_id"`
+	Name      string         `json:"name"`
+	Role      sql.NullString `json:"role"`
+	Email     sql.NullString `json:"email"`
+	Phone     sql.NullString `json:"phone"`
+}
+
+type Cue struct {
+	ID          uuid.UUID      `json:"id"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	EpisodeID   uuid.UUID      `json:"episode_id"`
+	CueNumber   string         `json:"cue_number"`
+	Reel        sql.NullString `json:"reel"`
+	FrameIn     int64          `json:"frame_in"`
+	FrameOut    int64          `json:"frame_out"`
+	FrameRate   string         `json:"frame_rate"`
+	Description sql.NullString `json:"description"`
+	Part        sql.NullString `json:"part"`
+	Status      CueStatus      `json:"status"`
 }
 
 type Episode struct {
-	ID            uuid.UUID      `json:"id"`
-	CreatedAt     time.Time      `json:"created_at"`
-	UpdatedAt     time.Time      `json:"updated_at"`
-	Title         sql.NullString `json:"title"`
-	EpisodeNumber int32          `json:"episode_number"`
-	ProjectID     uuid.UUID      `json:"project_id"`
+	ID             uuid.UUID      `json:"id"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	Title          sql.NullString `json:"title"`
+	EpisodeNumber  int32          `json:"episode_number"`
+	ProjectID      uuid.UUID      `json:"project_id"`
+	SeasonID       uuid.NullUUID  `json:"season_id"`
+	RuntimeSeconds sql.NullInt32  `json:"runtime_seconds"`
+	PictureLock    sql.NullTime   `json:"picture_lock"`
+	FrameRate      sql.NullString `json:"frame_rate"`
+	DeletedAt      sql.NullTime   `json:"deleted_at"`
 }
 
 type EpisodeCalc struct {
@@ -142,33 +623,163 @@ type EpisodeCalc struct {
 	CalcID    uuid.UUID `json:"calc_id"`
 }
 
+type EpisodePartStatus struct {
+	ID         uuid.UUID        `json:"id"`
+	CreatedAt  time.Time        `json:"created_at"`
+	UpdatedAt  time.Time        `json:"updated_at"`
+	EpisodeID  uuid.UUID        `json:"episode_id"`
+	Part       string           `json:"part"`
+	Status     ProductionStatus `json:"status"`
+	AssigneeID uuid.NullUUID    `json:"assignee_id"`
+	StatusDate sql.NullTime     `json:"status_date"`
+	Notes      sql.NullString   `json:"notes"`
+}
+
+type EpisodeTrack struct {
+	ID             uuid.UUID      `json:"id"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	EpisodeID      uuid.UUID      `json:"episode_id"`
+	SessionName    string         `json:"session_name"`
+	Position       int32          `json:"position"`
+	TrackName      string         `json:"track_name"`
+	Comments       sql.NullString `json:"comments"`
+	ClipCount      int32          `json:"clip_count"`
+	MutedClipCount int32          `json:"muted_clip_count"`
+	ClipFrames     int64          `json:"clip_frames"`
+	FrameRate      string         `json:"frame_rate"`
+}
+
+type Milestone struct {
+	ID          uuid.UUID      `json:"id"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	ProjectID   uuid.UUID      `json:"project_id"`
+	EpisodeID   uuid.NullUUID  `json:"episode_id"`
+	Kind        MilestoneKind  `json:"kind"`
+	Title       string         `json:"title"`
+	DueDate     time.Time      `json:"due_date"`
+	CompletedAt sql.NullTime   `json:"completed_at"`
+	Notes       sql.NullString `json:"notes"`
+}
+
+type Part struct {
+	ID            uuid.UUID      `json:"id"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	Code          string         `json:"code"`
+	DisplayName   string         `json:"display_name"`
+	SortOrder     int32          `json:"sort_order"`
+	Active        bool           `json:"active"`
+	BillingWeight sql.NullString `json:"billing_weight"`
+}
+
 type Project struct {
+	ID              uuid.UUID     `json:"id"`
+	CreatedAt       time.Time     `json:"created_at"`
+	UpdatedAt       time.Time     `json:"updated_at"`
+	Title           string        `json:"title"`
+	ClientID        uuid.UUID     `json:"client_id"`
+	Status          ProjectStatus `json:"status"`
+	StatusChangedAt sql.NullTime  `json:"status_changed_at"`
+	ProjectType     ProjectType   `json:"project_type"`
+	DeletedAt       sql.NullTime  `json:"deleted_at"`
+}
+
+type ProjectMember struct {
+	ID        uuid.UUID   `json:"id"`
+	CreatedAt time.Time   `json:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at"`
+	ProjectID uuid.UUID   `json:"project_id"`
+	UserID    uuid.UUID   `json:"user_id"`
+	Role      ProjectRole `json:"role"`
+}
+
+type ProjectUnit struct {
+	ID           uuid.UUID      `json:"id"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	ProjectID    uuid.UUID      `json:"project_id"`
+	ParentID     uuid.NullUUID  `json:"parent_id"`
+	Kind         UnitKind       `json:"kind"`
+	Name         string         `json:"name"`
+	SortOrder    int32          `json:"sort_order"`
+	Number       sql.NullInt32  `json:"number"`
+	StartsOn     sql.NullTime   `json:"starts_on"`
+	EndsOn       sql.NullTime   `json:"ends_on"`
+	Budget       sql.NullString `json:"budget"`
+	Currency     sql.NullString `json:"currency"`
+	ExchangeRate sql.NullString `json:"exchange_rate"`
+}
+
+type RefreshToken struct {
+	Token       string         `json:"token"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	UserID      uuid.UUID      `json:"user_id"`
+	ExpiresAt   time.Time      `json:"expires_at"`
+	RevokedAt   sql.NullTime   `json:"revoked_at"`
+	FamilyID    uuid.UUID      `json:"family_id"`
+	ReplacedBy  sql.NullString `json:"replaced_by"`
+	DeviceLabel sql.NullString `json:"device_label"`
+	UserAgent   sql.NullString `json:"user_agent"`
+	IpAddress   sql.NullString `json:"ip_address"`
+	LastUsedAt  time.Time      `json:"last_used_at"`
+}
+
+type Room struct {
+	ID        uuid.UUID      `json:"id"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	RoomName  string         `json:"room_name"`
+	Notes     sql.NullString `json:"notes"`
+}
+
+type Session struct {
+	ID           uuid.UUID      `json:"id"`
+	SessionDate  time.Time      `json:"session_date"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	EpisodeID    uuid.NullUUID  `json:"episode_id"`
+	ProjectID    uuid.UUID      `json:"project_id"`
+	Duration     int32          `json:"duration"`
+	PartWorkedOn string         `json:"part_worked_on"`
+	ActivityDone string         `json:"activity_done"`
+	ExternalUid  sql.NullString `json:"external_uid"`
+	StartTime    sql.NullTime   `json:"start_time"`
+	Status       ApprovalStatus `json:"status"`
+	TimesheetID  uuid.NullUUID  `json:"timesheet_id"`
+	UnitID       uuid.NullUUID  `json:"unit_id"`
+	DeletedAt    sql.NullTime   `json:"deleted_at"`
+}
+
+type SessionCue struct {
 	ID        uuid.UUID `json:"id"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
-	Title     string    `json:"title"`
-	ClientID  uuid.UUID `json:"client_id"`
+	SessionID uuid.UUID `json:"session_id"`
+	CueID     uuid.UUID `json:"cue_id"`
 }
 
-type RefreshToken struct {
-	Token     string       `json:"token"`
-	CreatedAt time.Time    `json:"created_at"`
-	UpdatedAt time.Time    `json:"updated_at"`
-	UserID    uuid.UUID    `json:"user_id"`
-	ExpiresAt time.Time    `json:"expires_at"`
-	RevokedAt sql.NullTime `json:"revoked_at"`
+type Timesheet struct {
+	ID            uuid.UUID      `json:"id"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	UserID        uuid.UUID      `json:"user_id"`
+	WeekStart     time.Time      `json:"week_start"`
+	Status        ApprovalStatus `json:"status"`
+	SubmittedAt   time.Time      `json:"submitted_at"`
+	ReviewedBy    uuid.NullUUID  `json:"reviewed_by"`
+	ReviewedAt    sql.NullTime   `json:"reviewed_at"`
+	ReviewComment sql.NullString `json:"review_comment"`
 }
 
-type Session struct {
-	ID           uuid.UUID `json:"id"`
-	SessionDate  time.Time `json:"session_date"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
-	EpisodeID    uuid.UUID `json:"episode_id"`
-	ProjectID    uuid.UUID `json:"project_id"`
-	Duration     int32     `json:"duration"`
-	PartWorkedOn Part      `json:"part_worked_on"`
-	ActivityDone Activity  `json:"activity_done"`
+type UnitCalc struct {
+	ID        uuid.UUID `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	UnitID    uuid.UUID `json:"unit_id"`
+	CalcID    uuid.UUID `json:"calc_id"`
 }
 
 type User struct {
@@ -178,6 +789,15 @@ type User struct {
 	Username       string    `json:"username"`
 	Email          string    `json:"email"`
 	HashedPassword string    `json:"hashed_password"`
+	Role           UserRole  `json:"role"`
+}
+
+type UserBooking struct {
+	ID        uuid.UUID `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	UserID    uuid.UUID `json:"user_id"`
+	BookingID uuid.UUID `json:"booking_id"`
 }
 
 type UserSession struct {
@@ -14,20 +14,31 @@ import (
 const createProject = `-- name: CreateProject :one
 INSERT INTO projects (
     title,
-    client_id
+    client_id,
+    status,
+    project_type
 ) VALUES (
     $1,
-    $2
-) RETURNING id, created_at, updated_at, title, client_id
+    $2,
+    $3,
+    $4
+) RETURNING id, created_at, updated_at, title, client_id, status, status_changed_at, project_type, deleted_at
 `
 
 type CreateProjectParams struct {
-	Title    string    `json:"title"`
-	ClientID uuid.UUID `json:"client_id"`
+	Title       string        `json:"title"`
+	ClientID    uuid.UUID     `json:"client_id"`
+	Status      ProjectStatus `json:"status"`
+	ProjectType ProjectType   `json:"project_type"`
 }
 
 func (q *Queries) CreateProject(ctx context.Context, arg CreateProjectParams) (Project, error) {
-	row := q.db.QueryRowContext(ctx, createProject, arg.Title, arg.ClientID)
+	row := q.db.QueryRowContext(ctx, createProject,
+		arg.Title,
+		arg.ClientID,
+		arg.Status,
+		arg.ProjectType,
+	)
 	var i Project
 	err := row.Scan(
 		&i.ID,
@@ -35,12 +46,16 @@ func (q *Queries) CreateProject(ctx context.Context, arg CreateProjectParams) (P
 		&i.UpdatedAt,
 		&i.Title,
 		&i.ClientID,
+		&i.Status,
+		&i.StatusChangedAt,
+		&i.ProjectType,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
 const deleteProject = `-- name: DeleteProject :one
-DELETE FROM projects WHERE id=$1 RETURNING id, created_at, updated_at, title, client_id
+DELETE FROM projects WHERE id=$1 RETURNING id, created_at, updated_at, title, client_id, status, status_changed_at, project_type, deleted_at
 `
 
 func (q *Queries) DeleteProject(ctx context.Context, id uuid.UUID) (Project, error) {
@@ -52,12 +67,16 @@ func (q *Queries) DeleteProject(ctx context.Context, id uuid.UUID) (Project, err
 		&i.UpdatedAt,
 		&i.Title,
 		&i.ClientID,
+		&i.Status,
+		&i.StatusChangedAt,
+		&i.ProjectType,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
 const getAllProjects = `-- name: GetAllProjects :many
-SELECT id, created_at, updated_at, title, client_id FROM projects
+SELECT id, created_at, updated_at, title, client_id, status, status_changed_at, project_type, deleted_at FROM projects WHERE deleted_at IS NULL
 `
 
 func (q *Queries) GetAllProjects(ctx context.Context) ([]Project, error) {
@@ -75,6 +94,10 @@ func (q *Queries) GetAllProjects(ctx context.Context) ([]Project, error) {
 			&i.UpdatedAt,
 			&i.Title,
 			&i.ClientID,
+			&i.Status,
+			&i.StatusChangedAt,
+			&i.ProjectType,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -90,7 +113,7 @@ func (q *Queries) GetAllProjects(ctx context.Context) ([]Project, error) {
 }
 
 const getProjectByID = `-- name: GetProjectByID :one
-SELECT id, created_at, updated_at, title, client_id FROM projects WHERE id=$1
+SELECT id, created_at, updated_at, title, client_id, status, status_changed_at, project_type, deleted_at FROM projects WHERE id=$1 AND deleted_at IS NULL
 `
 
 func (q *Queries) GetProjectByID(ctx context.Context, id uuid.UUID) (Project, error) {
@@ -102,12 +125,16 @@ func (q *Queries) GetProjectByID(ctx context.Context, id uuid.UUID) (Project, er
 		&i.UpdatedAt,
 		&i.Title,
 		&i.ClientID,
+		&i.Status,
+		&i.StatusChangedAt,
+		&i.ProjectType,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
 const getProjectByTitle = `-- name: GetProjectByTitle :one
-SELECT id, created_at, updated_at, title, client_id FROM projects WHERE title=$1
+SELECT id, created_at, updated_at, title, client_id, status, status_changed_at, project_type, deleted_at FROM projects WHERE title=$1 AND deleted_at IS NULL
 `
 
 func (q *Queries) GetProjectByTitle(ctx context.Context, title string) (Project, error) {
@@ -119,12 +146,16 @@ func (q *Queries) GetProjectByTitle(ctx context.Context, title string) (Project,
 		&i.UpdatedAt,
 		&i.Title,
 		&i.ClientID,
+		&i.Status,
+		&i.StatusChangedAt,
+		&i.ProjectType,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
 const getProjectsByClient = `-- name: GetProjectsByClient :many
-SELECT id, created_at, updated_at, title, client_id FROM projects WHERE client_id=$1
+SELECT id, created_at, updated_at, title, client_id, status, status_changed_at, project_type, deleted_at FROM projects WHERE client_id=$1 AND deleted_at IS NULL
 `
 
 func (q *Queries) GetProjectsByClient(ctx context.Context, clientID uuid.UUID) ([]Project, error) {
@@ -142,6 +173,52 @@ func (q *Queries) GetProjectsByClient(ctx context.Context, clientID uuid.UUID) (
 			&i.UpdatedAt,
 			&i.Title,
 			&i.ClientID,
+			&i.Status,
+			&i.StatusChangedAt,
+			&i.ProjectType,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const moveProjectsToClient = `-- name: MoveProjectsToClient :many
+UPDATE projects SET updated_at=NOW(), client_id=$2 WHERE client_id=$1 RETURNING id, created_at, updated_at, title, client_id, status, status_changed_at, project_type, deleted_at
+`
+
+type MoveProjectsToClientParams struct {
+	FromClientID uuid.UUID `json:"from_client_id"`
+	ToClientID   uuid.UUID `json:"to_client_id"`
+}
+
+func (q *Queries) MoveProjectsToClient(ctx context.Context, arg MoveProjectsToClientParams) ([]Project, error) {
+	rows, err := q.db.QueryContext(ctx, moveProjectsToClient, arg.FromClientID, arg.ToClientID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Project
+	for rows.Next() {
+		var i Project
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Title,
+			&i.ClientID,
+			&i.Status,
+			&i.StatusChangedAt,
+			&i.ProjectType,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -156,22 +233,59 @@ func (q *Queries) GetProjectsByClient(ctx context.Context, clientID uuid.UUID) (
 	return items, nil
 }
 
+const setProjectStatus = `-- name: SetProjectStatus :one
+UPDATE projects SET
+    updated_at = NOW(),
+    status = $2,
+    status_changed_at = NOW()
+WHERE id = $1 AND deleted_at IS NULL RETURNING id, created_at, updated_at, title, client_id, status, status_changed_at, project_type, deleted_at
+`
+
+type SetProjectStatusParams struct {
+	ID     uuid.UUID     `json:"id"`
+	Status ProjectStatus `json:"status"`
+}
+
+func (q *Queries) SetProjectStatus(ctx context.Context, arg SetProjectStatusParams) (Project, error) {
+	row := q.db.QueryRowContext(ctx, setProjectStatus, arg.ID, arg.Status)
+	var i Project
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Title,
+		&i.ClientID,
+		&i.Status,
+		&i.StatusChangedAt,
+		&i.ProjectType,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
 const updateProject = `-- name: UpdateProject :one
 UPDATE projects SET
     updated_at = NOW(),
     title = $2,
-    client_id = $3
-WHERE id = $1 RETURNING id, created_at, updated_at, title, client_id
+    client_id = $3,
+    project_type = $4
+WHERE id = $1 AND deleted_at IS NULL RETURNING id, created_at, updated_at, title, client_id, status, status_changed_at, project_type, deleted_at
 `
 
 type UpdateProjectParams struct {
-	ID       uuid.UUID `json:"id"`
-	Title    string    `json:"title"`
-	ClientID uuid.UUID `json:"client_id"`
+	ID          uuid.UUID   `json:"id"`
+	Title       string      `json:"title"`
+	ClientID    uuid.UUID   `json:"client_id"`
+	ProjectType ProjectType `json:"project_type"`
 }
 
 func (q *Queries) UpdateProject(ctx context.Context, arg UpdateProjectParams) (Project, error) {
-	row := q.db.QueryRowContext(ctx, updateProject, arg.ID, arg.Title, arg.ClientID)
+	row := q.db.QueryRowContext(ctx, updateProject,
+		arg.ID,
+		arg.Title,
+		arg.ClientID,
+		arg.ProjectType,
+	)
 	var i Project
 	err := row.Scan(
 		&i.ID,
@@ -179,6 +293,10 @@ func (q *Queries) UpdateProject(ctx context.Context, arg UpdateProjectParams) (P
 		&i.UpdatedAt,
 		&i.Title,
 		&i.ClientID,
+		&i.Status,
+		&i.StatusChangedAt,
+		&i.ProjectType,
+		&i.DeletedAt,
 	)
 	return i, err
 }
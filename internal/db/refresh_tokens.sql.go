@@ -7,13 +7,69 @@ package db
 
 import (
 	"context"
+	"database/sql"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+const getActiveLogins = `-- name: GetActiveLogins :many
+SELECT
+    refresh_tokens.family_id,
+    refresh_tokens.device_label,
+    refresh_tokens.user_agent,
+    refresh_tokens.ip_address,
+    (SELECT MIN(first.created_at) FROM refresh_tokens first WHERE first.family_id = refresh_tokens.family_id)::timestamp AS logged_in_at,
+    refresh_tokens.last_used_at,
+    refresh_tokens.expires_at
+FROM refresh_tokens
+WHERE refresh_tokens.user_id = $1 AND refresh_tokens.revoked_at IS NULL AND refresh_tokens.expires_at > NOW()
+ORDER BY refresh_tokens.last_used_at DESC
+`
+
+type GetActiveLoginsRow struct {
+	FamilyID    uuid.UUID      `json:"family_id"`
+	DeviceLabel sql.NullString `json:"device_label"`
+	UserAgent   sql.NullString `json:"user_agent"`
+	IpAddress   sql.NullString `json:"ip_address"`
+	LoggedInAt  time.Time      `json:"logged_in_at"`
+	LastUsedAt  time.Time      `json:"last_used_at"`
+	ExpiresAt   time.Time      `json:"expires_at"`
+}
+
+func (q *Queries) GetActiveLogins(ctx context.Context, userID uuid.UUID) ([]GetActiveLoginsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getActiveLogins, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetActiveLoginsRow
+	for rows.Next() {
+		var i GetActiveLoginsRow
+		if err := rows.Scan(
+			&i.FamilyID,
+			&i.DeviceLabel,
+			&i.UserAgent,
+			&i.IpAddress,
+			&i.LoggedInAt,
+			&i.LastUsedAt,
+			&i.ExpiresAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getRefToken = `-- name: GetRefToken :one
-SELECT token, created_at, updated_at, user_id, expires_at, revoked_at FROM refresh_tokens WHERE token = $1
+SELECT token, created_at, updated_at, user_id, expires_at, revoked_at, family_id, replaced_by, device_label, user_agent, ip_address, last_used_at FROM refresh_tokens WHERE token = $1
 `
 
 func (q *Queries) GetRefToken(ctx context.Context, token string) (RefreshToken, error) {
@@ -26,6 +82,12 @@ func (q *Queries) GetRefToken(ctx context.Context, token string) (RefreshToken,
 		&i.UserID,
 		&i.ExpiresAt,
 		&i.RevokedAt,
+		&i.FamilyID,
+		&i.ReplacedBy,
+		&i.DeviceLabel,
+		&i.UserAgent,
+		&i.IpAddress,
+		&i.LastUsedAt,
 	)
 	return i, err
 }
@@ -42,7 +104,7 @@ func (q *Queries) GetUserFromRefToken(ctx context.Context, token string) (uuid.U
 }
 
 const revokeToken = `-- name: RevokeToken :one
-UPDATE refresh_tokens SET revoked_at = Now(), updated_at = Now() WHERE token = $1 RETURNING token, created_at, updated_at, user_id, expires_at, revoked_at
+UPDATE refresh_tokens SET revoked_at = Now(), updated_at = Now() WHERE token = $1 RETURNING token, created_at, updated_at, user_id, expires_at, revoked_at, family_id, replaced_by, device_label, user_agent, ip_address, last_used_at
 `
 
 func (q *Queries) RevokeToken(ctx context.Context, token string) (RefreshToken, error) {
@@ -55,23 +117,162 @@ func (q *Queries) RevokeToken(ctx context.Context, token string) (RefreshToken,
 		&i.UserID,
 		&i.ExpiresAt,
 		&i.RevokedAt,
+		&i.FamilyID,
+		&i.ReplacedBy,
+		&i.DeviceLabel,
+		&i.UserAgent,
+		&i.IpAddress,
+		&i.LastUsedAt,
+	)
+	return i, err
+}
+
+const revokeTokenFamily = `-- name: RevokeTokenFamily :many
+UPDATE refresh_tokens SET revoked_at = NOW(), updated_at = NOW()
+WHERE family_id = $1 AND revoked_at IS NULL RETURNING token
+`
+
+func (q *Queries) RevokeTokenFamily(ctx context.Context, familyID uuid.UUID) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, revokeTokenFamily, familyID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var token string
+		if err := rows.Scan(&token); err != nil {
+			return nil, err
+		}
+		items = append(items, token)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const revokeUserTokenFamily = `-- name: RevokeUserTokenFamily :many
+UPDATE refresh_tokens SET revoked_at = NOW(), updated_at = NOW()
+WHERE family_id = $1 AND user_id = $2 AND revoked_at IS NULL RETURNING token
+`
+
+type RevokeUserTokenFamilyParams struct {
+	FamilyID uuid.UUID `json:"family_id"`
+	UserID   uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) RevokeUserTokenFamily(ctx context.Context, arg RevokeUserTokenFamilyParams) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, revokeUserTokenFamily, arg.FamilyID, arg.UserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var token string
+		if err := rows.Scan(&token); err != nil {
+			return nil, err
+		}
+		items = append(items, token)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const revokeUserTokens = `-- name: RevokeUserTokens :many
+UPDATE refresh_tokens SET revoked_at = NOW(), updated_at = NOW()
+WHERE user_id = $1 AND revoked_at IS NULL RETURNING token
+`
+
+func (q *Queries) RevokeUserTokens(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, revokeUserTokens, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var token string
+		if err := rows.Scan(&token); err != nil {
+			return nil, err
+		}
+		items = append(items, token)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const rotateRefToken = `-- name: RotateRefToken :one
+UPDATE refresh_tokens SET revoked_at = NOW(), updated_at = NOW(), replaced_by = $2
+WHERE token = $1 AND revoked_at IS NULL RETURNING token, created_at, updated_at, user_id, expires_at, revoked_at, family_id, replaced_by, device_label, user_agent, ip_address, last_used_at
+`
+
+type RotateRefTokenParams struct {
+	Token      string         `json:"token"`
+	ReplacedBy sql.NullString `json:"replaced_by"`
+}
+
+func (q *Queries) RotateRefToken(ctx context.Context, arg RotateRefTokenParams) (RefreshToken, error) {
+	row := q.db.QueryRowContext(ctx, rotateRefToken, arg.Token, arg.ReplacedBy)
+	var i RefreshToken
+	err := row.Scan(
+		&i.Token,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
+		&i.ExpiresAt,
+		&i.RevokedAt,
+		&i.FamilyID,
+		&i.ReplacedBy,
+		&i.DeviceLabel,
+		&i.UserAgent,
+		&i.IpAddress,
+		&i.LastUsedAt,
 	)
 	return i, err
 }
 
 const setRefToken = `-- name: SetRefToken :one
-INSERT INTO refresh_tokens (token, created_at, updated_at, user_id, expires_at,        revoked_at) VALUES 
-                              ($1,      NOW(),      NOW(),      $2, $3, NULL      ) RETURNING token, created_at, updated_at, user_id, expires_at, revoked_at
+INSERT INTO refresh_tokens (token, created_at, updated_at, user_id, expires_at,        revoked_at, family_id,
+                            device_label, user_agent, ip_address, last_used_at) VALUES 
+                              ($1,      NOW(),      NOW(),      $2, $3, NULL      , $4,
+                            $5, $6, $7, NOW()) RETURNING token, created_at, updated_at, user_id, expires_at, revoked_at, family_id, replaced_by, device_label, user_agent, ip_address, last_used_at
 `
 
 type SetRefTokenParams struct {
-	Token     string    `json:"token"`
-	UserID    uuid.UUID `json:"user_id"`
-	ExpiresAt time.Time `json:"expires_at"`
+	Token       string         `json:"token"`
+	UserID      uuid.UUID      `json:"user_id"`
+	ExpiresAt   time.Time      `json:"expires_at"`
+	FamilyID    uuid.UUID      `json:"family_id"`
+	DeviceLabel sql.NullString `json:"device_label"`
+	UserAgent   sql.NullString `json:"user_agent"`
+	IpAddress   sql.NullString `json:"ip_address"`
 }
 
 func (q *Queries) SetRefToken(ctx context.Context, arg SetRefTokenParams) (RefreshToken, error) {
-	row := q.db.QueryRowContext(ctx, setRefToken, arg.Token, arg.UserID, arg.ExpiresAt)
+	row := q.db.QueryRowContext(ctx, setRefToken,
+		arg.Token,
+		arg.UserID,
+		arg.ExpiresAt,
+		arg.FamilyID,
+		arg.DeviceLabel,
+		arg.UserAgent,
+		arg.IpAddress,
+	)
 	var i RefreshToken
 	err := row.Scan(
 		&i.Token,
@@ -80,6 +281,12 @@ func (q *Queries) SetRefToken(ctx context.Context, arg SetRefTokenParams) (Refre
 		&i.UserID,
 		&i.ExpiresAt,
 		&i.RevokedAt,
+		&i.FamilyID,
+		&i.ReplacedBy,
+		&i.DeviceLabel,
+		&i.UserAgent,
+		&i.IpAddress,
+		&i.LastUsedAt,
 	)
 	return i, err
 }
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 const addUserToSession = `-- name: AddUserToSession :one
@@ -45,35 +46,45 @@ const createSession = `-- name: CreateSession :one
 INSERT INTO sessions (
     duration,
     session_date,
-    episode_id,
     project_id,
+    episode_id,
+    unit_id,
     part_worked_on,
-    activity_done
+    activity_done,
+    start_time
 ) VALUES (
     $1,
     $2,
     $3,
-    (SELECT project_id FROM episodes WHERE id = $3),
     $4,
-    $5
-) RETURNING id, session_date, created_at, updated_at, episode_id, project_id, duration, part_worked_on, activity_done
+    $5,
+    $6,
+    $7,
+    $8
+) RETURNING id, session_date, created_at, updated_at, episode_id, project_id, duration, part_worked_on, activity_done, external_uid, start_time, status, timesheet_id, unit_id, deleted_at
 `
 
 type CreateSessionParams struct {
-	Duration     int32     `json:"duration"`
-	SessionDate  time.Time `json:"session_date"`
-	EpisodeID    uuid.UUID `json:"episode_id"`
-	PartWorkedOn Part      `json:"part_worked_on"`
-	ActivityDone Activity  `json:"activity_done"`
+	Duration     int32         `json:"duration"`
+	SessionDate  time.Time     `json:"session_date"`
+	ProjectID    uuid.UUID     `json:"project_id"`
+	EpisodeID    uuid.NullUUID `json:"episode_id"`
+	UnitID       uuid.NullUUID `json:"unit_id"`
+	PartWorkedOn string        `json:"part_worked_on"`
+	ActivityDone string        `json:"activity_done"`
+	StartTime    sql.NullTime  `json:"start_time"`
 }
 
 func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error) {
 	row := q.db.QueryRowContext(ctx, createSession,
 		arg.Duration,
 		arg.SessionDate,
+		arg.ProjectID,
 		arg.EpisodeID,
+		arg.UnitID,
 		arg.PartWorkedOn,
 		arg.ActivityDone,
+		arg.StartTime,
 	)
 	var i Session
 	err := row.Scan(
@@ -86,12 +97,18 @@ func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) (S
 		&i.Duration,
 		&i.PartWorkedOn,
 		&i.ActivityDone,
+		&i.ExternalUid,
+		&i.StartTime,
+		&i.Status,
+		&i.TimesheetID,
+		&i.UnitID,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
 const deleteSession = `-- name: DeleteSession :one
-DELETE FROM sessions WHERE id = $1 RETURNING id, session_date, created_at, updated_at, episode_id, project_id, duration, part_worked_on, activity_done
+DELETE FROM sessions WHERE id = $1 RETURNING id, session_date, created_at, updated_at, episode_id, project_id, duration, part_worked_on, activity_done, external_uid, start_time, status, timesheet_id, unit_id, deleted_at
 `
 
 func (q *Queries) DeleteSession(ctx context.Context, id uuid.UUID) (Session, error) {
@@ -107,6 +124,12 @@ func (q *Queries) DeleteSession(ctx context.Context, id uuid.UUID) (Session, err
 		&i.Duration,
 		&i.PartWorkedOn,
 		&i.ActivityDone,
+		&i.ExternalUid,
+		&i.StartTime,
+		&i.Status,
+		&i.TimesheetID,
+		&i.UnitID,
+		&i.DeletedAt,
 	)
 	return i, err
 }
@@ -115,32 +138,41 @@ const getSession = `-- name: GetSession :one
 SELECT 
     sessions.id,
     sessions.session_date,
+    sessions.start_time,
     sessions.created_at,
     sessions.updated_at,
     sessions.duration,
     sessions.part_worked_on,
     sessions.activity_done,
+    sessions.status,
     episodes.id AS episode_id,
     episodes.title AS episode_title,
     episodes.episode_number AS episode_number,
+    project_units.id AS unit_id,
+    project_units.name AS unit_name,
     projects.id AS project_id,
     projects.title AS project_title
 FROM sessions
-JOIN episodes ON episodes.id = sessions.episode_id
-JOIN projects ON projects.id = sessions.project_id WHERE sessions.id = $1
+LEFT JOIN episodes ON episodes.id = sessions.episode_id
+LEFT JOIN project_units ON project_units.id = sessions.unit_id
+JOIN projects ON projects.id = sessions.project_id WHERE sessions.id = $1 AND sessions.deleted_at IS NULL
 `
 
 type GetSessionRow struct {
 	ID            uuid.UUID      `json:"id"`
 	SessionDate   time.Time      `json:"session_date"`
+	StartTime     sql.NullTime   `json:"start_time"`
 	CreatedAt     time.Time      `json:"created_at"`
 	UpdatedAt     time.Time      `json:"updated_at"`
 	Duration      int32          `json:"duration"`
-	PartWorkedOn  Part           `json:"part_worked_on"`
-	ActivityDone  Activity       `json:"activity_done"`
-	EpisodeID     uuid.UUID      `json:"episode_id"`
+	PartWorkedOn  string         `json:"part_worked_on"`
+	ActivityDone  string         `json:"activity_done"`
+	Status        ApprovalStatus `json:"status"`
+	EpisodeID     uuid.NullUUID  `json:"episode_id"`
 	EpisodeTitle  sql.NullString `json:"episode_title"`
-	EpisodeNumber int32          `json:"episode_number"`
+	EpisodeNumber sql.NullInt32  `json:"episode_number"`
+	UnitID        uuid.NullUUID  `json:"unit_id"`
+	UnitName      sql.NullString `json:"unit_name"`
 	ProjectID     uuid.UUID      `json:"project_id"`
 	ProjectTitle  string         `json:"project_title"`
 }
@@ -151,26 +183,30 @@ func (q *Queries) GetSession(ctx context.Context, id uuid.UUID) (GetSessionRow,
 	err := row.Scan(
 		&i.ID,
 		&i.SessionDate,
+		&i.StartTime,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Duration,
 		&i.PartWorkedOn,
 		&i.ActivityDone,
+		&i.Status,
 		&i.EpisodeID,
 		&i.EpisodeTitle,
 		&i.EpisodeNumber,
+		&i.UnitID,
+		&i.UnitName,
 		&i.ProjectID,
 		&i.ProjectTitle,
 	)
 	return i, err
 }
 
-const getSessions = `-- name: GetSessions :many
-SELECT id, session_date, created_at, updated_at, episode_id, project_id, duration, part_worked_on, activity_done FROM sessions ORDER BY session_date DESC LIMIT $1
+const getSessionsByExternalUID = `-- name: GetSessionsByExternalUID :many
+SELECT id, session_date, created_at, updated_at, episode_id, project_id, duration, part_worked_on, activity_done, external_uid, start_time, status, timesheet_id, unit_id, deleted_at FROM sessions WHERE external_uid = ANY($1::text[])
 `
 
-func (q *Queries) GetSessions(ctx context.Context, limit int32) ([]Session, error) {
-	rows, err := q.db.QueryContext(ctx, getSessions, limit)
+func (q *Queries) GetSessionsByExternalUID(ctx context.Context, externalUids []string) ([]Session, error) {
+	rows, err := q.db.QueryContext(ctx, getSessionsByExternalUID, pq.Array(externalUids))
 	if err != nil {
 		return nil, err
 	}
@@ -188,6 +224,12 @@ func (q *Queries) GetSessions(ctx context.Context, limit int32) ([]Session, erro
 			&i.Duration,
 			&i.PartWorkedOn,
 			&i.ActivityDone,
+			&i.ExternalUid,
+			&i.StartTime,
+			&i.Status,
+			&i.TimesheetID,
+			&i.UnitID,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -202,34 +244,52 @@ func (q *Queries) GetSessions(ctx context.Context, limit int32) ([]Session, erro
 	return items, nil
 }
 
-const getSessionsForEpisode = `-- name: GetSessionsForEpisode :many
-SELECT sessions.id, sessions.session_date, sessions.created_at, sessions.updated_at, sessions.episode_id, sessions.project_id, sessions.duration, sessions.part_worked_on, sessions.activity_done FROM sessions WHERE sessions.episode_id = $1 ORDER BY session_date DESC LIMIT $2
+const getSessionsForAnomalyReport = `-- name: GetSessionsForAnomalyReport :many
+SELECT
+    sessions.id,
+    sessions.session_date,
+    sessions.start_time,
+    sessions.duration,
+    user_session.user_id,
+    users.username
+FROM sessions
+LEFT JOIN user_session ON user_session.session_id = sessions.id
+LEFT JOIN users ON users.id = user_session.user_id
+WHERE sessions.session_date >= $1
+AND sessions.session_date <= $2 AND sessions.deleted_at IS NULL
+ORDER BY sessions.session_date, sessions.start_time
 `
 
-type GetSessionsForEpisodeParams struct {
-	EpisodeID uuid.UUID `json:"episode_id"`
-	Limit     int32     `json:"limit"`
+type GetSessionsForAnomalyReportParams struct {
+	DateFrom time.Time `json:"date_from"`
+	DateTo   time.Time `json:"date_to"`
+}
+
+type GetSessionsForAnomalyReportRow struct {
+	ID          uuid.UUID      `json:"id"`
+	SessionDate time.Time      `json:"session_date"`
+	StartTime   sql.NullTime   `json:"start_time"`
+	Duration    int32          `json:"duration"`
+	UserID      uuid.NullUUID  `json:"user_id"`
+	Username    sql.NullString `json:"username"`
 }
 
-func (q *Queries) GetSessionsForEpisode(ctx context.Context, arg GetSessionsForEpisodeParams) ([]Session, error) {
-	rows, err := q.db.QueryContext(ctx, getSessionsForEpisode, arg.EpisodeID, arg.Limit)
+func (q *Queries) GetSessionsForAnomalyReport(ctx context.Context, arg GetSessionsForAnomalyReportParams) ([]GetSessionsForAnomalyReportRow, error) {
+	rows, err := q.db.QueryContext(ctx, getSessionsForAnomalyReport, arg.DateFrom, arg.DateTo)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Session
+	var items []GetSessionsForAnomalyReportRow
 	for rows.Next() {
-		var i Session
+		var i GetSessionsForAnomalyReportRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.SessionDate,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-			&i.EpisodeID,
-			&i.ProjectID,
+			&i.StartTime,
 			&i.Duration,
-			&i.PartWorkedOn,
-			&i.ActivityDone,
+			&i.UserID,
+			&i.Username,
 		); err != nil {
 			return nil, err
 		}
@@ -244,34 +304,131 @@ func (q *Queries) GetSessionsForEpisode(ctx context.Context, arg GetSessionsForE
 	return items, nil
 }
 
-const getSessionsForProject = `-- name: GetSessionsForProject :many
-SELECT sessions.id, sessions.session_date, sessions.created_at, sessions.updated_at, sessions.episode_id, sessions.project_id, sessions.duration, sessions.part_worked_on, sessions.activity_done FROM sessions WHERE sessions.project_id = $1 ORDER BY session_date DESC LIMIT $2
+const getSessionsForFeed = `-- name: GetSessionsForFeed :many
+SELECT
+    sessions.id,
+    sessions.session_date,
+    sessions.updated_at,
+    sessions.duration,
+    sessions.part_worked_on,
+    sessions.activity_done,
+    episodes.title AS episode_title,
+    episodes.episode_number,
+    seasons.number AS season_number,
+    project_units.name AS unit_name,
+    sessions.project_id,
+    projects.title AS project_title
+FROM sessions
+LEFT JOIN episodes ON episodes.id = sessions.episode_id
+LEFT JOIN project_units AS seasons ON seasons.id = episodes.season_id
+LEFT JOIN project_units ON project_units.id = sessions.unit_id
+JOIN projects ON projects.id = sessions.project_id
+WHERE sessions.session_date >= $1::date AND sessions.deleted_at IS NULL
+AND ($2::uuid IS NULL OR EXISTS (
+    SELECT 1 FROM user_session WHERE user_session.session_id = sessions.id AND user_session.user_id = $2::uuid
+))
+AND ($3::uuid IS NULL OR sessions.project_id = $3::uuid)
+ORDER BY sessions.session_date ASC
 `
 
-type GetSessionsForProjectParams struct {
-	ProjectID uuid.UUID `json:"project_id"`
-	Limit     int32     `json:"limit"`
+type GetSessionsForFeedParams struct {
+	Since     time.Time     `json:"since"`
+	UserID    uuid.NullUUID `json:"user_id"`
+	ProjectID uuid.NullUUID `json:"project_id"`
+}
+
+type GetSessionsForFeedRow struct {
+	ID            uuid.UUID      `json:"id"`
+	SessionDate   time.Time      `json:"session_date"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	Duration      int32          `json:"duration"`
+	PartWorkedOn  string         `json:"part_worked_on"`
+	ActivityDone  string         `json:"activity_done"`
+	EpisodeTitle  sql.NullString `json:"episode_title"`
+	EpisodeNumber sql.NullInt32  `json:"episode_number"`
+	SeasonNumber  sql.NullInt32  `json:"season_number"`
+	UnitName      sql.NullString `json:"unit_name"`
+	ProjectID     uuid.UUID      `json:"project_id"`
+	ProjectTitle  string         `json:"project_title"`
 }
 
-func (q *Queries) GetSessionsForProject(ctx context.Context, arg GetSessionsForProjectParams) ([]Session, error) {
-	rows, err := q.db.QueryContext(ctx, getSessionsForProject, arg.ProjectID, arg.Limit)
+func (q *Queries) GetSessionsForFeed(ctx context.Context, arg GetSessionsForFeedParams) ([]GetSessionsForFeedRow, error) {
+	rows, err := q.db.QueryContext(ctx, getSessionsForFeed, arg.Since, arg.UserID, arg.ProjectID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Session
+	var items []GetSessionsForFeedRow
 	for rows.Next() {
-		var i Session
+		var i GetSessionsForFeedRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.SessionDate,
-			&i.CreatedAt,
 			&i.UpdatedAt,
-			&i.EpisodeID,
-			&i.ProjectID,
 			&i.Duration,
 			&i.PartWorkedOn,
 			&i.ActivityDone,
+			&i.EpisodeTitle,
+			&i.EpisodeNumber,
+			&i.SeasonNumber,
+			&i.UnitName,
+			&i.ProjectID,
+			&i.ProjectTitle,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSessionsForUsersOnDate = `-- name: GetSessionsForUsersOnDate :many
+SELECT
+    user_session.user_id,
+    sessions.id,
+    sessions.session_date,
+    sessions.start_time,
+    sessions.duration
+FROM sessions
+JOIN user_session ON user_session.session_id = sessions.id
+WHERE user_session.user_id = ANY($1::uuid[])
+AND sessions.session_date = $2 AND sessions.deleted_at IS NULL
+`
+
+type GetSessionsForUsersOnDateParams struct {
+	UserIds     []uuid.UUID `json:"user_ids"`
+	SessionDate time.Time   `json:"session_date"`
+}
+
+type GetSessionsForUsersOnDateRow struct {
+	UserID      uuid.UUID    `json:"user_id"`
+	ID          uuid.UUID    `json:"id"`
+	SessionDate time.Time    `json:"session_date"`
+	StartTime   sql.NullTime `json:"start_time"`
+	Duration    int32        `json:"duration"`
+}
+
+func (q *Queries) GetSessionsForUsersOnDate(ctx context.Context, arg GetSessionsForUsersOnDateParams) ([]GetSessionsForUsersOnDateRow, error) {
+	rows, err := q.db.QueryContext(ctx, getSessionsForUsersOnDate, pq.Array(arg.UserIds), arg.SessionDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetSessionsForUsersOnDateRow
+	for rows.Next() {
+		var i GetSessionsForUsersOnDateRow
+		if err := rows.Scan(
+			&i.UserID,
+			&i.ID,
+			&i.SessionDate,
+			&i.StartTime,
+			&i.Duration,
 		); err != nil {
 			return nil, err
 		}
@@ -318,24 +475,142 @@ func (q *Queries) GetUsersForSession(ctx context.Context, sessionID uuid.UUID) (
 	return items, nil
 }
 
+const getUsersForSessions = `-- name: GetUsersForSessions :many
+SELECT user_session.session_id, user_session.user_id, users.username FROM user_session
+JOIN users ON users.id = user_session.user_id
+WHERE user_session.session_id = ANY($1::uuid[])
+ORDER BY users.username
+`
+
+type GetUsersForSessionsRow struct {
+	SessionID uuid.UUID `json:"session_id"`
+	UserID    uuid.UUID `json:"user_id"`
+	Username  string    `json:"username"`
+}
+
+func (q *Queries) GetUsersForSessions(ctx context.Context, sessionIds []uuid.UUID) ([]GetUsersForSessionsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getUsersForSessions, pq.Array(sessionIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUsersForSessionsRow
+	for rows.Next() {
+		var i GetUsersForSessionsRow
+		if err := rows.Scan(&i.SessionID, &i.UserID, &i.Username); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const importSession = `-- name: ImportSession :one
+INSERT INTO sessions (
+    duration,
+    session_date,
+    project_id,
+    episode_id,
+    part_worked_on,
+    activity_done,
+    external_uid,
+    start_time
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8
+) ON CONFLICT (external_uid) DO UPDATE SET
+    duration = EXCLUDED.duration,
+    session_date = EXCLUDED.session_date,
+    start_time = EXCLUDED.start_time,
+    project_id = EXCLUDED.project_id,
+    episode_id = EXCLUDED.episode_id,
+    unit_id = NULL,
+    part_worked_on = EXCLUDED.part_worked_on,
+    activity_done = EXCLUDED.activity_done,
+    updated_at = NOW()
+WHERE sessions.status IN ('draft', 'rejected') AND sessions.deleted_at IS NULL
+RETURNING id, session_date, created_at, updated_at, episode_id, project_id, duration, part_worked_on, activity_done, external_uid, start_time, status, timesheet_id, unit_id, deleted_at
+`
+
+type ImportSessionParams struct {
+	Duration     int32          `json:"duration"`
+	SessionDate  time.Time      `json:"session_date"`
+	ProjectID    uuid.UUID      `json:"project_id"`
+	EpisodeID    uuid.NullUUID  `json:"episode_id"`
+	PartWorkedOn string         `json:"part_worked_on"`
+	ActivityDone string         `json:"activity_done"`
+	ExternalUid  sql.NullString `json:"external_uid"`
+	StartTime    sql.NullTime   `json:"start_time"`
+}
+
+func (q *Queries) ImportSession(ctx context.Context, arg ImportSessionParams) (Session, error) {
+	row := q.db.QueryRowContext(ctx, importSession,
+		arg.Duration,
+		arg.SessionDate,
+		arg.ProjectID,
+		arg.EpisodeID,
+		arg.PartWorkedOn,
+		arg.ActivityDone,
+		arg.ExternalUid,
+		arg.StartTime,
+	)
+	var i Session
+	err := row.Scan(
+		&i.ID,
+		&i.SessionDate,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.EpisodeID,
+		&i.ProjectID,
+		&i.Duration,
+		&i.PartWorkedOn,
+		&i.ActivityDone,
+		&i.ExternalUid,
+		&i.StartTime,
+		&i.Status,
+		&i.TimesheetID,
+		&i.UnitID,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
 const updateSession = `-- name: UpdateSession :one
 UPDATE sessions SET
     duration = $2,
     session_date = $3,
-    episode_id = $4,
-    project_id = (SELECT project_id FROM episodes WHERE id = $4),
-    part_worked_on = $5,
-    activity_done = $6
-WHERE sessions.id = $1 RETURNING id, session_date, created_at, updated_at, episode_id, project_id, duration, part_worked_on, activity_done
+    project_id = $4,
+    episode_id = $5,
+    unit_id = $6,
+    part_worked_on = $7,
+    activity_done = $8,
+    start_time = $9,
+    updated_at = NOW()
+WHERE sessions.id = $1 AND sessions.deleted_at IS NULL RETURNING id, session_date, created_at, updated_at, episode_id, project_id, duration, part_worked_on, activity_done, external_uid, start_time, status, timesheet_id, unit_id, deleted_at
 `
 
 type UpdateSessionParams struct {
-	ID           uuid.UUID `json:"id"`
-	Duration     int32     `json:"duration"`
-	SessionDate  time.Time `json:"session_date"`
-	EpisodeID    uuid.UUID `json:"episode_id"`
-	PartWorkedOn Part      `json:"part_worked_on"`
-	ActivityDone Activity  `json:"activity_done"`
+	ID           uuid.UUID     `json:"id"`
+	Duration     int32         `json:"duration"`
+	SessionDate  time.Time     `json:"session_date"`
+	ProjectID    uuid.UUID     `json:"project_id"`
+	EpisodeID    uuid.NullUUID `json:"episode_id"`
+	UnitID       uuid.NullUUID `json:"unit_id"`
+	PartWorkedOn string        `json:"part_worked_on"`
+	ActivityDone string        `json:"activity_done"`
+	StartTime    sql.NullTime  `json:"start_time"`
 }
 
 func (q *Queries) UpdateSession(ctx context.Context, arg UpdateSessionParams) (Session, error) {
@@ -343,9 +618,12 @@ func (q *Queries) UpdateSession(ctx context.Context, arg UpdateSessionParams) (S
 		arg.ID,
 		arg.Duration,
 		arg.SessionDate,
+		arg.ProjectID,
 		arg.EpisodeID,
+		arg.UnitID,
 		arg.PartWorkedOn,
 		arg.ActivityDone,
+		arg.StartTime,
 	)
 	var i Session
 	err := row.Scan(
@@ -358,6 +636,12 @@ func (q *Queries) UpdateSession(ctx context.Context, arg UpdateSessionParams) (S
 		&i.Duration,
 		&i.PartWorkedOn,
 		&i.ActivityDone,
+		&i.ExternalUid,
+		&i.StartTime,
+		&i.Status,
+		&i.TimesheetID,
+		&i.UnitID,
+		&i.DeletedAt,
 	)
 	return i, err
 }
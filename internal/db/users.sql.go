@@ -13,7 +13,7 @@ import (
 )
 
 const changePassword = `-- name: ChangePassword :one
-UPDATE users SET hashed_password = $2 WHERE id = $1 RETURNING id, created_at, updated_at, username, email, hashed_password
+UPDATE users SET hashed_password = $2 WHERE id = $1 RETURNING id, created_at, updated_at, username, email, hashed_password, role
 `
 
 type ChangePasswordParams struct {
@@ -31,6 +31,7 @@ func (q *Queries) ChangePassword(ctx context.Context, arg ChangePasswordParams)
 		&i.Username,
 		&i.Email,
 		&i.HashedPassword,
+		&i.Role,
 	)
 	return i, err
 }
@@ -39,12 +40,14 @@ const createUser = `-- name: CreateUser :one
 INSERT INTO users (
     username,
     email,
-    hashed_password
+    hashed_password,
+    role
 ) VALUES (
     $1,
     $2,
-    $3
-) RETURNING id, created_at, updated_at, username, email, hashed_password
+    $3,
+    CASE WHEN EXISTS (SELECT 1 FROM users) THEN 'engineer'::user_role ELSE 'admin'::user_role END
+) RETURNING id, created_at, updated_at, username, email, hashed_password, role
 `
 
 type CreateUserParams struct {
@@ -63,12 +66,13 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.Username,
 		&i.Email,
 		&i.HashedPassword,
+		&i.Role,
 	)
 	return i, err
 }
 
 const deleteUser = `-- name: DeleteUser :one
-DELETE FROM users WHERE id = $1 RETURNING id, created_at, updated_at, username, email, hashed_password
+DELETE FROM users WHERE id = $1 RETURNING id, created_at, updated_at, username, email, hashed_password, role
 `
 
 func (q *Queries) DeleteUser(ctx context.Context, id uuid.UUID) (User, error) {
@@ -81,12 +85,13 @@ func (q *Queries) DeleteUser(ctx context.Context, id uuid.UUID) (User, error) {
 		&i.Username,
 		&i.Email,
 		&i.HashedPassword,
+		&i.Role,
 	)
 	return i, err
 }
 
 const getAllUsers = `-- name: GetAllUsers :many
-SELECT id, created_at, updated_at, username, email FROM users
+SELECT id, created_at, updated_at, username, email, role FROM users
 `
 
 type GetAllUsersRow struct {
@@ -95,6 +100,7 @@ type GetAllUsersRow struct {
 	UpdatedAt time.Time `json:"updated_at"`
 	Username  string    `json:"username"`
 	Email     string    `json:"email"`
+	Role      UserRole  `json:"role"`
 }
 
 func (q *Queries) GetAllUsers(ctx context.Context) ([]GetAllUsersRow, error) {
@@ -112,6 +118,7 @@ func (q *Queries) GetAllUsers(ctx context.Context) ([]GetAllUsersRow, error) {
 			&i.UpdatedAt,
 			&i.Username,
 			&i.Email,
+			&i.Role,
 		); err != nil {
 			return nil, err
 		}
@@ -127,7 +134,7 @@ func (q *Queries) GetAllUsers(ctx context.Context) ([]GetAllUsersRow, error) {
 }
 
 const getUserByEmail = `-- name: GetUserByEmail :one
-SELECT id, created_at, updated_at, username, email, hashed_password FROM users WHERE email = $1
+SELECT id, created_at, updated_at, username, email, hashed_password, role FROM users WHERE email = $1
 `
 
 func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error) {
@@ -140,12 +147,13 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error
 		&i.Username,
 		&i.Email,
 		&i.HashedPassword,
+		&i.Role,
 	)
 	return i, err
 }
 
 const getUserByID = `-- name: GetUserByID :one
-SELECT id, created_at, updated_at, username, email, hashed_password FROM users WHERE id = $1
+SELECT id, created_at, updated_at, username, email, hashed_password, role FROM users WHERE id = $1
 `
 
 func (q *Queries) GetUserByID(ctx context.Context, id uuid.UUID) (User, error) {
@@ -158,12 +166,13 @@ func (q *Queries) GetUserByID(ctx context.Context, id uuid.UUID) (User, error) {
 		&i.Username,
 		&i.Email,
 		&i.HashedPassword,
+		&i.Role,
 	)
 	return i, err
 }
 
 const getUserByName = `-- name: GetUserByName :one
-SELECT id, created_at, updated_at, username, email, hashed_password FROM users WHERE username = $1
+SELECT id, created_at, updated_at, username, email, hashed_password, role FROM users WHERE username = $1
 `
 
 func (q *Queries) GetUserByName(ctx context.Context, username string) (User, error) {
@@ -176,6 +185,67 @@ func (q *Queries) GetUserByName(ctx context.Context, username string) (User, err
 		&i.Username,
 		&i.Email,
 		&i.HashedPassword,
+		&i.Role,
+	)
+	return i, err
+}
+
+const lockAdmins = `-- name: LockAdmins :many
+SELECT id FROM users WHERE role = 'admin' FOR UPDATE
+`
+
+func (q *Queries) LockAdmins(ctx context.Context) ([]uuid.UUID, error) {
+	rows, err := q.db.QueryContext(ctx, lockAdmins)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockUsersForSignup = `-- name: LockUsersForSignup :exec
+LOCK TABLE users IN SHARE ROW EXCLUSIVE MODE
+`
+
+func (q *Queries) LockUsersForSignup(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, lockUsersForSignup)
+	return err
+}
+
+const setUserRole = `-- name: SetUserRole :one
+UPDATE users SET updated_at = NOW(), role = $2 WHERE id = $1 RETURNING id, created_at, updated_at, username, email, hashed_password, role
+`
+
+type SetUserRoleParams struct {
+	ID   uuid.UUID `json:"id"`
+	Role UserRole  `json:"role"`
+}
+
+func (q *Queries) SetUserRole(ctx context.Context, arg SetUserRoleParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, setUserRole, arg.ID, arg.Role)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Username,
+		&i.Email,
+		&i.HashedPassword,
+		&i.Role,
 	)
 	return i, err
 }
@@ -185,7 +255,7 @@ UPDATE users SET
     updated_at = NOW(),
     username = $2,
     email = $3
-WHERE id = $1 RETURNING id, created_at, updated_at, username, email, hashed_password
+WHERE id = $1 RETURNING id, created_at, updated_at, username, email, hashed_password, role
 `
 
 type UpdateUserParams struct {
@@ -204,6 +274,7 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 		&i.Username,
 		&i.Email,
 		&i.HashedPassword,
+		&i.Role,
 	)
 	return i, err
 }
@@ -0,0 +1,240 @@
+// Package edl reads CMX3600 edit decision lists, the plain text format
+// picture editors export cut lists in.
+package edl
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/Denisowiec/FoleyBookkeeper/internal/timecode"
+)
+
+// Locator is a marker placed on the record side, written as
+// * LOC: 01:00:12:05 RED     comment
+type Locator struct {
+	Timecode timecode.Timecode
+	Color    string
+	Comment  string
+}
+
+type Event struct {
+	Number int
+	Reel   string
+	// Track is V, A, A2, AA/V, B and so on
+	Track      string
+	Transition string
+	// Length of a dissolve or wipe in frames
+	TransitionLength int
+	SourceIn         timecode.Timecode
+	SourceOut        timecode.Timecode
+	RecordIn         timecode.Timecode
+	RecordOut        timecode.Timecode
+	ClipName         string
+	ToClipName       string
+	Comments         []string
+	Locators         []Locator
+}
+
+// Line is a line of the list that couldn't be understood
+type Line struct {
+	Number int
+	Text   string
+	Reason string
+}
+
+type List struct {
+	Title string
+	// Rate of the events, it can be changed by FCM lines along the way
+	Rate     timecode.Rate
+	Comments []string
+	Events   []Event
+	Unparsed []Line
+}
+
+func isEventNumber(s string) bool {
+	if len(s) == 0 {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func parseEventLine(fields []string, rate timecode.Rate) (Event, error) {
+	// 001  AX       V     C        01:00:00:00 01:00:05:00 00:59:58:00 01:00:03:00
+	// 002  TAPE2    V     D    030 01:00:10:00 01:00:20:00 01:00:03:00 01:00:13:00
+	if len(fields) < 8 {
+		return Event{}, fmt.Errorf("event line is too short")
+	}
+	number, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return Event{}, fmt.Errorf("invalid event number %s", fields[0])
+	}
+	event := Event{
+		Number:     number,
+		Reel:       fields[1],
+		Track:      fields[2],
+		Transition: fields[3],
+	}
+
+	// Key transitions can be split like "K B", the length is the only number
+	for _, f := range fields[4 : len(fields)-4] {
+		if length, err := strconv.Atoi(f); err == nil {
+			event.TransitionLength = length
+			continue
+		}
+		event.Transition += " " + f
+	}
+
+	tcs := [4]timecode.Timecode{}
+	for i, f := range fields[len(fields)-4:] {
+		tcs[i], err = timecode.Parse(f, rate)
+		if err != nil {
+			return Event{}, err
+		}
+	}
+	event.SourceIn, event.SourceOut, event.RecordIn, event.RecordOut = tcs[0], tcs[1], tcs[2], tcs[3]
+	if event.RecordOut.Frames < event.RecordIn.Frames {
+		return Event{}, fmt.Errorf("event ends before it starts")
+	}
+	return event, nil
+}
+
+func parseLocator(s string, rate timecode.Rate) (Locator, error) {
+	// 01:00:12:05 RED     comment, the color and comment are optional
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return Locator{}, fmt.Errorf("locator has no timecode")
+	}
+	tc, err := timecode.Parse(fields[0], rate)
+	if err != nil {
+		return Locator{}, err
+	}
+	loc := Locator{Timecode: tc}
+	rest := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(s), fields[0]))
+	if len(fields) > 1 {
+		loc.Color = fields[1]
+		rest = strings.TrimSpace(strings.TrimPrefix(rest, fields[1]))
+	}
+	loc.Comment = rest
+	return loc, nil
+}
+
+// Parse reads an edit decision list. The rate is used unless the list
+// says otherwise, it may be left empty if the list has a frame rate header.
+// Lines that can't be understood don't stop the parsing, they end up
+// in Unparsed
+func Parse(r io.Reader, rate timecode.Rate) (List, error) {
+	list := List{Rate: rate}
+	var current *Event
+	// FCM lines can come before the frame rate header
+	dropFrame := false
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		text := strings.TrimSpace(strings.TrimRight(scanner.Text(), "\r"))
+		if text == "" {
+			continue
+		}
+		unparsed := func(reason string) {
+			list.Unparsed = append(list.Unparsed, Line{Number: lineNumber, Text: text, Reason: reason})
+		}
+		upper := strings.ToUpper(text)
+
+		switch {
+		case strings.HasPrefix(upper, "TITLE:"):
+			list.Title = strings.TrimSpace(text[len("TITLE:"):])
+
+		case strings.HasPrefix(upper, "FCM:"):
+			fcm := strings.TrimSpace(upper[len("FCM:"):])
+			switch fcm {
+			case "DROP FRAME":
+				dropFrame = true
+			case "NON-DROP FRAME", "NON DROP FRAME":
+				dropFrame = false
+			default:
+				unparsed("unknown frame code mode")
+				continue
+			}
+			list.Rate = list.Rate.WithDropFrame(dropFrame)
+
+		case strings.HasPrefix(strings.TrimLeft(upper, "* "), "FRAME RATE:"):
+			value := strings.TrimSpace(text[strings.Index(upper, "FRAME RATE:")+len("FRAME RATE:"):])
+			newRate, err := timecode.ParseRate(value)
+			if err != nil {
+				unparsed(err.Error())
+				continue
+			}
+			list.Rate = newRate.WithDropFrame(dropFrame)
+
+		case strings.HasPrefix(text, "*"):
+			comment := strings.TrimSpace(strings.TrimLeft(text, "*"))
+			commentUpper := strings.ToUpper(comment)
+			if current == nil {
+				list.Comments = append(list.Comments, comment)
+				continue
+			}
+			switch {
+			case strings.HasPrefix(commentUpper, "FROM CLIP NAME:"):
+				current.ClipName = strings.TrimSpace(comment[len("FROM CLIP NAME:"):])
+			case strings.HasPrefix(commentUpper, "TO CLIP NAME:"):
+				current.ToClipName = strings.TrimSpace(comment[len("TO CLIP NAME:"):])
+			case strings.HasPrefix(commentUpper, "LOC:"):
+				loc, err := parseLocator(comment[len("LOC:"):], list.Rate)
+				if err != nil {
+					unparsed(err.Error())
+					continue
+				}
+				current.Locators = append(current.Locators, loc)
+			default:
+				current.Comments = append(current.Comments, comment)
+			}
+
+		case isEventNumber(strings.Fields(text)[0]):
+			if list.Rate.Num == 0 {
+				return List{}, fmt.Errorf("frame rate of the list unknown")
+			}
+			event, err := parseEventLine(strings.Fields(text), list.Rate)
+			if err != nil {
+				unparsed(err.Error())
+				continue
+			}
+			// A dissolve is written as two lines with the same number, the
+			// outgoing clip first. The event keeps the incoming clip
+			if current != nil && current.Number == event.Number {
+				event.RecordIn = current.RecordIn
+				event.ClipName = current.ClipName
+				event.Comments = current.Comments
+				event.Locators = current.Locators
+				*current = event
+				continue
+			}
+			list.Events = append(list.Events, event)
+			current = &list.Events[len(list.Events)-1]
+
+		case strings.HasPrefix(upper, "M2 "), strings.HasPrefix(upper, "SPLIT:"),
+			strings.HasPrefix(upper, "AUD "), strings.HasPrefix(upper, "EFFECTS NAME IS"):
+			// Speed changes, split edits and effects don't change where
+			// the event sits on the record side
+			if current != nil {
+				current.Comments = append(current.Comments, text)
+			}
+
+		default:
+			unparsed("line not recognized")
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return List{}, err
+	}
+	return list, nil
+}
@@ -0,0 +1,85 @@
+package edl
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Denisowiec/FoleyBookkeeper/internal/timecode"
+)
+
+const testList = "TITLE: EP105 LOCKED CUT\r\n" +
+	"FCM: DROP FRAME\r\n" +
+	"\r\n" +
+	"001  A001C003 V     C        10:12:01;10 10:12:04;00 01:00:00;00 01:00:02;20\r\n" +
+	"* FROM CLIP NAME: DOOR SLAM WIDE\r\n" +
+	"* LOC: 01:00:01;05 RED     footsteps on gravel\r\n" +
+	"\r\n" +
+	"002  A001C007 V     C        11:00:00;00 11:00:00;00 01:00:02;20 01:00:02;20\r\n" +
+	"002  A002C001 V     D    015 11:30:10;00 11:30:15;00 01:00:02;20 01:00:07;20\r\n" +
+	"* FROM CLIP NAME: HALLWAY\r\n" +
+	"* TO CLIP NAME: KITCHEN\r\n" +
+	"M2   A002C001       048.0                11:30:10;00\r\n" +
+	"003  BL       V     C        00:00:00;00 00:00:01;00\r\n" +
+	"004  A003C002 AA/V  C        12:00:00;00 12:00:02;00 01:00:07;20 01:00:09;20\r\n" +
+	"* SOURCE FILE: A003C002.MOV\r\n" +
+	">>> SOURCE A003C002 A003C002.MOV\r\n"
+
+func TestParse(t *testing.T) {
+	list, err := Parse(strings.NewReader(testList), timecode.Rate30)
+	if err != nil {
+		t.Fatalf("error parsing list: %s", err)
+	}
+	if list.Title != "EP105 LOCKED CUT" {
+		t.Errorf("title parsed as %q", list.Title)
+	}
+	if list.Rate != timecode.Rate2997DF {
+		t.Errorf("FCM line should switch the rate to 29.97df, got %s", list.Rate)
+	}
+	if len(list.Events) != 3 {
+		t.Fatalf("expected 3 events, got %d", len(list.Events))
+	}
+
+	first := list.Events[0]
+	if first.ClipName != "DOOR SLAM WIDE" || first.RecordIn.String() != "01:00:00;00" || first.RecordOut.String() != "01:00:02;20" {
+		t.Errorf("first event parsed as %+v", first)
+	}
+	if len(first.Locators) != 1 || first.Locators[0].Color != "RED" || first.Locators[0].Comment != "footsteps on gravel" ||
+		first.Locators[0].Timecode.String() != "01:00:01;05" {
+		t.Errorf("locator parsed as %+v", first.Locators)
+	}
+
+	dissolve := list.Events[1]
+	if dissolve.Reel != "A002C001" || dissolve.Transition != "D" || dissolve.TransitionLength != 15 {
+		t.Errorf("dissolve parsed as %+v", dissolve)
+	}
+	if dissolve.RecordIn.String() != "01:00:02;20" || dissolve.RecordOut.String() != "01:00:07;20" {
+		t.Errorf("dissolve spans %s - %s", dissolve.RecordIn, dissolve.RecordOut)
+	}
+	if dissolve.ClipName != "HALLWAY" || dissolve.ToClipName != "KITCHEN" || len(dissolve.Comments) != 1 {
+		t.Errorf("dissolve clip names parsed as %q, %q, comments %v", dissolve.ClipName, dissolve.ToClipName, dissolve.Comments)
+	}
+
+	if list.Events[2].Track != "AA/V" || len(list.Events[2].Comments) != 1 {
+		t.Errorf("last event parsed as %+v", list.Events[2])
+	}
+
+	if len(list.Unparsed) != 2 || list.Unparsed[0].Number != 13 || list.Unparsed[1].Number != 16 {
+		t.Errorf("unparsed lines reported as %+v", list.Unparsed)
+	}
+}
+
+func TestParseFrameRateHeader(t *testing.T) {
+	input := "TITLE: TEST\n* FRAME RATE: 25\n001  AX V C 00:00:00:00 00:00:01:00 01:00:00:00 01:00:01:00\n"
+	list, err := Parse(strings.NewReader(input), timecode.Rate{})
+	if err != nil {
+		t.Fatalf("error parsing list: %s", err)
+	}
+	if list.Rate != timecode.Rate25 || list.Events[0].RecordOut.Frames-list.Events[0].RecordIn.Frames != 25 {
+		t.Errorf("frame rate header not applied, rate %s", list.Rate)
+	}
+
+	_, err = Parse(strings.NewReader("001  AX V C 00:00:00:00 00:00:01:00 01:00:00:00 01:00:01:00\n"), timecode.Rate{})
+	if err == nil {
+		t.Error("list without a known frame rate should give an error")
+	}
+}
@@ -0,0 +1,67 @@
+// Package episodecode parses and formats episode codes like S02E05.
+// Episodes of series without seasons are plain numbers or E05.
+package episodecode
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Code identifies an episode within a project. Season is 0 for episodes
+// that don't belong to a season.
+type Code struct {
+	Season  int
+	Episode int
+}
+
+var codePattern = regexp.MustCompile(`^(?:S(\d+))?E?(\d+)$`)
+var seasonPattern = regexp.MustCompile(`^S?(\d+)$`)
+
+// Parse accepts "S02E05", "s2e5", "E05" and "5".
+func Parse(s string) (Code, error) {
+	groups := codePattern.FindStringSubmatch(strings.ToUpper(strings.TrimSpace(s)))
+	if groups == nil {
+		return Code{}, fmt.Errorf("invalid episode code %q", s)
+	}
+	code := Code{}
+	if groups[1] != "" {
+		code.Season, _ = strconv.Atoi(groups[1])
+		if code.Season == 0 {
+			return Code{}, fmt.Errorf("invalid season in %q", s)
+		}
+	}
+	code.Episode, _ = strconv.Atoi(groups[2])
+	if code.Episode == 0 {
+		return Code{}, fmt.Errorf("invalid episode in %q", s)
+	}
+	return code, nil
+}
+
+// ParseSeason accepts "S02", "s2" and "2".
+func ParseSeason(s string) (int, error) {
+	groups := seasonPattern.FindStringSubmatch(strings.ToUpper(strings.TrimSpace(s)))
+	if groups == nil {
+		return 0, fmt.Errorf("invalid season %q", s)
+	}
+	season, _ := strconv.Atoi(groups[1])
+	if season == 0 {
+		return 0, fmt.Errorf("invalid season %q", s)
+	}
+	return season, nil
+}
+
+// IsCode reports whether s looks like an episode code rather than a name.
+func IsCode(s string) bool {
+	_, err := Parse(s)
+	return err == nil
+}
+
+// String formats the code as S02E05, or E05 without a season.
+func (c Code) String() string {
+	if c.Season == 0 {
+		return fmt.Sprintf("E%02d", c.Episode)
+	}
+	return fmt.Sprintf("S%02dE%02d", c.Season, c.Episode)
+}
@@ -0,0 +1,53 @@
+package episodecode
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		in   string
+		want Code
+	}{
+		{"S02E05", Code{Season: 2, Episode: 5}},
+		{"s2e5", Code{Season: 2, Episode: 5}},
+		{" S10E112 ", Code{Season: 10, Episode: 112}},
+		{"E05", Code{Episode: 5}},
+		{"7", Code{Episode: 7}},
+	}
+	for _, c := range cases {
+		got, err := Parse(c.in)
+		if err != nil {
+			t.Errorf("%q: %v", c.in, err)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("%q: expected %+v, got %+v", c.in, c.want, got)
+		}
+	}
+
+	for _, in := range []string{"", "S02", "S00E01", "E0", "reel 1", "S2 E5"} {
+		if _, err := Parse(in); err == nil {
+			t.Errorf("%q: expected an error", in)
+		}
+	}
+}
+
+func TestParseSeason(t *testing.T) {
+	for _, in := range []string{"S02", "s2", "2"} {
+		season, err := ParseSeason(in)
+		if err != nil || season != 2 {
+			t.Errorf("%q: expected 2, got %d, %v", in, season, err)
+		}
+	}
+	if _, err := ParseSeason("S02E05"); err == nil {
+		t.Error("expected an error for an episode code")
+	}
+}
+
+func TestString(t *testing.T) {
+	if got := (Code{Season: 2, Episode: 5}).String(); got != "S02E05" {
+		t.Errorf("expected S02E05, got %s", got)
+	}
+	if got := (Code{Episode: 5}).String(); got != "E05" {
+		t.Errorf("expected E05, got %s", got)
+	}
+}
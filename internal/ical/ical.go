@@ -0,0 +1,127 @@
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Minimal RFC 5545 support: enough to publish read-only feeds that calendar
+// applications can subscribe to.
+
+const (
+	dateFormat     = "20060102"
+	dateTimeFormat = "20060102T150405"
+	utcFormat      = "20060102T150405Z"
+
+	// Content lines longer than this many octets have to be folded
+	maxLineLength = 75
+)
+
+type Event struct {
+	UID          string
+	Start        time.Time
+	End          time.Time
+	AllDay       bool
+	Summary      string
+	Description  string
+	Location     string
+	LastModified time.Time
+	// Recurring is set for events with a recurrence rule and for the
+	// overrides of single occurrences of such events
+	Recurring bool
+}
+
+type Calendar struct {
+	Name   string
+	Events []Event
+}
+
+func escapeText(s string) string {
+	// TEXT values have to have backslashes, semicolons, commas and newlines escaped
+	r := strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", `\n`,
+	)
+	return r.Replace(s)
+}
+
+func foldLine(line string) string {
+	// Lines are split into chunks of at most 75 octets. Every continuation
+	// line starts with a single space. We never split a multi-byte character.
+	if len(line) <= maxLineLength {
+		return line
+	}
+
+	var b strings.Builder
+	limit := maxLineLength
+	count := 0
+	for _, r := range line {
+		size := len(string(r))
+		if count+size > limit {
+			b.WriteString("\r\n ")
+			// The leading space counts towards the length of the new line
+			limit = maxLineLength - 1
+			count = 0
+		}
+		b.WriteRune(r)
+		count += size
+	}
+	return b.String()
+}
+
+func formatTime(name string, t time.Time, allDay bool) string {
+	if allDay {
+		return fmt.Sprintf("%s;VALUE=DATE:%s", name, t.Format(dateFormat))
+	}
+	// Times stored without a time zone are published as floating times,
+	// so they show up at the same wall-clock hour in every calendar
+	return fmt.Sprintf("%s:%s", name, t.Format(dateTimeFormat))
+}
+
+func (c Calendar) Encode(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	writeLine := func(line string) {
+		bw.WriteString(foldLine(line))
+		bw.WriteString("\r\n")
+	}
+
+	writeLine("BEGIN:VCALENDAR")
+	writeLine("VERSION:2.0")
+	writeLine("PRODID:-//FoleyBookkeeper//Calendar Feed//EN")
+	writeLine("CALSCALE:GREGORIAN")
+	writeLine("METHOD:PUBLISH")
+	if c.Name != "" {
+		writeLine("X-WR-CALNAME:" + escapeText(c.Name))
+	}
+
+	for _, e := range c.Events {
+		writeLine("BEGIN:VEVENT")
+		writeLine("UID:" + escapeText(e.UID))
+		// DTSTAMP is mandatory. We use the modification time so that it stays
+		// stable between downloads of an unchanged feed
+		writeLine("DTSTAMP:" + e.LastModified.UTC().Format(utcFormat))
+		writeLine(formatTime("DTSTART", e.Start, e.AllDay))
+		if !e.End.IsZero() {
+			writeLine(formatTime("DTEND", e.End, e.AllDay))
+		}
+		writeLine("SUMMARY:" + escapeText(e.Summary))
+		if e.Description != "" {
+			writeLine("DESCRIPTION:" + escapeText(e.Description))
+		}
+		if e.Location != "" {
+			writeLine("LOCATION:" + escapeText(e.Location))
+		}
+		writeLine("LAST-MODIFIED:" + e.LastModified.UTC().Format(utcFormat))
+		writeLine("END:VEVENT")
+	}
+
+	writeLine("END:VCALENDAR")
+	return bw.Flush()
+}
@@ -0,0 +1,85 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEscapeText(t *testing.T) {
+	input := "Footsteps, props; ProjectX\\E05\nsecond line"
+	expected := `Footsteps\, props\; ProjectX\\E05\nsecond line`
+	if escapeText(input) != expected {
+		t.Errorf("escaped text doesn't match\nExpected: %s\nGot: %s", expected, escapeText(input))
+	}
+}
+
+func TestFoldLine(t *testing.T) {
+	line := "DESCRIPTION:" + strings.Repeat("śżć", 40)
+	folded := foldLine(line)
+	for _, l := range strings.Split(folded, "\r\n") {
+		if len(l) > maxLineLength {
+			t.Errorf("folded line is %d octets long: %s", len(l), l)
+		}
+	}
+	unfolded := strings.ReplaceAll(folded, "\r\n ", "")
+	if unfolded != line {
+		t.Errorf("unfolding doesn't give back the original line")
+	}
+
+	short := "SUMMARY:short"
+	if foldLine(short) != short {
+		t.Errorf("short line shouldn't get folded")
+	}
+}
+
+func TestEncode(t *testing.T) {
+	modified := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	cal := Calendar{
+		Name: "Studio A",
+		Events: []Event{
+			{
+				UID:          "booking-1@foleybookkeeper",
+				Start:        time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC),
+				End:          time.Date(2024, 3, 4, 13, 0, 0, 0, time.UTC),
+				Summary:      "ProjectX E05 footsteps",
+				LastModified: modified,
+			},
+			{
+				UID:          "session-1@foleybookkeeper",
+				Start:        time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC),
+				End:          time.Date(2024, 3, 6, 0, 0, 0, 0, time.UTC),
+				AllDay:       true,
+				Summary:      "ProjectX E05",
+				LastModified: modified,
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	err := cal.Encode(&buf)
+	if err != nil {
+		t.Fatalf("error encoding calendar: %s", err)
+	}
+	out := buf.String()
+
+	expectedLines := []string{
+		"BEGIN:VCALENDAR\r\n",
+		"X-WR-CALNAME:Studio A\r\n",
+		"UID:booking-1@foleybookkeeper\r\n",
+		"DTSTART:20240304T090000\r\n",
+		"DTEND:20240304T130000\r\n",
+		"DTSTART;VALUE=DATE:20240305\r\n",
+		"DTSTAMP:20240301T120000Z\r\n",
+		"END:VCALENDAR\r\n",
+	}
+	for _, l := range expectedLines {
+		if !strings.Contains(out, l) {
+			t.Errorf("encoded calendar is missing line %q", l)
+		}
+	}
+	if strings.Count(out, "BEGIN:VEVENT") != 2 {
+		t.Errorf("expected 2 events in the calendar")
+	}
+}
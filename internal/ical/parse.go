@@ -0,0 +1,247 @@
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+func unfoldLines(r io.Reader) ([]string, error) {
+	// Continuation lines start with a space or a tab and get glued
+	// to the previous line
+	lines := []string{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(line) > 0 && (line[0] == ' ' || line[0] == '\t') && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if line == "" {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
+
+func parseProperty(line string) (property, error) {
+	// A content line looks like NAME;PARAM=VALUE;PARAM2=VALUE2:value
+	// The value may contain colons, parameters may contain quoted colons
+	inQuotes := false
+	colon := -1
+	for i, c := range line {
+		if c == '"' {
+			inQuotes = !inQuotes
+		}
+		if c == ':' && !inQuotes {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return property{}, fmt.Errorf("malformed line: %s", line)
+	}
+
+	prop := property{
+		params: map[string]string{},
+		value:  line[colon+1:],
+	}
+	nameAndParams := strings.Split(line[:colon], ";")
+	prop.name = strings.ToUpper(nameAndParams[0])
+	for _, p := range nameAndParams[1:] {
+		key, val, found := strings.Cut(p, "=")
+		if !found {
+			continue
+		}
+		prop.params[strings.ToUpper(key)] = strings.Trim(val, `"`)
+	}
+	return prop, nil
+}
+
+func unescapeText(s string) string {
+	r := strings.NewReplacer(
+		`\\`, `\`,
+		`\;`, ";",
+		`\,`, ",",
+		`\n`, "\n",
+		`\N`, "\n",
+	)
+	return r.Replace(s)
+}
+
+func parseTime(prop property) (time.Time, bool, error) {
+	// Returns the time and whether it's a date without a time of day
+	if prop.params["VALUE"] == "DATE" || len(prop.value) == len(dateFormat) {
+		t, err := time.ParseInLocation(dateFormat, prop.value, time.Local)
+		return t, true, err
+	}
+	if strings.HasSuffix(prop.value, "Z") {
+		t, err := time.Parse(utcFormat, prop.value)
+		return t, false, err
+	}
+
+	// Floating times are interpreted in the server's time zone, unless the
+	// property names a time zone we know
+	loc := time.Local
+	if tzid, ok := prop.params["TZID"]; ok {
+		l, err := time.LoadLocation(tzid)
+		if err == nil {
+			loc = l
+		}
+	}
+	t, err := time.ParseInLocation(dateTimeFormat, prop.value, loc)
+	return t, false, err
+}
+
+func parseDuration(s string) (time.Duration, error) {
+	// Supports the common subset of RFC 5545 durations, e.g. PT1H30M or P1D
+	var d time.Duration
+	negative := false
+	if strings.HasPrefix(s, "-") {
+		negative = true
+	}
+	s = strings.TrimLeft(s, "+-")
+	if !strings.HasPrefix(s, "P") {
+		return 0, fmt.Errorf("invalid duration: %s", s)
+	}
+	s = s[1:]
+	inTime := false
+	num := 0
+	for _, c := range s {
+		switch {
+		case c >= '0' && c <= '9':
+			num = num*10 + int(c-'0')
+		case c == 'T':
+			inTime = true
+		case c == 'W':
+			d += time.Duration(num) * 7 * 24 * time.Hour
+			num = 0
+		case c == 'D':
+			d += time.Duration(num) * 24 * time.Hour
+			num = 0
+		case c == 'H' && inTime:
+			d += time.Duration(num) * time.Hour
+			num = 0
+		case c == 'M' && inTime:
+			d += time.Duration(num) * time.Minute
+			num = 0
+		case c == 'S' && inTime:
+			d += time.Duration(num) * time.Second
+			num = 0
+		default:
+			return 0, fmt.Errorf("invalid duration: %s", s)
+		}
+	}
+	if negative {
+		d = -d
+	}
+	return d, nil
+}
+
+// Parse reads the VEVENTs of an iCalendar file. Recurrence rules
+// aren't expanded, recurring events show up once and are marked as such.
+func Parse(r io.Reader) (Calendar, error) {
+	lines, err := unfoldLines(r)
+	if err != nil {
+		return Calendar{}, err
+	}
+
+	cal := Calendar{}
+	var current *Event
+	var duration time.Duration
+	// Nested components like VALARM have properties we don't want
+	// to mistake for properties of the event
+	depth := 0
+
+	for _, line := range lines {
+		prop, err := parseProperty(line)
+		if err != nil {
+			return Calendar{}, err
+		}
+
+		switch prop.name {
+		case "BEGIN":
+			if strings.ToUpper(prop.value) == "VEVENT" {
+				current = &Event{}
+				duration = 0
+				depth = 0
+			} else if current != nil {
+				depth++
+			}
+			continue
+		case "END":
+			if strings.ToUpper(prop.value) == "VEVENT" && current != nil {
+				if current.End.IsZero() {
+					switch {
+					case duration != 0:
+						current.End = current.Start.Add(duration)
+					case current.AllDay:
+						current.End = current.Start.AddDate(0, 0, 1)
+					default:
+						current.End = current.Start
+					}
+				}
+				cal.Events = append(cal.Events, *current)
+				current = nil
+			} else if current != nil {
+				depth--
+			}
+			continue
+		}
+
+		if current == nil {
+			if prop.name == "X-WR-CALNAME" {
+				cal.Name = unescapeText(prop.value)
+			}
+			continue
+		}
+		if depth > 0 {
+			continue
+		}
+
+		switch prop.name {
+		case "UID":
+			current.UID = prop.value
+		case "SUMMARY":
+			current.Summary = unescapeText(prop.value)
+		case "DESCRIPTION":
+			current.Description = unescapeText(prop.value)
+		case "LOCATION":
+			current.Location = unescapeText(prop.value)
+		case "DTSTART":
+			current.Start, current.AllDay, err = parseTime(prop)
+			if err != nil {
+				return Calendar{}, err
+			}
+		case "DTEND":
+			current.End, _, err = parseTime(prop)
+			if err != nil {
+				return Calendar{}, err
+			}
+		case "DURATION":
+			duration, err = parseDuration(prop.value)
+			if err != nil {
+				return Calendar{}, err
+			}
+		case "RRULE", "RDATE", "RECURRENCE-ID":
+			current.Recurring = true
+		case "LAST-MODIFIED":
+			current.LastModified, _, err = parseTime(prop)
+			if err != nil {
+				return Calendar{}, err
+			}
+		}
+	}
+
+	return cal, nil
+}
@@ -0,0 +1,173 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testCalendar = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"X-WR-CALNAME:Studio plan\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:abc-123@example.com\r\n" +
+	"DTSTART;TZID=UTC:20240304T090000\r\n" +
+	"DTEND;TZID=UTC:20240304T130000\r\n" +
+	"SUMMARY:ProjectX E05 footsteps\r\n" +
+	"DESCRIPTION:First line\\nsecond line\\, with a comma and a very long text th\r\n" +
+	" at got folded\r\n" +
+	"BEGIN:VALARM\r\n" +
+	"DESCRIPTION:Reminder\r\n" +
+	"END:VALARM\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:def-456@example.com\r\n" +
+	"DTSTART:20240305T080000Z\r\n" +
+	"DURATION:PT2H30M\r\n" +
+	"SUMMARY:ProjectX E06 props\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:ghi-789@example.com\r\n" +
+	"DTSTART;VALUE=DATE:20240306\r\n" +
+	"SUMMARY:Day off\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestParse(t *testing.T) {
+	cal, err := Parse(strings.NewReader(testCalendar))
+	if err != nil {
+		t.Fatalf("error parsing calendar: %s", err)
+	}
+	if cal.Name != "Studio plan" {
+		t.Errorf("calendar name parsed as %q", cal.Name)
+	}
+	if len(cal.Events) != 3 {
+		t.Fatalf("expected 3 events, got %d", len(cal.Events))
+	}
+
+	first := cal.Events[0]
+	if first.UID != "abc-123@example.com" {
+		t.Errorf("wrong UID: %s", first.UID)
+	}
+	if first.Summary != "ProjectX E05 footsteps" {
+		t.Errorf("wrong summary: %s", first.Summary)
+	}
+	expectedDescription := "First line\nsecond line, with a comma and a very long text that got folded"
+	if first.Description != expectedDescription {
+		t.Errorf("wrong description, the alarm or folding wasn't handled: %q", first.Description)
+	}
+	if !first.Start.Equal(time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)) {
+		t.Errorf("wrong start time: %v", first.Start)
+	}
+	if first.End.Sub(first.Start) != 4*time.Hour {
+		t.Errorf("wrong end time: %v", first.End)
+	}
+
+	second := cal.Events[1]
+	if second.End.Sub(second.Start) != 150*time.Minute {
+		t.Errorf("duration wasn't applied, event ends at %v", second.End)
+	}
+
+	third := cal.Events[2]
+	if !third.AllDay {
+		t.Errorf("date-only event should be an all-day event")
+	}
+	if third.End.Sub(third.Start) != 24*time.Hour {
+		t.Errorf("all-day event without an end should last a day")
+	}
+}
+
+func TestParseRecurring(t *testing.T) {
+	calendar := "BEGIN:VCALENDAR\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"UID:weekly@example.com\r\n" +
+		"DTSTART:20240304T090000Z\r\n" +
+		"RRULE:FREQ=WEEKLY;COUNT=4\r\n" +
+		"END:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"UID:weekly@example.com\r\n" +
+		"RECURRENCE-ID:20240311T090000Z\r\n" +
+		"DTSTART:20240311T100000Z\r\n" +
+		"END:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"UID:once@example.com\r\n" +
+		"DTSTART:20240305T090000Z\r\n" +
+		"END:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+	cal, err := Parse(strings.NewReader(calendar))
+	if err != nil {
+		t.Fatalf("error parsing calendar: %s", err)
+	}
+	expected := []bool{true, true, false}
+	if len(cal.Events) != len(expected) {
+		t.Fatalf("expected %d events, got %d", len(expected), len(cal.Events))
+	}
+	for i, e := range cal.Events {
+		if e.Recurring != expected[i] {
+			t.Errorf("event %d: recurring %v, expected %v", i, e.Recurring, expected[i])
+		}
+	}
+}
+
+func TestEncodeParseRoundTrip(t *testing.T) {
+	original := Calendar{
+		Name: "Round trip",
+		Events: []Event{
+			{
+				UID:          "booking-1@foleybookkeeper",
+				Start:        time.Date(2024, 3, 4, 9, 0, 0, 0, time.Local),
+				End:          time.Date(2024, 3, 4, 13, 0, 0, 0, time.Local),
+				Summary:      "Footsteps; props, and more",
+				Description:  strings.Repeat("Long description ", 20),
+				Location:     "Studio A",
+				LastModified: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	err := original.Encode(&buf)
+	if err != nil {
+		t.Fatalf("error encoding calendar: %s", err)
+	}
+	parsed, err := Parse(&buf)
+	if err != nil {
+		t.Fatalf("error parsing calendar: %s", err)
+	}
+	if len(parsed.Events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(parsed.Events))
+	}
+	e := parsed.Events[0]
+	o := original.Events[0]
+	if e.UID != o.UID || e.Summary != o.Summary || e.Description != o.Description || e.Location != o.Location {
+		t.Errorf("event text doesn't survive a round trip: %+v", e)
+	}
+	if !e.Start.Equal(o.Start) || !e.End.Equal(o.End) {
+		t.Errorf("event times don't survive a round trip: %v - %v", e.Start, e.End)
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	cases := map[string]time.Duration{
+		"PT1H30M": 90 * time.Minute,
+		"P1D":     24 * time.Hour,
+		"P1W":     7 * 24 * time.Hour,
+		"PT45S":   45 * time.Second,
+		"-PT15M":  -15 * time.Minute,
+	}
+	for input, expected := range cases {
+		d, err := parseDuration(input)
+		if err != nil {
+			t.Errorf("error parsing duration %s: %s", input, err)
+		}
+		if d != expected {
+			t.Errorf("duration %s parsed as %v, expected %v", input, d, expected)
+		}
+	}
+
+	_, err := parseDuration("1H")
+	if err == nil {
+		t.Errorf("invalid duration should give an error")
+	}
+}
@@ -0,0 +1,386 @@
+// Package protools reads the "Session Info as Text" export of Pro Tools:
+// a header followed by tab-separated sections listing files, clips,
+// tracks with their clips, and markers.
+package protools
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Denisowiec/FoleyBookkeeper/internal/timecode"
+)
+
+// Frames in a foot of 35mm film, for times in feet+frames
+const framesPerFoot = 16
+
+type Clip struct {
+	Channel int
+	Event   int
+	Name    string
+	Start   timecode.Timecode
+	End     timecode.Timecode
+	Muted   bool
+}
+
+type Track struct {
+	Name     string
+	Comments string
+	State    string
+	Clips    []Clip
+}
+
+type Marker struct {
+	Number   int
+	Location timecode.Timecode
+	Name     string
+	Comments string
+}
+
+// Line is a line of the export that couldn't be understood
+type Line struct {
+	Number int
+	Text   string
+	Reason string
+}
+
+type Session struct {
+	Name       string
+	SampleRate float64
+	Rate       timecode.Rate
+	Start      timecode.Timecode
+	// Counts of the files and clips listings, tracks and markers are
+	// listed in full
+	OnlineFiles  int
+	OfflineFiles int
+	ClipCount    int
+	Tracks       []Track
+	Markers      []Marker
+	Unparsed     []Line
+}
+
+func sectionTitle(line string) (string, bool) {
+	// Section titles are spaced out, like "T R A C K  L I S T I N G"
+	if len(line) < 3 || line[1] != ' ' {
+		return "", false
+	}
+	for _, c := range line {
+		if c != ' ' && c != '-' && (c < 'A' || c > 'Z') {
+			return "", false
+		}
+	}
+	return strings.ReplaceAll(line, " ", ""), true
+}
+
+func splitRow(line string) []string {
+	fields := strings.Split(line, "\t")
+	for i := range fields {
+		fields[i] = strings.TrimSpace(fields[i])
+	}
+	return fields
+}
+
+// columns maps the names in a header row to their positions
+type columns map[string]int
+
+func newColumns(header []string) columns {
+	cols := columns{}
+	for i, name := range header {
+		cols[strings.ToUpper(name)] = i
+	}
+	return cols
+}
+
+func (c columns) get(row []string, name string) string {
+	i, ok := c[name]
+	if !ok || i >= len(row) {
+		return ""
+	}
+	return row[i]
+}
+
+func parseTimecodeFormat(s string) (timecode.Rate, error) {
+	// "23.976 Frame", "25 Frame", "29.97 Drop Frame" and the like
+	name := strings.ToLower(s)
+	dropFrame := strings.Contains(name, "drop")
+	name = strings.NewReplacer("drop", "", "frame", "", "non-", "").Replace(name)
+	rate, err := timecode.ParseRate(name)
+	if err != nil {
+		return timecode.Rate{}, err
+	}
+	return rate.WithDropFrame(dropFrame), nil
+}
+
+func (s *Session) parseTime(value string) (timecode.Timecode, error) {
+	// Times come in whatever format the main counter was set to when the
+	// text was exported. Timecode is absolute, the other formats count
+	// from the session start
+	value = strings.TrimSpace(value)
+	separators := strings.Count(value, ":") + strings.Count(value, ";")
+	switch {
+	case value == "":
+		return timecode.Timecode{}, fmt.Errorf("time missing")
+
+	case strings.Contains(value, "|"):
+		return timecode.Timecode{}, fmt.Errorf("bars and beats not supported")
+
+	case separators == 3:
+		// Subframes are cut off, e.g. 01:00:00:12.45
+		if dot := strings.LastIndex(value, "."); dot > strings.LastIndexAny(value, ":;") {
+			value = value[:dot]
+		}
+		return timecode.Parse(value, s.Rate)
+
+	case strings.Contains(value, "+"):
+		// Feet+frames, e.g. 90+08
+		feet, frames, _ := strings.Cut(value, "+")
+		frames, _, _ = strings.Cut(frames, ".")
+		f, err1 := strconv.ParseInt(feet, 10, 64)
+		ff, err2 := strconv.ParseInt(frames, 10, 64)
+		if err1 != nil || err2 != nil {
+			return timecode.Timecode{}, fmt.Errorf("invalid feet+frames %s", value)
+		}
+		return s.Start.Add(f*framesPerFoot + ff), nil
+
+	case separators == 1 || separators == 2:
+		// Minutes and seconds, e.g. 1:02.345, or with hours 1:01:02.345
+		parts := strings.Split(value, ":")
+		seconds, err := strconv.ParseFloat(parts[len(parts)-1], 64)
+		if err != nil {
+			return timecode.Timecode{}, fmt.Errorf("invalid time %s", value)
+		}
+		for i, p := range parts[:len(parts)-1] {
+			n, err := strconv.ParseInt(p, 10, 64)
+			if err != nil {
+				return timecode.Timecode{}, fmt.Errorf("invalid time %s", value)
+			}
+			multiplier := int64(60)
+			if len(parts) == 3 && i == 0 {
+				multiplier = 3600
+			}
+			seconds += float64(n * multiplier)
+		}
+		offset := timecode.FromDuration(time.Duration(seconds*float64(time.Second)), s.Rate)
+		return s.Start.Add(offset.Frames), nil
+
+	default:
+		// Samples
+		samples, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || s.SampleRate == 0 {
+			return timecode.Timecode{}, fmt.Errorf("invalid time %s", value)
+		}
+		offset := timecode.FromDuration(time.Duration(float64(samples)/s.SampleRate*float64(time.Second)), s.Rate)
+		return s.Start.Add(offset.Frames), nil
+	}
+}
+
+// Parse reads a session info export. The rate is used if the export
+// doesn't name its timecode format. Lines that can't be understood don't
+// stop the parsing, they end up in Unparsed
+func Parse(r io.Reader, rate timecode.Rate) (Session, error) {
+	session := Session{Rate: rate}
+	header := map[string]string{}
+	section := ""
+	var cols columns
+	var track *Track
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		raw := strings.TrimRight(scanner.Text(), "\r")
+		text := strings.TrimSpace(raw)
+		if text == "" {
+			continue
+		}
+		unparsed := func(reason string) {
+			session.Unparsed = append(session.Unparsed, Line{Number: lineNumber, Text: text, Reason: reason})
+		}
+
+		if title, ok := sectionTitle(text); ok {
+			if section == "" {
+				// The header is over, times can be read from here on
+				err := session.readHeader(header)
+				if err != nil {
+					return Session{}, err
+				}
+			}
+			section = title
+			cols = nil
+			continue
+		}
+
+		row := splitRow(raw)
+		switch section {
+		case "":
+			key, value, found := strings.Cut(text, ":")
+			if !found {
+				unparsed("header line not recognized")
+				continue
+			}
+			header[strings.ToUpper(strings.TrimSpace(key))] = strings.TrimSpace(value)
+
+		case "ONLINEFILESINSESSION", "OFFLINEFILESINSESSION", "ONLINECLIPSINSESSION":
+			// The first row names the columns, the rest are only counted
+			if cols == nil {
+				cols = newColumns(row)
+				continue
+			}
+			switch section {
+			case "ONLINEFILESINSESSION":
+				session.OnlineFiles++
+			case "OFFLINEFILESINSESSION":
+				session.OfflineFiles++
+			default:
+				session.ClipCount++
+			}
+
+		case "TRACKLISTING":
+			key, value, _ := strings.Cut(text, ":")
+			switch strings.ToUpper(strings.TrimSpace(key)) {
+			case "TRACK NAME":
+				session.Tracks = append(session.Tracks, Track{Name: strings.TrimSpace(value)})
+				track = &session.Tracks[len(session.Tracks)-1]
+				cols = nil
+				continue
+			case "COMMENTS":
+				if track != nil {
+					track.Comments = strings.TrimSpace(value)
+				}
+				continue
+			case "STATE":
+				if track != nil {
+					track.State = strings.TrimSpace(value)
+				}
+				continue
+			case "USER DELAY", "PLUG-INS", "INPUTS", "OUTPUTS":
+				continue
+			}
+			if track == nil {
+				unparsed("clip outside of a track")
+				continue
+			}
+			if cols == nil {
+				cols = newColumns(row)
+				continue
+			}
+			clip, err := session.parseClip(cols, row)
+			if err != nil {
+				unparsed(err.Error())
+				continue
+			}
+			track.Clips = append(track.Clips, clip)
+
+		case "MARKERSLISTING":
+			if cols == nil {
+				cols = newColumns(row)
+				continue
+			}
+			marker, err := session.parseMarker(cols, row)
+			if err != nil {
+				unparsed(err.Error())
+				continue
+			}
+			session.Markers = append(session.Markers, marker)
+
+		default:
+			// Sections like the plug-in listing don't matter to us
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return Session{}, err
+	}
+	if section == "" {
+		return Session{}, fmt.Errorf("no sections found, not a session info export")
+	}
+	return session, nil
+}
+
+func (s *Session) readHeader(header map[string]string) error {
+	s.Name = header["SESSION NAME"]
+	if rate, ok := header["SAMPLE RATE"]; ok {
+		sampleRate, err := strconv.ParseFloat(rate, 64)
+		if err != nil {
+			return fmt.Errorf("invalid sample rate %s", rate)
+		}
+		s.SampleRate = sampleRate
+	}
+	if format, ok := header["TIMECODE FORMAT"]; ok {
+		rate, err := parseTimecodeFormat(format)
+		if err != nil {
+			return err
+		}
+		s.Rate = rate
+	}
+	if s.Rate.Num == 0 {
+		return fmt.Errorf("timecode format of the session unknown")
+	}
+	if start, ok := header["SESSION START TIMECODE"]; ok {
+		tc, err := timecode.Parse(start, s.Rate)
+		if err != nil {
+			return err
+		}
+		s.Start = tc
+	} else {
+		s.Start = timecode.FromFrames(0, s.Rate)
+	}
+	return nil
+}
+
+func (s *Session) parseClip(cols columns, row []string) (Clip, error) {
+	// CHANNEL  EVENT  CLIP NAME  START TIME  END TIME  DURATION  STATE
+	channel, err := strconv.Atoi(cols.get(row, "CHANNEL"))
+	if err != nil {
+		return Clip{}, fmt.Errorf("invalid channel")
+	}
+	event, err := strconv.Atoi(cols.get(row, "EVENT"))
+	if err != nil {
+		return Clip{}, fmt.Errorf("invalid event")
+	}
+	start, err := s.parseTime(cols.get(row, "START TIME"))
+	if err != nil {
+		return Clip{}, err
+	}
+	end, err := s.parseTime(cols.get(row, "END TIME"))
+	if err != nil {
+		return Clip{}, err
+	}
+	return Clip{
+		Channel: channel,
+		Event:   event,
+		Name:    cols.get(row, "CLIP NAME"),
+		Start:   start,
+		End:     end,
+		Muted:   strings.EqualFold(cols.get(row, "STATE"), "Muted"),
+	}, nil
+}
+
+func (s *Session) parseMarker(cols columns, row []string) (Marker, error) {
+	// #  LOCATION  TIME REFERENCE  UNITS  NAME  COMMENTS
+	number, err := strconv.Atoi(cols.get(row, "#"))
+	if err != nil {
+		return Marker{}, fmt.Errorf("invalid marker number")
+	}
+	location, err := s.parseTime(cols.get(row, "LOCATION"))
+	if err != nil {
+		// The time reference is in samples, it's there when the
+		// location is in a format we can't read
+		if !strings.EqualFold(cols.get(row, "UNITS"), "Samples") {
+			return Marker{}, err
+		}
+		location, err = s.parseTime(cols.get(row, "TIME REFERENCE"))
+		if err != nil {
+			return Marker{}, err
+		}
+	}
+	return Marker{
+		Number:   number,
+		Location: location,
+		Name:     cols.get(row, "NAME"),
+		Comments: cols.get(row, "COMMENTS"),
+	}, nil
+}
@@ -0,0 +1,136 @@
+package protools
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Denisowiec/FoleyBookkeeper/internal/timecode"
+)
+
+const testExport = "SESSION NAME:\tEP105_FOLEY\n" +
+	"SAMPLE RATE:\t48000.000000\n" +
+	"BIT DEPTH:\t24-bit\n" +
+	"SESSION START TIMECODE:\t00:59:00:00\n" +
+	"TIMECODE FORMAT:\t25 Frame\n" +
+	"# OF AUDIO TRACKS:\t2\n" +
+	"# OF AUDIO CLIPS:\t3\n" +
+	"# OF AUDIO FILES:\t3\n" +
+	"\n\n" +
+	"O N L I N E  F I L E S  I N  S E S S I O N\n" +
+	"Filename                \tLocation\n" +
+	"FS Hero-01.wav          \tFoley:Audio Files:\n" +
+	"FS Hero-02.wav          \tFoley:Audio Files:\n" +
+	"\n" +
+	"O N L I N E  C L I P S  I N  S E S S I O N\n" +
+	"CLIP NAME                    \tSource File\n" +
+	"FS Hero-01                   \tFS Hero-01.wav\n" +
+	"\n" +
+	"T R A C K  L I S T I N G\n" +
+	"TRACK NAME:\tFS Hero\n" +
+	"COMMENTS:\tleather shoes\n" +
+	"USER DELAY:\t0 Samples\n" +
+	"STATE: \t\n" +
+	"PLUG-INS: \t\n" +
+	"CHANNEL \tEVENT   \tCLIP NAME                     \tSTART TIME    \tEND TIME      \tDURATION      \tSTATE\n" +
+	"1       \t1       \tFS Hero-01                    \t01:00:01:12   \t01:00:03:04   \t00:00:01:17   \tUnmuted\n" +
+	"1       \t2       \tFS Hero-02                    \t01:00:10:00.50\t01:00:11:00.00\t00:00:01:00   \tMuted\n" +
+	"\n" +
+	"TRACK NAME:\tProps\n" +
+	"COMMENTS:\t\n" +
+	"CHANNEL \tEVENT   \tCLIP NAME                     \tSTART TIME    \tEND TIME      \tDURATION      \tSTATE\n" +
+	"1       \t1       \tKeys                          \t1:00.000      \t1:02.000      \t0:02.000      \tUnmuted\n" +
+	"1       \t2       \tBroken                        \t2|1|000       \t3|1|000       \t1|0|000       \tUnmuted\n" +
+	"\n" +
+	"M A R K E R S  L I S T I N G\n" +
+	"#   \tLOCATION     \tTIME REFERENCE    \tUNITS    \tNAME                             \tCOMMENTS\n" +
+	"1   \t01:00:05:10  \t8016000           \tSamples  \tF101 door knock                  \t\n" +
+	"2   \t5|1|000      \t8640000           \tSamples  \tF102 keys                        \tsoft\n"
+
+func TestParse(t *testing.T) {
+	session, err := Parse(strings.NewReader(testExport), timecode.Rate{})
+	if err != nil {
+		t.Fatalf("error parsing export: %s", err)
+	}
+	if session.Name != "EP105_FOLEY" || session.Rate != timecode.Rate25 || session.Start.String() != "00:59:00:00" {
+		t.Errorf("header parsed as %s, %s, %s", session.Name, session.Rate, session.Start)
+	}
+	if session.OnlineFiles != 2 || session.ClipCount != 1 {
+		t.Errorf("listings counted as %d files and %d clips", session.OnlineFiles, session.ClipCount)
+	}
+	if len(session.Tracks) != 2 {
+		t.Fatalf("expected 2 tracks, got %d", len(session.Tracks))
+	}
+
+	hero := session.Tracks[0]
+	if hero.Name != "FS Hero" || hero.Comments != "leather shoes" || len(hero.Clips) != 2 {
+		t.Fatalf("first track parsed as %+v", hero)
+	}
+	if hero.Clips[0].Start.String() != "01:00:01:12" || hero.Clips[0].Muted {
+		t.Errorf("first clip parsed as %+v", hero.Clips[0])
+	}
+	if hero.Clips[1].Start.String() != "01:00:10:00" || !hero.Clips[1].Muted {
+		t.Errorf("clip with subframes parsed as %+v", hero.Clips[1])
+	}
+
+	// Minutes and seconds count from the session start
+	props := session.Tracks[1]
+	if len(props.Clips) != 1 || props.Clips[0].Start.String() != "01:00:00:00" || props.Clips[0].End.String() != "01:00:02:00" {
+		t.Errorf("min:secs clip parsed as %+v", props.Clips)
+	}
+
+	if len(session.Markers) != 2 {
+		t.Fatalf("expected 2 markers, got %d", len(session.Markers))
+	}
+	if session.Markers[0].Name != "F101 door knock" || session.Markers[0].Location.String() != "01:00:05:10" {
+		t.Errorf("first marker parsed as %+v", session.Markers[0])
+	}
+	// Bars and beats fall back to the sample position
+	if session.Markers[1].Location.String() != "01:02:00:00" || session.Markers[1].Comments != "soft" {
+		t.Errorf("second marker parsed as %+v", session.Markers[1])
+	}
+
+	if len(session.Unparsed) != 1 || !strings.Contains(session.Unparsed[0].Text, "Broken") {
+		t.Errorf("unparsed lines reported as %+v", session.Unparsed)
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	session := Session{
+		SampleRate: 48000,
+		Rate:       timecode.Rate2997DF,
+		Start:      timecode.FromFrames(0, timecode.Rate2997DF),
+	}
+	cases := map[string]string{
+		"00:01:00;02":    "00:01:00;02",
+		"00:01:00;02.99": "00:01:00;02",
+		"2+00":           "00:00:01;02",
+		"1:00.000":       "00:00:59;28",
+		"48000":          "00:00:01;00",
+	}
+	for input, expected := range cases {
+		tc, err := session.parseTime(input)
+		if err != nil {
+			t.Errorf("error parsing %s: %s", input, err)
+			continue
+		}
+		if tc.String() != expected {
+			t.Errorf("%s parsed as %s, expected %s", input, tc, expected)
+		}
+	}
+}
+
+func TestParseTimecodeFormat(t *testing.T) {
+	cases := map[string]timecode.Rate{
+		"23.976 Frame":     timecode.Rate23976,
+		"25 Frame":         timecode.Rate25,
+		"29.97 Drop Frame": timecode.Rate2997DF,
+		"29.97 Frame":      timecode.Rate2997,
+		"30 Drop Frame":    timecode.Rate2997DF,
+	}
+	for input, expected := range cases {
+		rate, err := parseTimecodeFormat(input)
+		if err != nil || rate != expected {
+			t.Errorf("%s parsed as %v, %v", input, rate, err)
+		}
+	}
+}
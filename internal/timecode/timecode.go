@@ -0,0 +1,242 @@
+// Package timecode handles SMPTE timecodes, including drop-frame timecode
+// used with 29.97 and 59.94 fps video. Timecodes convert to frame counts,
+// wall-clock durations and other rates.
+package timecode
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Rate is a frame rate. Fractional rates like 23.976 are exactly
+// Num/Den frames per second, e.g. 24000/1001.
+type Rate struct {
+	Name string
+	Num  int64
+	Den  int64
+	// Frames counted in each timecode second, e.g. 30 for 29.97
+	Nominal   int64
+	DropFrame bool
+}
+
+var (
+	Rate23976  = Rate{Name: "23.976", Num: 24000, Den: 1001, Nominal: 24}
+	Rate24     = Rate{Name: "24", Num: 24, Den: 1, Nominal: 24}
+	Rate25     = Rate{Name: "25", Num: 25, Den: 1, Nominal: 25}
+	Rate2997   = Rate{Name: "29.97", Num: 30000, Den: 1001, Nominal: 30}
+	Rate2997DF = Rate{Name: "29.97df", Num: 30000, Den: 1001, Nominal: 30, DropFrame: true}
+	Rate30     = Rate{Name: "30", Num: 30, Den: 1, Nominal: 30}
+	Rate50     = Rate{Name: "50", Num: 50, Den: 1, Nominal: 50}
+	Rate5994   = Rate{Name: "59.94", Num: 60000, Den: 1001, Nominal: 60}
+	Rate5994DF = Rate{Name: "59.94df", Num: 60000, Den: 1001, Nominal: 60, DropFrame: true}
+	Rate60     = Rate{Name: "60", Num: 60, Den: 1, Nominal: 60}
+)
+
+// Rates lists the supported frame rates
+var Rates = []Rate{Rate23976, Rate24, Rate25, Rate2997, Rate2997DF, Rate30, Rate50, Rate5994, Rate5994DF, Rate60}
+
+// ParseRate accepts the rate names as well as common spellings like
+// "23.98" or "29.97 DF".
+func ParseRate(s string) (Rate, error) {
+	name := strings.ToLower(strings.Join(strings.Fields(s), ""))
+	name = strings.TrimSuffix(name, "fps")
+	switch name {
+	case "23.98", "23.976":
+		return Rate23976, nil
+	case "29.97ndf":
+		return Rate2997, nil
+	case "29.97d", "29.97dropframe":
+		return Rate2997DF, nil
+	case "59.94ndf":
+		return Rate5994, nil
+	case "59.94d", "59.94dropframe":
+		return Rate5994DF, nil
+	}
+	for _, r := range Rates {
+		if r.Name == name {
+			return r, nil
+		}
+	}
+	return Rate{}, fmt.Errorf("frame rate %s not supported", s)
+}
+
+func (r Rate) String() string {
+	return r.Name
+}
+
+// WithDropFrame returns the drop-frame or non-drop variant of the rate.
+// 30 and 60 drop-frame are taken to mean 29.97 and 59.94, the way
+// editors tend to write them. Other rates have no drop-frame variant
+func (r Rate) WithDropFrame(dropFrame bool) Rate {
+	switch r.Nominal {
+	case 30:
+		if dropFrame {
+			return Rate2997DF
+		}
+		if r.DropFrame {
+			return Rate2997
+		}
+	case 60:
+		if dropFrame {
+			return Rate5994DF
+		}
+		if r.DropFrame {
+			return Rate5994
+		}
+	}
+	return r
+}
+
+// Frames dropped from the count at the start of each minute,
+// except every tenth minute
+func (r Rate) dropped() int64 {
+	if !r.DropFrame {
+		return 0
+	}
+	return r.Nominal / 15
+}
+
+// Timecode is a position counted in frames at a given rate
+type Timecode struct {
+	Frames int64
+	Rate   Rate
+}
+
+// FromFrames makes a timecode from a frame count
+func FromFrames(frames int64, rate Rate) Timecode {
+	return Timecode{Frames: frames, Rate: rate}
+}
+
+// Parse reads HH:MM:SS:FF timecode. Drop-frame timecode is usually written
+// HH:MM:SS;FF, but any of the separators : ; . , is accepted.
+func Parse(s string, rate Rate) (Timecode, error) {
+	fields := strings.FieldsFunc(strings.TrimSpace(s), func(c rune) bool {
+		return c == ':' || c == ';' || c == '.' || c == ','
+	})
+	if len(fields) != 4 {
+		return Timecode{}, fmt.Errorf("invalid timecode %s", s)
+	}
+
+	values := [4]int64{}
+	for i, f := range fields {
+		v, err := strconv.ParseInt(f, 10, 64)
+		if err != nil || v < 0 || len(f) > 2 {
+			return Timecode{}, fmt.Errorf("invalid timecode %s", s)
+		}
+		values[i] = v
+	}
+	hh, mm, ss, ff := values[0], values[1], values[2], values[3]
+
+	if mm >= 60 || ss >= 60 || ff >= rate.Nominal {
+		return Timecode{}, fmt.Errorf("invalid timecode %s at %s fps", s, rate)
+	}
+	drop := rate.dropped()
+	if drop > 0 && ss == 0 && ff < drop && mm%10 != 0 {
+		return Timecode{}, fmt.Errorf("timecode %s doesn't exist in drop-frame", s)
+	}
+
+	totalMinutes := hh*60 + mm
+	frames := ((hh*3600+mm*60+ss)*rate.Nominal + ff) - drop*(totalMinutes-totalMinutes/10)
+	return Timecode{Frames: frames, Rate: rate}, nil
+}
+
+// Components returns hours, minutes, seconds and frames of the timecode.
+// Hours don't wrap around at 24.
+func (t Timecode) Components() (int64, int64, int64, int64) {
+	frames := t.Frames
+	if frames < 0 {
+		frames = -frames
+	}
+
+	// Drop-frame skips frame numbers, so they get added back
+	// before splitting the count into components
+	if drop := t.Rate.dropped(); drop > 0 {
+		perMinute := t.Rate.Nominal*60 - drop
+		perTenMinutes := t.Rate.Nominal*600 - 9*drop
+		tens := frames / perTenMinutes
+		rest := frames % perTenMinutes
+		frames += 9 * drop * tens
+		if rest > drop {
+			frames += drop * ((rest - drop) / perMinute)
+		}
+	}
+
+	nominal := t.Rate.Nominal
+	return frames / (3600 * nominal), frames / (60 * nominal) % 60, frames / nominal % 60, frames % nominal
+}
+
+// String formats the timecode as HH:MM:SS:FF, or HH:MM:SS;FF for drop-frame
+func (t Timecode) String() string {
+	hh, mm, ss, ff := t.Components()
+	sep := ":"
+	if t.Rate.DropFrame {
+		sep = ";"
+	}
+	sign := ""
+	if t.Frames < 0 {
+		sign = "-"
+	}
+	return fmt.Sprintf("%s%02d:%02d:%02d%s%02d", sign, hh, mm, ss, sep, ff)
+}
+
+// FromDuration makes the timecode of the frame closest to a wall-clock
+// duration from zero
+func FromDuration(d time.Duration, rate Rate) Timecode {
+	// frames = seconds * Num / Den, rounded to the nearest frame
+	num := int64(d) * rate.Num
+	den := int64(time.Second) * rate.Den
+	frames := num / den
+	if rem := num % den; rem*2 >= den {
+		frames++
+	} else if rem*2 <= -den {
+		frames--
+	}
+	return Timecode{Frames: frames, Rate: rate}
+}
+
+// Duration is the wall-clock time from zero to the timecode. At fractional
+// rates it runs slightly longer than the timecode reads, e.g. one hour of
+// 23.976 timecode lasts 3603.6 seconds
+func (t Timecode) Duration() time.Duration {
+	return time.Duration(t.Frames * t.Rate.Den * int64(time.Second) / t.Rate.Num)
+}
+
+// Add moves the timecode by a number of frames, which may be negative
+func (t Timecode) Add(frames int64) Timecode {
+	return Timecode{Frames: t.Frames + frames, Rate: t.Rate}
+}
+
+// Sub returns the number of frames from other to t. Both need the same rate
+func (t Timecode) Sub(other Timecode) (int64, error) {
+	if t.Rate != other.Rate {
+		return 0, fmt.Errorf("can't subtract %s timecode from %s timecode", other.Rate, t.Rate)
+	}
+	return t.Frames - other.Frames, nil
+}
+
+// Compare returns -1, 0 or 1 depending on whether t comes before, at the
+// same time as or after other. Timecodes at different rates are compared
+// by wall-clock time
+func (t Timecode) Compare(other Timecode) int {
+	// Frames * Den / Num cross multiplied, so fractional rates compare exactly
+	a := t.Frames * t.Rate.Den * other.Rate.Num
+	b := other.Frames * other.Rate.Den * t.Rate.Num
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Convert returns the frame closest in wall-clock time at another rate
+func (t Timecode) Convert(rate Rate) Timecode {
+	if t.Rate == rate {
+		return t
+	}
+	return FromDuration(t.Duration(), rate)
+}
@@ -0,0 +1,221 @@
+package timecode
+
+import (
+	"fmt"
+	"testing"
+	"testing/quick"
+	"time"
+)
+
+func TestParseAndFormat(t *testing.T) {
+	cases := []struct {
+		input  string
+		rate   Rate
+		frames int64
+		output string
+	}{
+		{"00:00:01:00", Rate25, 25, "00:00:01:00"},
+		{"01:00:00:00", Rate24, 86400, "01:00:00:00"},
+		{"01:00:00:00", Rate23976, 86400, "01:00:00:00"},
+		{"00:01:00:00", Rate2997, 1800, "00:01:00:00"},
+		{"00:00:59;29", Rate2997DF, 1799, "00:00:59;29"},
+		{"00:01:00;02", Rate2997DF, 1800, "00:01:00;02"},
+		{"00:10:00;00", Rate2997DF, 17982, "00:10:00;00"},
+		{"01:00:00;00", Rate2997DF, 107892, "01:00:00;00"},
+		{"10:00:00:00", Rate30, 1080000, "10:00:00:00"},
+	}
+	for _, c := range cases {
+		tc, err := Parse(c.input, c.rate)
+		if err != nil {
+			t.Errorf("error parsing %s at %s: %s", c.input, c.rate, err)
+			continue
+		}
+		if tc.Frames != c.frames {
+			t.Errorf("%s at %s parsed as %d frames, expected %d", c.input, c.rate, tc.Frames, c.frames)
+		}
+		if tc.String() != c.output {
+			t.Errorf("%d frames at %s formatted as %s, expected %s", c.frames, c.rate, tc.String(), c.output)
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	cases := []struct {
+		input string
+		rate  Rate
+	}{
+		{"00:00:00:25", Rate25},
+		{"00:60:00:00", Rate25},
+		{"00:00:00", Rate25},
+		{"aa:00:00:00", Rate25},
+		{"00:01:00;00", Rate2997DF},
+		{"00:01:00;01", Rate2997DF},
+	}
+	for _, c := range cases {
+		_, err := Parse(c.input, c.rate)
+		if err == nil {
+			t.Errorf("%s at %s should give an error", c.input, c.rate)
+		}
+	}
+}
+
+func TestParseRate(t *testing.T) {
+	cases := map[string]Rate{
+		"23.98":    Rate23976,
+		"25":       Rate25,
+		"29.97 DF": Rate2997DF,
+		"29.97":    Rate2997,
+		"30fps":    Rate30,
+		"59.94df":  Rate5994DF,
+		"60":       Rate60,
+	}
+	for input, expected := range cases {
+		r, err := ParseRate(input)
+		if err != nil || r != expected {
+			t.Errorf("rate %s parsed as %v, %v", input, r, err)
+		}
+	}
+	if _, err := ParseRate("17"); err == nil {
+		t.Error("unsupported rate should give an error")
+	}
+}
+
+func TestDuration(t *testing.T) {
+	cases := []struct {
+		tc       string
+		rate     Rate
+		duration time.Duration
+	}{
+		{"00:00:01:00", Rate25, time.Second},
+		{"01:00:00:00", Rate24, time.Hour},
+		{"01:00:00:00", Rate23976, 3603600 * time.Millisecond},
+		{"01:00:00;00", Rate2997DF, 3599996400 * time.Microsecond},
+		{"00:00:00:30", Rate60, 500 * time.Millisecond},
+	}
+	for _, c := range cases {
+		tc, err := Parse(c.tc, c.rate)
+		if err != nil {
+			t.Fatalf("error parsing %s at %s: %s", c.tc, c.rate, err)
+		}
+		if tc.Duration() != c.duration {
+			t.Errorf("%s at %s lasts %s, expected %s", c.tc, c.rate, tc.Duration(), c.duration)
+		}
+		if back := FromDuration(c.duration, c.rate); back != tc {
+			t.Errorf("%s at %s came back as %s", c.duration, c.rate, back)
+		}
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	a, _ := Parse("00:00:59;29", Rate2997DF)
+	b := a.Add(1)
+	if b.String() != "00:01:00;02" {
+		t.Errorf("one frame after %s is %s, expected 00:01:00;02", a, b)
+	}
+	if diff, err := b.Sub(a); err != nil || diff != 1 {
+		t.Errorf("%s - %s gave %d, %v", b, a, diff, err)
+	}
+	if _, err := b.Sub(FromFrames(0, Rate2997)); err == nil {
+		t.Error("subtracting timecodes at different rates should give an error")
+	}
+	if a.Compare(b) != -1 || b.Compare(a) != 1 || a.Compare(a) != 0 {
+		t.Errorf("comparing %s and %s gave the wrong order", a, b)
+	}
+
+	// One second at 25 fps is the same moment as one second at 50 fps
+	if FromFrames(25, Rate25).Compare(FromFrames(50, Rate50)) != 0 {
+		t.Error("timecodes at different rates should compare by wall-clock time")
+	}
+	if c := FromFrames(86400, Rate24).Convert(Rate25); c.String() != "01:00:00:00" {
+		t.Errorf("one hour at 24 fps converted to %s at 25 fps", c)
+	}
+}
+
+// Frame counts up to a day, for the property tests
+func dayOfFrames(rate Rate) func(uint32) int64 {
+	day := FromDuration(24*time.Hour, rate).Frames
+	return func(n uint32) int64 {
+		return int64(n) % day
+	}
+}
+
+func TestFormatRoundTrip(t *testing.T) {
+	for _, rate := range Rates {
+		frames := dayOfFrames(rate)
+		property := func(n uint32) bool {
+			tc := FromFrames(frames(n), rate)
+			parsed, err := Parse(tc.String(), rate)
+			return err == nil && parsed == tc
+		}
+		if err := quick.Check(property, nil); err != nil {
+			t.Errorf("formatting at %s doesn't round trip: %s", rate, err)
+		}
+	}
+}
+
+func TestDropFrameMinuteBoundaries(t *testing.T) {
+	for _, rate := range []Rate{Rate2997DF, Rate5994DF} {
+		drop := rate.dropped()
+		perMinute := rate.Nominal*60 - drop
+		perTenMinutes := rate.Nominal*600 - 9*drop
+
+		// The last frame of every minute, followed by the first frame of
+		// the next one. Frame numbers are skipped except on tenth minutes
+		property := func(n uint16) bool {
+			minute := int64(n) % (24 * 60)
+			start := minute/10*perTenMinutes + minute%10*perMinute
+			if minute%10 != 0 {
+				start += drop
+			}
+			last := FromFrames(start+perMinute-1, rate)
+			if minute%10 == 0 {
+				last = last.Add(drop)
+			}
+			next := last.Add(1)
+
+			_, lm, ls, lf := last.Components()
+			_, nm, ns, nf := next.Components()
+			expectedFrame := drop
+			if nm%10 == 0 {
+				expectedFrame = 0
+			}
+			return lm == minute%60 && ls == 59 && lf == rate.Nominal-1 &&
+				nm == (minute+1)%60 && ns == 0 && nf == expectedFrame
+		}
+		if err := quick.Check(property, nil); err != nil {
+			t.Errorf("minute boundary at %s formatted wrong: %s", rate, err)
+		}
+
+		// Dropped frame numbers never show up and don't parse
+		property = func(n uint16) bool {
+			minute := int64(n) % (24 * 60)
+			for ff := int64(0); ff < drop; ff++ {
+				label := fmt.Sprintf("%02d:%02d:00;%02d", minute/60, minute%60, ff)
+				_, err := Parse(label, rate)
+				if (err == nil) != (minute%10 == 0) {
+					return false
+				}
+			}
+			return true
+		}
+		if err := quick.Check(property, nil); err != nil {
+			t.Errorf("dropped frame numbers at %s handled wrong: %s", rate, err)
+		}
+	}
+}
+
+func TestArithmeticProperties(t *testing.T) {
+	for _, rate := range Rates {
+		frames := dayOfFrames(rate)
+		property := func(a, b uint32) bool {
+			x := FromFrames(frames(a), rate)
+			d := frames(b)
+			diff, err := x.Add(d).Sub(x)
+			return err == nil && diff == d && x.Add(d).Add(-d) == x &&
+				x.Compare(x.Add(d)) <= 0 && FromDuration(x.Duration(), rate) == x
+		}
+		if err := quick.Check(property, nil); err != nil {
+			t.Errorf("arithmetic at %s: %s", rate, err)
+		}
+	}
+}
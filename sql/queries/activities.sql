@@ -0,0 +1,41 @@
+-- name: CreateActivity :one
+INSERT INTO activities (
+    code,
+    display_name,
+    sort_order,
+    active,
+    billing_weight,
+    advances_to
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6
+) RETURNING *;
+
+-- name: UpdateActivity :one
+UPDATE activities SET
+    display_name = $2,
+    sort_order = $3,
+    active = $4,
+    billing_weight = $5,
+    advances_to = $6,
+    updated_at = NOW()
+WHERE id = $1 RETURNING *;
+
+-- name: GetActivityByID :one
+SELECT * FROM activities WHERE id = $1;
+
+-- name: GetActivityByCode :one
+SELECT * FROM activities WHERE code = $1;
+
+-- name: GetAllActivities :many
+SELECT * FROM activities ORDER BY sort_order, display_name;
+
+-- name: DeleteActivity :one
+DELETE FROM activities WHERE id = $1 RETURNING *;
+
+-- name: CountSessionsForActivity :one
+SELECT COUNT(*) FROM sessions WHERE activity_done = $1;
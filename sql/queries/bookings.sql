@@ -0,0 +1,103 @@
+-- name: CreateBooking :one
+INSERT INTO bookings (
+    title,
+    starts_at,
+    ends_at,
+    room_id,
+    project_id,
+    episode_id,
+    notes
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7
+) RETURNING *;
+
+-- name: UpdateBooking :one
+UPDATE bookings SET
+    title = $2,
+    starts_at = $3,
+    ends_at = $4,
+    room_id = $5,
+    project_id = $6,
+    episode_id = $7,
+    notes = $8,
+    updated_at = NOW()
+WHERE id = $1 RETURNING *;
+
+-- name: GetBooking :one
+SELECT * FROM bookings WHERE id = $1;
+
+-- name: GetBookingsInRange :many
+SELECT * FROM bookings WHERE ends_at > $1 AND starts_at < $2 ORDER BY starts_at ASC;
+
+-- name: DeleteBooking :one
+DELETE FROM bookings WHERE id = $1 RETURNING *;
+
+-- name: AddUserToBooking :one
+INSERT INTO user_booking (
+    user_id,
+    booking_id
+) VALUES (
+    $1,
+    $2
+) RETURNING *;
+
+-- name: GetUsersForBooking :many
+SELECT user_booking.user_id, users.username FROM user_booking JOIN users ON users.id = user_booking.user_id WHERE user_booking.booking_id = $1;
+
+-- name: GetBookingsForFeed :many
+SELECT
+    bookings.id,
+    bookings.updated_at,
+    bookings.title,
+    bookings.starts_at,
+    bookings.ends_at,
+    bookings.notes,
+    bookings.project_id,
+    projects.title AS project_title,
+    rooms.room_name
+FROM bookings
+JOIN projects ON projects.id = bookings.project_id
+LEFT JOIN rooms ON rooms.id = bookings.room_id
+WHERE bookings.ends_at >= sqlc.arg('since')::timestamp AND projects.deleted_at IS NULL
+AND (sqlc.narg('user_id')::uuid IS NULL OR EXISTS (
+    SELECT 1 FROM user_booking WHERE user_booking.booking_id = bookings.id AND user_booking.user_id = sqlc.narg('user_id')::uuid
+))
+AND (sqlc.narg('room_id')::uuid IS NULL OR bookings.room_id = sqlc.narg('room_id')::uuid)
+AND (sqlc.narg('project_id')::uuid IS NULL OR bookings.project_id = sqlc.narg('project_id')::uuid)
+ORDER BY bookings.starts_at ASC;
+
+-- name: ImportBooking :one
+INSERT INTO bookings (
+    title,
+    starts_at,
+    ends_at,
+    room_id,
+    project_id,
+    episode_id,
+    external_uid
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7
+) ON CONFLICT (external_uid) DO UPDATE SET
+    title = EXCLUDED.title,
+    starts_at = EXCLUDED.starts_at,
+    ends_at = EXCLUDED.ends_at,
+    room_id = EXCLUDED.room_id,
+    project_id = EXCLUDED.project_id,
+    episode_id = EXCLUDED.episode_id,
+    updated_at = NOW()
+RETURNING *;
+
+-- name: GetBookingsByExternalUID :many
+SELECT * FROM bookings WHERE external_uid = ANY(sqlc.arg('external_uids')::text[]);
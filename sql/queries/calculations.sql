@@ -3,12 +3,14 @@ INSERT INTO calculations (
     project_id,
     budget,
     currency,
-    exchange_rate
+    exchange_rate,
+    whole_project
 ) VALUES (
     $1,
     $2,
     $3,
-    $4
+    $4,
+    $5
 ) RETURNING *;
 
 -- name: UpdateCalculation :one
@@ -18,7 +20,7 @@ UPDATE calculations SET
     currency = $4,
     exchange_rate = $5,
     updated_at = NOW()
-WHERE id = $1 RETURNING *;
+WHERE id = $1 AND deleted_at IS NULL RETURNING *;
 
 -- name: AddEpisodeToCalculation :one
 INSERT INTO episode_calc (
@@ -32,16 +34,42 @@ INSERT INTO episode_calc (
 -- name: RemoveEpisodeFromCalculation :one
 DELETE FROM episode_calc WHERE calc_id = $1 AND episode_id = $2 RETURNING *;
 
+-- name: AddUnitToCalculation :one
+INSERT INTO unit_calc (
+    unit_id,
+    calc_id
+) VALUES (
+    $1,
+    $2
+) RETURNING *;
+
+-- name: GetUnitsForCalculation :many
+SELECT unit_id FROM unit_calc WHERE calc_id = $1;
+
 -- name: GetEpisodesForCalculation :many
 SELECT episode_id FROM episode_calc WHERE calc_id = $1;
 
 -- name: GetCalculation :one
-SELECT * FROM calculations WHERE id = $1;
+SELECT * FROM calculations WHERE id = $1 AND deleted_at IS NULL;
 
 -- name: GetAllCalculationsForProject :many
-SELECT * FROM calculations WHERE project_id = $1;
+SELECT * FROM calculations WHERE project_id = $1 AND deleted_at IS NULL;
 
 -- name: GetMinutesForCalculation :one
-SELECT SUM(sessions.duration) AS minutes FROM sessions
-JOIN episode_calc ON episode_calc.episode_id = sessions.episode_id
-WHERE episode_calc.calc_id = $1;
+WITH RECURSIVE calc_units AS (
+    SELECT unit_calc.unit_id AS id FROM unit_calc WHERE unit_calc.calc_id = $1
+    UNION
+    SELECT project_units.id FROM project_units JOIN calc_units ON project_units.parent_id = calc_units.id
+)
+SELECT COALESCE(ROUND(SUM(
+    sessions.duration * COALESCE(parts.billing_weight, 1) * COALESCE(activities.billing_weight, 1)
+)), 0)::bigint AS minutes FROM sessions
+JOIN calculations ON calculations.id = $1 AND calculations.project_id = sessions.project_id
+JOIN parts ON parts.code = sessions.part_worked_on
+JOIN activities ON activities.code = sessions.activity_done
+WHERE sessions.status = 'approved' AND sessions.deleted_at IS NULL AND (
+    sessions.episode_id IN (SELECT episode_calc.episode_id FROM episode_calc WHERE episode_calc.calc_id = $1)
+    OR sessions.unit_id IN (SELECT calc_units.id FROM calc_units)
+    OR sessions.episode_id IN (SELECT episodes.id FROM episodes WHERE episodes.season_id IN (SELECT calc_units.id FROM calc_units))
+    OR calculations.whole_project
+);
@@ -0,0 +1,21 @@
+-- name: CreateCalendarFeed :one
+INSERT INTO calendar_feeds (
+    token,
+    owner_id,
+    scope,
+    target_id
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4
+) RETURNING *;
+
+-- name: GetCalendarFeed :one
+SELECT * FROM calendar_feeds WHERE token = $1;
+
+-- name: GetCalendarFeedsForUser :many
+SELECT * FROM calendar_feeds WHERE owner_id = $1 ORDER BY created_at ASC;
+
+-- name: DeleteCalendarFeed :one
+DELETE FROM calendar_feeds WHERE token = $1 AND owner_id = $2 RETURNING *;
@@ -10,13 +10,13 @@ INSERT INTO clients (
 ) RETURNING *;
 
 -- name: GetClientByID :one
-SELECT * FROM clients WHERE id=$1;
+SELECT * FROM clients WHERE id=$1 AND deleted_at IS NULL;
 
 -- name: GetClientByName :one
-SELECT * FROM clients WHERE client_name=$1;
+SELECT * FROM clients WHERE client_name=$1 AND deleted_at IS NULL;
 
 -- name: GetAllClients :many
-SELECT * FROM clients;
+SELECT * FROM clients WHERE deleted_at IS NULL;
 
 -- name: UpdateClient :one
 UPDATE clients SET
@@ -24,7 +24,109 @@ UPDATE clients SET
     client_name=$2,
     email=$3,
     notes=$4
-WHERE id=$1 RETURNING *;
+WHERE id=$1 AND deleted_at IS NULL RETURNING *;
 
 -- name: DeleteClient :one
 DELETE FROM clients WHERE id=$1 RETURNING *;
+
+-- name: SetClientBilling :one
+UPDATE clients SET
+    updated_at=NOW(),
+    tax_id=$2,
+    default_currency=$3,
+    payment_terms_days=$4,
+    invoice_language=$5
+WHERE id=$1 AND deleted_at IS NULL RETURNING *;
+
+-- name: CreateClientContact :one
+INSERT INTO client_contacts (
+    client_id,
+    name,
+    role,
+    email,
+    phone
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5
+) RETURNING *;
+
+-- name: UpdateClientContact :one
+UPDATE client_contacts SET
+    updated_at=NOW(),
+    name=$2,
+    role=$3,
+    email=$4,
+    phone=$5
+WHERE id=$1 RETURNING *;
+
+-- name: GetClientContactByID :one
+SELECT * FROM client_contacts WHERE id=$1;
+
+-- name: GetContactsForClient :many
+SELECT * FROM client_contacts WHERE client_id=$1 ORDER BY name ASC;
+
+-- name: DeleteClientContact :one
+DELETE FROM client_contacts WHERE id=$1 RETURNING *;
+
+-- name: SetClientAddress :one
+INSERT INTO client_addresses (
+    client_id,
+    kind,
+    line1,
+    line2,
+    postal_code,
+    city,
+    country
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7
+) ON CONFLICT (client_id, kind) DO UPDATE SET
+    updated_at=NOW(),
+    line1=EXCLUDED.line1,
+    line2=EXCLUDED.line2,
+    postal_code=EXCLUDED.postal_code,
+    city=EXCLUDED.city,
+    country=EXCLUDED.country
+RETURNING *;
+
+-- name: GetAddressesForClient :many
+SELECT * FROM client_addresses WHERE client_id=$1 ORDER BY kind ASC;
+
+-- name: DeleteClientAddress :one
+DELETE FROM client_addresses WHERE client_id=$1 AND kind=$2 RETURNING *;
+
+-- name: GetClientDependencies :one
+SELECT
+    (SELECT COUNT(*) FROM projects p WHERE p.client_id=$1 AND p.deleted_at IS NULL) AS projects,
+    (SELECT COUNT(*) FROM episodes e JOIN projects p ON e.project_id=p.id
+        WHERE p.client_id=$1 AND p.deleted_at IS NULL AND e.deleted_at IS NULL) AS episodes,
+    (SELECT COUNT(*) FROM sessions s JOIN projects p ON s.project_id=p.id
+        WHERE p.client_id=$1 AND p.deleted_at IS NULL AND s.deleted_at IS NULL) AS sessions,
+    (SELECT COUNT(*) FROM calculations c JOIN projects p ON c.project_id=p.id
+        WHERE p.client_id=$1 AND p.deleted_at IS NULL AND c.deleted_at IS NULL) AS calculations,
+    (SELECT COUNT(*) FROM bookings b JOIN projects p ON b.project_id=p.id
+        WHERE p.client_id=$1 AND p.deleted_at IS NULL) AS bookings;
+
+-- name: GetMovableClientDependencies :one
+SELECT
+    (SELECT COUNT(*) FROM projects p WHERE p.client_id=$1) AS projects,
+    (SELECT COUNT(*) FROM episodes e JOIN projects p ON e.project_id=p.id WHERE p.client_id=$1) AS episodes,
+    (SELECT COUNT(*) FROM sessions s JOIN projects p ON s.project_id=p.id WHERE p.client_id=$1) AS sessions,
+    (SELECT COUNT(*) FROM calculations c JOIN projects p ON c.project_id=p.id WHERE p.client_id=$1) AS calculations,
+    (SELECT COUNT(*) FROM bookings b JOIN projects p ON b.project_id=p.id WHERE p.client_id=$1) AS bookings;
+
+-- name: MoveContactsToClient :many
+UPDATE client_contacts SET updated_at=NOW(), client_id=$2 WHERE client_id=$1 RETURNING *;
+
+-- name: MoveAddressesToClient :many
+UPDATE client_addresses SET updated_at=NOW(), client_id=$2
+WHERE client_id=$1 AND kind NOT IN (SELECT kind FROM client_addresses WHERE client_id=$2)
+RETURNING *;
@@ -0,0 +1,105 @@
+-- name: CreateCue :one
+INSERT INTO cues (
+    episode_id,
+    cue_number,
+    reel,
+    frame_in,
+    frame_out,
+    frame_rate,
+    description,
+    part,
+    status
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8,
+    $9
+) RETURNING *;
+
+-- name: UpdateCue :one
+UPDATE cues SET
+    cue_number = $2,
+    reel = $3,
+    frame_in = $4,
+    frame_out = $5,
+    frame_rate = $6,
+    description = $7,
+    part = $8,
+    status = $9,
+    updated_at = NOW()
+WHERE id = $1 RETURNING *;
+
+-- name: GetCue :one
+SELECT cues.* FROM cues
+JOIN episodes ON episodes.id = cues.episode_id
+WHERE cues.id = $1 AND episodes.deleted_at IS NULL;
+
+-- name: GetCuesForEpisode :many
+SELECT cues.* FROM cues
+JOIN episodes ON episodes.id = cues.episode_id
+WHERE cues.episode_id = $1 AND episodes.deleted_at IS NULL
+ORDER BY cues.reel, cues.frame_in, cues.cue_number;
+
+-- name: DeleteCue :one
+DELETE FROM cues WHERE id = $1 RETURNING *;
+
+-- name: AddCueToSession :one
+INSERT INTO session_cue (
+    session_id,
+    cue_id
+) VALUES (
+    $1,
+    $2
+) ON CONFLICT (session_id, cue_id) DO UPDATE SET updated_at = NOW()
+RETURNING *;
+
+-- name: GetCuesForSession :many
+SELECT cues.* FROM cues
+JOIN session_cue ON session_cue.cue_id = cues.id
+WHERE session_cue.session_id = $1
+ORDER BY cues.reel, cues.frame_in, cues.cue_number;
+
+-- name: GetCueProgressForEpisode :many
+SELECT
+    cues.id,
+    COUNT(sessions.id) AS session_count,
+    COALESCE(ROUND(SUM(sessions.duration::numeric / shares.cue_count)), 0)::bigint AS minutes
+FROM cues
+LEFT JOIN session_cue ON session_cue.cue_id = cues.id
+LEFT JOIN sessions ON sessions.id = session_cue.session_id AND sessions.deleted_at IS NULL
+LEFT JOIN (
+    SELECT session_cue.session_id, COUNT(*) AS cue_count FROM session_cue GROUP BY session_cue.session_id
+) AS shares ON shares.session_id = sessions.id
+WHERE cues.episode_id = $1
+GROUP BY cues.id;
+
+-- name: ImportCue :one
+INSERT INTO cues (
+    episode_id,
+    cue_number,
+    reel,
+    frame_in,
+    frame_out,
+    frame_rate,
+    description
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7
+) ON CONFLICT (episode_id, cue_number) DO UPDATE SET
+    reel = EXCLUDED.reel,
+    frame_in = EXCLUDED.frame_in,
+    frame_out = EXCLUDED.frame_out,
+    frame_rate = EXCLUDED.frame_rate,
+    description = EXCLUDED.description,
+    updated_at = NOW()
+RETURNING *;
@@ -0,0 +1,28 @@
+-- name: CreateEpisodeTrack :one
+INSERT INTO episode_tracks (
+    episode_id,
+    session_name,
+    position,
+    track_name,
+    comments,
+    clip_count,
+    muted_clip_count,
+    clip_frames,
+    frame_rate
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8,
+    $9
+) RETURNING *;
+
+-- name: DeleteEpisodeTracks :exec
+DELETE FROM episode_tracks WHERE episode_id = $1;
+
+-- name: GetEpisodeTracks :many
+SELECT * FROM episode_tracks WHERE episode_id = $1 ORDER BY position;
@@ -2,11 +2,19 @@
 INSERT INTO episodes (
     title,
     episode_number,
-    project_id
+    project_id,
+    season_id,
+    runtime_seconds,
+    picture_lock,
+    frame_rate
 ) VALUES (
     $1,
     $2,
-    $3
+    $3,
+    $4,
+    $5,
+    $6,
+    $7
 ) RETURNING *;
 
 -- name: UpdateEpisode :one
@@ -14,20 +22,49 @@ UPDATE episodes SET
     title = $2,
     episode_number = $3,
     project_id = $4,
+    season_id = $5,
+    runtime_seconds = $6,
+    picture_lock = $7,
+    frame_rate = $8,
     updated_at = NOW()
-WHERE id = $1 RETURNING *;
+WHERE id = $1 AND deleted_at IS NULL RETURNING *;
 
 -- name: GetEpisodeByID :one
-SELECT * FROM episodes WHERE id = $1;
+SELECT * FROM episodes WHERE id = $1 AND deleted_at IS NULL;
 
--- name: GetEpisodeByNumber :one
-SELECT * FROM episodes WHERE project_id = $1 AND episode_number = $2;
+-- name: GetEpisodesByNumber :many
+SELECT * FROM episodes WHERE project_id = $1 AND episode_number = $2 AND deleted_at IS NULL;
+
+-- name: GetEpisodeInSeason :one
+SELECT * FROM episodes WHERE season_id = $1 AND episode_number = $2 AND deleted_at IS NULL;
 
 -- name: GetAllEpisodes :many
-SELECT * FROM episodes;
+SELECT * FROM episodes WHERE deleted_at IS NULL;
 
 -- name: GetAllEpisodesForProject :many
-SELECT * FROM episodes WHERE project_id = $1 ORDER BY episode_number ASC;
+SELECT episodes.* FROM episodes
+LEFT JOIN project_units ON project_units.id = episodes.season_id
+WHERE episodes.project_id = $1 AND episodes.deleted_at IS NULL
+ORDER BY project_units.number ASC NULLS FIRST, episodes.episode_number ASC;
+
+-- name: GetEpisodesForSeason :many
+SELECT * FROM episodes WHERE season_id = $1 AND deleted_at IS NULL ORDER BY episode_number ASC;
+
+-- name: GetRuntimeReportForProject :many
+SELECT
+    episodes.id,
+    episodes.episode_number,
+    episodes.title,
+    episodes.season_id,
+    episodes.runtime_seconds,
+    episodes.picture_lock,
+    COALESCE(SUM(sessions.duration), 0)::bigint AS minutes
+FROM episodes
+LEFT JOIN project_units ON project_units.id = episodes.season_id
+LEFT JOIN sessions ON sessions.episode_id = episodes.id AND sessions.deleted_at IS NULL
+WHERE episodes.project_id = $1 AND episodes.deleted_at IS NULL
+GROUP BY episodes.id, project_units.number
+ORDER BY project_units.number ASC NULLS FIRST, episodes.episode_number ASC;
 
 -- name: DeleteEpisode :one
 DELETE FROM episodes WHERE id = $1 RETURNING *;
@@ -0,0 +1,60 @@
+-- name: CreateMilestone :one
+INSERT INTO milestones (
+    project_id,
+    episode_id,
+    kind,
+    title,
+    due_date,
+    notes
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6
+) RETURNING *;
+
+-- name: UpdateMilestone :one
+UPDATE milestones SET
+    episode_id = $2,
+    kind = $3,
+    title = $4,
+    due_date = $5,
+    notes = $6,
+    completed_at = $7,
+    updated_at = NOW()
+WHERE id = $1 RETURNING *;
+
+-- name: GetMilestoneByID :one
+SELECT milestones.* FROM milestones
+JOIN projects ON projects.id = milestones.project_id
+LEFT JOIN episodes ON episodes.id = milestones.episode_id
+WHERE milestones.id = $1 AND projects.deleted_at IS NULL AND episodes.deleted_at IS NULL;
+
+-- name: GetMilestonesForProject :many
+SELECT * FROM milestones WHERE project_id = $1 ORDER BY due_date ASC, title ASC;
+
+-- name: GetOpenMilestonesDueBefore :many
+SELECT
+    milestones.id,
+    milestones.project_id,
+    projects.title AS project_title,
+    milestones.episode_id,
+    episodes.episode_number,
+    milestones.kind,
+    milestones.title,
+    milestones.due_date,
+    milestones.notes,
+    (SELECT COALESCE(SUM(sessions.duration), 0) FROM sessions
+        WHERE sessions.project_id = milestones.project_id AND sessions.deleted_at IS NULL
+        AND (milestones.episode_id IS NULL OR sessions.episode_id = milestones.episode_id))::BIGINT AS minutes
+FROM milestones
+JOIN projects ON projects.id = milestones.project_id
+LEFT JOIN episodes ON episodes.id = milestones.episode_id
+WHERE milestones.completed_at IS NULL AND milestones.due_date < $1
+AND projects.deleted_at IS NULL AND episodes.deleted_at IS NULL
+ORDER BY milestones.due_date ASC, projects.title ASC, episodes.episode_number ASC;
+
+-- name: DeleteMilestone :one
+DELETE FROM milestones WHERE id = $1 RETURNING *;
@@ -0,0 +1,38 @@
+-- name: CreatePart :one
+INSERT INTO parts (
+    code,
+    display_name,
+    sort_order,
+    active,
+    billing_weight
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5
+) RETURNING *;
+
+-- name: UpdatePart :one
+UPDATE parts SET
+    display_name = $2,
+    sort_order = $3,
+    active = $4,
+    billing_weight = $5,
+    updated_at = NOW()
+WHERE id = $1 RETURNING *;
+
+-- name: GetPartByID :one
+SELECT * FROM parts WHERE id = $1;
+
+-- name: GetPartByCode :one
+SELECT * FROM parts WHERE code = $1;
+
+-- name: GetAllParts :many
+SELECT * FROM parts ORDER BY sort_order, display_name;
+
+-- name: DeletePart :one
+DELETE FROM parts WHERE id = $1 RETURNING *;
+
+-- name: CountSessionsForPart :one
+SELECT COUNT(*) FROM sessions WHERE part_worked_on = $1;
@@ -0,0 +1,57 @@
+-- name: SetEpisodePartStatus :one
+INSERT INTO episode_part_status (
+    episode_id,
+    part,
+    status,
+    assignee_id,
+    status_date,
+    notes
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6
+) ON CONFLICT (episode_id, part) DO UPDATE SET
+    status = EXCLUDED.status,
+    assignee_id = EXCLUDED.assignee_id,
+    status_date = EXCLUDED.status_date,
+    notes = EXCLUDED.notes,
+    updated_at = NOW()
+RETURNING *;
+
+-- name: GetEpisodePartStatus :one
+SELECT * FROM episode_part_status WHERE episode_id = $1 AND part = $2;
+
+-- name: AdvanceEpisodePartStatus :one
+INSERT INTO episode_part_status (
+    episode_id,
+    part,
+    status,
+    status_date
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4
+) ON CONFLICT (episode_id, part) DO UPDATE SET
+    status = EXCLUDED.status,
+    status_date = EXCLUDED.status_date,
+    updated_at = NOW()
+WHERE episode_part_status.status < EXCLUDED.status
+RETURNING *;
+
+-- name: GetStatusMatrixForProject :many
+SELECT
+    episode_part_status.episode_id,
+    episode_part_status.part,
+    episode_part_status.status,
+    episode_part_status.assignee_id,
+    users.username AS assignee,
+    episode_part_status.status_date,
+    episode_part_status.notes
+FROM episode_part_status
+JOIN episodes ON episodes.id = episode_part_status.episode_id
+LEFT JOIN users ON users.id = episode_part_status.assignee_id
+WHERE episodes.project_id = $1 AND episodes.deleted_at IS NULL;
@@ -0,0 +1,26 @@
+-- name: SetProjectMember :one
+INSERT INTO project_members (
+    project_id,
+    user_id,
+    role
+) VALUES (
+    $1,
+    $2,
+    $3
+) ON CONFLICT (project_id, user_id) DO UPDATE SET
+    role = EXCLUDED.role,
+    updated_at = NOW()
+RETURNING *;
+
+-- name: GetProjectMembers :many
+SELECT project_members.user_id, users.username, project_members.role, project_members.created_at
+FROM project_members
+JOIN users ON users.id = project_members.user_id
+WHERE project_members.project_id = $1
+ORDER BY users.username;
+
+-- name: GetProjectRolesForUser :many
+SELECT project_id, role FROM project_members WHERE user_id = $1;
+
+-- name: RemoveProjectMember :one
+DELETE FROM project_members WHERE project_id = $1 AND user_id = $2 RETURNING *;
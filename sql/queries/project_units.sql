@@ -0,0 +1,56 @@
+-- name: CreateProjectUnit :one
+INSERT INTO project_units (
+    project_id,
+    parent_id,
+    kind,
+    name,
+    sort_order,
+    number,
+    starts_on,
+    ends_on,
+    budget,
+    currency,
+    exchange_rate
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8,
+    $9,
+    $10,
+    $11
+) RETURNING *;
+
+-- name: UpdateProjectUnit :one
+UPDATE project_units SET
+    parent_id = $2,
+    kind = $3,
+    name = $4,
+    sort_order = $5,
+    number = $6,
+    starts_on = $7,
+    ends_on = $8,
+    budget = $9,
+    currency = $10,
+    exchange_rate = $11,
+    updated_at = NOW()
+WHERE id = $1 RETURNING *;
+
+-- name: GetProjectUnitByID :one
+SELECT * FROM project_units WHERE id = $1;
+
+-- name: GetUnitByNumber :one
+SELECT * FROM project_units WHERE project_id = $1 AND kind = $2 AND number = $3;
+
+-- name: GetUnitsForProject :many
+SELECT * FROM project_units WHERE project_id = $1 ORDER BY sort_order ASC, number ASC NULLS LAST, name ASC;
+
+-- name: CountEpisodesForSeason :one
+SELECT COUNT(*) FROM episodes WHERE season_id = $1;
+
+-- name: DeleteProjectUnit :one
+DELETE FROM project_units WHERE id = $1 RETURNING *;
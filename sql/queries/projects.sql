@@ -1,30 +1,45 @@
 -- name: CreateProject :one
 INSERT INTO projects (
     title,
-    client_id
+    client_id,
+    status,
+    project_type
 ) VALUES (
     $1,
-    $2
+    $2,
+    $3,
+    $4
 ) RETURNING *;
 
 -- name: GetProjectByID :one
-SELECT * FROM projects WHERE id=$1;
+SELECT * FROM projects WHERE id=$1 AND deleted_at IS NULL;
 
 -- name: GetProjectByTitle :one
-SELECT * FROM projects WHERE title=$1;
+SELECT * FROM projects WHERE title=$1 AND deleted_at IS NULL;
 
 -- name: GetAllProjects :many
-SELECT * FROM projects;
+SELECT * FROM projects WHERE deleted_at IS NULL;
 
 -- name: GetProjectsByClient :many
-SELECT * FROM projects WHERE client_id=$1;
+SELECT * FROM projects WHERE client_id=$1 AND deleted_at IS NULL;
 
 -- name: UpdateProject :one
 UPDATE projects SET
     updated_at = NOW(),
     title = $2,
-    client_id = $3
-WHERE id = $1 RETURNING *;
+    client_id = $3,
+    project_type = $4
+WHERE id = $1 AND deleted_at IS NULL RETURNING *;
+
+-- name: SetProjectStatus :one
+UPDATE projects SET
+    updated_at = NOW(),
+    status = $2,
+    status_changed_at = NOW()
+WHERE id = $1 AND deleted_at IS NULL RETURNING *;
 
 -- name: DeleteProject :one
 DELETE FROM projects WHERE id=$1 RETURNING *;
+
+-- name: MoveProjectsToClient :many
+UPDATE projects SET updated_at=NOW(), client_id=$2 WHERE client_id=$1 RETURNING *;
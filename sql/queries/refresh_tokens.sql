@@ -1,6 +1,8 @@
 -- name: SetRefToken :one
-INSERT INTO refresh_tokens (token, created_at, updated_at, user_id, expires_at,        revoked_at) VALUES 
-                              ($1,      NOW(),      NOW(),      $2, $3, NULL      ) RETURNING *;
+INSERT INTO refresh_tokens (token, created_at, updated_at, user_id, expires_at,        revoked_at, family_id,
+                            device_label, user_agent, ip_address, last_used_at) VALUES 
+                              ($1,      NOW(),      NOW(),      $2, $3, NULL      , $4,
+                            $5, $6, $7, NOW()) RETURNING *;
 
 -- name: GetRefToken :one
 SELECT * FROM refresh_tokens WHERE token = $1;
@@ -9,4 +11,33 @@ SELECT * FROM refresh_tokens WHERE token = $1;
 SELECT user_id FROM refresh_tokens WHERE token = $1;
 
 -- name: RevokeToken :one
-UPDATE refresh_tokens SET revoked_at = Now(), updated_at = Now() WHERE token = $1 RETURNING *;
\ No newline at end of file
+UPDATE refresh_tokens SET revoked_at = Now(), updated_at = Now() WHERE token = $1 RETURNING *;
+
+-- name: RotateRefToken :one
+UPDATE refresh_tokens SET revoked_at = NOW(), updated_at = NOW(), replaced_by = $2
+WHERE token = $1 AND revoked_at IS NULL RETURNING *;
+
+-- name: RevokeTokenFamily :many
+UPDATE refresh_tokens SET revoked_at = NOW(), updated_at = NOW()
+WHERE family_id = $1 AND revoked_at IS NULL RETURNING token;
+
+-- name: RevokeUserTokens :many
+UPDATE refresh_tokens SET revoked_at = NOW(), updated_at = NOW()
+WHERE user_id = $1 AND revoked_at IS NULL RETURNING token;
+
+-- name: GetActiveLogins :many
+SELECT
+    refresh_tokens.family_id,
+    refresh_tokens.device_label,
+    refresh_tokens.user_agent,
+    refresh_tokens.ip_address,
+    (SELECT MIN(first.created_at) FROM refresh_tokens first WHERE first.family_id = refresh_tokens.family_id)::timestamp AS logged_in_at,
+    refresh_tokens.last_used_at,
+    refresh_tokens.expires_at
+FROM refresh_tokens
+WHERE refresh_tokens.user_id = $1 AND refresh_tokens.revoked_at IS NULL AND refresh_tokens.expires_at > NOW()
+ORDER BY refresh_tokens.last_used_at DESC;
+
+-- name: RevokeUserTokenFamily :many
+UPDATE refresh_tokens SET revoked_at = NOW(), updated_at = NOW()
+WHERE family_id = $1 AND user_id = $2 AND revoked_at IS NULL RETURNING token;
@@ -0,0 +1,27 @@
+-- name: CreateRoom :one
+INSERT INTO rooms (
+    room_name,
+    notes
+) VALUES (
+    $1,
+    $2
+) RETURNING *;
+
+-- name: GetRoomByID :one
+SELECT * FROM rooms WHERE id = $1;
+
+-- name: GetRoomByName :one
+SELECT * FROM rooms WHERE room_name = $1;
+
+-- name: GetAllRooms :many
+SELECT * FROM rooms ORDER BY room_name ASC;
+
+-- name: UpdateRoom :one
+UPDATE rooms SET
+    updated_at = NOW(),
+    room_name = $2,
+    notes = $3
+WHERE id = $1 RETURNING *;
+
+-- name: DeleteRoom :one
+DELETE FROM rooms WHERE id = $1 RETURNING *;
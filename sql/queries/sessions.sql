@@ -2,55 +2,58 @@
 INSERT INTO sessions (
     duration,
     session_date,
-    episode_id,
     project_id,
+    episode_id,
+    unit_id,
     part_worked_on,
-    activity_done
+    activity_done,
+    start_time
 ) VALUES (
     $1,
     $2,
     $3,
-    (SELECT project_id FROM episodes WHERE id = $3),
     $4,
-    $5
+    $5,
+    $6,
+    $7,
+    $8
 ) RETURNING * ;
 
 -- name: UpdateSession :one
 UPDATE sessions SET
     duration = $2,
     session_date = $3,
-    episode_id = $4,
-    project_id = (SELECT project_id FROM episodes WHERE id = $4),
-    part_worked_on = $5,
-    activity_done = $6
-WHERE sessions.id = $1 RETURNING *;
+    project_id = $4,
+    episode_id = $5,
+    unit_id = $6,
+    part_worked_on = $7,
+    activity_done = $8,
+    start_time = $9,
+    updated_at = NOW()
+WHERE sessions.id = $1 AND sessions.deleted_at IS NULL RETURNING *;
 
 -- name: GetSession :one
 SELECT 
     sessions.id,
     sessions.session_date,
+    sessions.start_time,
     sessions.created_at,
     sessions.updated_at,
     sessions.duration,
     sessions.part_worked_on,
     sessions.activity_done,
+    sessions.status,
     episodes.id AS episode_id,
     episodes.title AS episode_title,
     episodes.episode_number AS episode_number,
+    project_units.id AS unit_id,
+    project_units.name AS unit_name,
     projects.id AS project_id,
     projects.title AS project_title
 FROM sessions
-JOIN episodes ON episodes.id = sessions.episode_id
-JOIN projects ON projects.id = sessions.project_id WHERE sessions.id = $1;
-
--- name: GetSessionsForProject :many
-SELECT sessions.* FROM sessions WHERE sessions.project_id = $1 ORDER BY session_date DESC LIMIT $2;
-
--- name: GetSessionsForEpisode :many
-SELECT sessions.* FROM sessions WHERE sessions.episode_id = $1 ORDER BY session_date DESC LIMIT $2;
-
--- name: GetSessions :many
-SELECT * FROM sessions ORDER BY session_date DESC LIMIT $1;
+LEFT JOIN episodes ON episodes.id = sessions.episode_id
+LEFT JOIN project_units ON project_units.id = sessions.unit_id
+JOIN projects ON projects.id = sessions.project_id WHERE sessions.id = $1 AND sessions.deleted_at IS NULL;
 
 -- name: DeleteSession :one
 DELETE FROM sessions WHERE id = $1 RETURNING *;
@@ -66,3 +69,97 @@ INSERT INTO user_session (
 
 -- name: GetUsersForSession :many
 SELECT user_session.user_id, users.username FROM user_session JOIN users ON users.id = user_session.user_id WHERE user_session.session_id = $1;
+
+-- name: GetSessionsForFeed :many
+SELECT
+    sessions.id,
+    sessions.session_date,
+    sessions.updated_at,
+    sessions.duration,
+    sessions.part_worked_on,
+    sessions.activity_done,
+    episodes.title AS episode_title,
+    episodes.episode_number,
+    seasons.number AS season_number,
+    project_units.name AS unit_name,
+    sessions.project_id,
+    projects.title AS project_title
+FROM sessions
+LEFT JOIN episodes ON episodes.id = sessions.episode_id
+LEFT JOIN project_units AS seasons ON seasons.id = episodes.season_id
+LEFT JOIN project_units ON project_units.id = sessions.unit_id
+JOIN projects ON projects.id = sessions.project_id
+WHERE sessions.session_date >= sqlc.arg('since')::date AND sessions.deleted_at IS NULL
+AND (sqlc.narg('user_id')::uuid IS NULL OR EXISTS (
+    SELECT 1 FROM user_session WHERE user_session.session_id = sessions.id AND user_session.user_id = sqlc.narg('user_id')::uuid
+))
+AND (sqlc.narg('project_id')::uuid IS NULL OR sessions.project_id = sqlc.narg('project_id')::uuid)
+ORDER BY sessions.session_date ASC;
+
+-- name: ImportSession :one
+INSERT INTO sessions (
+    duration,
+    session_date,
+    project_id,
+    episode_id,
+    part_worked_on,
+    activity_done,
+    external_uid,
+    start_time
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8
+) ON CONFLICT (external_uid) DO UPDATE SET
+    duration = EXCLUDED.duration,
+    session_date = EXCLUDED.session_date,
+    start_time = EXCLUDED.start_time,
+    project_id = EXCLUDED.project_id,
+    episode_id = EXCLUDED.episode_id,
+    unit_id = NULL,
+    part_worked_on = EXCLUDED.part_worked_on,
+    activity_done = EXCLUDED.activity_done,
+    updated_at = NOW()
+WHERE sessions.status IN ('draft', 'rejected') AND sessions.deleted_at IS NULL
+RETURNING *;
+
+-- name: GetSessionsByExternalUID :many
+SELECT * FROM sessions WHERE external_uid = ANY(sqlc.arg('external_uids')::text[]);
+
+-- name: GetSessionsForUsersOnDate :many
+SELECT
+    user_session.user_id,
+    sessions.id,
+    sessions.session_date,
+    sessions.start_time,
+    sessions.duration
+FROM sessions
+JOIN user_session ON user_session.session_id = sessions.id
+WHERE user_session.user_id = ANY(sqlc.arg('user_ids')::uuid[])
+AND sessions.session_date = sqlc.arg('session_date') AND sessions.deleted_at IS NULL;
+
+-- name: GetSessionsForAnomalyReport :many
+SELECT
+    sessions.id,
+    sessions.session_date,
+    sessions.start_time,
+    sessions.duration,
+    user_session.user_id,
+    users.username
+FROM sessions
+LEFT JOIN user_session ON user_session.session_id = sessions.id
+LEFT JOIN users ON users.id = user_session.user_id
+WHERE sessions.session_date >= sqlc.arg('date_from')
+AND sessions.session_date <= sqlc.arg('date_to') AND sessions.deleted_at IS NULL
+ORDER BY sessions.session_date, sessions.start_time;
+
+-- name: GetUsersForSessions :many
+SELECT user_session.session_id, user_session.user_id, users.username FROM user_session
+JOIN users ON users.id = user_session.user_id
+WHERE user_session.session_id = ANY(sqlc.arg('session_ids')::uuid[])
+ORDER BY users.username;
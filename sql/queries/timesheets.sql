@@ -0,0 +1,69 @@
+-- name: SubmitTimesheet :one
+INSERT INTO timesheets (
+    user_id,
+    week_start
+) VALUES (
+    $1,
+    $2
+) ON CONFLICT (user_id, week_start) DO UPDATE SET
+    status = 'submitted',
+    submitted_at = NOW(),
+    reviewed_by = NULL,
+    reviewed_at = NULL,
+    review_comment = NULL,
+    updated_at = NOW()
+WHERE timesheets.status IN ('draft', 'rejected')
+RETURNING *;
+
+-- name: AttachSessionsToTimesheet :many
+UPDATE sessions SET
+    status = 'submitted',
+    timesheet_id = sqlc.arg('timesheet_id'),
+    updated_at = NOW()
+WHERE sessions.status IN ('draft', 'rejected') AND sessions.deleted_at IS NULL
+AND (sessions.timesheet_id IS NULL OR sessions.timesheet_id = sqlc.arg('timesheet_id'))
+AND sessions.session_date >= sqlc.arg('week_start')
+AND sessions.session_date < sqlc.arg('week_end')
+AND EXISTS (
+    SELECT 1 FROM user_session WHERE user_session.session_id = sessions.id AND user_session.user_id = sqlc.arg('user_id')
+) RETURNING *;
+
+-- name: ReviewTimesheet :one
+UPDATE timesheets SET
+    status = $2,
+    reviewed_by = $3,
+    reviewed_at = NOW(),
+    review_comment = $4,
+    updated_at = NOW()
+WHERE id = $1 AND status = 'submitted' RETURNING *;
+
+-- name: SetTimesheetSessionsStatus :many
+UPDATE sessions SET
+    status = $2,
+    updated_at = NOW()
+WHERE timesheet_id = $1 AND status = 'submitted' AND deleted_at IS NULL RETURNING *;
+
+-- name: GetTimesheet :one
+SELECT * FROM timesheets WHERE id = $1;
+
+-- name: GetTimesheetsForUser :many
+SELECT * FROM timesheets WHERE user_id = $1 ORDER BY week_start DESC;
+
+-- name: GetPendingTimesheets :many
+SELECT
+    timesheets.id,
+    timesheets.user_id,
+    users.username,
+    timesheets.week_start,
+    timesheets.submitted_at,
+    COUNT(sessions.id) AS session_count,
+    COALESCE(SUM(sessions.duration), 0)::bigint AS minutes
+FROM timesheets
+JOIN users ON users.id = timesheets.user_id
+LEFT JOIN sessions ON sessions.timesheet_id = timesheets.id AND sessions.status = 'submitted' AND sessions.deleted_at IS NULL
+WHERE timesheets.status = 'submitted'
+GROUP BY timesheets.id, users.username
+ORDER BY timesheets.submitted_at;
+
+-- name: GetSessionsForTimesheet :many
+SELECT * FROM sessions WHERE timesheet_id = $1 AND deleted_at IS NULL ORDER BY session_date, start_time;
@@ -0,0 +1,163 @@
+-- name: SoftDeleteClient :one
+UPDATE clients SET deleted_at=NOW() WHERE id=$1 AND deleted_at IS NULL RETURNING *;
+
+-- name: SoftDeleteProjectsForClient :many
+UPDATE projects SET deleted_at=NOW() WHERE client_id=$1 AND deleted_at IS NULL RETURNING *;
+
+-- name: SoftDeleteProject :one
+UPDATE projects SET deleted_at=NOW() WHERE id=$1 AND deleted_at IS NULL RETURNING *;
+
+-- name: SoftDeleteEpisodesForProject :exec
+UPDATE episodes SET deleted_at=NOW() WHERE project_id=$1 AND deleted_at IS NULL;
+
+-- name: SoftDeleteSessionsForProject :exec
+UPDATE sessions SET deleted_at=NOW() WHERE project_id=$1 AND deleted_at IS NULL;
+
+-- name: SoftDeleteCalculationsForProject :exec
+UPDATE calculations SET deleted_at=NOW() WHERE project_id=$1 AND deleted_at IS NULL;
+
+-- name: SoftDeleteEpisode :one
+UPDATE episodes SET deleted_at=NOW() WHERE id=$1 AND deleted_at IS NULL RETURNING *;
+
+-- name: SoftDeleteSessionsForEpisode :exec
+UPDATE sessions SET deleted_at=NOW() WHERE episode_id=$1 AND deleted_at IS NULL;
+
+-- name: SoftDeleteSession :one
+UPDATE sessions SET deleted_at=NOW() WHERE id=$1 AND deleted_at IS NULL RETURNING *;
+
+-- name: SoftDeleteCalculation :one
+UPDATE calculations SET deleted_at=NOW() WHERE id=$1 AND deleted_at IS NULL RETURNING *;
+
+-- name: GetDeletedProjectsForClient :many
+SELECT projects.* FROM projects
+JOIN clients ON clients.id = projects.client_id
+WHERE projects.client_id=$1 AND projects.deleted_at = clients.deleted_at;
+
+-- name: RestoreEpisodesForProject :exec
+UPDATE episodes SET deleted_at=NULL
+WHERE project_id=$1 AND deleted_at = (SELECT projects.deleted_at FROM projects WHERE projects.id=$1);
+
+-- name: RestoreSessionsForProject :exec
+UPDATE sessions SET deleted_at=NULL
+WHERE project_id=$1 AND deleted_at = (SELECT projects.deleted_at FROM projects WHERE projects.id=$1);
+
+-- name: RestoreCalculationsForProject :exec
+UPDATE calculations SET deleted_at=NULL
+WHERE project_id=$1 AND deleted_at = (SELECT projects.deleted_at FROM projects WHERE projects.id=$1);
+
+-- name: RestoreSessionsForEpisode :exec
+UPDATE sessions SET deleted_at=NULL
+WHERE episode_id=$1 AND deleted_at = (SELECT episodes.deleted_at FROM episodes WHERE episodes.id=$1);
+
+-- name: RestoreClient :one
+UPDATE clients SET deleted_at=NULL WHERE id=$1 AND deleted_at IS NOT NULL RETURNING *;
+
+-- name: RestoreProject :one
+UPDATE projects SET deleted_at=NULL WHERE id=$1 AND deleted_at IS NOT NULL RETURNING *;
+
+-- name: RestoreEpisode :one
+UPDATE episodes SET deleted_at=NULL WHERE id=$1 AND deleted_at IS NOT NULL RETURNING *;
+
+-- name: RestoreSession :one
+UPDATE sessions SET deleted_at=NULL WHERE id=$1 AND deleted_at IS NOT NULL RETURNING *;
+
+-- name: RestoreCalculation :one
+UPDATE calculations SET deleted_at=NULL WHERE id=$1 AND deleted_at IS NOT NULL RETURNING *;
+
+-- name: GetTrash :many
+SELECT
+    'client'::text AS kind,
+    clients.id,
+    clients.client_name AS name,
+    NULL::text AS project_title,
+    clients.deleted_at::timestamp AS deleted_at,
+    (SELECT COUNT(*) FROM projects
+        WHERE projects.client_id = clients.id AND projects.deleted_at = clients.deleted_at) AS projects,
+    (SELECT COUNT(*) FROM episodes JOIN projects ON projects.id = episodes.project_id
+        WHERE projects.client_id = clients.id AND episodes.deleted_at = clients.deleted_at) AS episodes,
+    (SELECT COUNT(*) FROM sessions JOIN projects ON projects.id = sessions.project_id
+        WHERE projects.client_id = clients.id AND sessions.deleted_at = clients.deleted_at) AS sessions,
+    (SELECT COUNT(*) FROM calculations JOIN projects ON projects.id = calculations.project_id
+        WHERE projects.client_id = clients.id AND calculations.deleted_at = clients.deleted_at) AS calculations
+FROM clients
+WHERE clients.deleted_at IS NOT NULL
+UNION ALL
+SELECT
+    'project'::text,
+    projects.id,
+    projects.title,
+    NULL::text,
+    projects.deleted_at::timestamp,
+    0::bigint,
+    (SELECT COUNT(*) FROM episodes
+        WHERE episodes.project_id = projects.id AND episodes.deleted_at = projects.deleted_at),
+    (SELECT COUNT(*) FROM sessions
+        WHERE sessions.project_id = projects.id AND sessions.deleted_at = projects.deleted_at),
+    (SELECT COUNT(*) FROM calculations
+        WHERE calculations.project_id = projects.id AND calculations.deleted_at = projects.deleted_at)
+FROM projects
+JOIN clients ON clients.id = projects.client_id
+WHERE projects.deleted_at IS NOT NULL AND projects.deleted_at IS DISTINCT FROM clients.deleted_at
+UNION ALL
+SELECT
+    'episode'::text,
+    episodes.id,
+    COALESCE(episodes.title, ''),
+    projects.title,
+    episodes.deleted_at::timestamp,
+    0::bigint,
+    0::bigint,
+    (SELECT COUNT(*) FROM sessions
+        WHERE sessions.episode_id = episodes.id AND sessions.deleted_at = episodes.deleted_at),
+    0::bigint
+FROM episodes
+JOIN projects ON projects.id = episodes.project_id
+WHERE episodes.deleted_at IS NOT NULL AND episodes.deleted_at IS DISTINCT FROM projects.deleted_at
+UNION ALL
+SELECT
+    'session'::text,
+    sessions.id,
+    sessions.session_date::text || ' ' || sessions.part_worked_on || ' ' || sessions.activity_done,
+    projects.title,
+    sessions.deleted_at::timestamp,
+    0::bigint,
+    0::bigint,
+    0::bigint,
+    0::bigint
+FROM sessions
+JOIN projects ON projects.id = sessions.project_id
+LEFT JOIN episodes ON episodes.id = sessions.episode_id
+WHERE sessions.deleted_at IS NOT NULL AND sessions.deleted_at IS DISTINCT FROM projects.deleted_at
+AND sessions.deleted_at IS DISTINCT FROM episodes.deleted_at
+UNION ALL
+SELECT
+    'calculation'::text,
+    calculations.id,
+    calculations.budget::text || ' ' || calculations.currency,
+    projects.title,
+    calculations.deleted_at::timestamp,
+    0::bigint,
+    0::bigint,
+    0::bigint,
+    0::bigint
+FROM calculations
+JOIN projects ON projects.id = calculations.project_id
+WHERE calculations.deleted_at IS NOT NULL AND calculations.deleted_at IS DISTINCT FROM projects.deleted_at
+ORDER BY deleted_at DESC;
+
+-- name: PurgeSessions :many
+DELETE FROM sessions WHERE deleted_at < NOW() - $1::int * INTERVAL '1 day' RETURNING id;
+
+-- name: PurgeCalculations :many
+DELETE FROM calculations WHERE deleted_at < NOW() - $1::int * INTERVAL '1 day' RETURNING id;
+
+-- name: PurgeEpisodes :many
+DELETE FROM episodes WHERE deleted_at < NOW() - $1::int * INTERVAL '1 day' RETURNING id;
+
+-- name: PurgeProjects :many
+DELETE FROM projects WHERE deleted_at < NOW() - $1::int * INTERVAL '1 day' RETURNING id;
+
+-- name: PurgeClients :many
+DELETE FROM clients WHERE deleted_at < NOW() - $1::int * INTERVAL '1 day'
+AND NOT EXISTS (SELECT 1 FROM projects WHERE projects.client_id = clients.id)
+RETURNING id;
@@ -2,11 +2,13 @@
 INSERT INTO users (
     username,
     email,
-    hashed_password
+    hashed_password,
+    role
 ) VALUES (
     $1,
     $2,
-    $3
+    $3,
+    CASE WHEN EXISTS (SELECT 1 FROM users) THEN 'engineer'::user_role ELSE 'admin'::user_role END
 ) RETURNING *;
 
 -- name: GetUserByEmail :one
@@ -19,7 +21,7 @@ SELECT * FROM users WHERE username = $1;
 SELECT * FROM users WHERE id = $1;
 
 -- name: GetAllUsers :many
-SELECT id, created_at, updated_at, username, email FROM users;
+SELECT id, created_at, updated_at, username, email, role FROM users;
 
 -- name: DeleteUser :one
 DELETE FROM users WHERE id = $1 RETURNING *;
@@ -33,3 +35,12 @@ WHERE id = $1 RETURNING *;
 
 -- name: ChangePassword :one
 UPDATE users SET hashed_password = $2 WHERE id = $1 RETURNING *;
+
+-- name: SetUserRole :one
+UPDATE users SET updated_at = NOW(), role = $2 WHERE id = $1 RETURNING *;
+
+-- name: LockAdmins :many
+SELECT id FROM users WHERE role = 'admin' FOR UPDATE;
+
+-- name: LockUsersForSignup :exec
+LOCK TABLE users IN SHARE ROW EXCLUSIVE MODE;
@@ -0,0 +1,11 @@
+-- +goose Up
+CREATE TABLE rooms (
+    id UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    room_name TEXT UNIQUE NOT NULL,
+    notes TEXT
+);
+
+-- +goose Down
+DROP TABLE rooms;
@@ -0,0 +1,27 @@
+-- +goose Up
+CREATE TABLE bookings (
+    id UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    title TEXT NOT NULL,
+    starts_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP NOT NULL,
+    room_id UUID REFERENCES rooms ON DELETE SET NULL,
+    project_id UUID NOT NULL REFERENCES projects ON DELETE CASCADE,
+    episode_id UUID REFERENCES episodes ON DELETE CASCADE,
+    notes TEXT,
+    CHECK (ends_at > starts_at)
+);
+
+CREATE TABLE user_booking (
+    id UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    user_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
+    booking_id UUID NOT NULL REFERENCES bookings ON DELETE CASCADE,
+    UNIQUE (user_id, booking_id)
+);
+
+-- +goose Down
+DROP TABLE user_booking;
+DROP TABLE bookings;
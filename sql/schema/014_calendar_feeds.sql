@@ -0,0 +1,15 @@
+-- +goose Up
+CREATE TYPE feed_scope AS ENUM ('user', 'room', 'project');
+
+CREATE TABLE calendar_feeds (
+    token TEXT PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    owner_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
+    scope FEED_SCOPE NOT NULL,
+    target_id UUID NOT NULL
+);
+
+-- +goose Down
+DROP TABLE calendar_feeds;
+DROP TYPE feed_scope;
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE bookings ADD external_uid TEXT UNIQUE;
+ALTER TABLE sessions ADD external_uid TEXT UNIQUE;
+
+-- +goose Down
+ALTER TABLE bookings DROP COLUMN external_uid;
+ALTER TABLE sessions DROP COLUMN external_uid;
@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE sessions ADD COLUMN start_time TIME;
+
+-- +goose Down
+ALTER TABLE sessions DROP COLUMN start_time;
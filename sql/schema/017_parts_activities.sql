@@ -0,0 +1,64 @@
+-- +goose Up
+CREATE TABLE parts (
+    id UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    code TEXT NOT NULL UNIQUE,
+    display_name TEXT NOT NULL,
+    sort_order INTEGER NOT NULL DEFAULT 0,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    billing_weight NUMERIC(6, 3)
+);
+
+CREATE TABLE activities (
+    id UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    code TEXT NOT NULL UNIQUE,
+    display_name TEXT NOT NULL,
+    sort_order INTEGER NOT NULL DEFAULT 0,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    billing_weight NUMERIC(6, 3)
+);
+
+-- The values of the old enums become the first entries
+INSERT INTO parts (code, display_name, sort_order) VALUES
+    ('props', 'Props', 10),
+    ('footsteps', 'Footsteps', 20),
+    ('movements', 'Movements', 30),
+    ('dialogue', 'Dialogue', 40),
+    ('adr', 'ADR', 50),
+    ('music', 'Music', 60),
+    ('background', 'Background', 70),
+    ('other', 'Other', 80);
+
+INSERT INTO activities (code, display_name, sort_order) VALUES
+    ('record', 'Record', 10),
+    ('edit', 'Edit', 20),
+    ('service', 'Service', 30),
+    ('spotting', 'Spotting', 40),
+    ('other', 'Other', 50);
+
+ALTER TABLE sessions
+    ALTER COLUMN part_worked_on TYPE TEXT USING part_worked_on::text,
+    ALTER COLUMN activity_done TYPE TEXT USING activity_done::text,
+    ADD CONSTRAINT sessions_part_worked_on_fkey FOREIGN KEY (part_worked_on) REFERENCES parts (code),
+    ADD CONSTRAINT sessions_activity_done_fkey FOREIGN KEY (activity_done) REFERENCES activities (code);
+
+DROP TYPE part;
+DROP TYPE activity;
+
+-- +goose Down
+-- Parts and activities added after the migration can't go back into the enums,
+-- sessions using them have to be changed before migrating down
+CREATE TYPE part AS ENUM ('props', 'footsteps', 'movements', 'dialogue', 'adr', 'music', 'background', 'other');
+CREATE TYPE activity AS ENUM ('record', 'edit', 'service', 'spotting', 'other');
+
+ALTER TABLE sessions
+    DROP CONSTRAINT sessions_part_worked_on_fkey,
+    DROP CONSTRAINT sessions_activity_done_fkey,
+    ALTER COLUMN part_worked_on TYPE part USING part_worked_on::part,
+    ALTER COLUMN activity_done TYPE activity USING activity_done::activity;
+
+DROP TABLE activities;
+DROP TABLE parts;
@@ -0,0 +1,27 @@
+-- +goose Up
+CREATE TYPE approval_status AS ENUM ('draft', 'submitted', 'approved', 'rejected');
+
+CREATE TABLE timesheets (
+    id UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    user_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
+    week_start DATE NOT NULL,
+    status APPROVAL_STATUS NOT NULL DEFAULT 'submitted',
+    submitted_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    reviewed_by UUID REFERENCES users ON DELETE SET NULL,
+    reviewed_at TIMESTAMP,
+    review_comment TEXT,
+    UNIQUE (user_id, week_start)
+);
+
+-- Sessions logged before the workflow existed were already counted, so they stay approved
+ALTER TABLE sessions
+    ADD COLUMN status APPROVAL_STATUS NOT NULL DEFAULT 'approved',
+    ADD COLUMN timesheet_id UUID REFERENCES timesheets ON DELETE SET NULL;
+ALTER TABLE sessions ALTER COLUMN status SET DEFAULT 'draft';
+
+-- +goose Down
+ALTER TABLE sessions DROP COLUMN timesheet_id, DROP COLUMN status;
+DROP TABLE timesheets;
+DROP TYPE approval_status;
@@ -0,0 +1,34 @@
+-- +goose Up
+CREATE TYPE cue_status AS ENUM ('pending', 'recorded', 'edited', 'approved', 'omitted');
+
+-- Timecodes are stored as frame counts at the cue's frame rate
+CREATE TABLE cues (
+    id UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    episode_id UUID NOT NULL REFERENCES episodes ON DELETE CASCADE,
+    cue_number TEXT NOT NULL,
+    reel TEXT,
+    frame_in BIGINT NOT NULL,
+    frame_out BIGINT NOT NULL,
+    frame_rate TEXT NOT NULL CHECK (frame_rate IN ('23.976', '24', '25', '29.97', '29.97df', '30')),
+    description TEXT,
+    part TEXT REFERENCES parts (code),
+    status CUE_STATUS NOT NULL DEFAULT 'pending',
+    UNIQUE (episode_id, cue_number),
+    CHECK (frame_out >= frame_in)
+);
+
+CREATE TABLE session_cue (
+    id UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    session_id UUID NOT NULL REFERENCES sessions ON DELETE CASCADE,
+    cue_id UUID NOT NULL REFERENCES cues ON DELETE CASCADE,
+    UNIQUE (session_id, cue_id)
+);
+
+-- +goose Down
+DROP TABLE session_cue;
+DROP TABLE cues;
+DROP TYPE cue_status;
@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE cues DROP CONSTRAINT cues_frame_rate_check;
+ALTER TABLE cues ADD CONSTRAINT cues_frame_rate_check
+    CHECK (frame_rate IN ('23.976', '24', '25', '29.97', '29.97df', '30', '50', '59.94', '59.94df', '60'));
+
+-- +goose Down
+ALTER TABLE cues DROP CONSTRAINT cues_frame_rate_check;
+ALTER TABLE cues ADD CONSTRAINT cues_frame_rate_check
+    CHECK (frame_rate IN ('23.976', '24', '25', '29.97', '29.97df', '30'));
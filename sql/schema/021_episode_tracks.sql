@@ -0,0 +1,20 @@
+-- +goose Up
+-- Tracks of the Pro Tools session an episode was finished in, with
+-- statistics of their clips. Lengths are frame counts at frame_rate
+CREATE TABLE episode_tracks (
+    id UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    episode_id UUID NOT NULL REFERENCES episodes ON DELETE CASCADE,
+    session_name TEXT NOT NULL,
+    position INTEGER NOT NULL,
+    track_name TEXT NOT NULL,
+    comments TEXT,
+    clip_count INTEGER NOT NULL,
+    muted_clip_count INTEGER NOT NULL,
+    clip_frames BIGINT NOT NULL,
+    frame_rate TEXT NOT NULL
+);
+
+-- +goose Down
+DROP TABLE episode_tracks;
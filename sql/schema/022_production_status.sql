@@ -0,0 +1,28 @@
+-- +goose Up
+CREATE TYPE production_status AS ENUM ('not_started', 'spotted', 'recorded', 'edited', 'premixed', 'delivered', 'approved');
+
+-- Where each part of each episode is in production. Parts without
+-- a row haven't been started
+CREATE TABLE episode_part_status (
+    id UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    episode_id UUID NOT NULL REFERENCES episodes ON DELETE CASCADE,
+    part TEXT NOT NULL REFERENCES parts (code) ON DELETE CASCADE,
+    status PRODUCTION_STATUS NOT NULL DEFAULT 'not_started',
+    assignee_id UUID REFERENCES users ON DELETE SET NULL,
+    status_date DATE,
+    notes TEXT,
+    UNIQUE (episode_id, part)
+);
+
+-- Logging a session with one of these activities moves its part forward
+ALTER TABLE activities ADD COLUMN advances_to PRODUCTION_STATUS;
+UPDATE activities SET advances_to = 'spotted' WHERE code = 'spotting';
+UPDATE activities SET advances_to = 'recorded' WHERE code = 'record';
+UPDATE activities SET advances_to = 'edited' WHERE code = 'edit';
+
+-- +goose Down
+ALTER TABLE activities DROP COLUMN advances_to;
+DROP TABLE episode_part_status;
+DROP TYPE production_status;
@@ -0,0 +1,23 @@
+-- +goose Up
+CREATE TYPE milestone_kind AS ENUM ('delivery', 'client_review', 'internal');
+
+-- Dates a project, or one of its episodes, has to meet. Milestones
+-- without an episode are for the whole project
+CREATE TABLE milestones (
+    id UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    project_id UUID NOT NULL REFERENCES projects ON DELETE CASCADE,
+    episode_id UUID REFERENCES episodes ON DELETE CASCADE,
+    kind MILESTONE_KIND NOT NULL,
+    title TEXT NOT NULL,
+    due_date DATE NOT NULL,
+    completed_at TIMESTAMP,
+    notes TEXT
+);
+
+CREATE INDEX milestones_due_date_idx ON milestones (due_date) WHERE completed_at IS NULL;
+
+-- +goose Down
+DROP TABLE milestones;
+DROP TYPE milestone_kind;
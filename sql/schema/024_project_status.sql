@@ -0,0 +1,11 @@
+-- +goose Up
+CREATE TYPE project_status AS ENUM ('bidding', 'active', 'on_hold', 'delivered', 'closed', 'archived');
+
+-- Existing projects are taken to be in production
+ALTER TABLE projects ADD COLUMN status PROJECT_STATUS NOT NULL DEFAULT 'active';
+ALTER TABLE projects ADD COLUMN status_changed_at TIMESTAMP;
+
+-- +goose Down
+ALTER TABLE projects DROP COLUMN status_changed_at;
+ALTER TABLE projects DROP COLUMN status;
+DROP TYPE project_status;
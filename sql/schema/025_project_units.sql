@@ -0,0 +1,44 @@
+-- +goose Up
+CREATE TYPE project_type AS ENUM ('series', 'feature', 'commercial', 'game');
+ALTER TABLE projects ADD COLUMN project_type PROJECT_TYPE NOT NULL DEFAULT 'series';
+
+-- Units break projects down below the project level, e.g. the reels of
+-- a feature or the deliverables of a commercial. They can be nested
+CREATE TYPE unit_kind AS ENUM ('season', 'reel', 'deliverable');
+
+CREATE TABLE project_units (
+    id UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    project_id UUID NOT NULL REFERENCES projects ON DELETE CASCADE,
+    parent_id UUID REFERENCES project_units ON DELETE CASCADE,
+    kind UNIT_KIND NOT NULL,
+    name TEXT NOT NULL,
+    sort_order INTEGER NOT NULL DEFAULT 0
+);
+
+-- Sessions are logged for an episode, a unit, or the whole project
+ALTER TABLE sessions ALTER COLUMN episode_id DROP NOT NULL;
+ALTER TABLE sessions ADD COLUMN unit_id UUID REFERENCES project_units ON DELETE CASCADE;
+ALTER TABLE sessions ADD CONSTRAINT sessions_level_check CHECK (episode_id IS NULL OR unit_id IS NULL);
+
+CREATE TABLE unit_calc (
+    id UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    unit_id UUID NOT NULL REFERENCES project_units ON DELETE CASCADE,
+    calc_id UUID NOT NULL REFERENCES calculations ON DELETE CASCADE,
+    UNIQUE (unit_id, calc_id)
+);
+
+-- +goose Down
+DROP TABLE unit_calc;
+-- Sessions above the episode level can't be kept
+DELETE FROM sessions WHERE episode_id IS NULL;
+ALTER TABLE sessions DROP CONSTRAINT sessions_level_check;
+ALTER TABLE sessions DROP COLUMN unit_id;
+ALTER TABLE sessions ALTER COLUMN episode_id SET NOT NULL;
+DROP TABLE project_units;
+DROP TYPE unit_kind;
+ALTER TABLE projects DROP COLUMN project_type;
+DROP TYPE project_type;
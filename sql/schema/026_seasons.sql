@@ -0,0 +1,30 @@
+-- +goose Up
+-- Seasons are units of the season kind. Units can carry a number, dates
+-- and the defaults for calculations made for them
+ALTER TABLE project_units ADD COLUMN number INTEGER;
+ALTER TABLE project_units ADD COLUMN starts_on DATE;
+ALTER TABLE project_units ADD COLUMN ends_on DATE;
+ALTER TABLE project_units ADD COLUMN budget NUMERIC;
+ALTER TABLE project_units ADD COLUMN currency TEXT;
+ALTER TABLE project_units ADD COLUMN exchange_rate NUMERIC;
+CREATE UNIQUE INDEX project_units_number_idx ON project_units (project_id, kind, number) WHERE number IS NOT NULL;
+
+-- Episode numbers are unique per season, or per project for episodes
+-- without a season
+ALTER TABLE episodes ADD COLUMN season_id UUID REFERENCES project_units ON DELETE RESTRICT;
+ALTER TABLE episodes DROP CONSTRAINT episodes_episode_number_project_id_key;
+CREATE UNIQUE INDEX episodes_project_number_idx ON episodes (project_id, episode_number) WHERE season_id IS NULL;
+CREATE UNIQUE INDEX episodes_season_number_idx ON episodes (season_id, episode_number) WHERE season_id IS NOT NULL;
+
+-- +goose Down
+DROP INDEX episodes_season_number_idx;
+DROP INDEX episodes_project_number_idx;
+ALTER TABLE episodes ADD CONSTRAINT episodes_episode_number_project_id_key UNIQUE (episode_number, project_id);
+ALTER TABLE episodes DROP COLUMN season_id;
+DROP INDEX project_units_number_idx;
+ALTER TABLE project_units DROP COLUMN exchange_rate;
+ALTER TABLE project_units DROP COLUMN currency;
+ALTER TABLE project_units DROP COLUMN budget;
+ALTER TABLE project_units DROP COLUMN ends_on;
+ALTER TABLE project_units DROP COLUMN starts_on;
+ALTER TABLE project_units DROP COLUMN number;
@@ -0,0 +1,10 @@
+-- +goose Up
+-- Runtime is kept in seconds, frame rates by their timecode names, e.g. 23.976
+ALTER TABLE episodes ADD COLUMN runtime_seconds INTEGER;
+ALTER TABLE episodes ADD COLUMN picture_lock DATE;
+ALTER TABLE episodes ADD COLUMN frame_rate TEXT;
+
+-- +goose Down
+ALTER TABLE episodes DROP COLUMN frame_rate;
+ALTER TABLE episodes DROP COLUMN picture_lock;
+ALTER TABLE episodes DROP COLUMN runtime_seconds;
@@ -0,0 +1,44 @@
+-- +goose Up
+-- Billing details. Tax IDs are Polish NIPs or EU VAT numbers, payment terms
+-- are days from the invoice date, languages are ISO 639-1 codes
+ALTER TABLE clients ADD COLUMN tax_id TEXT;
+ALTER TABLE clients ADD COLUMN default_currency TEXT;
+ALTER TABLE clients ADD COLUMN payment_terms_days INTEGER;
+ALTER TABLE clients ADD COLUMN invoice_language TEXT;
+
+CREATE TABLE client_contacts (
+    id UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    client_id UUID NOT NULL REFERENCES clients ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    role TEXT,
+    email TEXT,
+    phone TEXT
+);
+
+CREATE TYPE address_kind AS ENUM ('billing', 'postal');
+
+-- A client has at most one address of each kind
+CREATE TABLE client_addresses (
+    id UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    client_id UUID NOT NULL REFERENCES clients ON DELETE CASCADE,
+    kind ADDRESS_KIND NOT NULL,
+    line1 TEXT NOT NULL,
+    line2 TEXT,
+    postal_code TEXT NOT NULL,
+    city TEXT NOT NULL,
+    country TEXT NOT NULL,
+    UNIQUE (client_id, kind)
+);
+
+-- +goose Down
+DROP TABLE client_addresses;
+DROP TYPE address_kind;
+DROP TABLE client_contacts;
+ALTER TABLE clients DROP COLUMN invoice_language;
+ALTER TABLE clients DROP COLUMN payment_terms_days;
+ALTER TABLE clients DROP COLUMN default_currency;
+ALTER TABLE clients DROP COLUMN tax_id;
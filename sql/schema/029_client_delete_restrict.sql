@@ -0,0 +1,21 @@
+-- +goose Up
+-- Deleting a client no longer takes its projects with it, forced deletes
+-- remove the projects explicitly first
+ALTER TABLE projects DROP CONSTRAINT projects_client_id_fkey;
+ALTER TABLE projects ADD CONSTRAINT projects_client_id_fkey
+    FOREIGN KEY (client_id) REFERENCES clients ON DELETE RESTRICT;
+
+-- Seasons and their episodes go together when a project is deleted. RESTRICT
+-- is checked row by row and can refuse that depending on the cascade order,
+-- NO ACTION waits for the end of the statement
+ALTER TABLE episodes DROP CONSTRAINT episodes_season_id_fkey;
+ALTER TABLE episodes ADD CONSTRAINT episodes_season_id_fkey
+    FOREIGN KEY (season_id) REFERENCES project_units;
+
+-- +goose Down
+ALTER TABLE episodes DROP CONSTRAINT episodes_season_id_fkey;
+ALTER TABLE episodes ADD CONSTRAINT episodes_season_id_fkey
+    FOREIGN KEY (season_id) REFERENCES project_units ON DELETE RESTRICT;
+ALTER TABLE projects DROP CONSTRAINT projects_client_id_fkey;
+ALTER TABLE projects ADD CONSTRAINT projects_client_id_fkey
+    FOREIGN KEY (client_id) REFERENCES clients ON DELETE CASCADE;
@@ -0,0 +1,41 @@
+-- +goose Up
+-- Deleted clients, projects, episodes, sessions and calculations stay in
+-- the trash until purged. Everything deleted together shares the deleted_at
+-- of the item the deletion started from, which is how restores find them
+ALTER TABLE clients ADD COLUMN deleted_at TIMESTAMP;
+ALTER TABLE projects ADD COLUMN deleted_at TIMESTAMP;
+ALTER TABLE episodes ADD COLUMN deleted_at TIMESTAMP;
+ALTER TABLE sessions ADD COLUMN deleted_at TIMESTAMP;
+ALTER TABLE calculations ADD COLUMN deleted_at TIMESTAMP;
+
+-- Names and numbers only have to be unique among the items not in the trash
+ALTER TABLE clients DROP CONSTRAINT clients_client_name_key;
+CREATE UNIQUE INDEX clients_client_name_idx ON clients (client_name) WHERE deleted_at IS NULL;
+ALTER TABLE projects DROP CONSTRAINT projects_title_key;
+CREATE UNIQUE INDEX projects_title_idx ON projects (title) WHERE deleted_at IS NULL;
+DROP INDEX episodes_project_number_idx;
+DROP INDEX episodes_season_number_idx;
+CREATE UNIQUE INDEX episodes_project_number_idx ON episodes (project_id, episode_number)
+    WHERE season_id IS NULL AND deleted_at IS NULL;
+CREATE UNIQUE INDEX episodes_season_number_idx ON episodes (season_id, episode_number)
+    WHERE season_id IS NOT NULL AND deleted_at IS NULL;
+
+-- +goose Down
+DELETE FROM sessions WHERE deleted_at IS NOT NULL;
+DELETE FROM calculations WHERE deleted_at IS NOT NULL;
+DELETE FROM episodes WHERE deleted_at IS NOT NULL;
+DELETE FROM projects WHERE deleted_at IS NOT NULL;
+DELETE FROM clients WHERE deleted_at IS NOT NULL;
+DROP INDEX episodes_season_number_idx;
+DROP INDEX episodes_project_number_idx;
+CREATE UNIQUE INDEX episodes_project_number_idx ON episodes (project_id, episode_number) WHERE season_id IS NULL;
+CREATE UNIQUE INDEX episodes_season_number_idx ON episodes (season_id, episode_number) WHERE season_id IS NOT NULL;
+DROP INDEX projects_title_idx;
+ALTER TABLE projects ADD CONSTRAINT projects_title_key UNIQUE (title);
+DROP INDEX clients_client_name_idx;
+ALTER TABLE clients ADD CONSTRAINT clients_client_name_key UNIQUE (client_name);
+ALTER TABLE calculations DROP COLUMN deleted_at;
+ALTER TABLE sessions DROP COLUMN deleted_at;
+ALTER TABLE episodes DROP COLUMN deleted_at;
+ALTER TABLE projects DROP COLUMN deleted_at;
+ALTER TABLE clients DROP COLUMN deleted_at;
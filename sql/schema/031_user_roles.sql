@@ -0,0 +1,12 @@
+-- +goose Up
+-- What a user is allowed to do is decided by their role, see
+-- cmd/server/permissions.go for the matrix
+CREATE TYPE user_role AS ENUM ('admin', 'manager', 'engineer', 'accountant');
+ALTER TABLE users ADD COLUMN role USER_ROLE NOT NULL DEFAULT 'engineer';
+
+-- Existing installations keep someone able to assign roles
+UPDATE users SET role = 'admin' WHERE id = (SELECT id FROM users ORDER BY created_at LIMIT 1);
+
+-- +goose Down
+ALTER TABLE users DROP COLUMN role;
+DROP TYPE user_role;
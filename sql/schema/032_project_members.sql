@@ -0,0 +1,24 @@
+-- +goose Up
+-- Users other than admins and managers only see the projects they're
+-- members of. Leads manage the members, members log sessions, viewers
+-- only look
+CREATE TYPE project_role AS ENUM ('lead', 'member', 'viewer');
+
+CREATE TABLE project_members (
+    id UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    project_id UUID NOT NULL REFERENCES projects ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
+    role PROJECT_ROLE NOT NULL DEFAULT 'member',
+    UNIQUE (project_id, user_id)
+);
+
+-- Whoever already logged sessions on a project keeps access to it
+INSERT INTO project_members (project_id, user_id)
+SELECT DISTINCT sessions.project_id, user_session.user_id
+FROM sessions JOIN user_session ON user_session.session_id = sessions.id;
+
+-- +goose Down
+DROP TABLE project_members;
+DROP TYPE project_role;
@@ -0,0 +1,15 @@
+-- +goose Up
+-- Refresh tokens are rotated on every use. A token and the ones it was
+-- rotated into form a family, which gets revoked as a whole when a rotated
+-- token shows up again, as that means it was stolen
+ALTER TABLE refresh_tokens ADD COLUMN family_id UUID NOT NULL DEFAULT gen_random_uuid();
+ALTER TABLE refresh_tokens ALTER COLUMN family_id DROP DEFAULT;
+ALTER TABLE refresh_tokens ADD COLUMN replaced_by TEXT;
+CREATE INDEX refresh_tokens_family_idx ON refresh_tokens (family_id);
+CREATE INDEX refresh_tokens_user_idx ON refresh_tokens (user_id);
+
+-- +goose Down
+DROP INDEX refresh_tokens_user_idx;
+DROP INDEX refresh_tokens_family_idx;
+ALTER TABLE refresh_tokens DROP COLUMN replaced_by;
+ALTER TABLE refresh_tokens DROP COLUMN family_id;
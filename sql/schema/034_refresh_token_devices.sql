@@ -0,0 +1,15 @@
+-- +goose Up
+-- What users see when they list their logins. The device label is what the
+-- client called itself, the rest comes from the requests
+ALTER TABLE refresh_tokens ADD COLUMN device_label TEXT;
+ALTER TABLE refresh_tokens ADD COLUMN user_agent TEXT;
+ALTER TABLE refresh_tokens ADD COLUMN ip_address TEXT;
+-- A refresh token is only used when it's traded for the next one, which is
+-- when that one gets written. Requests made with the JWT don't touch it
+ALTER TABLE refresh_tokens ADD COLUMN last_used_at TIMESTAMP NOT NULL DEFAULT NOW();
+
+-- +goose Down
+ALTER TABLE refresh_tokens DROP COLUMN last_used_at;
+ALTER TABLE refresh_tokens DROP COLUMN ip_address;
+ALTER TABLE refresh_tokens DROP COLUMN user_agent;
+ALTER TABLE refresh_tokens DROP COLUMN device_label;
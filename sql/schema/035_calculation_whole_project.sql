@@ -0,0 +1,15 @@
+-- +goose Up
+-- Calculations count the sessions of the episodes and units added to them.
+-- Counting the whole project instead has to be asked for
+ALTER TABLE calculations ADD COLUMN whole_project BOOLEAN NOT NULL DEFAULT FALSE;
+-- Calculations of other project types with nothing added have counted the
+-- whole project so far, series ones have counted nothing
+UPDATE calculations SET whole_project = TRUE
+WHERE NOT EXISTS (SELECT 1 FROM episode_calc WHERE episode_calc.calc_id = calculations.id)
+AND NOT EXISTS (SELECT 1 FROM unit_calc WHERE unit_calc.calc_id = calculations.id)
+AND EXISTS (
+    SELECT 1 FROM projects WHERE projects.id = calculations.project_id AND projects.project_type <> 'series'
+);
+
+-- +goose Down
+ALTER TABLE calculations DROP COLUMN whole_project;